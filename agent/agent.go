@@ -0,0 +1,273 @@
+// Package agent 实现自动执行工具调用的智能体循环：注册 Go 函数作为工具，
+// Run 会反复调用模型、执行模型请求的函数并提交结果，直到模型给出最终回答或达到最大步数。
+//
+//	a, _ := agent.New(cfg, agent.WithTools(weatherTool))
+//	result, err := a.Run(ctx, "杭州今天适合户外跑步吗？")
+//	fmt.Println(result.Answer)
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultMaxSteps 是默认的最大步数
+const defaultMaxSteps = 10
+
+// ErrMaxSteps 表示达到最大步数时模型仍在请求调用工具
+var ErrMaxSteps = errors.New("agent: max steps reached without a final answer")
+
+// Agent 是一个可以自动执行工具调用的智能体，可并发使用
+type Agent struct {
+	config   llm.Config
+	client   spec.Client
+	tools    map[string]Tool
+	order    []string
+	maxSteps int
+	parallel bool
+	chatOpts []spec.Option
+	hooks    []StepHook
+	approver Approver
+
+	// release 释放 client，见 llm.AcquireClient
+	release func()
+}
+
+// Option 用于配置 Agent
+type Option func(a *Agent)
+
+// StepHook 在每一步（一次模型调用及其工具执行）完成后调用，返回错误会中止 Run
+type StepHook func(ctx context.Context, step *Step) error
+
+// WithTools 注册工具，同名工具以后注册的为准
+func WithTools(tools ...Tool) Option {
+	return func(a *Agent) {
+		for _, t := range tools {
+			a.register(t)
+		}
+	}
+}
+
+// WithMaxSteps 设置最大步数，即最多调用模型的次数，默认为 10
+func WithMaxSteps(n int) Option {
+	return func(a *Agent) {
+		a.maxSteps = n
+	}
+}
+
+// WithParallelTools 同一步中的多个工具调用并发执行，默认按顺序执行
+func WithParallelTools() Option {
+	return func(a *Agent) {
+		a.parallel = true
+	}
+}
+
+// WithChatOptions 为每次模型调用附加选项，如 spec.WithTemperature、spec.WithToolChoice
+func WithChatOptions(opts ...spec.Option) Option {
+	return func(a *Agent) {
+		a.chatOpts = append(a.chatOpts, opts...)
+	}
+}
+
+// WithStepHook 注册每步完成后的钩子，可用于记录日志、展示进度或在满足条件时中止
+func WithStepHook(hooks ...StepHook) Option {
+	return func(a *Agent) {
+		a.hooks = append(a.hooks, hooks...)
+	}
+}
+
+// New 创建智能体，cfg 与 client.New 使用的配置相同，SystemPrompt 作为智能体的系统提示词
+func New(cfg llm.Config, opts ...Option) (*Agent, error) {
+	pc, release, err := llm.AcquireClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	a := &Agent{
+		config:   cfg,
+		client:   pc,
+		release:  release,
+		tools:    make(map[string]Tool),
+		maxSteps: defaultMaxSteps,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+// Close 释放智能体独占的 provider 客户端（配置了 Interceptors 等选项且未设置 ClientID 时），
+// 缓存中共享的客户端不受影响。关闭后不能再调用 Run
+func (a *Agent) Close() error {
+	a.release()
+	return nil
+}
+
+// register 注册一个工具
+func (a *Agent) register(t Tool) {
+	if _, ok := a.tools[t.Name]; !ok {
+		a.order = append(a.order, t.Name)
+	}
+	a.tools[t.Name] = t
+}
+
+// Step 是智能体循环中的一步
+type Step struct {
+	// Index 从 1 开始的步数
+	Index int
+	// Response 本步模型的回复
+	Response *spec.Response
+	// Results 本步执行的工具调用，模型给出最终回答时为空
+	Results []ToolResult
+}
+
+// ToolResult 是一次工具调用的执行结果
+type ToolResult struct {
+	Call spec.ToolCall
+	// Output 提交给模型的结果；执行失败时为错误信息
+	Output string
+	// Err 工具返回的错误，错误信息会作为结果提交给模型，不会中止循环
+	Err error
+	// Rejected 工具调用是否被审批拒绝（见 WithApproval），被拒绝的调用不会执行
+	Rejected bool
+}
+
+// Result 是 Run 的结果
+type Result struct {
+	// Answer 模型的最终回答
+	Answer string
+	// Response 最后一次模型调用的回复
+	Response *spec.Response
+	// Messages 完整的对话记录，包括工具调用和结果，可用于继续对话
+	Messages []spec.Message
+	// Steps 每一步的详情
+	Steps []Step
+	// Usage 所有模型调用的用量之和
+	Usage spec.Usage
+}
+
+// Run 以 prompt 开始一轮任务，直到模型给出最终回答。
+// 达到最大步数时返回已有的结果和 ErrMaxSteps。
+func (a *Agent) Run(ctx context.Context, prompt string) (*Result, error) {
+	var messages []spec.Message
+	if a.config.SystemPrompt != "" {
+		messages = append(messages, spec.NewSystemMessage(a.config.SystemPrompt))
+	}
+	messages = append(messages, spec.NewUserMessage(prompt))
+	return a.RunMessages(ctx, messages)
+}
+
+// RunMessages 与 Run 相同，但以完整的消息列表开始，可传入上一次 Result.Messages 继续对话
+func (a *Agent) RunMessages(ctx context.Context, messages []spec.Message) (*Result, error) {
+	result := &Result{Messages: spec.CloneMessages(messages)}
+	opts := a.options()
+
+	for i := 1; i <= a.maxSteps; i++ {
+		resp, err := llm.RunFailover(ctx, a.config, a.client, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+			return pc.Model(cfg.Model).Chat(ctx, result.Messages, opts...)
+		})
+		if err != nil {
+			return result, fmt.Errorf("agent: step %d: %w", i, err)
+		}
+		result.Response = resp
+		result.Usage = result.Usage.Add(resp.Usage)
+		result.Messages = append(result.Messages, resp.Message)
+
+		step := Step{Index: i, Response: resp}
+		if len(resp.Message.ToolCalls) > 0 {
+			calls, decisions, err := a.approve(ctx, resp.Message.ToolCalls)
+			if err != nil {
+				return result, fmt.Errorf("agent: step %d: approval failed: %w", i, err)
+			}
+			// 记录实际执行的参数，使模型看到修改后的调用
+			result.Messages[len(result.Messages)-1].ToolCalls = calls
+			step.Results = a.execute(ctx, calls, decisions)
+			for _, r := range step.Results {
+				result.Messages = append(result.Messages, spec.NewToolResultMessage(r.Call.ID, r.Output))
+			}
+		}
+		result.Steps = append(result.Steps, step)
+
+		for _, hook := range a.hooks {
+			if err := hook(ctx, &result.Steps[len(result.Steps)-1]); err != nil {
+				return result, err
+			}
+		}
+		if len(resp.Message.ToolCalls) == 0 {
+			result.Answer = resp.Message.Content
+			return result, nil
+		}
+	}
+	return result, ErrMaxSteps
+}
+
+// options 构建每次模型调用的选项
+func (a *Agent) options() []spec.Option {
+	var opts []spec.Option
+	if a.config.Parameters != nil {
+		opts = append(opts, spec.WithParameters(a.config.Parameters))
+	}
+	if a.config.Thinking != nil {
+		opts = append(opts, spec.WithThinking(*a.config.Thinking))
+	}
+	if a.config.StreamCallback != nil {
+		opts = append(opts, spec.WithStreamCallback(a.config.StreamCallback))
+	}
+	tools := make([]spec.Tool, 0, len(a.order))
+	for _, name := range a.order {
+		tools = append(tools, a.tools[name].spec())
+	}
+	if len(tools) > 0 {
+		opts = append(opts, spec.WithTools(tools...))
+	}
+	return append(opts, a.chatOpts...)
+}
+
+// execute 执行一步中的全部工具调用，被拒绝的调用不执行，结果顺序与调用顺序一致
+func (a *Agent) execute(ctx context.Context, calls []spec.ToolCall, decisions []Decision) []ToolResult {
+	results := make([]ToolResult, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		if decisions[i].Action == ActionReject {
+			results[i] = ToolResult{Call: call, Output: rejection(decisions[i]), Rejected: true}
+			continue
+		}
+		if !a.parallel {
+			results[i] = a.call(ctx, call)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = a.call(ctx, call)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// call 执行一次工具调用，未知工具和执行错误都转换为提交给模型的错误信息
+func (a *Agent) call(ctx context.Context, call spec.ToolCall) (result ToolResult) {
+	result.Call = call
+	tool, ok := a.tools[call.Function.Name]
+	if !ok {
+		result.Err = fmt.Errorf("unknown tool %q", call.Function.Name)
+	} else {
+		defer func() {
+			if r := recover(); r != nil {
+				result.Err = fmt.Errorf("tool %s panicked: %v", call.Function.Name, r)
+				result.Output = "error: " + result.Err.Error()
+			}
+		}()
+		result.Output, result.Err = tool.Handler(ctx, json.RawMessage(call.Function.Arguments))
+	}
+	if result.Err != nil {
+		result.Output = "error: " + result.Err.Error()
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Action 是对一次工具调用的审批结果
+type Action int
+
+const (
+	// ActionApprove 按模型生成的参数执行
+	ActionApprove Action = iota
+	// ActionModify 使用修改后的参数执行
+	ActionModify
+	// ActionReject 不执行，把拒绝原因作为结果提交给模型
+	ActionReject
+)
+
+// Decision 是对一次工具调用的审批决定
+type Decision struct {
+	Action Action
+	// Arguments ActionModify 时使用的 JSON 参数
+	Arguments string
+	// Reason ActionReject 时提交给模型的拒绝原因
+	Reason string
+}
+
+// Approve 批准执行
+func Approve() Decision {
+	return Decision{Action: ActionApprove}
+}
+
+// Modify 使用修改后的 JSON 参数执行
+func Modify(arguments string) Decision {
+	return Decision{Action: ActionModify, Arguments: arguments}
+}
+
+// Reject 拒绝执行，reason 会提交给模型，使其调整方案或向用户说明
+func Reject(reason string) Decision {
+	return Decision{Action: ActionReject, Reason: reason}
+}
+
+// Approver 在工具执行前审批一步中的全部工具调用，返回与 calls 一一对应的决定。
+// 返回错误会中止 Run，可用于用户取消任务
+type Approver func(ctx context.Context, calls []spec.ToolCall) ([]Decision, error)
+
+// WithApproval 注册工具调用的审批回调，适用于有副作用的工具（发送邮件、写数据库、付款等）。
+// 每一步的工具调用都会先交给 approver，批准后才执行；需要只审批部分工具时使用 RequireApproval
+func WithApproval(approver Approver) Option {
+	return func(a *Agent) {
+		a.approver = approver
+	}
+}
+
+// RequireApproval 返回只审批指定工具的 Approver：调用 names 中的工具时逐个询问 ask，其余工具直接批准
+//
+//	agent.WithApproval(agent.RequireApproval(func(ctx context.Context, call spec.ToolCall) (agent.Decision, error) {
+//		fmt.Printf("允许执行 %s(%s)？[y/N] ", call.Function.Name, call.Function.Arguments)
+//		if readYes() {
+//			return agent.Approve(), nil
+//		}
+//		return agent.Reject("用户拒绝了该操作"), nil
+//	}, "send_email", "delete_file"))
+func RequireApproval(ask func(ctx context.Context, call spec.ToolCall) (Decision, error), names ...string) Approver {
+	return func(ctx context.Context, calls []spec.ToolCall) ([]Decision, error) {
+		decisions := make([]Decision, len(calls))
+		for i, call := range calls {
+			if !slices.Contains(names, call.Function.Name) {
+				decisions[i] = Approve()
+				continue
+			}
+			d, err := ask(ctx, call)
+			if err != nil {
+				return nil, err
+			}
+			decisions[i] = d
+		}
+		return decisions, nil
+	}
+}
+
+// approve 审批工具调用，返回应用了参数修改的调用副本和对应的决定；未注册 Approver 时全部批准
+func (a *Agent) approve(ctx context.Context, calls []spec.ToolCall) ([]spec.ToolCall, []Decision, error) {
+	decisions := make([]Decision, len(calls))
+	if a.approver == nil {
+		return calls, decisions, nil
+	}
+	got, err := a.approver(ctx, slices.Clone(calls))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(got) != len(calls) {
+		return nil, nil, fmt.Errorf("got %d decisions for %d tool calls", len(got), len(calls))
+	}
+	calls = slices.Clone(calls)
+	for i, d := range got {
+		if d.Action == ActionModify {
+			if !json.Valid([]byte(d.Arguments)) {
+				return nil, nil, fmt.Errorf("modified arguments for %s are not valid JSON", calls[i].Function.Name)
+			}
+			calls[i].Function.Arguments = d.Arguments
+		}
+	}
+	return calls, got, nil
+}
+
+// rejection 返回拒绝执行时提交给模型的结果
+func rejection(d Decision) string {
+	if d.Reason == "" {
+		return "rejected: the user did not approve this tool call"
+	}
+	return "rejected: the user did not approve this tool call: " + d.Reason
+}
@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tools"
+)
+
+// Tool 是注册给智能体的一个 Go 函数
+type Tool struct {
+	// Name 函数名，只能包含字母、数字、下划线和连字符
+	Name string
+	// Description 函数说明，模型据此决定何时调用
+	Description string
+	// Parameters 参数的 JSON Schema，可以是 map、结构体或 json.RawMessage；nil 表示无参数
+	Parameters any
+	// Handler 执行函数，args 为模型生成的 JSON 参数，返回的文本作为结果提交给模型
+	Handler func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// Func 用带类型的参数创建 Tool，模型生成的参数会先解析到 T 再调用 fn。
+// parameters 为 nil 时由 tools.SchemaOf 根据 T 的字段和标签生成
+func Func[T any](name, description string, parameters any, fn func(ctx context.Context, args T) (string, error)) Tool {
+	if parameters == nil {
+		if schema, err := tools.SchemaOf(reflect.TypeFor[T]()); err == nil {
+			parameters = schema
+		}
+	}
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var args T
+			if len(raw) > 0 {
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			return fn(ctx, args)
+		},
+	}
+}
+
+// FromFunc 从 Go 函数创建 Tool，函数签名和参数 Schema 的规则见 tools.FromFunc
+func FromFunc(fn any, opts ...tools.Option) (Tool, error) {
+	f, err := tools.FromFunc(fn, opts...)
+	if err != nil {
+		return Tool{}, err
+	}
+	return Tool{
+		Name:        f.Name,
+		Description: f.Description,
+		Parameters:  f.Parameters,
+		Handler:     f.Call,
+	}, nil
+}
+
+// spec 返回发送给模型的函数定义
+func (t Tool) spec() spec.Tool {
+	params := t.Parameters
+	if params == nil {
+		params = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	return spec.NewFunctionTool(t.Name, t.Description, params)
+}
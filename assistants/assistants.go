@@ -0,0 +1,189 @@
+// Package assistants 是 OpenAI Assistants API (v2) 的轻量封装：助手、会话线程、消息、运行、运行步骤和工具结果提交。
+// 需要由服务端保存会话状态时使用；只需要多轮对话时推荐使用 client 包的本地历史管理。
+package assistants
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Client 是 Assistants API 的客户端，可并发使用
+type Client struct {
+	requester *requester.Requester
+	config    spec.ClientConfig
+	baseURL   string
+}
+
+// New 创建 Assistants API 客户端，重试、代理、超时等选项与 Provider 客户端一致。
+// WithAPIURL 可以传入 API 的基础地址（如 "https://api.openai.com/v1"）或 Chat 端点。
+func New(opts ...spec.ClientOption) (*Client, error) {
+	config := spec.NewClientConfig()
+	config.APIURL = "https://api.openai.com/v1"
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("assistants: API key is required, use spec.WithAPIKey()")
+	}
+
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("assistants: %w", err)
+	}
+	return &Client{
+		requester: req.WithErrorParser(requester.ParseOpenAIError),
+		config:    *config,
+		baseURL:   strings.TrimSuffix(strings.TrimSuffix(config.APIURL, "/chat/completions"), "/"),
+	}, nil
+}
+
+// Close 取消进行中的请求并释放空闲连接
+func (c *Client) Close() error {
+	return c.requester.Close()
+}
+
+// headers 返回 Assistants API 的请求头
+func (c *Client) headers() http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+	headers.Set("OpenAI-Beta", "assistants=v2")
+	return headers
+}
+
+// post 发送 POST 请求并将响应解析到 out
+func (c *Client) post(ctx context.Context, path string, body any, out any) error {
+	rawBody, err := c.requester.Post(ctx, c.baseURL+path, c.headers(), body)
+	if err != nil {
+		return err
+	}
+	return decode(rawBody, out)
+}
+
+// get 发送 GET 请求并将响应解析到 out
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	rawBody, err := c.requester.Get(ctx, c.baseURL+path, c.headers())
+	if err != nil {
+		return err
+	}
+	return decode(rawBody, out)
+}
+
+// delete 发送 DELETE 请求，确认对象已被删除
+func (c *Client) delete(ctx context.Context, path string) error {
+	rawBody, err := c.requester.Delete(ctx, c.baseURL+path, c.headers())
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Deleted bool `json:"deleted"`
+	}
+	if err := decode(rawBody, &result); err != nil {
+		return err
+	}
+	if !result.Deleted {
+		return fmt.Errorf("assistants: %s was not deleted", path)
+	}
+	return nil
+}
+
+// list 按 after 游标翻页取回列表中的全部对象
+func list[T any](ctx context.Context, c *Client, path string, query url.Values, id func(T) string) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", "100")
+	var items []T
+	for {
+		var page struct {
+			Data    []T  `json:"data"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := c.get(ctx, path+"?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Data...)
+		if !page.HasMore || len(page.Data) == 0 {
+			return items, nil
+		}
+		query.Set("after", id(items[len(items)-1]))
+	}
+}
+
+// decode 解析响应体
+func decode(rawBody []byte, out any) error {
+	if err := json.Unmarshal(rawBody, out); err != nil {
+		return fmt.Errorf("assistants: failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// Assistant 是服务端保存的助手配置
+type Assistant struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Model        string            `json:"model"`
+	Instructions string            `json:"instructions"`
+	Tools        []map[string]any  `json:"tools"`
+	Metadata     map[string]string `json:"metadata"`
+	CreatedAt    int64             `json:"created_at"`
+}
+
+// AssistantRequest 创建或修改助手的参数
+type AssistantRequest struct {
+	Model        string `json:"model,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	// Tools 助手可用的工具，如 {"type": "file_search"}、{"type": "code_interpreter"} 或函数定义
+	Tools []map[string]any `json:"tools,omitempty"`
+	// ToolResources 工具使用的资源，如 file_search 的 vector_store_ids
+	ToolResources map[string]any    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateAssistant 创建助手
+func (c *Client) CreateAssistant(ctx context.Context, req AssistantRequest) (*Assistant, error) {
+	var a Assistant
+	if err := c.post(ctx, "/assistants", req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateAssistant 修改助手，只更新 req 中的非零字段
+func (c *Client) UpdateAssistant(ctx context.Context, id string, req AssistantRequest) (*Assistant, error) {
+	var a Assistant
+	if err := c.post(ctx, "/assistants/"+url.PathEscape(id), req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAssistant 查询助手
+func (c *Client) GetAssistant(ctx context.Context, id string) (*Assistant, error) {
+	var a Assistant
+	if err := c.get(ctx, "/assistants/"+url.PathEscape(id), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAssistants 列出全部助手
+func (c *Client) ListAssistants(ctx context.Context) ([]Assistant, error) {
+	return list(ctx, c, "/assistants", nil, func(a Assistant) string { return a.ID })
+}
+
+// DeleteAssistant 删除助手
+func (c *Client) DeleteAssistant(ctx context.Context, id string) error {
+	return c.delete(ctx, "/assistants/"+url.PathEscape(id))
+}
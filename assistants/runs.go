@@ -0,0 +1,222 @@
+package assistants
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultPollInterval 是等待运行结束时的默认轮询间隔
+const defaultPollInterval = time.Second
+
+// RunStatus 是运行的状态
+type RunStatus string
+
+const (
+	RunQueued         RunStatus = "queued"
+	RunInProgress     RunStatus = "in_progress"
+	RunRequiresAction RunStatus = "requires_action"
+	RunCancelling     RunStatus = "cancelling"
+	RunCancelled      RunStatus = "cancelled"
+	RunFailed         RunStatus = "failed"
+	RunCompleted      RunStatus = "completed"
+	RunIncomplete     RunStatus = "incomplete"
+	RunExpired        RunStatus = "expired"
+)
+
+// Done 判断运行是否已经结束
+func (s RunStatus) Done() bool {
+	switch s {
+	case RunCancelled, RunFailed, RunCompleted, RunIncomplete, RunExpired:
+		return true
+	}
+	return false
+}
+
+// Run 是助手在线程上的一次运行
+type Run struct {
+	ID             string          `json:"id"`
+	ThreadID       string          `json:"thread_id"`
+	AssistantID    string          `json:"assistant_id"`
+	Status         RunStatus       `json:"status"`
+	Model          string          `json:"model"`
+	RequiredAction *RequiredAction `json:"required_action"`
+	LastError      *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"last_error"`
+	Usage       *spec.Usage `json:"usage"`
+	CreatedAt   int64       `json:"created_at"`
+	CompletedAt int64       `json:"completed_at"`
+}
+
+// RequiredAction 表示运行需要调用方执行函数并提交结果
+type RequiredAction struct {
+	Type              string `json:"type"`
+	SubmitToolOutputs struct {
+		ToolCalls []ToolCall `json:"tool_calls"`
+	} `json:"submit_tool_outputs"`
+}
+
+// ToolCall 是助手请求调用的函数
+type ToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// ToolCalls 返回运行等待执行的函数调用，不需要执行时返回 nil
+func (r *Run) ToolCalls() []ToolCall {
+	if r.Status != RunRequiresAction || r.RequiredAction == nil {
+		return nil
+	}
+	return r.RequiredAction.SubmitToolOutputs.ToolCalls
+}
+
+// ToolOutput 是一次函数调用的执行结果
+type ToolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+// ToolHandler 执行助手请求的函数调用并返回结果文本
+type ToolHandler func(ctx context.Context, call ToolCall) (string, error)
+
+// RunRequest 创建运行的参数，零值字段使用助手的配置
+type RunRequest struct {
+	AssistantID string `json:"assistant_id"`
+	Model       string `json:"model,omitempty"`
+	// Instructions 覆盖助手的指令
+	Instructions string `json:"instructions,omitempty"`
+	// AdditionalInstructions 追加在助手指令之后
+	AdditionalInstructions string            `json:"additional_instructions,omitempty"`
+	Tools                  []map[string]any  `json:"tools,omitempty"`
+	Metadata               map[string]string `json:"metadata,omitempty"`
+}
+
+// RunStep 是运行中的一个步骤，Type 为 "message_creation" 或 "tool_calls"
+type RunStep struct {
+	ID          string          `json:"id"`
+	RunID       string          `json:"run_id"`
+	Type        string          `json:"type"`
+	Status      string          `json:"status"`
+	StepDetails json.RawMessage `json:"step_details"`
+	Usage       *spec.Usage     `json:"usage"`
+	CreatedAt   int64           `json:"created_at"`
+}
+
+// CreateRun 在线程上启动一次运行
+func (c *Client) CreateRun(ctx context.Context, threadID string, req RunRequest) (*Run, error) {
+	var r Run
+	if err := c.post(ctx, "/threads/"+url.PathEscape(threadID)+"/runs", req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetRun 查询运行状态
+func (c *Client) GetRun(ctx context.Context, threadID, runID string) (*Run, error) {
+	var r Run
+	if err := c.get(ctx, runPath(threadID, runID), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CancelRun 取消进行中的运行
+func (c *Client) CancelRun(ctx context.Context, threadID, runID string) (*Run, error) {
+	var r Run
+	if err := c.post(ctx, runPath(threadID, runID)+"/cancel", map[string]any{}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// SubmitToolOutputs 提交函数调用的结果，运行随后继续执行
+func (c *Client) SubmitToolOutputs(ctx context.Context, threadID, runID string, outputs []ToolOutput) (*Run, error) {
+	var r Run
+	body := map[string]any{"tool_outputs": outputs}
+	if err := c.post(ctx, runPath(threadID, runID)+"/submit_tool_outputs", body, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListRunSteps 按时间顺序列出运行的全部步骤
+func (c *Client) ListRunSteps(ctx context.Context, threadID, runID string) ([]RunStep, error) {
+	query := url.Values{"order": {"asc"}}
+	return list(ctx, c, runPath(threadID, runID)+"/steps", query, func(s RunStep) string { return s.ID })
+}
+
+// WaitRun 按 interval 轮询，直到运行结束或需要提交函数结果，interval <= 0 时每秒查询一次
+func (c *Client) WaitRun(ctx context.Context, threadID, runID string, interval time.Duration) (*Run, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		run, err := c.GetRun(ctx, threadID, runID)
+		if err != nil {
+			return nil, err
+		}
+		if run.Status.Done() || run.Status == RunRequiresAction {
+			return run, nil
+		}
+		select {
+		case <-ctx.Done():
+			return run, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunAndWait 启动运行并等待结束，期间助手请求的函数调用交给 handler 执行并自动提交结果。
+// handler 为 nil 且助手请求函数调用时返回错误；运行失败时返回的错误包含 last_error 信息。
+func (c *Client) RunAndWait(ctx context.Context, threadID string, req RunRequest, handler ToolHandler) (*Run, error) {
+	run, err := c.CreateRun(ctx, threadID, req)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		run, err = c.WaitRun(ctx, threadID, run.ID, 0)
+		if err != nil {
+			return run, err
+		}
+
+		calls := run.ToolCalls()
+		if calls == nil {
+			if run.Status == RunFailed && run.LastError != nil {
+				return run, fmt.Errorf("assistants: run %s failed: %s: %s", run.ID, run.LastError.Code, run.LastError.Message)
+			}
+			return run, nil
+		}
+		if handler == nil {
+			return run, fmt.Errorf("assistants: run %s requires tool outputs but no handler was given", run.ID)
+		}
+
+		outputs := make([]ToolOutput, 0, len(calls))
+		for _, call := range calls {
+			output, err := handler(ctx, call)
+			if err != nil {
+				return run, fmt.Errorf("assistants: tool %s failed: %w", call.Function.Name, err)
+			}
+			outputs = append(outputs, ToolOutput{ToolCallID: call.ID, Output: output})
+		}
+		if run, err = c.SubmitToolOutputs(ctx, threadID, run.ID, outputs); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// runPath 返回运行的接口路径
+func runPath(threadID, runID string) string {
+	return "/threads/" + url.PathEscape(threadID) + "/runs/" + url.PathEscape(runID)
+}
@@ -0,0 +1,124 @@
+package assistants
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Thread 是服务端保存的会话线程
+type Thread struct {
+	ID        string            `json:"id"`
+	Metadata  map[string]string `json:"metadata"`
+	CreatedAt int64             `json:"created_at"`
+}
+
+// Attachment 将已上传的文件附加到消息上，供指定的工具使用
+type Attachment struct {
+	FileID string `json:"file_id"`
+	// Tools 使用该文件的工具，如 {"type": "file_search"}
+	Tools []map[string]any `json:"tools"`
+}
+
+// ThreadMessage 是线程中的一条消息
+type ThreadMessage struct {
+	ID          string           `json:"id"`
+	ThreadID    string           `json:"thread_id"`
+	Role        spec.Role        `json:"role"`
+	Content     []MessageContent `json:"content"`
+	AssistantID string           `json:"assistant_id"`
+	RunID       string           `json:"run_id"`
+	Attachments []Attachment     `json:"attachments"`
+	CreatedAt   int64            `json:"created_at"`
+}
+
+// MessageContent 是消息中的一个内容块，Type 为 "text"、"image_file" 或 "image_url"
+type MessageContent struct {
+	Type string `json:"type"`
+	Text *struct {
+		Value       string           `json:"value"`
+		Annotations []map[string]any `json:"annotations"`
+	} `json:"text,omitempty"`
+	ImageFile *struct {
+		FileID string `json:"file_id"`
+	} `json:"image_file,omitempty"`
+	ImageURL *spec.ImageURL `json:"image_url,omitempty"`
+}
+
+// Text 返回消息中全部文本块拼接后的内容
+func (m *ThreadMessage) Text() string {
+	var sb strings.Builder
+	for _, c := range m.Content {
+		if c.Text != nil {
+			sb.WriteString(c.Text.Value)
+		}
+	}
+	return sb.String()
+}
+
+// messageBody 将 spec.Message 转换为创建消息的请求体，多模态内容原样使用 text / image_url 内容块
+func messageBody(msg spec.Message, attachments []Attachment) map[string]any {
+	body := map[string]any{"role": msg.Role}
+	if len(msg.Parts) > 0 {
+		body["content"] = msg.Parts
+	} else {
+		body["content"] = msg.Content
+	}
+	if len(attachments) > 0 {
+		body["attachments"] = attachments
+	}
+	return body
+}
+
+// CreateThread 创建线程，messages 为初始消息，角色只能是 user 或 assistant
+func (c *Client) CreateThread(ctx context.Context, messages ...spec.Message) (*Thread, error) {
+	initial := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		initial = append(initial, messageBody(msg, nil))
+	}
+	var t Thread
+	if err := c.post(ctx, "/threads", map[string]any{"messages": initial}, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetThread 查询线程
+func (c *Client) GetThread(ctx context.Context, id string) (*Thread, error) {
+	var t Thread
+	if err := c.get(ctx, "/threads/"+url.PathEscape(id), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteThread 删除线程
+func (c *Client) DeleteThread(ctx context.Context, id string) error {
+	return c.delete(ctx, "/threads/"+url.PathEscape(id))
+}
+
+// AddMessage 向线程追加一条消息，attachments 可附加已上传的文件
+func (c *Client) AddMessage(ctx context.Context, threadID string, msg spec.Message, attachments ...Attachment) (*ThreadMessage, error) {
+	var m ThreadMessage
+	if err := c.post(ctx, "/threads/"+url.PathEscape(threadID)+"/messages", messageBody(msg, attachments), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListMessages 按时间顺序列出线程中的全部消息
+func (c *Client) ListMessages(ctx context.Context, threadID string) ([]ThreadMessage, error) {
+	query := url.Values{"order": {"asc"}}
+	return list(ctx, c, "/threads/"+url.PathEscape(threadID)+"/messages", query, func(m ThreadMessage) string { return m.ID })
+}
+
+// Messages 将线程中的消息转换为 spec.Message，只保留文本内容，可用于导入 client 包的本地历史
+func Messages(messages []ThreadMessage) []spec.Message {
+	result := make([]spec.Message, 0, len(messages))
+	for i := range messages {
+		result = append(result, spec.Message{Role: messages[i].Role, Content: messages[i].Text()})
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+// Package chain 把提示词渲染、模型调用、解析和数据转换组合为类型安全的处理链。
+// 每一步接收上一步的输出，类型在编译期检查；用 WithTrace 可以记录每一步的输入、输出和耗时。
+//
+//	type Input struct{ Topic string }
+//
+//	c := chain.Pipe4(
+//		chain.Template[Input]("你是一名科普作者。", "用三句话介绍{{.Topic}}。"),
+//		chain.Chat(cfg),
+//		chain.Text(),
+//		chain.Map("trim", strings.TrimSpace),
+//	)
+//	ctx, trace := chain.WithTrace(ctx)
+//	text, err := c.Run(ctx, Input{Topic: "黑洞"})
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Step 是处理链中的一步，把 In 转换为 Out，可并发使用
+type Step[In, Out any] struct {
+	name string
+	fn   func(ctx context.Context, in In) (Out, error)
+	// composite 由 Pipe 组合而成的步骤只记录其中的各步，自身不记录
+	composite bool
+}
+
+// Func 用函数创建名为 name 的步骤
+func Func[In, Out any](name string, fn func(ctx context.Context, in In) (Out, error)) Step[In, Out] {
+	return Step[In, Out]{name: name, fn: fn}
+}
+
+// Map 用不会失败的转换函数创建步骤
+func Map[In, Out any](name string, fn func(in In) Out) Step[In, Out] {
+	return Func(name, func(ctx context.Context, in In) (Out, error) {
+		return fn(in), nil
+	})
+}
+
+// Name 返回步骤名称
+func (s Step[In, Out]) Name() string {
+	return s.name
+}
+
+// Run 执行步骤。ctx 中有 Trace 时记录本步的输入、输出和耗时；出错时错误中包含步骤名称
+func (s Step[In, Out]) Run(ctx context.Context, in In) (Out, error) {
+	if s.fn == nil {
+		var zero Out
+		return zero, fmt.Errorf("chain: step %q is not initialized", s.name)
+	}
+	if s.composite {
+		return s.fn(ctx, in)
+	}
+	start := time.Now()
+	out, err := s.fn(ctx, in)
+	if err != nil {
+		err = fmt.Errorf("chain: step %s: %w", s.name, err)
+	}
+	if t := traceFrom(ctx); t != nil {
+		t.record(Span{Step: s.name, Input: in, Output: out, Err: err, Start: start, Duration: time.Since(start)})
+	}
+	return out, err
+}
+
+// Pipe 组合两步，前一步的输出作为后一步的输入
+func Pipe[A, B, C any](s1 Step[A, B], s2 Step[B, C]) Step[A, C] {
+	return Step[A, C]{
+		name:      s1.name + " | " + s2.name,
+		composite: true,
+		fn: func(ctx context.Context, a A) (C, error) {
+			b, err := s1.Run(ctx, a)
+			if err != nil {
+				var zero C
+				return zero, err
+			}
+			return s2.Run(ctx, b)
+		},
+	}
+}
+
+// Pipe3 依次组合三步
+func Pipe3[A, B, C, D any](s1 Step[A, B], s2 Step[B, C], s3 Step[C, D]) Step[A, D] {
+	return Pipe(Pipe(s1, s2), s3)
+}
+
+// Pipe4 依次组合四步
+func Pipe4[A, B, C, D, E any](s1 Step[A, B], s2 Step[B, C], s3 Step[C, D], s4 Step[D, E]) Step[A, E] {
+	return Pipe(Pipe3(s1, s2, s3), s4)
+}
+
+// Pipe5 依次组合五步，更长的链可以嵌套使用 Pipe
+func Pipe5[A, B, C, D, E, F any](s1 Step[A, B], s2 Step[B, C], s3 Step[C, D], s4 Step[D, E], s5 Step[E, F]) Step[A, F] {
+	return Pipe(Pipe4(s1, s2, s3, s4), s5)
+}
@@ -0,0 +1,125 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/outputs"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Template 返回用 text/template 渲染提示词的步骤：system 和 user 是以 T 为数据的模板，
+// 渲染结果为系统消息（system 为空时省略）和用户消息。模板语法错误会导致 panic，应在初始化时创建
+func Template[T any](system, user string) Step[T, []spec.Message] {
+	var sys *template.Template
+	if system != "" {
+		sys = template.Must(template.New("system").Option("missingkey=error").Parse(system))
+	}
+	usr := template.Must(template.New("user").Option("missingkey=error").Parse(user))
+	return Func("template", func(ctx context.Context, data T) ([]spec.Message, error) {
+		var messages []spec.Message
+		if sys != nil {
+			text, err := render(sys, data)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, spec.NewSystemMessage(text))
+		}
+		text, err := render(usr, data)
+		if err != nil {
+			return nil, err
+		}
+		return append(messages, spec.NewUserMessage(text)), nil
+	})
+}
+
+// render 渲染模板
+func render(t *template.Template, data any) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Prompt 返回把字符串作为用户消息的步骤，cfg.SystemPrompt 不会被加入，需要时使用 Template
+func Prompt() Step[string, []spec.Message] {
+	return Map("prompt", func(text string) []spec.Message {
+		return []spec.Message{spec.NewUserMessage(text)}
+	})
+}
+
+// Chat 返回调用模型的步骤，cfg 的 Failover 配置同样生效，opts 附加到每次调用
+func Chat(cfg llm.Config, opts ...spec.Option) Step[[]spec.Message, *spec.Response] {
+	return Func("chat", func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+		return chat(ctx, cfg, messages, opts)
+	})
+}
+
+// chat 通过 cfg 调用模型
+func chat(ctx context.Context, cfg llm.Config, messages []spec.Message, opts []spec.Option) (*spec.Response, error) {
+	pc, release, err := llm.AcquireClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	callOpts := append(chatOptions(cfg), opts...)
+	return llm.RunFailover(ctx, cfg, pc, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+		return pc.Model(cfg.Model).Chat(ctx, messages, callOpts...)
+	})
+}
+
+// chatOptions 构建调用 cfg 对应模型的选项
+func chatOptions(cfg llm.Config) []spec.Option {
+	var opts []spec.Option
+	if cfg.Parameters != nil {
+		opts = append(opts, spec.WithParameters(cfg.Parameters))
+	}
+	if cfg.Thinking != nil {
+		opts = append(opts, spec.WithThinking(*cfg.Thinking))
+	}
+	if cfg.StreamCallback != nil {
+		opts = append(opts, spec.WithStreamCallback(cfg.StreamCallback))
+	}
+	return opts
+}
+
+// Text 返回取出回复文本的步骤
+func Text() Step[*spec.Response, string] {
+	return Func("text", func(ctx context.Context, resp *spec.Response) (string, error) {
+		if resp == nil {
+			return "", errors.New("nil response")
+		}
+		return resp.Message.PlainText(), nil
+	})
+}
+
+// Parse 返回用 p 解析文本的步骤，解析失败时不会重试，需要自动修复时使用 ChatParsed
+func Parse[T any](p outputs.Parser[T]) Step[string, T] {
+	return Func("parse", func(ctx context.Context, text string) (T, error) {
+		return p.Parse(text)
+	})
+}
+
+// ChatParsed 返回调用模型并用 p 解析回复的步骤：最后一条用户消息后会追加 p 的格式说明，
+// 解析失败时把错误反馈给模型重试，最多 retries 次（见 outputs.Run）
+func ChatParsed[T any](cfg llm.Config, p outputs.Parser[T], retries int, opts ...spec.Option) Step[[]spec.Message, T] {
+	return Func("chat_parsed", func(ctx context.Context, messages []spec.Message) (T, error) {
+		messages = spec.CloneMessages(messages)
+		if n := len(messages); n > 0 && messages[n-1].Role == spec.RoleUser {
+			messages[n-1].Content = outputs.WithInstructions(messages[n-1].Content, p)
+		} else {
+			var zero T
+			return zero, fmt.Errorf("the last message must be a user message")
+		}
+		call := func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+			return chat(ctx, cfg, messages, opts)
+		}
+		v, _, err := outputs.Run(ctx, call, messages, p, retries)
+		return v, err
+	})
+}
@@ -0,0 +1,67 @@
+package chain
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Span 记录一步的执行情况
+type Span struct {
+	// Step 步骤名称
+	Step string
+	// Input 和 Output 是该步的输入和输出
+	Input  any
+	Output any
+	Err    error
+	Start  time.Time
+	// Duration 执行耗时
+	Duration time.Duration
+}
+
+// Trace 按完成顺序收集处理链中每一步的 Span，可并发使用
+type Trace struct {
+	mu    sync.Mutex
+	spans []Span
+	hooks []func(Span)
+}
+
+type traceKey struct{}
+
+// WithTrace 返回记录执行过程的 ctx 和对应的 Trace，hooks 在每一步完成后被调用，可用于实时输出中间结果
+func WithTrace(ctx context.Context, hooks ...func(Span)) (context.Context, *Trace) {
+	t := &Trace{hooks: hooks}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// traceFrom 返回 ctx 中的 Trace，没有时返回 nil
+func traceFrom(ctx context.Context) *Trace {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+// record 记录一个 Span 并调用回调
+func (t *Trace) record(s Span) {
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	hooks := t.hooks
+	t.mu.Unlock()
+	for _, hook := range hooks {
+		hook(s)
+	}
+}
+
+// Spans 返回已记录的 Span
+func (t *Trace) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.spans)
+}
+
+// Reset 清除已记录的 Span，以便在下一次运行时复用
+func (t *Trace) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// providerEnvKeys 记录了各 Provider 默认读取 API Key 的环境变量
+var providerEnvKeys = map[string]string{
+	"dashscope":  "DASHSCOPE_API_KEY",
+	"openai":     "OPENAI_API_KEY",
+	"deepseek":   "DEEPSEEK_API_KEY",
+	"openrouter": "OPENROUTER_API_KEY",
+	"generic":    "LLM_API_KEY",
+}
+
+// Builder 以链式调用的方式构造 Client，并在 Build 时集中校验配置。
+//
+// 用法示例：
+//
+//	c, err := client.NewBuilder().
+//	    Provider("openai").
+//	    Model("gpt-4o").
+//	    APIKeyFromEnv().
+//	    SystemPrompt("你是一个乐于助人的助手").
+//	    Build()
+type Builder struct {
+	cfg    llm.Config
+	opts   []Option
+	envKey []string // 非 nil 表示需要从环境变量读取 API Key
+}
+
+// NewBuilder 创建一个新的 Builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Provider 设置厂商标识，如 "dashscope"、"openai"
+func (b *Builder) Provider(name string) *Builder {
+	b.cfg.Provider = name
+	return b
+}
+
+// Model 设置模型名称
+func (b *Builder) Model(name string) *Builder {
+	b.cfg.Model = name
+	return b
+}
+
+// APIKey 直接设置 API Key
+func (b *Builder) APIKey(key string) *Builder {
+	b.cfg.APIKey = key
+	b.envKey = nil
+	return b
+}
+
+// APIKeyFromEnv 在 Build 时从环境变量读取 API Key。
+// 不传参数时按 Provider 使用默认变量名（如 OPENAI_API_KEY、DASHSCOPE_API_KEY）；
+// 传入多个变量名时依次尝试，使用第一个非空的值。
+func (b *Builder) APIKeyFromEnv(names ...string) *Builder {
+	b.envKey = append([]string{}, names...)
+	return b
+}
+
+// APIURL 设置自定义接口地址
+func (b *Builder) APIURL(url string) *Builder {
+	b.cfg.APIURL = url
+	return b
+}
+
+// SystemPrompt 设置系统提示词
+func (b *Builder) SystemPrompt(prompt string) *Builder {
+	b.cfg.SystemPrompt = prompt
+	return b
+}
+
+// Thinking 显式开启或关闭思考模式
+func (b *Builder) Thinking(enabled bool) *Builder {
+	b.cfg.Thinking = &enabled
+	return b
+}
+
+// Parameter 附加一个透传给模型的参数
+func (b *Builder) Parameter(key string, value any) *Builder {
+	if b.cfg.Parameters == nil {
+		b.cfg.Parameters = make(map[string]any)
+	}
+	b.cfg.Parameters[key] = value
+	return b
+}
+
+// APIKeyPool 使用多个 API Key 进行负载均衡
+func (b *Builder) APIKeyPool(pool spec.APIKeyPool) *Builder {
+	b.cfg.APIKeyPool = &pool
+	return b
+}
+
+// Proxy 设置代理地址，支持 http/https/socks5
+func (b *Builder) Proxy(proxyURL string) *Builder {
+	b.cfg.Proxy = proxyURL
+	return b
+}
+
+// Timeouts 设置分阶段的超时
+func (b *Builder) Timeouts(t spec.Timeouts) *Builder {
+	b.cfg.Timeouts = &t
+	return b
+}
+
+// Interceptors 注册请求/响应拦截器，先注册的位于外层
+func (b *Builder) Interceptors(interceptors ...spec.Interceptor) *Builder {
+	b.cfg.Interceptors = append(b.cfg.Interceptors, interceptors...)
+	return b
+}
+
+// StreamCallback 设置默认的流式回调
+func (b *Builder) StreamCallback(cb spec.StreamCallback) *Builder {
+	b.cfg.StreamCallback = cb
+	return b
+}
+
+// Options 附加创建 Client 时使用的选项，如 WithHistoryStore、WithBeforeHook
+func (b *Builder) Options(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Config 校验并返回最终的 llm.Config，不创建客户端。
+// 所有校验错误会被合并返回，便于一次性修正。
+func (b *Builder) Config() (llm.Config, error) {
+	cfg := b.cfg
+	var errs []error
+
+	switch {
+	case cfg.Provider == "":
+		errs = append(errs, fmt.Errorf("provider is required, call Provider() with one of: %s", strings.Join(llm.SupportedProviders(), ", ")))
+	case !slices.Contains(llm.SupportedProviders(), cfg.Provider):
+		errs = append(errs, fmt.Errorf("unknown provider %q, supported providers: %s", cfg.Provider, strings.Join(llm.SupportedProviders(), ", ")))
+	}
+
+	if cfg.Model == "" {
+		errs = append(errs, errors.New("model is required, call Model() with a model name such as \"qwen-plus\" or \"gpt-4o\""))
+	}
+
+	var envNames []string
+	if b.envKey != nil {
+		envNames = b.envKey
+		if len(envNames) == 0 {
+			if name, ok := providerEnvKeys[cfg.Provider]; ok {
+				envNames = []string{name}
+			}
+		}
+		for _, name := range envNames {
+			if v := os.Getenv(name); v != "" {
+				cfg.APIKey = v
+				break
+			}
+		}
+	}
+	if cfg.APIKey == "" && (cfg.APIKeyPool == nil || len(cfg.APIKeyPool.Keys) == 0) {
+		if len(envNames) > 0 {
+			errs = append(errs, fmt.Errorf("API key not found, set environment variable %s", strings.Join(envNames, " or ")))
+		} else {
+			errs = append(errs, errors.New("API key is required, call APIKey() or APIKeyFromEnv()"))
+		}
+	}
+
+	if cfg.Provider == "generic" && cfg.APIURL == "" {
+		errs = append(errs, errors.New("the generic provider requires an API URL, call APIURL() with your deployment's chat/completions endpoint"))
+	}
+
+	if len(errs) > 0 {
+		return llm.Config{}, fmt.Errorf("client builder: invalid configuration: %w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// Build 校验配置并创建 Client
+func (b *Builder) Build() (*Client, error) {
+	cfg, err := b.Config()
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg, b.opts...)
+}
@@ -3,8 +3,15 @@ package client
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"text/template"
 
+	"github.com/iEvan-lhr/go-llm-client/cost"
 	"github.com/iEvan-lhr/go-llm-client/llm"
 	"github.com/iEvan-lhr/go-llm-client/spec"
 )
@@ -14,36 +21,305 @@ type Client struct {
 	config  llm.Config
 	history []spec.Message
 	client  spec.Client // 持有底层的 provider client 实例
+	// owner 在 Fork 出的客户端之间共享 client，最后一个客户端关闭时释放
+	owner       *providerRef
+	releaseOnce sync.Once
+
+	// mu 保护 history，保证每轮对话在完成时原子地写入历史
+	mu sync.Mutex
+
+	// 可选的历史持久化存储
+	store     HistoryStore
+	sessionID string
+
+	// 可选的外部记忆压缩配置
+	compaction *MemoryCompaction
+
+	// 发送前后的中间件钩子
+	beforeHooks []BeforeHook
+	afterHooks  []AfterHook
+
+	// 可选的空回复/拒答重试策略
+	refusalRetry *RefusalRetryPolicy
+
+	// 结构化输出解析失败后的重试次数，nil 表示使用 outputs.DefaultRetries
+	outputRetries *int
+
+	// 系统提示词模板及其变量
+	promptTmpl *template.Template
+	promptVars map[string]any
+
+	// 出错时 SendText 返回的兜底文本，nil 表示使用默认值
+	errorFallback *string
+
+	// 可选的语义缓存
+	semanticCache *SemanticCache
+
+	// 可选的发送前上下文长度检查
+	contextCheck *contextCheck
+
+	// 可选的回复语言要求
+	replyLanguage *ReplyLanguage
+
+	// 可选的费用统计
+	costTracker *cost.Tracker
+	costKey     string
+
+	// initErr 记录 Option 应用过程中产生的错误，由 New 统一返回
+	initErr error
+	// initCtx New 中加载和写入历史使用的上下文，nil 表示 context.Background()
+	initCtx context.Context
+
+	// 生命周期控制，Close 后取消本客户端所有进行中的请求
+	lifetime context.Context
+	shutdown context.CancelFunc
+}
+
+// Option 用于在创建 Client 时进行额外配置。
+type Option func(c *Client)
+
+// WithHistoryStore 为客户端设置历史持久化存储。
+// 创建客户端时会从 store 中加载 sessionID 对应的历史，之后每轮对话都会追加写入，
+// 从而使对话在进程重启后依然可以继续。
+func WithHistoryStore(store HistoryStore, sessionID string) Option {
+	return func(c *Client) {
+		c.store = store
+		c.sessionID = sessionID
+	}
+}
+
+// BeforeHook 在每次请求发出前执行，可以改写即将发送的消息列表（例如注入上下文、脱敏），
+// 返回错误会中止本次请求。传入的切片是副本，可以直接修改。
+type BeforeHook func(ctx context.Context, messages []spec.Message) ([]spec.Message, error)
+
+// AfterHook 在每次收到响应后执行，可以检查或修改响应（例如记录日志、过滤内容），
+// 返回错误会使本次调用失败，且本轮对话不会写入历史。
+type AfterHook func(ctx context.Context, resp *spec.Response) error
+
+// WithBeforeHook 注册一个或多个发送前钩子，按注册顺序执行。
+func WithBeforeHook(hooks ...BeforeHook) Option {
+	return func(c *Client) {
+		c.beforeHooks = append(c.beforeHooks, hooks...)
+	}
+}
+
+// WithAfterHook 注册一个或多个响应后钩子，按注册顺序执行。
+func WithAfterHook(hooks ...AfterHook) Option {
+	return func(c *Client) {
+		c.afterHooks = append(c.afterHooks, hooks...)
+	}
 }
 
 // New 创建一个新的、有状态的LLM客户端实例。
-func New(cfg llm.Config) (*Client, error) {
+func New(cfg llm.Config, opts ...Option) (*Client, error) {
 	// 使用 llm 包的工厂方法获取实例
-	providerClient, err := llm.GetClient(cfg)
+	providerClient, release, err := llm.AcquireClient(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	var history []spec.Message
-	if cfg.SystemPrompt != "" {
-		history = append(history, spec.NewSystemMessage(cfg.SystemPrompt))
+	c := &Client{
+		config: cfg,
+		client: providerClient,
+		owner:  newProviderRef(release),
+	}
+	c.lifetime, c.shutdown = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.init(); err != nil {
+		c.shutdown()
+		c.owner.release()
+		return nil, err
+	}
+	return c, nil
+}
+
+// init 完成 New 中 Option 之后的初始化：渲染系统提示词并加载历史
+func (c *Client) init() error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	if c.promptTmpl != nil {
+		rendered, err := renderPrompt(c.promptTmpl, c.promptVars)
+		if err != nil {
+			return err
+		}
+		c.config.SystemPrompt = rendered
+	}
+
+	initCtx := c.initCtx
+	if initCtx == nil {
+		initCtx = context.Background()
 	}
+	if c.store != nil {
+		history, err := c.store.Load(initCtx, c.sessionID)
+		if err != nil {
+			return fmt.Errorf("client: failed to load history for session '%s': %w", c.sessionID, err)
+		}
+		c.history = history
+	}
+
+	// 新会话（或未持久化）时注入系统提示词
+	if len(c.history) == 0 && c.config.SystemPrompt != "" {
+		sys := spec.NewSystemMessage(c.config.SystemPrompt)
+		c.history = append(c.history, sys)
+		if c.store != nil {
+			if err := c.store.Append(initCtx, c.sessionID, sys); err != nil {
+				return fmt.Errorf("client: failed to persist history: %w", err)
+			}
+		}
+	}
+	return nil
+}
 
-	return &Client{
-		config:  cfg,
-		history: history,
-		client:  providerClient,
-	}, nil
+// providerRef 对 provider client 引用计数，计数归零时调用 llm.AcquireClient 返回的 release
+type providerRef struct {
+	refs    atomic.Int32
+	release func()
+}
+
+func newProviderRef(release func()) *providerRef {
+	r := &providerRef{release: release}
+	r.refs.Store(1)
+	return r
+}
+
+func (r *providerRef) acquire() *providerRef {
+	r.refs.Add(1)
+	return r
+}
+
+func (r *providerRef) drop() {
+	if r.refs.Add(-1) == 0 {
+		r.release()
+	}
 }
 
 // invoke 调用底层的 Chat 方法，统一封装 Option 的构建逻辑
 func (c *Client) invoke(ctx context.Context, messages []spec.Message, tempConfig *llm.Config, extraOpts ...spec.Option) (*spec.Response, error) {
+	if c.lifetime.Err() != nil {
+		return nil, spec.ErrClientClosed
+	}
+	// Close 时取消本次调用（包括进行中的流式输出）
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(c.lifetime, cancel)()
+	// 本次调用的重试、故障转移共用同一个请求 ID
+	if spec.RequestIDFromContext(ctx) == "" {
+		ctx = spec.ContextWithRequestID(ctx, spec.NewRequestID())
+	}
+
 	// 使用传入的临时配置，如果没有则使用 Client 自身的配置
-	cfg := c.config
+	var cfg llm.Config
 	if tempConfig != nil {
 		cfg = *tempConfig
+	} else {
+		cfg = c.snapshotConfig()
+	}
+
+	// 执行发送前钩子，使用副本避免钩子修改调用方的切片
+	if len(c.beforeHooks) > 0 {
+		messages = append([]spec.Message(nil), messages...)
+		for _, hook := range c.beforeHooks {
+			var err error
+			if messages, err = hook(ctx, messages); err != nil {
+				return nil, err
+			}
+		}
 	}
 
+	messages, replyLang := c.languageMessages(messages)
+	if err := c.checkContext(llm.ResolveAlias(cfg).Model, messages, cfg.Parameters); err != nil {
+		return nil, err
+	}
+
+	// 命中语义缓存时不再调用模型；临时配置和额外选项（工具、单次参数等）不在缓存范围内，直接跳过
+	var resp *spec.Response
+	var store func(*spec.Response)
+	if tempConfig == nil && len(extraOpts) == 0 {
+		var err error
+		if resp, store, err = c.cachedReply(ctx, cfg, messages); err != nil {
+			return nil, err
+		}
+	}
+	if resp == nil {
+		var err error
+		// 直接使用结构体中保存的 client 实例，配置了故障转移时按需切换到备用后端
+		resp, err = llm.RunFailover(ctx, cfg, c.client, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+			return c.chat(ctx, pc, cfg, messages, extraOpts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := c.enforceLanguage(ctx, cfg, replyLang, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, hook := range c.afterHooks {
+		if err := hook(ctx, resp); err != nil {
+			return nil, err
+		}
+	}
+	// 响应后钩子可能修改或拒绝回复，全部通过后再写入缓存
+	if store != nil && !IsEmptyOrRefusal(resp) {
+		store(resp)
+	}
+	return resp, nil
+}
+
+// chat 使用指定的后端执行一次调用，并按策略处理空回复/拒答重试
+func (c *Client) chat(ctx context.Context, pc spec.Client, cfg llm.Config, messages []spec.Message, extraOpts []spec.Option) (*spec.Response, error) {
+	opts := chatOptions(cfg, extraOpts)
+	model := pc.Model(cfg.Model)
+	p := c.refusalRetry
+	if p == nil {
+		resp, err := model.Chat(ctx, messages, opts...)
+		if err != nil {
+			return nil, err
+		}
+		c.recordCost(cfg.Model, resp)
+		return resp, nil
+	}
+
+	// 开启重试时暂存流式输出，只输出最终被接受的那次尝试
+	buf := newStreamBuffer(opts)
+	attemptOpts := func(extra ...spec.Option) []spec.Option {
+		o := append(append([]spec.Option(nil), opts...), extra...)
+		if buf != nil {
+			o = append(o, buf.capture())
+		}
+		return o
+	}
+	resp, err := model.Chat(ctx, messages, attemptOpts()...)
+	if err != nil {
+		return nil, err
+	}
+	c.recordCost(cfg.Model, resp)
+
+	// 空回复或拒答时按策略重试
+	if resp.DryRun == nil {
+		for attempt := 1; attempt <= p.MaxAttempts && p.isRefusal(resp); attempt++ {
+			retryMessages, retryOpts := p.retryMessages(messages, resp, attempt)
+			next, err := model.Chat(ctx, retryMessages, attemptOpts(retryOpts...)...)
+			if err != nil {
+				return nil, err
+			}
+			c.recordCost(cfg.Model, next)
+			resp = next
+		}
+	}
+	if buf != nil {
+		if err := buf.flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// chatOptions 根据配置构建调用选项，extraOpts 在最后应用
+func chatOptions(cfg llm.Config, extraOpts []spec.Option) []spec.Option {
 	var opts []spec.Option
 	// 【新增】处理 WebExtractor：将工具组装到 Parameters 中，同时执行深拷贝避免污染全局配置
 	// 【核心修复】适配 Chat Completions API 的联网搜索参数
@@ -82,7 +358,7 @@ func (c *Client) invoke(ctx context.Context, messages []spec.Message, tempConfig
 	}
 	// 【新增】处理 Translation 配置
 	if cfg.Translation != nil {
-		opts = append(opts, spec.WithTranslation(cfg.Translation.SourceLang, cfg.Translation.TargetLang))
+		opts = append(opts, spec.WithTranslationOptions(*cfg.Translation))
 	}
 	if cfg.StreamCallback != nil {
 		opts = append(opts, spec.WithStreamCallback(cfg.StreamCallback))
@@ -90,16 +366,66 @@ func (c *Client) invoke(ctx context.Context, messages []spec.Message, tempConfig
 	if len(extraOpts) > 0 {
 		opts = append(opts, extraOpts...)
 	}
-	// 直接使用结构体中保存的 client 实例，无需再次查询缓存
-	model := c.client.Model(cfg.Model)
-	return model.Chat(ctx, messages, opts...)
+	return opts
+}
+
+// exchange 以"当前历史 + userMsg"发起调用。
+// 调用期间不修改历史；成功时将 userMsg 与回复一起原子地写入历史（配置了 HistoryStore 时同步持久化），
+// 失败时历史保持不变。
+func (c *Client) exchange(ctx context.Context, userMsg spec.Message, tempConfig *llm.Config, extraOpts ...spec.Option) (*spec.Response, error) {
+	return c.exchangeMessages(ctx, []spec.Message{userMsg}, tempConfig, extraOpts...)
+}
+
+// exchangeMessages 发送历史和新消息，成功后将新消息和回复一起写入历史
+func (c *Client) exchangeMessages(ctx context.Context, newMsgs []spec.Message, tempConfig *llm.Config, extraOpts ...spec.Option) (*spec.Response, error) {
+	c.mu.Lock()
+	messages := append(append([]spec.Message(nil), c.history...), newMsgs...)
+	c.mu.Unlock()
+
+	resp, err := c.invoke(ctx, messages, tempConfig, extraOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.DryRun != nil {
+		// dry run 没有真实回复，不写入历史
+		return resp, nil
+	}
+
+	turn := append(append([]spec.Message(nil), newMsgs...), resp.Message)
+	if err := c.appendTurn(ctx, turn); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// appendTurn 将一轮对话原子地写入历史，并追加到持久化存储
+func (c *Client) appendTurn(ctx context.Context, turn []spec.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = append(c.history, turn...)
+	if c.store != nil {
+		if err := c.store.Append(ctx, c.sessionID, turn...); err != nil {
+			return fmt.Errorf("client: failed to persist history: %w", err)
+		}
+	}
+	return nil
+}
+
+// SendToolResults 提交上一轮回复中函数调用（Response.Message.ToolCalls）的执行结果，模型据此继续回答。
+// results 使用 spec.NewToolResultMessage 创建；工具结果和回复都会写入历史，
+// 模型可能继续请求函数调用，此时 opts 需要再次传入 spec.WithTools。
+func (c *Client) SendToolResults(ctx context.Context, results []spec.Message, opts ...spec.Option) (*spec.Response, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("client: no tool results to send")
+	}
+	return c.exchangeMessages(ctx, results, nil, opts...)
 }
 
 // SendEmbedding 获取文本的向量表示。
 // 参数 input 可以是一段文本 (string)，也可以是多段文本的切片 ([]string)。
 func (c *Client) SendEmbedding(ctx context.Context, input any) (*spec.EmbeddingResponse, error) {
 	// 获取底层具体的模型实例
-	model := c.client.Model(c.config.Model)
+	model := c.client.Model(c.model())
 
 	// 使用类型断言，判断当前模型提供商是否支持向量化接口
 	if embedded, ok := model.(spec.Embedded); ok {
@@ -107,36 +433,50 @@ func (c *Client) SendEmbedding(ctx context.Context, input any) (*spec.EmbeddingR
 	}
 
 	// 如果断言失败，说明该 Provider 尚未实现 Embed 方法
-	return nil, fmt.Errorf("provider '%s' model '%s' does not support embeddings (Embedder interface not implemented)", c.config.Provider, c.config.Model)
+	return nil, fmt.Errorf("provider '%s' model '%s' does not support embeddings (Embedder interface not implemented)", c.config.Provider, c.model())
+}
+
+// Embed 使用当前配置的模型批量获取文本向量，返回的向量与 texts 一一对应。
+// 输入超过提供商单次请求的上限时自动分批。
+func (c *Client) Embed(ctx context.Context, texts []string, opts ...spec.EmbedOption) ([][]float32, error) {
+	if p, ok := c.client.(spec.EmbedderProvider); ok {
+		return p.Embedder(c.model()).Embed(ctx, texts, opts...)
+	}
+	return nil, fmt.Errorf("provider '%s' does not support embeddings (EmbedderProvider interface not implemented)", c.config.Provider)
+}
+
+// Transcribe 使用当前配置的模型进行语音转写（如 whisper-1、paraformer-v2）
+func (c *Client) Transcribe(ctx context.Context, audio spec.AudioInput, opts ...spec.TranscribeOption) (*spec.Transcription, error) {
+	if p, ok := c.client.(spec.TranscriberProvider); ok {
+		return p.Transcriber(c.model()).Transcribe(ctx, audio, opts...)
+	}
+	return nil, fmt.Errorf("provider '%s' does not support transcription (TranscriberProvider interface not implemented)", c.config.Provider)
 }
 
 // Send 向当前对话发送一条新消息，并返回完整的响应。
 // 对话历史会被自动维护。
 func (c *Client) Send(ctx context.Context, userPrompt string) (*spec.Response, error) {
-	c.history = append(c.history, spec.NewUserMessage(userPrompt))
+	return c.exchange(ctx, spec.NewUserMessage(userPrompt), nil)
+}
 
-	resp, err := c.invoke(ctx, c.history, nil)
-	if err != nil {
-		c.history = c.history[:len(c.history)-1]
-		return nil, err
-	}
+// SendWithOptions 与 Send 相同，但允许为本次调用附加 spec.Option。
+// 附加的选项在客户端配置之后应用，可临时覆盖温度、最大 token 数、思考模式等，
+// 不会修改客户端共享的配置。
+func (c *Client) SendWithOptions(ctx context.Context, userPrompt string, opts ...spec.Option) (*spec.Response, error) {
+	return c.exchange(ctx, spec.NewUserMessage(userPrompt), nil, opts...)
+}
 
-	c.history = append(c.history, resp.Message)
-	return resp, nil
+// SendStreamWithOptions 是 SendWithOptions 的流式版本。
+func (c *Client) SendStreamWithOptions(ctx context.Context, userPrompt string, callback spec.StreamCallback, opts ...spec.Option) (*spec.Response, error) {
+	tempConfig := c.snapshotConfig()
+	tempConfig.StreamCallback = callback
+
+	return c.exchange(ctx, spec.NewUserMessage(userPrompt), &tempConfig, opts...)
 }
 
 // SendParts 发送多模态消息，并写入历史
 func (c *Client) SendParts(ctx context.Context, parts ...spec.ContentPart) (*spec.Response, error) {
-	c.history = append(c.history, spec.NewUserPartsMessage(parts...))
-
-	resp, err := c.invoke(ctx, c.history, nil)
-	if err != nil {
-		c.history = c.history[:len(c.history)-1]
-		return nil, err
-	}
-
-	c.history = append(c.history, resp.Message)
-	return resp, nil
+	return c.exchange(ctx, spec.NewUserPartsMessage(parts...), nil)
 }
 
 // ============== 修改：SendText2Image 方法 ==============
@@ -150,8 +490,6 @@ func (c *Client) SendParts(ctx context.Context, parts ...spec.ContentPart) (*spe
 //	    WithText2ImageWatermark(false),
 //	    WithText2ImageNegativePrompt("低分辨率，模糊"))
 func (c *Client) SendText2Image(ctx context.Context, userPrompt string, opts ...spec.Text2ImageOption) (*spec.Response, error) {
-	c.history = append(c.history, spec.NewUserMessage(userPrompt))
-
 	// 应用文生图配置选项
 	tiConfig := applyText2ImageOptions(opts...)
 
@@ -176,14 +514,7 @@ func (c *Client) SendText2Image(ctx context.Context, userPrompt string, opts ...
 		Parameters: parameters,
 	}
 
-	resp, err := c.invoke(ctx, c.history, tempConfig, spec.WithText2Image())
-	if err != nil {
-		c.history = c.history[:len(c.history)-1]
-		return nil, err
-	}
-
-	c.history = append(c.history, resp.Message)
-	return resp, nil
+	return c.exchange(ctx, spec.NewUserMessage(userPrompt), tempConfig, spec.WithText2Image())
 }
 
 // applyText2ImageOptions 应用文生图选项到配置
@@ -200,6 +531,40 @@ func applyText2ImageOptions(opts ...spec.Text2ImageOption) *spec.Text2ImageConfi
 	return cfg
 }
 
+// SendImageEdit 发送图像编辑请求，images 为待编辑的图片（URL 或 data URL，可用 spec.NewImageBytesPart 构造），
+// prompt 为编辑指令。图像编辑不会写入对话历史。
+// 用法示例：
+//
+//	resp, err := client.SendImageEdit(ctx, "把背景换成海边", []string{imageURL},
+//	    spec.WithImageEditMask(maskURL),
+//	    spec.WithImageEditCount(2))
+func (c *Client) SendImageEdit(ctx context.Context, prompt string, images []string, opts ...spec.ImageEditOption) (*spec.Response, error) {
+	parts := make([]spec.ContentPart, 0, len(images)+1)
+	for _, img := range images {
+		parts = append(parts, spec.NewImageURLPart(img))
+	}
+	parts = append(parts, spec.NewTextPart(prompt))
+	return c.sendImage(ctx, spec.NewUserPartsMessage(parts...), spec.WithImageEdit(), opts)
+}
+
+// SendImageVariation 基于一张图片（URL 或 data URL）生成相似的新图片，不会写入对话历史
+func (c *Client) SendImageVariation(ctx context.Context, image string, opts ...spec.ImageEditOption) (*spec.Response, error) {
+	return c.sendImage(ctx, spec.NewUserPartsMessage(spec.NewImageURLPart(image)), spec.WithImageVariation(), opts)
+}
+
+// sendImage 应用图像编辑选项并发起调用
+func (c *Client) sendImage(ctx context.Context, msg spec.Message, mode spec.Option, opts []spec.ImageEditOption) (*spec.Response, error) {
+	ieConfig := &spec.ImageEditConfig{ImageCount: 1}
+	for _, opt := range opts {
+		opt(ieConfig)
+	}
+	tempConfig := &llm.Config{
+		Model:      c.config.Model,
+		Parameters: ieConfig.Parameters(),
+	}
+	return c.invoke(ctx, []spec.Message{msg}, tempConfig, mode)
+}
+
 // ptrBool 辅助函数：返回 bool 指针
 func ptrBool(b bool) *bool {
 	return &b
@@ -208,36 +573,81 @@ func ptrBool(b bool) *bool {
 // SendStream 是支持流式输出的 Send 方法。
 // 它接收一个 callback 函数，实时处理返回的文本片段。
 func (c *Client) SendStream(ctx context.Context, userPrompt string, callback spec.StreamCallback) (*spec.Response, error) {
-	c.history = append(c.history, spec.NewUserMessage(userPrompt))
-
 	// 创建临时配置以携带回调函数
-	tempConfig := c.config
+	tempConfig := c.snapshotConfig()
 	tempConfig.StreamCallback = callback
 
-	resp, err := c.invoke(ctx, c.history, &tempConfig)
+	return c.exchange(ctx, spec.NewUserMessage(userPrompt), &tempConfig)
+}
+
+// SendStreamWriter 是 SendStream 的 StreamWriter 版本。
+// 每个数据块写入后都会 Flush，流结束时调用一次 w.Close，成功时传入完整响应，失败时传入 nil。
+// 对话历史的维护方式与 SendStream 一致。
+func (c *Client) SendStreamWriter(ctx context.Context, userPrompt string, w spec.StreamWriter) (*spec.Response, error) {
+	resp, err := c.SendStream(ctx, userPrompt, spec.StreamWriterCallback(w))
 	if err != nil {
-		c.history = c.history[:len(c.history)-1]
+		w.Close(nil)
 		return nil, err
 	}
-
-	c.history = append(c.history, resp.Message)
+	if err := w.Close(resp); err != nil {
+		return resp, err
+	}
 	return resp, nil
 }
 
-func (c *Client) SendStreamParts(ctx context.Context, parts []spec.ContentPart, callback spec.StreamCallback) (*spec.Response, error) {
-	c.history = append(c.history, spec.NewUserPartsMessage(parts...))
+// SendStreamChan 是 SendStream 的 channel 版本，适用于 goroutine 较多的 Web 处理逻辑。
+// 增量文本从第一个 channel 依次读出，流结束后该 channel 会被关闭；
+// 随后从第二个 channel 读取最终错误（成功时 channel 直接关闭，读到 nil）。
+// 调用方必须持续读取增量 channel 直到其关闭，或者取消 ctx 来提前结束。
+// 流成功结束后，本轮对话会写入历史。
+//
+// 用法示例：
+//
+//	deltas, errs := c.SendStreamChan(ctx, "你好")
+//	for d := range deltas {
+//	    fmt.Print(d)
+//	}
+//	if err := <-errs; err != nil { ... }
+func (c *Client) SendStreamChan(ctx context.Context, userPrompt string) (<-chan string, <-chan error) {
+	deltas := make(chan string)
+	errs := make(chan error, 1)
 
-	tempConfig := c.config
-	tempConfig.StreamCallback = callback
+	go func() {
+		defer close(errs)
+		defer close(deltas)
 
-	resp, err := c.invoke(ctx, c.history, &tempConfig)
-	if err != nil {
-		c.history = c.history[:len(c.history)-1]
-		return nil, err
-	}
+		streamed := false
+		resp, err := c.SendStream(ctx, userPrompt, func(ctx context.Context, chunk string) error {
+			streamed = true
+			select {
+			case deltas <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+			return
+		}
+		// 不支持流式的 Provider 不会触发回调，与 spec.ChatSeq 一致地把完整内容作为一个增量发送
+		if !streamed && resp.Message.Content != "" {
+			select {
+			case deltas <- resp.Message.Content:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+			}
+		}
+	}()
 
-	c.history = append(c.history, resp.Message)
-	return resp, nil
+	return deltas, errs
+}
+
+func (c *Client) SendStreamParts(ctx context.Context, parts []spec.ContentPart, callback spec.StreamCallback) (*spec.Response, error) {
+	tempConfig := c.snapshotConfig()
+	tempConfig.StreamCallback = callback
+
+	return c.exchange(ctx, spec.NewUserPartsMessage(parts...), &tempConfig)
 }
 
 func (c *Client) SendImageURL(ctx context.Context, imageURL, question string) (*spec.Response, error) {
@@ -254,10 +664,22 @@ func (c *Client) SendImageBase64(ctx context.Context, mimeType, base64Data, ques
 	)
 }
 
+// SendImageFile 读取本地图片并连同问题一起发送，MIME 类型由文件内容推断
+func (c *Client) SendImageFile(ctx context.Context, path, question string) (*spec.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return c.SendParts(ctx,
+		spec.NewImageBytesPart(http.DetectContentType(data), data),
+		spec.NewTextPart(question),
+	)
+}
+
 func (c *Client) SendPartsNoHistory(ctx context.Context, parts ...spec.ContentPart) (*spec.Response, error) {
 	var messages []spec.Message
-	if c.config.SystemPrompt != "" {
-		messages = append(messages, spec.NewSystemMessage(c.config.SystemPrompt))
+	if prompt := c.snapshotConfig().SystemPrompt; prompt != "" {
+		messages = append(messages, spec.NewSystemMessage(prompt))
 	}
 	messages = append(messages, spec.NewUserPartsMessage(parts...))
 	return c.invoke(ctx, messages, nil)
@@ -272,18 +694,18 @@ func (c *Client) SendStreamNoHistory(ctx context.Context, userPrompt string, cal
 	// 1. 重新构建消息列表，只包含 System Prompt (如果有) 和当前 User Prompt
 	var messages []spec.Message
 
+	// 2. 创建临时配置以携带回调函数
+	tempConfig := c.snapshotConfig()
+	tempConfig.StreamCallback = callback
+
 	// 如果配置了系统提示词，需要加上，保证人设一致
-	if c.config.SystemPrompt != "" {
-		messages = append(messages, spec.NewSystemMessage(c.config.SystemPrompt))
+	if tempConfig.SystemPrompt != "" {
+		messages = append(messages, spec.NewSystemMessage(tempConfig.SystemPrompt))
 	}
 
 	// 添加当前用户消息
 	messages = append(messages, spec.NewUserMessage(userPrompt))
 
-	// 2. 创建临时配置以携带回调函数
-	tempConfig := c.config
-	tempConfig.StreamCallback = callback
-
 	// 3. 调用 invoke
 	// invoke 内部只会使用传入的 messages，不会读取 c.history
 	return c.invoke(ctx, messages, &tempConfig)
@@ -291,36 +713,328 @@ func (c *Client) SendStreamNoHistory(ctx context.Context, userPrompt string, cal
 
 // SendNoHistory 发送消息但不记录到历史（单次问答），但会携带之前的历史上下文
 func (c *Client) SendNoHistory(ctx context.Context, userPrompt string) (*spec.Response, error) {
-	var messages []spec.Message
 	// 复制现有历史，避免修改底层切片
-	if len(c.history) > 0 {
-		messages = make([]spec.Message, len(c.history))
-		copy(messages, c.history)
-	}
+	messages := c.GetHistory()
 	messages = append(messages, spec.NewUserMessage(userPrompt))
 
 	return c.invoke(ctx, messages, nil)
 }
 
+// defaultErrorFallback 是 SendText 出错时返回的默认文本
+const defaultErrorFallback = "对话错误，请联系管理员"
+
+// WithErrorFallback 设置 SendText / SendTextNoHistory 出错时返回的文本，
+// 便于非中文应用自定义提示语。
+func WithErrorFallback(text string) Option {
+	return func(c *Client) {
+		c.errorFallback = &text
+	}
+}
+
+// fallbackText 返回出错时的兜底文本
+func (c *Client) fallbackText() string {
+	if c.errorFallback != nil {
+		return *c.errorFallback
+	}
+	return defaultErrorFallback
+}
+
+// model 返回解析了别名（见 llm.RegisterAlias）的模型名
+func (c *Client) model() string {
+	return llm.ResolveAlias(c.snapshotConfig()).Model
+}
+
+// snapshotConfig 在 c.mu 的保护下返回配置的副本。SetSystemPrompt、SetVars 会在运行时修改配置，
+// 读取配置的地方都需要通过它获取，调用方不能持有 c.mu
+func (c *Client) snapshotConfig() llm.Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config
+}
+
+// logger 返回客户端使用的日志记录器，会自动遮盖已配置的 API Key
+func (c *Client) logger() spec.Logger {
+	secrets := []string{c.config.APIKey}
+	if c.config.APIKeyPool != nil {
+		secrets = append(secrets, c.config.APIKeyPool.Keys...)
+	}
+	return spec.NewRedactingLogger(c.config.Logger, secrets...)
+}
+
 // SendText 是Send方法的简化版，只返回回复的文本内容。
+// 出错时记录日志并返回兜底文本（可通过 WithErrorFallback 配置），需要处理错误时请使用 SendTextCtx。
 func (c *Client) SendText(userPrompt string) string {
-	resp, err := c.Send(context.Background(), userPrompt)
+	text, err := c.SendTextCtx(context.Background(), userPrompt)
+	if err != nil {
+		c.logger().Error("llm send failed", "error", err)
+		return c.fallbackText()
+	}
+	return text
+}
+
+// SendTextCtx 与 Send 相同，但只返回回复的文本内容，并把错误交给调用方处理。
+func (c *Client) SendTextCtx(ctx context.Context, userPrompt string) (string, error) {
+	resp, err := c.Send(ctx, userPrompt)
+	if err != nil {
+		return "", err
+	}
+	return resp.Message.Content, nil
+}
+
+// SendTextNoHistory 是 SendNoHistory 的简化版，只返回回复的文本内容，出错时返回兜底文本。
+func (c *Client) SendTextNoHistory(userPrompt string) string {
+	text, err := c.SendTextNoHistoryCtx(context.Background(), userPrompt)
 	if err != nil {
-		log.Println("LLM Error:", err.Error())
-		return "对话错误，请联系管理员"
+		c.logger().Error("llm send failed", "error", err)
+		return c.fallbackText()
 	}
-	return resp.Message.Content
+	return text
+}
+
+// SendTextNoHistoryCtx 与 SendNoHistory 相同，但只返回回复的文本内容。
+func (c *Client) SendTextNoHistoryCtx(ctx context.Context, userPrompt string) (string, error) {
+	resp, err := c.SendNoHistory(ctx, userPrompt)
+	if err != nil {
+		return "", err
+	}
+	return resp.Message.Content, nil
 }
 
 // ResetHistory 清空当前客户端的对话历史，并重新设置系统提示词。
+// 如果配置了 HistoryStore，持久化的历史也会被清空，持久化失败时会记录日志。
 func (c *Client) ResetHistory() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.history = c.history[:0]
 	if c.config.SystemPrompt != "" {
 		c.history = append(c.history, spec.NewSystemMessage(c.config.SystemPrompt))
 	}
+	if c.store != nil {
+		if err := c.resetStore(context.Background()); err != nil {
+			c.logger().Error("llm failed to reset history store", "error", err)
+		}
+	}
 }
 
-// GetHistory 返回当前对话的完整历史记录。
+// resetStore 将持久化存储中的历史重置为当前内存中的历史，调用方需持有 c.mu。
+// 存储实现了 HistoryReplacer 时一次性替换，否则依次调用 Reset 和 Append
+func (c *Client) resetStore(ctx context.Context) error {
+	if r, ok := c.store.(HistoryReplacer); ok {
+		return r.Replace(ctx, c.sessionID, c.history)
+	}
+	if err := c.store.Reset(ctx, c.sessionID); err != nil {
+		return err
+	}
+	if len(c.history) == 0 {
+		return nil
+	}
+	return c.store.Append(ctx, c.sessionID, c.history...)
+}
+
+// GetHistory 返回当前对话的完整历史记录（副本）。
 func (c *Client) GetHistory() []spec.Message {
-	return c.history
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.history) == 0 {
+		return nil
+	}
+	messages := make([]spec.Message, len(c.history))
+	copy(messages, c.history)
+	return messages
+}
+
+// Fork 返回一个拥有独立历史副本的新客户端，用于探索"如果换个问法会怎样"的分支对话。
+// 历史会被深拷贝，两个客户端后续的对话互不影响；底层的 provider client 实例会被共享。
+// 新客户端不会继承 HistoryStore，以免两个分支写入同一个会话。
+func (c *Client) Fork() *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	forked := &Client{
+		config:  c.config,
+		history: spec.CloneMessages(c.history),
+		client:  c.client,
+		owner:   c.owner.acquire(),
+
+		compaction:  c.compaction,
+		beforeHooks: c.beforeHooks,
+		afterHooks:  c.afterHooks,
+
+		refusalRetry:  c.refusalRetry,
+		outputRetries: c.outputRetries,
+		promptTmpl:    c.promptTmpl,
+		promptVars:    maps.Clone(c.promptVars),
+
+		errorFallback: c.errorFallback,
+
+		costTracker: c.costTracker,
+		costKey:     c.costKey,
+
+		semanticCache: c.semanticCache,
+		contextCheck:  c.contextCheck,
+		replyLanguage: c.replyLanguage,
+	}
+	forked.lifetime, forked.shutdown = context.WithCancel(context.Background())
+	if c.config.Parameters != nil {
+		forked.config.Parameters = make(map[string]any, len(c.config.Parameters))
+		for k, v := range c.config.Parameters {
+			forked.config.Parameters[k] = v
+		}
+	}
+	return forked
+}
+
+// RemoveMessage 删除历史中下标为 i 的消息。
+// 配置了 HistoryStore 时，持久化的历史会同步重写。
+func (c *Client) RemoveMessage(i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkIndex(i); err != nil {
+		return err
+	}
+	c.history = append(c.history[:i], c.history[i+1:]...)
+	return c.syncStore()
+}
+
+// ReplaceMessage 将历史中下标为 i 的消息替换为 msg，常用于内容审核后的改写。
+func (c *Client) ReplaceMessage(i int, msg spec.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkIndex(i); err != nil {
+		return err
+	}
+	c.history[i] = msg
+	return c.syncStore()
+}
+
+// TruncateAfter 只保留下标 0..i 的消息，丢弃其后的全部历史。
+// 典型用法是"重新生成回答"：丢弃最后一轮助手回复后重新发送。
+func (c *Client) TruncateAfter(i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkIndex(i); err != nil {
+		return err
+	}
+	c.history = c.history[:i+1]
+	return c.syncStore()
+}
+
+// checkIndex 校验历史下标是否越界
+func (c *Client) checkIndex(i int) error {
+	if i < 0 || i >= len(c.history) {
+		return fmt.Errorf("client: history index %d out of range [0, %d)", i, len(c.history))
+	}
+	return nil
+}
+
+// syncStore 在历史被修改后重写持久化存储，调用方需持有 c.mu
+func (c *Client) syncStore() error {
+	if c.store == nil {
+		return nil
+	}
+	if err := c.resetStore(context.Background()); err != nil {
+		return fmt.Errorf("client: failed to persist history: %w", err)
+	}
+	return nil
+}
+
+// SetSystemPrompt 热切换系统提示词，保留其余对话历史。
+// 如果历史的第一条是系统消息则直接改写，否则在最前面插入一条；
+// 传入空字符串会移除开头的系统消息。
+func (c *Client) SetSystemPrompt(prompt string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.setSystemPrompt(prompt)
+}
+
+// setSystemPrompt 更新系统提示词并同步历史，调用方需持有 c.mu
+func (c *Client) setSystemPrompt(prompt string) error {
+	c.config.SystemPrompt = prompt
+
+	hasSystem := len(c.history) > 0 && c.history[0].Role == spec.RoleSystem
+	switch {
+	case prompt == "" && hasSystem:
+		c.history = c.history[1:]
+	case prompt == "":
+		return nil
+	case hasSystem:
+		c.history[0] = spec.NewSystemMessage(prompt)
+	default:
+		c.history = append([]spec.Message{spec.NewSystemMessage(prompt)}, c.history...)
+	}
+	return c.syncStore()
+}
+
+// Future 表示一次尚未完成的异步调用结果。
+type Future struct {
+	done chan struct{}
+	resp *spec.Response
+	err  error
+}
+
+// Wait 阻塞直到调用完成，返回响应与错误。可以被多次调用。
+func (f *Future) Wait() (*spec.Response, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// Done 返回一个在调用完成时关闭的 channel，便于配合 select 使用。
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// SendAsync 异步发送消息并立即返回 Future。
+// 请求基于调用时刻的历史发起，只有在调用成功完成时，本轮的用户消息和回复才会原子地写入历史。
+func (c *Client) SendAsync(ctx context.Context, userPrompt string) *Future {
+	f := &Future{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		f.resp, f.err = c.Send(ctx, userPrompt)
+	}()
+	return f
+}
+
+// Token 是对话历史的检查点，由 Checkpoint 创建，传给 Rollback 以恢复历史。
+type Token struct {
+	history []spec.Message
+}
+
+// Checkpoint 记录当前对话历史，返回可用于 Rollback 的检查点。
+// 适用于 Agent 探索某条路径失败后回退到已知的正确状态。
+func (c *Client) Checkpoint() Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Token{history: append([]spec.Message(nil), c.history...)}
+}
+
+// Rollback 将对话历史恢复到 t 记录的状态，配置了 HistoryStore 时同步重写持久化历史。
+// 同一个检查点可以多次回滚。
+func (c *Client) Rollback(t Token) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = append(c.history[:0:0], t.history...)
+	return c.syncStore()
+}
+
+// Close 关闭客户端：取消本客户端所有进行中的请求（包括流式请求），
+// 如果 HistoryStore 实现了 io.Closer 则将其关闭以刷新数据，并释放空闲的 HTTP 连接。
+// 未被缓存的 provider client（配置了 Interceptors 等选项且未设置 ClientID 时）在它和所有 Fork 出的客户端都关闭后释放；
+// 缓存中的 provider client 可能被其他 Client 共享，因此不会被关闭，进程退出时可调用 llm.CloseAll 关闭。
+// 关闭后再调用 Send 等方法会返回 spec.ErrClientClosed。
+func (c *Client) Close() error {
+	return c.close(true)
+}
+
+// close 关闭客户端，closeStore 为 false 时不关闭 HistoryStore，用于 SessionManager 等多个 Client 共享存储的场景
+func (c *Client) close(closeStore bool) error {
+	c.shutdown()
+
+	var err error
+	if closer, ok := c.store.(io.Closer); ok && closeStore {
+		err = closer.Close()
+	}
+	if idle, ok := c.client.(interface{ CloseIdleConnections() }); ok {
+		idle.CloseIdleConnections()
+	}
+	c.releaseOnce.Do(c.owner.drop)
+	return err
 }
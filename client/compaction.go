@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// CompactionStrategy 决定记忆超出预算时如何压缩。
+type CompactionStrategy int
+
+const (
+	// CompactDrop 直接丢弃最早的对话轮次
+	CompactDrop CompactionStrategy = iota
+	// CompactSummarize 调用模型将较早的对话总结为一条摘要消息，失败时退化为丢弃
+	CompactSummarize
+)
+
+// defaultSummaryPrompt 是生成对话摘要时使用的默认系统提示词
+const defaultSummaryPrompt = "请将以下对话内容总结为一段简洁的摘要，保留关键事实、用户偏好和尚未解决的问题，不要添加对话中没有的信息。"
+
+// MemoryCompaction 配置外部记忆（SendByMemory / SendWithMemory）的自动压缩。
+type MemoryCompaction struct {
+	// MaxTokens 记忆（含本次用户消息）的 token 预算，<= 0 表示不压缩
+	MaxTokens int
+	// Strategy 压缩策略，默认 CompactDrop
+	Strategy CompactionStrategy
+	// KeepRecent 无论如何都保留的最近消息条数
+	KeepRecent int
+	// SummaryPrompt 自定义摘要提示词，仅在 CompactSummarize 下生效
+	SummaryPrompt string
+	// CountTokens 自定义 token 计数函数，为 nil 时使用 tokens.CountMessages（未注册词表时为字符数估算）
+	CountTokens func(messages []spec.Message) int
+}
+
+// WithMemoryCompaction 为客户端开启外部记忆压缩。
+// 每次 SendByMemory / SendWithMemory 调用前，如果记忆超出预算，会先压缩再请求，
+// 返回的记忆即为压缩后的结果，避免 Redis 中的会话无限膨胀直至超出上下文长度。
+func WithMemoryCompaction(cfg MemoryCompaction) Option {
+	return func(c *Client) {
+		c.compaction = &cfg
+	}
+}
+
+// compactMessages 按配置压缩消息列表，开头的系统消息始终保留
+func (c *Client) compactMessages(ctx context.Context, messages []spec.Message) []spec.Message {
+	cfg := c.compaction
+	if cfg == nil || cfg.MaxTokens <= 0 {
+		return messages
+	}
+	count := cfg.CountTokens
+	if count == nil {
+		model := c.model()
+		count = func(messages []spec.Message) int {
+			return tokens.CountMessages(model, messages)
+		}
+	}
+	if count(messages) <= cfg.MaxTokens {
+		return messages
+	}
+
+	// 拆分出开头的系统消息
+	head := 0
+	for head < len(messages) && messages[head].Role == spec.RoleSystem {
+		head++
+	}
+	system := messages[:head]
+	rest := messages[head:]
+
+	keep := cfg.KeepRecent
+	if keep < 1 {
+		keep = 1 // 至少保留本次的用户消息
+	}
+	if keep >= len(rest) {
+		return messages
+	}
+
+	if cfg.Strategy == CompactSummarize {
+		old, recent := rest[:len(rest)-keep], rest[len(rest)-keep:]
+		if summary, err := c.summarize(ctx, old, cfg.SummaryPrompt); err == nil {
+			compacted := append(append([]spec.Message(nil), system...), spec.NewSystemMessage("此前对话的摘要："+summary))
+			compacted = append(compacted, recent...)
+			if count(compacted) <= cfg.MaxTokens {
+				return compacted
+			}
+			system, rest = compacted[:len(system)+1], recent
+		}
+	}
+
+	// 丢弃策略：从最早的消息开始丢弃，直到满足预算或只剩 keep 条
+	for len(rest) > keep {
+		rest = rest[1:]
+		// 不以助手消息开头，保证对话轮次完整
+		for len(rest) > keep && rest[0].Role != spec.RoleUser {
+			rest = rest[1:]
+		}
+		candidate := append(append([]spec.Message(nil), system...), rest...)
+		if count(candidate) <= cfg.MaxTokens {
+			return candidate
+		}
+	}
+	return append(append([]spec.Message(nil), system...), rest...)
+}
+
+// summarize 调用模型将一段对话总结为摘要
+func (c *Client) summarize(ctx context.Context, messages []spec.Message, prompt string) (string, error) {
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+	var sb strings.Builder
+	for _, m := range messages {
+		fmt.Fprintf(&sb, "%s: %s\n", m.Role, m.PlainText())
+	}
+
+	tempConfig := c.snapshotConfig()
+	tempConfig.StreamCallback = nil
+	resp, err := c.invoke(ctx, []spec.Message{
+		spec.NewSystemMessage(prompt),
+		spec.NewUserMessage(sb.String()),
+	}, &tempConfig)
+	if err != nil {
+		return "", err
+	}
+	if resp.Message.Content == "" {
+		return "", fmt.Errorf("client: empty summary")
+	}
+	return resp.Message.Content, nil
+}
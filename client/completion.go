@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Complete 使用当前配置的模型进行文本补全（/v1/completions），适用于只提供补全端点的后端和基座模型。
+// 客户端配置中的 Parameters、流式回调等同样生效；补全不读写对话历史。
+func (c *Client) Complete(ctx context.Context, prompt string, opts ...spec.Option) (*spec.Response, error) {
+	if c.lifetime.Err() != nil {
+		return nil, spec.ErrClientClosed
+	}
+	p, ok := c.client.(spec.CompleterProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' does not support text completion (CompleterProvider interface not implemented)", c.config.Provider)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(c.lifetime, cancel)()
+
+	resp, err := p.Completer(c.model()).Complete(ctx, prompt, chatOptions(c.snapshotConfig(), opts)...)
+	if err != nil {
+		return nil, err
+	}
+	c.recordCost(c.model(), resp)
+	return resp, nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// contextCheck 发送前的上下文长度检查配置
+type contextCheck struct {
+	reserveOutput int
+}
+
+// WithContextCheck 开启发送前的上下文长度检查：输入的 token 数加上 reserveOutput 超出模型上下文长度时，
+// 直接返回满足 errors.Is(err, spec.ErrContextLengthExceeded) 的错误，不再请求模型。
+// reserveOutput <= 0 时使用配置中的 max_tokens 参数（如有）。
+// token 数由 tokens 包计算，未注册词表时为估算值；未知上下文长度的模型不做检查。
+func WithContextCheck(reserveOutput int) Option {
+	return func(c *Client) {
+		c.contextCheck = &contextCheck{reserveOutput: reserveOutput}
+	}
+}
+
+// checkContext 按配置检查消息是否超出上下文长度
+func (c *Client) checkContext(model string, messages []spec.Message, parameters map[string]any) error {
+	if c.contextCheck == nil {
+		return nil
+	}
+	reserve := c.contextCheck.reserveOutput
+	if reserve <= 0 {
+		if n, ok := parameters["max_tokens"].(int); ok {
+			reserve = n
+		}
+	}
+	return tokens.CheckContext(model, messages, reserve)
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"github.com/iEvan-lhr/go-llm-client/cost"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// WithCostTracker 将本客户端每次调用的用量和费用记录到 tracker 的 key 下。
+// 多个客户端可以共享同一个 tracker，用不同的 key 区分客户端或会话；
+// 空回复重试、故障转移等产生的每一次实际调用都会被计入。
+func WithCostTracker(tracker *cost.Tracker, key string) Option {
+	return func(c *Client) {
+		c.costTracker = tracker
+		c.costKey = key
+	}
+}
+
+// Cost 返回本客户端累计的用量和费用，未配置 WithCostTracker 时返回零值
+func (c *Client) Cost() cost.Summary {
+	if c.costTracker == nil {
+		return cost.Summary{Cost: map[cost.Currency]float64{}}
+	}
+	return c.costTracker.Summary(c.costKey)
+}
+
+// recordCost 记录一次实际调用的费用
+func (c *Client) recordCost(model string, resp *spec.Response) {
+	if c.costTracker != nil {
+		c.costTracker.Record(c.costKey, model, resp.Usage)
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+)
+
+// WithFallback 为客户端配置备用后端。主后端返回限流、5xx、网络错误或超时时，
+// 按顺序改用 cfgs 中的配置重试本次调用，实际应答的后端记录在 Response.Provider / Response.Model 中。
+// 需要单独设置每个后端的超时或自定义切换条件时，可直接设置 llm.Config.Failover。
+//
+// 注意：流式调用在主后端已输出部分内容后失败时，切换后的内容会再次经过 StreamCallback。
+func WithFallback(cfgs ...llm.Config) Option {
+	return func(c *Client) {
+		for _, cfg := range cfgs {
+			_, release, err := llm.AcquireClient(cfg)
+			if err != nil {
+				c.initErr = fmt.Errorf("client: invalid fallback %s/%s: %w", cfg.Provider, cfg.Model, err)
+				return
+			}
+			release()
+		}
+		var fc llm.FailoverConfig
+		if c.config.Failover != nil {
+			fc = *c.config.Failover
+		}
+		fc.Fallbacks = append(append([]llm.Config(nil), fc.Fallbacks...), cfgs...)
+		c.config.Failover = &fc
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Files 返回提供商的文件接口，提供商不支持时返回错误
+func (c *Client) Files() (spec.FileStore, error) {
+	if p, ok := c.client.(spec.FileStoreProvider); ok {
+		return p.Files(), nil
+	}
+	return nil, fmt.Errorf("provider '%s' does not support files (FileStoreProvider interface not implemented)", c.config.Provider)
+}
+
+// UploadFile 上传文件，purpose 为文件用途，如 spec.FilePurposeBatch、spec.FilePurposeFileExtract
+func (c *Client) UploadFile(ctx context.Context, filename string, data []byte, purpose string) (*spec.File, error) {
+	files, err := c.Files()
+	if err != nil {
+		return nil, err
+	}
+	return files.Upload(ctx, filename, data, purpose)
+}
+
+// UploadFilePath 读取本地文件并上传，使用文件的基本名作为上传文件名
+func (c *Client) UploadFilePath(ctx context.Context, path string, purpose string) (*spec.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return c.UploadFile(ctx, filepath.Base(path), data, purpose)
+}
+
+// ListFiles 列出已上传的文件，purpose 为空时返回全部文件
+func (c *Client) ListFiles(ctx context.Context, purpose string) ([]spec.File, error) {
+	files, err := c.Files()
+	if err != nil {
+		return nil, err
+	}
+	return files.List(ctx, purpose)
+}
+
+// DeleteFile 删除已上传的文件
+func (c *Client) DeleteFile(ctx context.Context, id string) error {
+	files, err := c.Files()
+	if err != nil {
+		return err
+	}
+	return files.Delete(ctx, id)
+}
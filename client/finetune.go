@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultFineTunePollInterval 是等待微调任务时的默认轮询间隔
+const defaultFineTunePollInterval = 30 * time.Second
+
+// FineTuner 返回提供商的微调接口，提供商不支持时返回错误
+func (c *Client) FineTuner() (spec.FineTuner, error) {
+	if p, ok := c.client.(spec.FineTunerProvider); ok {
+		return p.FineTuner(), nil
+	}
+	return nil, fmt.Errorf("provider '%s' does not support fine-tuning (FineTunerProvider interface not implemented)", c.config.Provider)
+}
+
+// WaitFineTuneJob 按 interval 轮询任务直到结束并返回最终状态，interval <= 0 时每 30 秒查询一次。
+// onUpdate 不为 nil 时每次查询后回调，可用于打印进度。任务失败或被取消时不返回错误，由调用方检查 Status。
+func (c *Client) WaitFineTuneJob(ctx context.Context, id string, interval time.Duration, onUpdate func(*spec.FineTuneJob)) (*spec.FineTuneJob, error) {
+	tuner, err := c.FineTuner()
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = defaultFineTunePollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := tuner.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if onUpdate != nil {
+			onUpdate(job)
+		}
+		if job.Status.Done() {
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// FineTunedModels 返回所有已成功的微调任务产出的模型名称，可直接用于 Config.Model
+func (c *Client) FineTunedModels(ctx context.Context) ([]string, error) {
+	tuner, err := c.FineTuner()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := tuner.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, job := range jobs {
+		if job.Status == spec.FineTuneSucceeded && job.FineTunedModel != "" {
+			models = append(models, job.FineTunedModel)
+		}
+	}
+	return models, nil
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// HistoryStore 定义了对话历史的持久化存储。
+// 所有操作都以会话 ID 区分，实现必须是并发安全的。
+type HistoryStore interface {
+	// Load 按顺序返回会话的全部历史消息，会话不存在时返回空切片
+	Load(ctx context.Context, sessionID string) ([]spec.Message, error)
+	// Append 按顺序追加一条或多条消息
+	Append(ctx context.Context, sessionID string, msgs ...spec.Message) error
+	// Reset 清空会话的全部历史
+	Reset(ctx context.Context, sessionID string) error
+}
+
+// HistoryReplacer 是 HistoryStore 的可选扩展，原子地把会话的全部历史替换为 msgs。
+// 删除或编辑历史时需要重写整个会话，未实现时依次调用 Reset 和 Append，两步之间失败会丢失已持久化的历史。
+type HistoryReplacer interface {
+	Replace(ctx context.Context, sessionID string, msgs []spec.Message) error
+}
+
+// ============== 内存存储 ==============
+
+// MemoryHistoryStore 是基于内存的 HistoryStore，进程退出后数据丢失，主要用于测试和单机场景。
+type MemoryHistoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]spec.Message
+}
+
+// NewMemoryHistoryStore 创建一个内存历史存储
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{sessions: make(map[string][]spec.Message)}
+}
+
+func (s *MemoryHistoryStore) Load(ctx context.Context, sessionID string) ([]spec.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	msgs := s.sessions[sessionID]
+	out := make([]spec.Message, len(msgs))
+	copy(out, msgs)
+	return out, nil
+}
+
+func (s *MemoryHistoryStore) Append(ctx context.Context, sessionID string, msgs ...spec.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = append(s.sessions[sessionID], msgs...)
+	return nil
+}
+
+func (s *MemoryHistoryStore) Reset(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+func (s *MemoryHistoryStore) Replace(ctx context.Context, sessionID string, msgs []spec.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(msgs) == 0 {
+		delete(s.sessions, sessionID)
+		return nil
+	}
+	s.sessions[sessionID] = append([]spec.Message(nil), msgs...)
+	return nil
+}
+
+// ============== 文件存储 ==============
+
+// FileHistoryStore 将每个会话保存为目录下的一个 JSON Lines 文件，每行一条消息。
+type FileHistoryStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileHistoryStore 创建一个文件历史存储，目录不存在时会自动创建
+func NewFileHistoryStore(dir string) (*FileHistoryStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("file history store: failed to create dir: %w", err)
+	}
+	return &FileHistoryStore{dir: dir}, nil
+}
+
+// path 返回会话对应的文件路径，会话 ID 会被转义以避免路径穿越
+func (s *FileHistoryStore) path(sessionID string) string {
+	return filepath.Join(s.dir, url.PathEscape(sessionID)+".jsonl")
+}
+
+func (s *FileHistoryStore) Load(ctx context.Context, sessionID string) ([]spec.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file history store: failed to open session file: %w", err)
+	}
+	defer f.Close()
+
+	var msgs []spec.Message
+	dec := json.NewDecoder(f)
+	for {
+		var msg spec.Message
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("file history store: failed to decode message: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (s *FileHistoryStore) Append(ctx context.Context, sessionID string, msgs ...spec.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path(sessionID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("file history store: failed to open session file: %w", err)
+	}
+	defer f.Close()
+	return writeLines(f, msgs)
+}
+
+// Replace 把新的历史写入临时文件后重命名为会话文件，写入失败时原文件保持不变
+func (s *FileHistoryStore) Replace(ctx context.Context, sessionID string, msgs []spec.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.CreateTemp(s.dir, ".history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("file history store: failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if err := writeLines(f, msgs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("file history store: failed to write session file: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path(sessionID)); err != nil {
+		return fmt.Errorf("file history store: failed to replace session file: %w", err)
+	}
+	return nil
+}
+
+// writeLines 以 JSON Lines 格式写入消息
+func writeLines(f *os.File, msgs []spec.Message) error {
+	w := bufio.NewWriter(f)
+	for i := range msgs {
+		line, err := json.Marshal(&msgs[i])
+		if err != nil {
+			return fmt.Errorf("file history store: failed to encode message: %w", err)
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("file history store: failed to write session file: %w", err)
+	}
+	return nil
+}
+
+func (s *FileHistoryStore) Reset(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(sessionID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("file history store: failed to remove session file: %w", err)
+	}
+	return nil
+}
+
+// ============== SQLite 存储 ==============
+
+// validTable 是允许的表名格式，表名会直接拼接到 SQL 中
+var validTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLiteHistoryStore 将历史保存在 SQLite 表中。
+// 为了不引入 cgo 或第三方依赖，数据库连接由调用方创建并注册驱动
+// （例如 modernc.org/sqlite 或 github.com/mattn/go-sqlite3）。
+// 建表语句使用 SQLite 的语法（AUTOINCREMENT、TEXT 列上的索引），不适用于 MySQL 等其他数据库，
+// 这类数据库请自行实现 HistoryStore。
+type SQLiteHistoryStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteHistoryStore 使用已打开的数据库连接创建历史存储，并自动建表。
+// table 为空时使用默认表名 llm_history，只能包含字母、数字和下划线。
+func NewSQLiteHistoryStore(ctx context.Context, db *sql.DB, table string) (*SQLiteHistoryStore, error) {
+	if table == "" {
+		table = "llm_history"
+	}
+	if !validTable.MatchString(table) {
+		return nil, fmt.Errorf("sqlite history store: invalid table name %q", table)
+	}
+	s := &SQLiteHistoryStore{db: db, table: table}
+
+	ddl := []string{
+		`CREATE TABLE IF NOT EXISTS ` + table + ` (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			message TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_session ON ` + table + ` (session_id, id)`,
+	}
+	for _, stmt := range ddl {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("sqlite history store: failed to init table: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *SQLiteHistoryStore) Load(ctx context.Context, sessionID string) ([]spec.Message, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT message FROM `+s.table+` WHERE session_id = ? ORDER BY id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite history store: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []spec.Message
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("sqlite history store: scan failed: %w", err)
+		}
+		var msg spec.Message
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+			return nil, fmt.Errorf("sqlite history store: failed to decode message: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, rows.Err()
+}
+
+func (s *SQLiteHistoryStore) Append(ctx context.Context, sessionID string, msgs ...spec.Message) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite history store: failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.insert(ctx, tx, sessionID, msgs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteHistoryStore) Reset(ctx context.Context, sessionID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("sqlite history store: delete failed: %w", err)
+	}
+	return nil
+}
+
+// Replace 在同一个事务中删除会话的历史并写入 msgs
+func (s *SQLiteHistoryStore) Replace(ctx context.Context, sessionID string, msgs []spec.Message) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite history store: failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("sqlite history store: delete failed: %w", err)
+	}
+	if err := s.insert(ctx, tx, sessionID, msgs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insert 在事务中按顺序插入消息
+func (s *SQLiteHistoryStore) insert(ctx context.Context, tx *sql.Tx, sessionID string, msgs []spec.Message) error {
+	for i := range msgs {
+		raw, err := json.Marshal(&msgs[i])
+		if err != nil {
+			return fmt.Errorf("sqlite history store: failed to encode message: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO `+s.table+` (session_id, message) VALUES (?, ?)`, sessionID, string(raw)); err != nil {
+			return fmt.Errorf("sqlite history store: insert failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/lang"
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultLanguageConfidence 是默认的最低识别置信度
+const defaultLanguageConfidence = 0.5
+
+// ReplyLanguage 配置回复语言，语言使用 ISO 639-1 代码（见 lang.Codes）
+type ReplyLanguage struct {
+	// Language 固定的回复语言，为空表示跟随用户最后一条消息的语言
+	Language string
+	// Default 识别失败或置信度不足时使用的语言，为空表示此时不做要求
+	Default string
+	// MinConfidence 最低识别置信度，为 0 时使用 0.5
+	MinConfidence float64
+	// Translate 回复的语言仍与要求不一致时，再调用一次模型把回复翻译为目标语言。
+	// 流式输出的内容已经发出，不会被翻译；带函数调用的回复不做处理
+	Translate bool
+	// TranslateConfig 翻译使用的配置，nil 表示使用客户端的配置；
+	// 可以指定 DashScope 的 qwen-mt 系列等专用翻译模型
+	TranslateConfig *llm.Config
+}
+
+// WithReplyLanguage 让模型使用指定语言回复：每次请求前识别目标语言，
+// 把语言要求追加到系统提示词（不会写入历史），按配置在回复语言不一致时自动翻译
+func WithReplyLanguage(rl ReplyLanguage) Option {
+	return func(c *Client) {
+		c.replyLanguage = &rl
+	}
+}
+
+// WithAutoLanguage 是 WithReplyLanguage(ReplyLanguage{}) 的简写：回复语言跟随用户
+func WithAutoLanguage() Option {
+	return WithReplyLanguage(ReplyLanguage{})
+}
+
+// target 返回本次请求的目标语言，为空表示不做要求
+func (rl *ReplyLanguage) target(messages []spec.Message) string {
+	if rl.Language != "" {
+		return rl.Language
+	}
+	minConfidence := rl.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultLanguageConfidence
+	}
+	if d := lang.LastUser(messages); d.Code != "" && d.Confidence >= minConfidence {
+		return d.Code
+	}
+	return rl.Default
+}
+
+// languageMessages 按回复语言配置改写发送的消息，返回改写后的消息和目标语言
+func (c *Client) languageMessages(messages []spec.Message) ([]spec.Message, string) {
+	if c.replyLanguage == nil {
+		return messages, ""
+	}
+	code := c.replyLanguage.target(messages)
+	return lang.Instruct(messages, code), code
+}
+
+// enforceLanguage 在回复语言与 code 不一致时按配置翻译回复
+func (c *Client) enforceLanguage(ctx context.Context, cfg llm.Config, code string, resp *spec.Response) error {
+	rl := c.replyLanguage
+	if rl == nil || !rl.Translate || code == "" || resp.DryRun != nil || len(resp.Message.ToolCalls) > 0 {
+		return nil
+	}
+	text := resp.Message.PlainText()
+	if d := lang.Detect(text); d.Code == "" || d.Code == code {
+		return nil
+	}
+	if rl.TranslateConfig != nil {
+		cfg = *rl.TranslateConfig
+	}
+	// 翻译结果不应再次输出到流式回调
+	cfg.StreamCallback = nil
+	translated, err := llm.Translate(ctx, text, "auto", lang.Name(code), cfg)
+	if err != nil {
+		return fmt.Errorf("client: failed to translate reply to %s: %w", code, err)
+	}
+	resp.Message.Content, resp.Message.Parts = translated, nil
+	return nil
+}
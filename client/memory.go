@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/iEvan-lhr/go-llm-client/spec"
@@ -16,42 +15,69 @@ import (
 // 适用场景：Web 服务后端，需要将上下文存储在 Redis/数据库中，每次请求时取出传入。
 func (c *Client) SendByMemory(userPrompt string, memoryJSON string) (*spec.Response, string, error) {
 	// 在内部新建上下文，简化调用方传参
-	ctx := context.Background()
+	return c.SendByMemoryCtx(context.Background(), userPrompt, memoryJSON)
+}
 
-	var messages []spec.Message
+// SendByMemoryCtx 是 SendByMemory 的完整版本。
+// 它使用调用方传入的 ctx（可传递超时与取消信号），并允许通过 opts 为本次调用附加参数，
+// 附加的选项会覆盖 Client 的默认配置。
+func (c *Client) SendByMemoryCtx(ctx context.Context, userPrompt string, memoryJSON string, opts ...spec.Option) (*spec.Response, string, error) {
+	codec := JSONMemoryCodec{}
 
-	// 1. 解析传入的记忆字符串 (JSON -> []Message)
+	// 1. 解析传入的记忆字符串 (JSON -> Memory)
+	mem := &Memory{}
 	if memoryJSON != "" {
-		if err := json.Unmarshal([]byte(memoryJSON), &messages); err != nil {
+		decoded, err := codec.Decode([]byte(memoryJSON))
+		if err != nil {
 			return nil, memoryJSON, fmt.Errorf("failed to parse memory json: %w", err)
 		}
+		mem = decoded
+	}
+
+	// 2. 调用模型并得到更新后的记忆
+	resp, newMem, err := c.SendWithMemory(ctx, userPrompt, mem, opts...)
+	if err != nil {
+		// 如果出错，返回原始记忆，不包含本次失败的对话
+		return nil, memoryJSON, err
+	}
+
+	// 3. 序列化更新后的记忆 (Memory -> JSON)
+	newMemoryBytes, err := codec.Encode(newMem)
+	if err != nil {
+		return nil, memoryJSON, fmt.Errorf("failed to marshal new memory: %w", err)
+	}
+
+	return resp, string(newMemoryBytes), nil
+}
+
+// SendWithMemory 是外部状态调用方式的强类型版本。
+// mem 为 nil 或为空时视为新会话；传入的 mem 不会被修改，更新后的记忆以新对象返回。
+// 配合 MemoryCodec 可以将记忆以 JSON、压缩或加密的二进制形式存入 Redis/数据库。
+func (c *Client) SendWithMemory(ctx context.Context, userPrompt string, mem *Memory, opts ...spec.Option) (*spec.Response, *Memory, error) {
+	var messages []spec.Message
+	if mem != nil {
+		messages = append(messages, mem.Messages...)
 	}
 
-	// 2. 如果记忆为空，且配置了系统提示词，则自动注入 System Prompt
+	// 1. 如果记忆为空，且配置了系统提示词，则自动注入 System Prompt
 	// (这保证了即使是外部记忆，也能通过 Client 统一管理 System Prompt)
-	if len(messages) == 0 && c.config.SystemPrompt != "" {
-		messages = append(messages, spec.NewSystemMessage(c.config.SystemPrompt))
+	if prompt := c.snapshotConfig().SystemPrompt; len(messages) == 0 && prompt != "" {
+		messages = append(messages, spec.NewSystemMessage(prompt))
 	}
 
-	// 3. 追加当前用户问题
+	// 2. 追加当前用户问题，超出预算时先压缩记忆
 	messages = append(messages, spec.NewUserMessage(userPrompt))
+	messages = c.compactMessages(ctx, messages)
 
-	// 4. 调用底层模型 (使用 invoke 方法复用 Config 逻辑)
+	// 3. 调用底层模型 (使用 invoke 方法复用 Config 逻辑)
 	// 注意：这里传 nil 作为 tempConfig，表示使用 Client 初始化的默认配置
-	resp, err := c.invoke(ctx, messages, nil)
+	resp, err := c.invoke(ctx, messages, nil, opts...)
 	if err != nil {
-		// 如果出错，返回原始记忆，不包含本次失败的对话
-		return nil, memoryJSON, err
+		return nil, mem, err
 	}
 
-	// 5. 将 AI 的回答追加到上下文中
+	// 4. 将 AI 的回答追加到上下文中
 	messages = append(messages, resp.Message)
 
-	// 6. 序列化更新后的记忆 ([]Message -> JSON)
-	newMemoryBytes, err := json.Marshal(messages)
-	if err != nil {
-		return nil, memoryJSON, fmt.Errorf("failed to marshal new memory: %w", err)
-	}
-
-	return resp, string(newMemoryBytes), nil
+	return resp, &Memory{Messages: messages}, nil
 }
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Memory 是外部状态调用方式下的对话上下文。
+type Memory struct {
+	Messages []spec.Message
+}
+
+// MemoryCodec 定义了 Memory 与字节之间的编解码方式。
+// 各实现可以相互嵌套，例如先 JSON 编码再压缩再加密。
+type MemoryCodec interface {
+	Encode(m *Memory) ([]byte, error)
+	Decode(data []byte) (*Memory, error)
+}
+
+// JSONMemoryCodec 将 Memory 编码为消息数组的 JSON，与 SendByMemory 使用的格式兼容。
+type JSONMemoryCodec struct{}
+
+func (JSONMemoryCodec) Encode(m *Memory) ([]byte, error) {
+	if m == nil {
+		m = &Memory{}
+	}
+	messages := m.Messages
+	if messages == nil {
+		messages = []spec.Message{}
+	}
+	return json.Marshal(messages)
+}
+
+func (JSONMemoryCodec) Decode(data []byte) (*Memory, error) {
+	var messages []spec.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+	return &Memory{Messages: messages}, nil
+}
+
+// GzipMemoryCodec 在内部编解码器的基础上进行 gzip 压缩，Inner 为 nil 时使用 JSONMemoryCodec。
+type GzipMemoryCodec struct {
+	Inner MemoryCodec
+}
+
+func (c GzipMemoryCodec) inner() MemoryCodec {
+	if c.Inner == nil {
+		return JSONMemoryCodec{}
+	}
+	return c.Inner
+}
+
+func (c GzipMemoryCodec) Encode(m *Memory) ([]byte, error) {
+	raw, err := c.inner().Encode(m)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("gzip memory codec: compress failed: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gzip memory codec: compress failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c GzipMemoryCodec) Decode(data []byte) (*Memory, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip memory codec: invalid data: %w", err)
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip memory codec: decompress failed: %w", err)
+	}
+	return c.inner().Decode(raw)
+}
+
+// AESMemoryCodec 使用 AES-GCM 对内部编解码器的输出进行加密。
+// 输出格式为 nonce || ciphertext。
+type AESMemoryCodec struct {
+	aead  cipher.AEAD
+	inner MemoryCodec
+}
+
+// NewAESMemoryCodec 创建加密编解码器，key 长度必须为 16、24 或 32 字节。
+// inner 为 nil 时使用 JSONMemoryCodec。
+func NewAESMemoryCodec(key []byte, inner MemoryCodec) (*AESMemoryCodec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes memory codec: invalid key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes memory codec: %w", err)
+	}
+	if inner == nil {
+		inner = JSONMemoryCodec{}
+	}
+	return &AESMemoryCodec{aead: aead, inner: inner}, nil
+}
+
+func (c *AESMemoryCodec) Encode(m *Memory) ([]byte, error) {
+	raw, err := c.inner.Encode(m)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("aes memory codec: failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, raw, nil), nil
+}
+
+func (c *AESMemoryCodec) Decode(data []byte) (*Memory, error) {
+	n := c.aead.NonceSize()
+	if len(data) < n {
+		return nil, fmt.Errorf("aes memory codec: data too short")
+	}
+	raw, err := c.aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("aes memory codec: decrypt failed: %w", err)
+	}
+	return c.inner.Decode(raw)
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// ListModels 列出当前 API Key 可用的模型，可用于填充模型选择列表。
+// 提供商未返回上下文窗口时使用 tokens 包内置的已知值补全。
+func (c *Client) ListModels(ctx context.Context) ([]spec.ModelInfo, error) {
+	p, ok := c.client.(spec.ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' does not support listing models (ModelLister interface not implemented)", c.config.Provider)
+	}
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range models {
+		if models[i].ContextWindow == 0 {
+			models[i].ContextWindow = tokens.ContextWindow(models[i].ID)
+		}
+	}
+	return models, nil
+}
+
+// ValidateModel 检查配置的模型是否在提供商的可用模型列表中，返回该模型的信息。
+// 模型不存在时返回的错误满足 errors.Is(err, spec.ErrModelNotFound)，
+// 便于在第一次调用前发现拼写错误或无权限的模型。
+func (c *Client) ValidateModel(ctx context.Context) (spec.ModelInfo, error) {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return spec.ModelInfo{}, err
+	}
+	info, ok := spec.FindModel(models, c.model())
+	if !ok {
+		return spec.ModelInfo{}, fmt.Errorf("%w: provider '%s' has no model %q", spec.ErrModelNotFound, c.config.Provider, c.model())
+	}
+	return info, nil
+}
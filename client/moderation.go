@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// WithModeration 在每次请求发出前使用 moderator 审核最后一条用户消息，
+// 未通过时返回 *spec.ModerationError（errors.Is(err, spec.ErrContentFiltered) 为 true），请求不会发送给模型。
+// 审核以发送前钩子的形式实现，与 WithBeforeHook 注册的钩子按注册顺序执行。
+func WithModeration(moderator spec.Moderator) Option {
+	return WithBeforeHook(moderationHook(moderator))
+}
+
+// moderationHook 返回审核最后一条用户消息的发送前钩子
+func moderationHook(moderator spec.Moderator) BeforeHook {
+	return func(ctx context.Context, messages []spec.Message) ([]spec.Message, error) {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role != spec.RoleUser {
+				continue
+			}
+			text := messages[i].PlainText()
+			if text == "" {
+				break
+			}
+			results, err := moderator.Moderate(ctx, []string{text})
+			if err != nil {
+				return nil, fmt.Errorf("client: moderation failed: %w", err)
+			}
+			if len(results) > 0 && results[0].Flagged {
+				return nil, &spec.ModerationError{Result: results[0]}
+			}
+			break
+		}
+		return messages, nil
+	}
+}
+
+// Moderate 使用当前配置的模型审核文本，例如 OpenAI 的 omni-moderation-latest
+func (c *Client) Moderate(ctx context.Context, inputs ...string) ([]spec.ModerationResult, error) {
+	if p, ok := c.client.(spec.ModeratorProvider); ok {
+		return p.Moderator(c.model()).Moderate(ctx, inputs)
+	}
+	return nil, fmt.Errorf("provider '%s' does not support moderation (ModeratorProvider interface not implemented)", c.config.Provider)
+}
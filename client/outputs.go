@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"github.com/iEvan-lhr/go-llm-client/outputs"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// WithOutputRetries 设置 SendParsed 在回复无法解析时的重试次数，默认为 1，0 表示不重试。
+// 每次重试会把解析错误反馈给模型，要求其修正格式。
+func WithOutputRetries(n int) Option {
+	return func(c *Client) {
+		c.outputRetries = &n
+	}
+}
+
+// SendParsed 发送 prompt（自动追加解析器的格式说明）并把回复解析为 T。
+// 解析失败时按 WithOutputRetries 的设置自动重试，重试的中间过程不会写入历史，
+// 成功后只有本轮提问和最终回复写入历史；重试后仍失败时返回 *outputs.ParseError。
+//
+//	items, _, err := client.SendParsed(ctx, c, "列出三个杭州的景点", outputs.List())
+func SendParsed[T any](ctx context.Context, c *Client, prompt string, p outputs.Parser[T], opts ...spec.Option) (T, *spec.Response, error) {
+	userMsg := spec.NewUserMessage(outputs.WithInstructions(prompt, p))
+
+	c.mu.Lock()
+	messages := append(append([]spec.Message(nil), c.history...), userMsg)
+	c.mu.Unlock()
+
+	retries := outputs.DefaultRetries
+	if c.outputRetries != nil {
+		retries = *c.outputRetries
+	}
+	chat := func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+		return c.invoke(ctx, messages, nil, opts...)
+	}
+	value, resp, err := outputs.Run(ctx, chat, messages, p, retries)
+	if err != nil || resp.DryRun != nil {
+		return value, resp, err
+	}
+	if err := c.appendTurn(ctx, []spec.Message{userMsg, resp.Message}); err != nil {
+		return value, resp, err
+	}
+	return value, resp, nil
+}
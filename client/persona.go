@@ -0,0 +1,35 @@
+package client
+
+import (
+	"github.com/iEvan-lhr/go-llm-client/persona"
+)
+
+// NewWithPersona 使用默认注册表中的人设创建客户端：系统提示词、默认参数、提供商和首选模型都来自人设，
+// API Key 从人设指定的环境变量读取，未指定时按提供商使用默认变量名（如 DASHSCOPE_API_KEY）。
+// 需要自定义连接配置时使用 NewBuilder().Persona(p)
+//
+// 用法示例：
+//
+//	if err := persona.LoadDir("personas"); err != nil { ... }
+//	c, err := client.NewWithPersona("support-agent", client.WithHistoryStore(store, sessionID))
+func NewWithPersona(name string, opts ...Option) (*Client, error) {
+	p, err := persona.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilder().Persona(p).Options(opts...).Build()
+}
+
+// Persona 应用人设：覆盖系统提示词，合并参数，人设指定了提供商和模型时一并替换（见 persona.Persona.Apply）。
+// 尚未设置 API Key 时改为从环境变量读取，人设的 APIKeyEnv 优先于提供商的默认变量名
+func (b *Builder) Persona(p *persona.Persona) *Builder {
+	b.cfg = p.Apply(b.cfg)
+	if b.cfg.APIKey == "" && b.envKey == nil && b.cfg.APIKeyPool == nil {
+		if p.APIKeyEnv != "" {
+			b.APIKeyFromEnv(p.APIKeyEnv)
+		} else {
+			b.APIKeyFromEnv()
+		}
+	}
+	return b
+}
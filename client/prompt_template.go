@@ -0,0 +1,74 @@
+package client
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+)
+
+// WithSystemPromptTemplate 使用 text/template 模板作为系统提示词，vars 为初始变量。
+// 模板在创建客户端时渲染，之后可以通过 SetVar / SetVars 修改变量并重新渲染系统消息，
+// 适用于嵌入用户名、日期、语言区域等信息的人设提示词。该选项会覆盖 llm.Config.SystemPrompt。
+//
+// 用法示例：
+//
+//	c, err := client.New(cfg, client.WithSystemPromptTemplate(
+//	    "你是{{.Company}}的客服，正在为{{.UserName}}服务，请使用{{.Locale}}回答。",
+//	    map[string]any{"Company": "ACME", "UserName": "小明", "Locale": "中文"},
+//	))
+//	c.SetVar("Locale", "English")
+func WithSystemPromptTemplate(tmpl string, vars map[string]any) Option {
+	return func(c *Client) {
+		t, err := template.New("system_prompt").Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			c.initErr = fmt.Errorf("client: invalid system prompt template: %w", err)
+			return
+		}
+		c.promptTmpl = t
+		c.promptVars = maps.Clone(vars)
+		if c.promptVars == nil {
+			c.promptVars = make(map[string]any)
+		}
+	}
+}
+
+// SetVar 设置单个模板变量并重新渲染系统提示词，其余历史保持不变。
+func (c *Client) SetVar(key string, value any) error {
+	return c.SetVars(map[string]any{key: value})
+}
+
+// SetVars 批量设置模板变量并重新渲染系统提示词。
+// 渲染失败时变量不会被修改。变量、配置中的系统提示词和历史在同一次加锁中更新，
+// 并发的 Send 等调用通过 snapshotConfig 读取配置，只会看到更新前或更新后的提示词。
+func (c *Client) SetVars(vars map[string]any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.promptTmpl == nil {
+		return fmt.Errorf("client: no system prompt template configured, use WithSystemPromptTemplate")
+	}
+	next := maps.Clone(c.promptVars)
+	maps.Copy(next, vars)
+	rendered, err := renderPrompt(c.promptTmpl, next)
+	if err != nil {
+		return err
+	}
+	c.promptVars = next
+	return c.setSystemPrompt(rendered)
+}
+
+// Vars 返回当前模板变量的副本
+func (c *Client) Vars() map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.promptVars)
+}
+
+// renderPrompt 使用变量渲染模板
+func renderPrompt(t *template.Template, vars map[string]any) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("client: failed to render system prompt template: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,268 @@
+// Package redisstore 提供基于 Redis 的对话历史存储，可通过 client.WithHistoryStore 使用。
+// 内置了一个最小化的 RESP 协议实现，无需引入第三方 Redis 客户端。
+package redisstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Store 将每个会话保存为一个 Redis List（RPUSH/LRANGE/DEL），实现了 client.HistoryStore 和 client.HistoryReplacer。
+type Store struct {
+	addr     string
+	password string
+	db       int
+	prefix   string
+	// TTL 每次追加后为会话 key 设置的过期时间，0 表示永不过期
+	TTL time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// New 创建一个 Redis 历史存储，key 的格式为 "llm:history:<sessionID>"
+func New(addr, password string, db int) *Store {
+	return &Store{
+		addr:     addr,
+		password: password,
+		db:       db,
+		prefix:   "llm:history:",
+	}
+}
+
+func (s *Store) Load(ctx context.Context, sessionID string) ([]spec.Message, error) {
+	reply, err := s.do(ctx, "LRANGE", s.prefix+sessionID, "0", "-1")
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]any)
+
+	msgs := make([]spec.Message, 0, len(items))
+	for _, item := range items {
+		raw, _ := item.(string)
+		var msg spec.Message
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+			return nil, fmt.Errorf("redisstore: failed to decode message: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (s *Store) Append(ctx context.Context, sessionID string, msgs ...spec.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	key := s.prefix + sessionID
+	args := []string{"RPUSH", key}
+	for i := range msgs {
+		raw, err := json.Marshal(&msgs[i])
+		if err != nil {
+			return fmt.Errorf("redisstore: failed to encode message: %w", err)
+		}
+		args = append(args, string(raw))
+	}
+	if _, err := s.do(ctx, args...); err != nil {
+		return err
+	}
+	if s.TTL > 0 {
+		if _, err := s.do(ctx, "PEXPIRE", key, strconv.FormatInt(s.TTL.Milliseconds(), 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Reset(ctx context.Context, sessionID string) error {
+	_, err := s.do(ctx, "DEL", s.prefix+sessionID)
+	return err
+}
+
+// Replace 在 MULTI/EXEC 事务中删除会话并写入 msgs
+func (s *Store) Replace(ctx context.Context, sessionID string, msgs []spec.Message) error {
+	key := s.prefix + sessionID
+	cmds := [][]string{{"MULTI"}, {"DEL", key}}
+	if len(msgs) > 0 {
+		push := []string{"RPUSH", key}
+		for i := range msgs {
+			raw, err := json.Marshal(&msgs[i])
+			if err != nil {
+				return fmt.Errorf("redisstore: failed to encode message: %w", err)
+			}
+			push = append(push, string(raw))
+		}
+		cmds = append(cmds, push)
+		if s.TTL > 0 {
+			cmds = append(cmds, []string{"PEXPIRE", key, strconv.FormatInt(s.TTL.Milliseconds(), 10)})
+		}
+	}
+	cmds = append(cmds, []string{"EXEC"})
+	_, err := s.pipeline(ctx, cmds...)
+	return err
+}
+
+// Close 关闭底层的 Redis 连接
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.rd = nil, nil
+	return err
+}
+
+// do 执行一条 Redis 命令并返回解析后的回复，网络错误时会丢弃连接以便下次重连
+func (s *Store) do(ctx context.Context, args ...string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := s.roundTrip(ctx, args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		s.conn.Close()
+		s.conn, s.rd = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: %s failed: %w", args[0], err)
+	}
+	return reply, nil
+}
+
+// pipeline 依次执行多条命令并返回最后一条的回复，任一命令失败时返回错误，用于 MULTI/EXEC 事务
+func (s *Store) pipeline(ctx context.Context, cmds ...[]string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var reply any
+	for _, args := range cmds {
+		var err error
+		if reply, err = s.roundTrip(ctx, args...); err != nil {
+			// 事务中途失败时连接处于 MULTI 状态，直接丢弃连接使服务端放弃事务
+			s.conn.Close()
+			s.conn, s.rd = nil, nil
+			return nil, fmt.Errorf("redisstore: %s failed: %w", args[0], err)
+		}
+	}
+	return reply, nil
+}
+
+// connect 建立连接并完成认证与选库
+func (s *Store) connect(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("redisstore: failed to connect: %w", err)
+	}
+	s.conn, s.rd = conn, bufio.NewReader(conn)
+
+	if s.password != "" {
+		if _, err := s.roundTrip(ctx, "AUTH", s.password); err != nil {
+			s.conn.Close()
+			s.conn, s.rd = nil, nil
+			return fmt.Errorf("redisstore: auth failed: %w", err)
+		}
+	}
+	if s.db != 0 {
+		if _, err := s.roundTrip(ctx, "SELECT", strconv.Itoa(s.db)); err != nil {
+			s.conn.Close()
+			s.conn, s.rd = nil, nil
+			return fmt.Errorf("redisstore: select db failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) roundTrip(ctx context.Context, args ...string) (any, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		s.conn.SetDeadline(deadline)
+	} else {
+		s.conn.SetDeadline(time.Time{})
+	}
+
+	// 按 RESP 数组格式编码命令
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readRESP(s.rd)
+}
+
+// redisError 表示 Redis 服务端返回的错误回复（连接本身仍然可用）
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// readRESP 解析一个 RESP 回复
+func readRESP(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("invalid reply %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readRESP(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", line[0])
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultNudgePrompt 是重试时追加的默认引导语
+const defaultNudgePrompt = "请直接给出完整的回答。"
+
+// refusalPrefixes 是常见的拒答开头，仅用于较短的回复，避免误判
+var refusalPrefixes = []string{
+	"i'm sorry, but i can",
+	"i am sorry, but i can",
+	"sorry, i can't",
+	"i can't help with",
+	"i cannot help with",
+	"i can't assist with",
+	"i cannot assist with",
+	"i'm unable to",
+	"抱歉，我无法",
+	"抱歉，我不能",
+	"对不起，我无法",
+	"对不起，我不能",
+	"很抱歉，我无法",
+	"很抱歉，我不能",
+}
+
+// RefusalRetryPolicy 配置模型返回空内容或明显拒答时的自动重试。
+type RefusalRetryPolicy struct {
+	// MaxAttempts 最多重试的次数（不含首次调用）
+	MaxAttempts int
+	// NudgePrompt 重试时追加在上一次回复之后的引导语，为空时使用默认引导语；
+	// 设置为 "-" 表示不追加引导语，原样重发
+	NudgePrompt string
+	// Temperatures 依次用于每次重试的温度，次数超过长度时沿用最后一个值；为空表示不调整温度
+	Temperatures []float32
+	// IsRefusal 自定义判定函数，为 nil 时使用 IsEmptyOrRefusal
+	IsRefusal func(resp *spec.Response) bool
+}
+
+// WithRefusalRetry 为客户端开启空回复/拒答自动重试。
+// 流式调用时，每次尝试的内容会先暂存，判定通过（或重试次数用尽）后才一次性交给 StreamCallback 和 StreamTee，
+// 因此调用方不会收到被拒绝的内容，但也无法再逐块实时接收输出。
+func WithRefusalRetry(policy RefusalRetryPolicy) Option {
+	return func(c *Client) {
+		c.refusalRetry = &policy
+	}
+}
+
+// IsEmptyOrRefusal 判断响应是否为空内容或较短的常见拒答。
+func IsEmptyOrRefusal(resp *spec.Response) bool {
+	if resp == nil {
+		return true
+	}
+	content := strings.TrimSpace(resp.Message.PlainText())
+	if content == "" {
+		return true
+	}
+	if utf8.RuneCountInString(content) > 200 {
+		return false
+	}
+	lower := strings.ToLower(content)
+	for _, prefix := range refusalPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryMessages 构造第 attempt 次重试的消息与附加选项
+func (p *RefusalRetryPolicy) retryMessages(messages []spec.Message, last *spec.Response, attempt int) ([]spec.Message, []spec.Option) {
+	next := append([]spec.Message(nil), messages...)
+	if p.NudgePrompt != "-" {
+		nudge := p.NudgePrompt
+		if nudge == "" {
+			nudge = defaultNudgePrompt
+		}
+		if last != nil && strings.TrimSpace(last.Message.PlainText()) != "" {
+			next = append(next, last.Message)
+		}
+		next = append(next, spec.NewUserMessage(nudge))
+	}
+
+	var opts []spec.Option
+	if n := len(p.Temperatures); n > 0 {
+		i := attempt - 1
+		if i >= n {
+			i = n - 1
+		}
+		opts = append(opts, spec.WithTemperature(p.Temperatures[i]))
+	}
+	return next, opts
+}
+
+// isRefusal 根据策略判断响应是否需要重试
+func (p *RefusalRetryPolicy) isRefusal(resp *spec.Response) bool {
+	if p.IsRefusal != nil {
+		return p.IsRefusal(resp)
+	}
+	return IsEmptyOrRefusal(resp)
+}
+
+// streamBuffer 在开启拒答重试时暂存每次尝试的流式文本，只有被接受的那次尝试才会输出给调用方，
+// 避免 StreamCallback 和 StreamTee 先收到被拒绝的内容再收到重试的内容
+type streamBuffer struct {
+	callback spec.StreamCallback
+	tee      io.Writer
+	chunks   []string
+}
+
+// newStreamBuffer 从 opts 中取出流式回调，未设置 StreamCallback 和 StreamTee 时返回 nil
+func newStreamBuffer(opts []spec.Option) *streamBuffer {
+	var rc spec.RequestConfig
+	for _, opt := range opts {
+		opt(&rc)
+	}
+	if rc.StreamCallback == nil && rc.StreamTee == nil {
+		return nil
+	}
+	return &streamBuffer{callback: rc.StreamCallback, tee: rc.StreamTee}
+}
+
+// capture 清空已暂存的内容，返回把本次尝试的流式文本写入缓冲区的选项
+func (b *streamBuffer) capture() spec.Option {
+	b.chunks = b.chunks[:0]
+	return func(r *spec.RequestConfig) {
+		r.StreamCallback = func(_ context.Context, chunk string) error {
+			b.chunks = append(b.chunks, chunk)
+			return nil
+		}
+		r.StreamTee = nil
+	}
+}
+
+// flush 按 spec.RequestConfig.EmitStreamChunk 的顺序将暂存的内容输出给调用方
+func (b *streamBuffer) flush(ctx context.Context) error {
+	for _, chunk := range b.chunks {
+		if b.tee != nil {
+			if _, err := io.WriteString(b.tee, chunk); err != nil {
+				return fmt.Errorf("stream tee write failed: %w", err)
+			}
+		}
+		if b.callback != nil {
+			if err := b.callback(ctx, chunk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultSemanticThreshold 是默认的相似度阈值
+const defaultSemanticThreshold = 0.95
+
+// defaultSemanticMaxEntries 是默认的最大缓存条目数
+const defaultSemanticMaxEntries = 1000
+
+// SemanticCache 基于向量相似度的回复缓存。
+// 新问题与已缓存问题的余弦相似度达到 Threshold 时直接返回缓存的回复，适合 FAQ 类流量。
+//
+// 只有不包含历史回复和图片、且没有临时配置或额外选项（如工具）的调用（例如 SendNoHistory
+// 或新会话的第一轮）会使用缓存，并且只在模型、请求参数和系统提示词都相同的条目之间匹配，避免跨上下文返回错误答案。
+type SemanticCache struct {
+	// Embedder 用于计算问题向量，可以与对话使用不同的模型
+	Embedder spec.Embedded
+	// Threshold 余弦相似度阈值 (0~1]，为 0 时使用 0.95
+	Threshold float64
+	// MaxEntries 最大缓存条目数，超出后淘汰最早的条目，为 0 时使用 1000
+	MaxEntries int
+	// TTL 条目的有效期，为 0 表示不过期
+	TTL time.Duration
+
+	mu      sync.RWMutex
+	entries []semanticEntry
+}
+
+type semanticEntry struct {
+	scope    string
+	vector   []float32 // 已归一化
+	resp     *spec.Response
+	storedAt time.Time
+}
+
+// NewSemanticCache 使用 embedder 创建语义缓存，threshold 为 0 时使用默认阈值 0.95
+func NewSemanticCache(embedder spec.Embedded, threshold float64) *SemanticCache {
+	return &SemanticCache{Embedder: embedder, Threshold: threshold}
+}
+
+// WithSemanticCache 为客户端开启语义缓存。多个客户端可以共享同一个缓存实例。
+// 命中缓存时不会调用模型，配置了 StreamCallback 时完整回复会作为一个数据块输出；
+// 计算向量失败时直接调用模型，不影响正常对话。
+func WithSemanticCache(cache *SemanticCache) Option {
+	return func(c *Client) {
+		if cache == nil || cache.Embedder == nil {
+			c.initErr = errors.New("client: semantic cache requires an embedder")
+			return
+		}
+		c.semanticCache = cache
+	}
+}
+
+// Lookup 查找与 prompt 最相似的缓存条目，返回缓存回复的副本及相似度。
+// vector 为 prompt 的向量，可在未命中时传给 Store 以避免重复计算。
+func (s *SemanticCache) Lookup(ctx context.Context, scope, prompt string) (resp *spec.Response, similarity float64, vector []float32, err error) {
+	vector, err = s.embed(ctx, prompt)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	threshold := s.Threshold
+	if threshold <= 0 {
+		threshold = defaultSemanticThreshold
+	}
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var best *semanticEntry
+	for i := range s.entries {
+		e := &s.entries[i]
+		if e.scope != scope || (s.TTL > 0 && now.Sub(e.storedAt) > s.TTL) {
+			continue
+		}
+		if sim := dot(vector, e.vector); sim >= threshold && sim > similarity {
+			best, similarity = e, sim
+		}
+	}
+	if best == nil {
+		return nil, 0, vector, nil
+	}
+	hit := *best.resp
+	hit.Message = best.resp.Message.Clone()
+	return &hit, similarity, vector, nil
+}
+
+// Store 缓存 prompt 对应的回复，vector 为 nil 时重新计算
+func (s *SemanticCache) Store(ctx context.Context, scope, prompt string, vector []float32, resp *spec.Response) error {
+	if vector == nil {
+		var err error
+		if vector, err = s.embed(ctx, prompt); err != nil {
+			return err
+		}
+	}
+	stored := *resp
+	stored.Message = resp.Message.Clone()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := s.MaxEntries
+	if limit <= 0 {
+		limit = defaultSemanticMaxEntries
+	}
+	if len(s.entries) >= limit {
+		s.entries = append(s.entries[:0], s.entries[len(s.entries)-limit+1:]...)
+	}
+	s.entries = append(s.entries, semanticEntry{scope: scope, vector: vector, resp: &stored, storedAt: time.Now()})
+	return nil
+}
+
+// Len 返回当前缓存条目数
+func (s *SemanticCache) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Clear 清空缓存
+func (s *SemanticCache) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+// embed 计算并归一化 text 的向量
+func (s *SemanticCache) embed(ctx context.Context, text string) ([]float32, error) {
+	resp, err := s.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("semantic cache: failed to embed prompt: %w", err)
+	}
+	if len(resp.Data) == 0 || len(resp.Data[0].Embedding) == 0 {
+		return nil, errors.New("semantic cache: empty embedding response")
+	}
+	return normalize(resp.Data[0].Embedding), nil
+}
+
+// normalize 返回单位长度的向量副本，使余弦相似度可以直接用点积计算
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// dot 计算两个向量的点积，长度不同时返回 0
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// semanticKey 判断消息列表是否可以使用语义缓存，返回匹配范围和待比较的问题文本。
+// 包含助手回复（即存在历史对话）或图片等非文本内容的调用不使用缓存。
+// 匹配范围包含 Provider、模型、请求参数和系统提示词，参数不同的调用不会互相命中
+func semanticKey(cfg llm.Config, messages []spec.Message) (scope, prompt string, ok bool) {
+	if len(messages) == 0 {
+		return "", "", false
+	}
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			if p.Type != "text" {
+				return "", "", false
+			}
+		}
+	}
+	last := messages[len(messages)-1]
+	if last.Role != spec.RoleUser {
+		return "", "", false
+	}
+	params, err := json.Marshal(struct {
+		Parameters   map[string]any
+		ProviderOpts map[string]any
+		Thinking     *bool
+		Translation  *spec.TranslationOptions
+		WebExtractor *llm.WebExtractorOptions
+	}{cfg.Parameters, cfg.ProviderOpts, cfg.Thinking, cfg.Translation, cfg.WebExtractor})
+	if err != nil {
+		return "", "", false
+	}
+	scope = cfg.Provider + "\x00" + cfg.Model + "\x00" + string(params)
+	for _, m := range messages[:len(messages)-1] {
+		if m.Role != spec.RoleSystem {
+			return "", "", false
+		}
+		scope += "\x00" + m.PlainText()
+	}
+	prompt = last.PlainText()
+	return scope, prompt, prompt != ""
+}
+
+// cachedReply 查询语义缓存，命中时返回回复；未命中时返回用于写入缓存的 store 函数
+func (c *Client) cachedReply(ctx context.Context, cfg llm.Config, messages []spec.Message) (*spec.Response, func(*spec.Response), error) {
+	cache := c.semanticCache
+	if cache == nil {
+		return nil, nil, nil
+	}
+	scope, prompt, ok := semanticKey(cfg, messages)
+	if !ok {
+		return nil, nil, nil
+	}
+	hit, _, vector, err := cache.Lookup(ctx, scope, prompt)
+	if err != nil {
+		c.logger().Warn("llm semantic cache lookup failed", "error", err)
+		return nil, nil, nil
+	}
+	if hit != nil {
+		if cfg.StreamCallback != nil {
+			if err := cfg.StreamCallback(ctx, hit.Message.Content); err != nil {
+				return nil, nil, err
+			}
+		}
+		return hit, nil, nil
+	}
+	return nil, func(resp *spec.Response) {
+		if err := cache.Store(ctx, scope, prompt, vector, resp); err != nil {
+			c.logger().Warn("llm semantic cache store failed", "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// sessionLoadTimeout 是创建会话时加载和写入历史的超时时间
+const sessionLoadTimeout = 30 * time.Second
+
+// SessionManager 按会话 ID 管理多个有状态的 Client 实例，适用于多用户的 Web 后端。
+// 会话在首次访问时创建（配置了 HistoryStore 时会从存储中加载历史），
+// 超过 TTL 未被访问的会话会被自动回收并关闭其 Client，正在 Do 中使用的会话不会被回收；
+// 持久化的历史不受回收影响，下次访问时重新加载。
+type SessionManager struct {
+	cfg   llm.Config
+	store HistoryStore
+	ttl   time.Duration
+	opts  []Option
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	closed   bool
+
+	// lifetime 在 Close 时取消，用于停止回收协程和中断进行中的历史加载
+	lifetime context.Context
+	shutdown context.CancelFunc
+}
+
+// session 是 SessionManager 内部的会话条目
+type session struct {
+	// mu 串行化同一会话上的多轮调用：Client 的每个方法是并发安全的，
+	// 但并发的 Send 会交错写入历史，导致对话顺序错乱
+	mu sync.Mutex
+
+	// ready 在 Client 创建完成后关闭，之后 client 和 err 不再改变
+	ready  chan struct{}
+	client *Client
+	err    error
+
+	// 以下字段由 SessionManager.mu 保护
+	lastUsed time.Time
+	inUse    int  // 正在执行的 Do 数量，大于 0 时不会被回收
+	evicted  bool // 已从 sessions 中移除，最后一个 Do 结束后关闭 Client
+}
+
+// NewSessionManager 创建一个会话管理器。
+// store 为 nil 时会话历史只保存在内存中；ttl <= 0 表示会话永不过期。
+// opts 会应用到每一个新建的 Client 上。
+func NewSessionManager(cfg llm.Config, store HistoryStore, ttl time.Duration, opts ...Option) *SessionManager {
+	m := &SessionManager{
+		cfg:      cfg,
+		store:    store,
+		ttl:      ttl,
+		opts:     opts,
+		sessions: make(map[string]*session),
+	}
+	m.lifetime, m.shutdown = context.WithCancel(context.Background())
+	if ttl > 0 {
+		go m.janitor()
+	}
+	return m
+}
+
+// Get 返回会话对应的 Client，不存在时自动创建。
+// 同一会话存在并发请求时请使用 Do；Get 不会阻止会话被回收，回收后 Client 会被关闭，
+// 需要长时间使用时也请使用 Do。
+func (m *SessionManager) Get(sessionID string) (*Client, error) {
+	s, err := m.session(sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.client, nil
+}
+
+// Do 在持有会话锁的情况下执行 fn，保证同一会话上的调用按顺序进行。
+// fn 执行期间会话不会被回收。
+func (m *SessionManager) Do(sessionID string, fn func(c *Client) error) error {
+	s, err := m.session(sessionID, true)
+	if err != nil {
+		return err
+	}
+	defer m.unpin(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.client)
+}
+
+// unpin 结束一次 Do，会话已被移除且没有其他 Do 时关闭 Client
+func (m *SessionManager) unpin(s *session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.inUse--
+	s.lastUsed = time.Now()
+	if s.evicted && s.inUse == 0 && s.client != nil {
+		s.client.close(false)
+	}
+}
+
+// Evict 立即从内存中移除会话并关闭其 Client，持久化的历史不会被删除。
+// 会话正在 Do 中使用时，Client 在 Do 结束后关闭。
+func (m *SessionManager) Evict(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[sessionID]; ok {
+		m.remove(sessionID, s)
+	}
+}
+
+// remove 从 sessions 中移除会话并关闭其 Client，调用方需持有 m.mu。
+// Client 仍在创建中时由 create 在完成后关闭；共享的 HistoryStore 由调用方管理，不会被关闭
+func (m *SessionManager) remove(sessionID string, s *session) {
+	delete(m.sessions, sessionID)
+	s.evicted = true
+	if s.inUse == 0 && s.client != nil {
+		s.client.close(false)
+	}
+}
+
+// Len 返回当前内存中的会话数量
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Close 停止后台回收协程，清空并关闭所有会话的 Client，正在 Do 中使用的会话在 Do 结束后关闭。
+// 关闭后 Get 和 Do 返回 spec.ErrClientClosed。
+func (m *SessionManager) Close() {
+	m.shutdown()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for id, s := range m.sessions {
+		m.remove(id, s)
+	}
+}
+
+// session 获取或创建会话条目并刷新访问时间，pin 为 true 时标记会话正在使用，由调用方通过 unpin 释放。
+// Client 在 m.mu 之外创建，加载历史较慢时不会阻塞其他会话；同一会话的并发请求等待同一次创建
+func (m *SessionManager) session(sessionID string, pin bool) (*session, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("client: session manager: %w", spec.ErrClientClosed)
+	}
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		s = &session{ready: make(chan struct{})}
+		m.sessions[sessionID] = s
+	}
+	s.lastUsed = time.Now()
+	if pin {
+		s.inUse++
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		m.create(sessionID, s)
+	}
+	<-s.ready
+	if s.err != nil {
+		if pin {
+			m.mu.Lock()
+			s.inUse--
+			m.mu.Unlock()
+		}
+		return nil, s.err
+	}
+	return s, nil
+}
+
+// create 为会话条目创建 Client，失败时移除条目，使下次访问重新创建
+func (m *SessionManager) create(sessionID string, s *session) {
+	ctx, cancel := context.WithTimeout(m.lifetime, sessionLoadTimeout)
+	defer cancel()
+	opts := append(append([]Option(nil), m.opts...), withInitContext(ctx))
+	if m.store != nil {
+		opts = append(opts, WithHistoryStore(m.store, sessionID))
+	}
+	c, err := New(m.cfg, opts...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.client, s.err = c, err
+	switch {
+	case err != nil:
+		if m.sessions[sessionID] == s {
+			delete(m.sessions, sessionID)
+		}
+	case s.evicted && s.inUse == 0:
+		c.close(false)
+	}
+	close(s.ready)
+}
+
+// withInitContext 设置 New 中读写 HistoryStore 使用的上下文
+func withInitContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.initCtx = ctx
+	}
+}
+
+// janitor 定期回收超过 TTL 未被访问的会话
+func (m *SessionManager) janitor() {
+	interval := m.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.lifetime.Done():
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			for id, s := range m.sessions {
+				if s.inUse == 0 && now.Sub(s.lastUsed) > m.ttl {
+					m.remove(id, s)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// NodeID 是对话树中节点的标识
+type NodeID int
+
+// RootID 表示虚拟根节点，树中第一层消息的父节点都是 RootID
+const RootID NodeID = -1
+
+// Node 是对话树中的一条消息。同一父节点下的多个子节点代表不同的分支
+// （例如对同一问题的多次重新生成，或用户编辑后的不同提问）。
+type Node struct {
+	ID       NodeID
+	Parent   NodeID
+	Message  spec.Message
+	Children []NodeID
+}
+
+// Tree 是支持分支的对话结构，用于实现类似 ChatGPT 的"编辑并重新生成"界面。
+// Tree 维护一个当前节点（游标），从根到当前节点的路径即为当前生效的对话历史。
+// Tree 是并发安全的。
+type Tree struct {
+	mu      sync.RWMutex
+	nodes   []*Node
+	roots   []NodeID
+	current NodeID
+}
+
+// NewTree 创建一棵空的对话树
+func NewTree() *Tree {
+	return &Tree{current: RootID}
+}
+
+// Add 在 parent 下添加一条消息并返回新节点的 ID，不移动游标。
+func (t *Tree) Add(parent NodeID, msg spec.Message) (NodeID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.add(parent, msg)
+}
+
+// Append 在当前节点下添加一条消息，并将游标移动到新节点。
+func (t *Tree) Append(msg spec.Message) NodeID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id, _ := t.add(t.current, msg)
+	t.current = id
+	return id
+}
+
+// Edit 为节点 id 创建一个内容为 msg 的兄弟分支，并将游标移动到新分支。
+// 原节点及其后续对话保持不变，可以随时切换回去。
+func (t *Tree) Edit(id NodeID, msg spec.Message) (NodeID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, ok := t.get(id)
+	if !ok {
+		return 0, fmt.Errorf("client: tree node %d not found", id)
+	}
+	newID, err := t.add(n.Parent, msg)
+	if err != nil {
+		return 0, err
+	}
+	t.current = newID
+	return newID, nil
+}
+
+// Checkout 将游标移动到节点 id，传入 RootID 表示回到空对话。
+func (t *Tree) Checkout(id NodeID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id != RootID {
+		if _, ok := t.get(id); !ok {
+			return fmt.Errorf("client: tree node %d not found", id)
+		}
+	}
+	t.current = id
+	return nil
+}
+
+// Current 返回游标所在的节点 ID
+func (t *Tree) Current() NodeID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.current
+}
+
+// Node 返回节点的副本
+func (t *Tree) Node(id NodeID) (Node, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n, ok := t.get(id)
+	if !ok {
+		return Node{}, false
+	}
+	cp := *n
+	cp.Children = append([]NodeID(nil), n.Children...)
+	return cp, true
+}
+
+// Children 返回节点的全部子节点，传入 RootID 返回第一层节点
+func (t *Tree) Children(id NodeID) []NodeID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]NodeID(nil), t.children(id)...)
+}
+
+// Siblings 返回与 id 同一父节点的全部节点（包含 id 本身），按创建顺序排列
+func (t *Tree) Siblings(id NodeID) []NodeID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n, ok := t.get(id)
+	if !ok {
+		return nil
+	}
+	return append([]NodeID(nil), t.children(n.Parent)...)
+}
+
+// NextSibling 返回 id 之后的兄弟节点
+func (t *Tree) NextSibling(id NodeID) (NodeID, bool) {
+	return t.sibling(id, 1)
+}
+
+// PrevSibling 返回 id 之前的兄弟节点
+func (t *Tree) PrevSibling(id NodeID) (NodeID, bool) {
+	return t.sibling(id, -1)
+}
+
+// Leaf 从 id 出发沿着最新的分支一直走到叶子节点，常用于切换分支后恢复到该分支的末尾
+func (t *Tree) Leaf(id NodeID) NodeID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for {
+		children := t.children(id)
+		if len(children) == 0 {
+			return id
+		}
+		id = children[len(children)-1]
+	}
+}
+
+// Path 返回从根到节点 id 的消息列表
+func (t *Tree) Path(id NodeID) []spec.Message {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.path(id)
+}
+
+// Messages 返回从根到当前节点的消息列表，即当前生效的对话历史
+func (t *Tree) Messages() []spec.Message {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.path(t.current)
+}
+
+// Len 返回树中的节点总数
+func (t *Tree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.nodes)
+}
+
+func (t *Tree) add(parent NodeID, msg spec.Message) (NodeID, error) {
+	id := NodeID(len(t.nodes))
+	if parent == RootID {
+		t.roots = append(t.roots, id)
+	} else {
+		p, ok := t.get(parent)
+		if !ok {
+			return 0, fmt.Errorf("client: tree node %d not found", parent)
+		}
+		p.Children = append(p.Children, id)
+	}
+	t.nodes = append(t.nodes, &Node{ID: id, Parent: parent, Message: msg})
+	return id, nil
+}
+
+func (t *Tree) get(id NodeID) (*Node, bool) {
+	if id < 0 || int(id) >= len(t.nodes) {
+		return nil, false
+	}
+	return t.nodes[id], true
+}
+
+func (t *Tree) children(id NodeID) []NodeID {
+	if id == RootID {
+		return t.roots
+	}
+	if n, ok := t.get(id); ok {
+		return n.Children
+	}
+	return nil
+}
+
+func (t *Tree) sibling(id NodeID, offset int) (NodeID, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n, ok := t.get(id)
+	if !ok {
+		return 0, false
+	}
+	siblings := t.children(n.Parent)
+	for i, s := range siblings {
+		if s == id {
+			j := i + offset
+			if j < 0 || j >= len(siblings) {
+				return 0, false
+			}
+			return siblings[j], true
+		}
+	}
+	return 0, false
+}
+
+func (t *Tree) path(id NodeID) []spec.Message {
+	var reversed []spec.Message
+	for id != RootID {
+		n, ok := t.get(id)
+		if !ok {
+			break
+		}
+		reversed = append(reversed, n.Message)
+		id = n.Parent
+	}
+	messages := make([]spec.Message, len(reversed))
+	for i, m := range reversed {
+		messages[len(reversed)-1-i] = m
+	}
+	return messages
+}
+
+// SendTree 在对话树的当前节点下追加用户消息并请求回复，回复作为新节点追加，游标移动到回复节点。
+// 树为空且配置了系统提示词时，会先插入系统消息。失败时树的结构和游标保持不变。
+func (c *Client) SendTree(ctx context.Context, t *Tree, userPrompt string) (*spec.Response, error) {
+	start := t.Current()
+	messages := t.Messages()
+	prompt := c.snapshotConfig().SystemPrompt
+	if len(messages) == 0 && prompt != "" {
+		messages = append(messages, spec.NewSystemMessage(prompt))
+	}
+	userMsg := spec.NewUserMessage(userPrompt)
+	messages = append(messages, userMsg)
+
+	resp, err := c.invoke(ctx, messages, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := start
+	if start == RootID && prompt != "" && len(messages) == 2 {
+		parent, _ = t.Add(RootID, messages[0])
+	}
+	userID, err := t.Add(parent, userMsg)
+	if err != nil {
+		return nil, err
+	}
+	replyID, err := t.Add(userID, resp.Message)
+	if err != nil {
+		return nil, err
+	}
+	return resp, t.Checkout(replyID)
+}
+
+// CompleteTree 基于从根到当前节点的历史请求一次回复，并作为当前节点的新子节点追加，游标移动到回复节点。
+// 典型用法是在 Tree.Edit 修改用户提问后生成新的回答。
+func (c *Client) CompleteTree(ctx context.Context, t *Tree) (*spec.Response, error) {
+	current := t.Current()
+	resp, err := c.invoke(ctx, t.Path(current), nil)
+	if err != nil {
+		return nil, err
+	}
+	replyID, err := t.Add(current, resp.Message)
+	if err != nil {
+		return nil, err
+	}
+	return resp, t.Checkout(replyID)
+}
+
+// RegenerateTree 为当前的助手回复生成一个兄弟分支（重新生成），游标移动到新回复。
+// 当前节点必须是助手消息。
+func (c *Client) RegenerateTree(ctx context.Context, t *Tree) (*spec.Response, error) {
+	n, ok := t.Node(t.Current())
+	if !ok || n.Message.Role != spec.RoleAssistant {
+		return nil, fmt.Errorf("client: current tree node is not an assistant message")
+	}
+	if err := t.Checkout(n.Parent); err != nil {
+		return nil, err
+	}
+	resp, err := c.CompleteTree(ctx, t)
+	if err != nil {
+		t.Checkout(n.ID)
+		return nil, err
+	}
+	return resp, nil
+}
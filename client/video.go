@@ -0,0 +1,16 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// GenerateVideo 使用当前配置的模型生成视频（如 wanx2.1-t2v-turbo、sora-2），阻塞直到任务完成
+func (c *Client) GenerateVideo(ctx context.Context, req spec.VideoRequest, opts ...spec.VideoOption) (*spec.Video, error) {
+	if p, ok := c.client.(spec.VideoModelProvider); ok {
+		return p.VideoModel(c.model()).GenerateVideo(ctx, req, opts...)
+	}
+	return nil, fmt.Errorf("provider '%s' does not support video generation (VideoModelProvider interface not implemented)", c.config.Provider)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/client"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// chatOutput 是 --json 输出的结果
+type chatOutput struct {
+	Content   string          `json:"content"`
+	Reasoning string          `json:"reasoning,omitempty"`
+	ToolCalls []spec.ToolCall `json:"tool_calls,omitempty"`
+	Provider  string          `json:"provider,omitempty"`
+	Model     string          `json:"model,omitempty"`
+	Usage     spec.Usage      `json:"usage"`
+	RequestID string          `json:"request_id,omitempty"`
+	// DurationMS 调用总耗时（毫秒）
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// runChat 发送一次对话。问题来自参数和标准输入：两者都有时标准输入的内容附加在问题之后，
+// 便于 "cat file | llm chat 总结这段代码" 这样的用法
+func runChat(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("llm chat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "用法：llm chat [flags] [question]")
+		fs.PrintDefaults()
+	}
+	var f connFlags
+	f.register(fs)
+	var stream, asJSON bool
+	fs.BoolVar(&stream, "stream", false, "流式输出")
+	fs.BoolVar(&asJSON, "json", false, "以 JSON 输出结果（包含用量和耗时），与 --stream 同时使用时每行输出一个增量事件")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	prompt, err := readPrompt(strings.Join(fs.Args(), " "), stdin)
+	if err != nil {
+		return err
+	}
+	cfg, err := f.clientConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	var resp *spec.Response
+	// streamed 记录是否收到过增量，不支持流式的 Provider 直接返回完整响应
+	streamed := false
+	if stream {
+		resp, err = c.SendStreamNoHistory(ctx, prompt, func(_ context.Context, chunk string) error {
+			streamed = true
+			if asJSON {
+				return enc.Encode(map[string]string{"delta": chunk})
+			}
+			_, err := io.WriteString(stdout, chunk)
+			return err
+		})
+	} else {
+		resp, err = c.SendNoHistory(ctx, prompt)
+	}
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return enc.Encode(chatOutput{
+			Content:    resp.Message.Content,
+			Reasoning:  resp.Message.ReasoningContent,
+			ToolCalls:  resp.Message.ToolCalls,
+			Provider:   resp.Provider,
+			Model:      resp.Model,
+			Usage:      resp.Usage,
+			RequestID:  resp.RequestID,
+			DurationMS: resp.Timing.Duration.Milliseconds(),
+		})
+	}
+	if !streamed {
+		_, err = io.WriteString(stdout, resp.Message.Content)
+	}
+	if err == nil {
+		_, err = io.WriteString(stdout, "\n")
+	}
+	return err
+}
+
+// readPrompt 合并参数中的问题和管道输入的内容，标准输入是终端时不读取
+func readPrompt(question string, stdin io.Reader) (string, error) {
+	var piped string
+	if !isTerminal(stdin) {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		piped = strings.TrimSpace(string(data))
+	}
+	switch {
+	case question == "" && piped == "":
+		return "", fmt.Errorf("%w: no question given, pass it as an argument or through stdin", errUsage)
+	case piped == "":
+		return question, nil
+	case question == "":
+		return piped, nil
+	default:
+		return question + "\n\n" + piped, nil
+	}
+}
+
+// isTerminal 判断 r 是否为交互式终端
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
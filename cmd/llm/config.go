@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/client"
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// configEnv 指定配置文件路径的环境变量
+const configEnv = "LLM_CONFIG"
+
+// connFlags 是选择 Provider 和模型的公共参数
+type connFlags struct {
+	provider    string
+	model       string
+	apiKey      string
+	apiURL      string
+	profile     string
+	config      string
+	system      string
+	temperature float64
+	maxTokens   int
+	timeout     time.Duration
+}
+
+// register 在 fs 上注册参数，常用参数同时提供短名称
+func (f *connFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.provider, "p", "", "Provider，如 dashscope、openai、deepseek")
+	fs.StringVar(&f.provider, "provider", "", "同 -p")
+	fs.StringVar(&f.model, "m", "", "模型名称或别名，使用配置文件时覆盖配置中的模型")
+	fs.StringVar(&f.model, "model", "", "同 -m")
+	fs.StringVar(&f.apiKey, "api-key", "", "API Key，默认从 Provider 对应的环境变量读取（如 DASHSCOPE_API_KEY）")
+	fs.StringVar(&f.apiURL, "api-url", "", "自定义 chat/completions 接口地址")
+	fs.StringVar(&f.profile, "profile", "", "配置文件中的命名配置，默认使用 "+llm.ProfileEnv+" 环境变量或文件中的 default")
+	fs.StringVar(&f.config, "config", "", "配置文件路径，默认依次查找 "+configEnv+"、./llm.yaml、./llm.json 和用户配置目录下的 llm/config.yaml")
+	fs.StringVar(&f.system, "system", "", "系统提示词")
+	fs.Float64Var(&f.temperature, "t", -1, "temperature，默认使用模型的默认值")
+	fs.IntVar(&f.maxTokens, "max-tokens", 0, "最大输出 token 数")
+	fs.DurationVar(&f.timeout, "timeout", 0, "整体超时，如 60s")
+}
+
+// clientConfig 返回连接配置：指定了 -p 时直接使用命令行参数，否则从配置文件读取命名配置
+func (f *connFlags) clientConfig() (llm.Config, error) {
+	var cfg llm.Config
+	if f.provider != "" && f.profile != "" {
+		return llm.Config{}, fmt.Errorf("%w: -p and --profile cannot be used together", errUsage)
+	}
+	if f.provider != "" {
+		b := client.NewBuilder().Provider(f.provider).Model(f.model).APIURL(f.apiURL)
+		if f.apiKey != "" {
+			b.APIKey(f.apiKey)
+		} else {
+			b.APIKeyFromEnv()
+		}
+		var err error
+		if cfg, err = b.Config(); err != nil {
+			return llm.Config{}, err
+		}
+	} else {
+		profiles, err := f.loadProfiles()
+		if err != nil {
+			return llm.Config{}, err
+		}
+		if cfg, err = profiles.Config(f.profile); err != nil {
+			return llm.Config{}, err
+		}
+		if f.model != "" {
+			cfg.Model = f.model
+		}
+		if f.apiKey != "" {
+			cfg.APIKey, cfg.APIKeyPool = f.apiKey, nil
+		}
+		if f.apiURL != "" {
+			cfg.APIURL = f.apiURL
+		}
+	}
+
+	if f.system != "" {
+		cfg.SystemPrompt = f.system
+	}
+	if f.temperature >= 0 {
+		cfg.Parameters = setParam(cfg.Parameters, "temperature", f.temperature)
+	}
+	if f.maxTokens > 0 {
+		cfg.Parameters = setParam(cfg.Parameters, "max_tokens", f.maxTokens)
+	}
+	if f.timeout > 0 {
+		t := spec.DefaultTimeouts()
+		t.Total, t.ResponseHeader = f.timeout, f.timeout
+		cfg.Timeouts = &t
+	}
+	return cfg, nil
+}
+
+// setParam 设置参数，不修改配置中原有的 map
+func setParam(params map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(params)+1)
+	for k, v := range params {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// loadProfiles 加载配置文件
+func (f *connFlags) loadProfiles() (*llm.Profiles, error) {
+	path, err := configPath(f.config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := llm.ParseProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return profiles, nil
+}
+
+// configPath 返回配置文件路径，explicit 为空时依次查找环境变量、当前目录和用户配置目录
+func configPath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if path := os.Getenv(configEnv); path != "" {
+		return path, nil
+	}
+	candidates := []string{"llm.yaml", "llm.yml", "llm.json"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, "llm", "config.yaml"), filepath.Join(dir, "llm", "config.json"))
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: specify -p provider -m model, or create a config file (%s)", errUsage, candidates[0])
+}
+
+// runProviders 列出支持的 Provider
+func runProviders(stdout io.Writer) error {
+	for _, name := range llm.SupportedProviders() {
+		fmt.Fprintln(stdout, name)
+	}
+	return nil
+}
+
+// runProfiles 列出配置文件中的命名配置，默认配置以 * 标记
+func runProfiles(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("llm profiles", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var f connFlags
+	fs.StringVar(&f.config, "config", "", "配置文件路径")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	profiles, err := f.loadProfiles()
+	if err != nil {
+		return err
+	}
+	for _, name := range profiles.Names() {
+		mark := " "
+		if name == profiles.Default {
+			mark = "*"
+		}
+		line := fmt.Sprintf("%s %s", mark, name)
+		// 配置引用的环境变量未设置时只列出名称
+		if p, err := profiles.Profile(name); err == nil {
+			line += fmt.Sprintf("\t%s/%s", p.Provider, p.Model)
+		}
+		fmt.Fprintln(stdout, line)
+	}
+	return nil
+}
+
+// parseFlags 解析参数，把参数错误归类为 errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Command llm 是命令行工具，用于冒烟测试各 Provider 以及在 Shell 脚本中调用模型：
+//
+//	llm chat -p dashscope -m qwen-plus "什么是向量数据库？"
+//	cat main.go | llm chat --profile prod-qwen --system "你是代码审查助手" "找出潜在的 bug"
+//	llm chat -p openai -m gpt-4o-mini --json "hi" | jq .content
+//	llm repl --profile prod-qwen
+//	llm providers
+//	llm profiles
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `llm 是 go-llm-client 的命令行工具
+
+用法：
+  llm chat [flags] [question]   发送一次对话，问题也可以通过标准输入传入
+  llm repl [flags]              交互式多轮对话
+  llm providers                 列出支持的 Provider
+  llm profiles [--config file]  列出配置文件中的命名配置
+
+使用 "llm <command> -h" 查看命令的参数。
+`
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, "llm:", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "llm:", err)
+		os.Exit(1)
+	}
+}
+
+// errUsage 表示命令行参数错误，退出码为 2
+var errUsage = errors.New("invalid usage")
+
+// run 执行 args 指定的子命令
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "chat":
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		return runChat(ctx, rest, stdin, stdout, stderr)
+	case "repl":
+		// REPL 自行处理 Ctrl-C，只中断当前回复
+		return runREPL(ctx, rest, stdin, stdout, stderr)
+	case "providers":
+		return runProviders(stdout)
+	case "profiles":
+		return runProfiles(rest, stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+}
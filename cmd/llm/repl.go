@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/client"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/transcript"
+)
+
+const replHelp = `命令：
+  /reset            清空对话历史（保留系统提示词）
+  /system [prompt]  查看或设置系统提示词，"/system -" 移除系统提示词
+  /save <file>      保存对话记录，.html 后缀保存为 HTML，其他保存为 Markdown
+  /help             显示帮助
+  /exit             退出（也可以按 Ctrl-D）
+以 \ 结尾的行会与下一行合并为一条消息；回复过程中按 Ctrl-C 中断本次回复。
+`
+
+// runREPL 启动交互式对话，历史由 client.Client 维护，回复逐个 token 流式输出。
+// 提示符和提示信息写入 stderr，stdout 只包含模型回复，便于重定向保存
+func runREPL(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("llm repl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "用法：llm repl [flags]")
+		fs.PrintDefaults()
+	}
+	var f connFlags
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: llm repl takes no arguments, use llm chat for one-off questions", errUsage)
+	}
+	cfg, err := f.clientConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	r := &repl{client: c, title: cfg.Provider + "/" + cfg.Model, stdout: stdout, stderr: stderr}
+	fmt.Fprintf(stderr, "%s，输入 /help 查看命令\n", r.title)
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var pending []string
+	for {
+		if len(pending) == 0 {
+			fmt.Fprint(stderr, "> ")
+		} else {
+			fmt.Fprint(stderr, ". ")
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(stderr)
+			return scanner.Err()
+		}
+		line := scanner.Text()
+		if strings.HasSuffix(line, `\`) {
+			pending = append(pending, strings.TrimSuffix(line, `\`))
+			continue
+		}
+		input := strings.TrimSpace(strings.Join(append(pending, line), "\n"))
+		pending = nil
+		switch {
+		case input == "":
+		case strings.HasPrefix(input, "/"):
+			if done := r.command(input); done {
+				return nil
+			}
+		default:
+			r.send(ctx, input)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// repl 是交互式对话的状态
+type repl struct {
+	client *client.Client
+	title  string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// send 流式发送一条消息，Ctrl-C 只中断本次回复。出错时输出到 stderr 并继续对话
+func (r *repl) send(ctx context.Context, input string) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	streamed := false
+	resp, err := r.client.SendStream(ctx, input, func(_ context.Context, chunk string) error {
+		streamed = true
+		_, err := io.WriteString(r.stdout, chunk)
+		return err
+	})
+	switch {
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		fmt.Fprintln(r.stderr, "\n[已中断]")
+		return
+	case err != nil:
+		if streamed {
+			fmt.Fprintln(r.stdout)
+		}
+		fmt.Fprintln(r.stderr, "error:", err)
+		return
+	}
+	// 不支持流式的 Provider 直接返回完整响应
+	if !streamed {
+		io.WriteString(r.stdout, resp.Message.Content)
+	}
+	fmt.Fprintln(r.stdout)
+}
+
+// command 执行斜杠命令，返回 true 表示退出
+func (r *repl) command(input string) bool {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "/exit", "/quit":
+		return true
+	case "/help":
+		fmt.Fprint(r.stderr, replHelp)
+	case "/reset":
+		r.client.ResetHistory()
+		fmt.Fprintln(r.stderr, "对话历史已清空")
+	case "/system":
+		r.system(arg)
+	case "/save":
+		if arg == "" {
+			fmt.Fprintln(r.stderr, "用法：/save <file>")
+			break
+		}
+		if err := r.save(arg); err != nil {
+			fmt.Fprintln(r.stderr, "error:", err)
+			break
+		}
+		fmt.Fprintln(r.stderr, "已保存到", arg)
+	default:
+		fmt.Fprintf(r.stderr, "未知命令 %s，输入 /help 查看命令\n", name)
+	}
+	return false
+}
+
+// system 查看或设置系统提示词
+func (r *repl) system(arg string) {
+	if arg == "" {
+		history := r.client.GetHistory()
+		if len(history) > 0 && history[0].Role == spec.RoleSystem {
+			fmt.Fprintln(r.stderr, history[0].Content)
+		} else {
+			fmt.Fprintln(r.stderr, "未设置系统提示词")
+		}
+		return
+	}
+	if arg == "-" {
+		arg = ""
+	}
+	if err := r.client.SetSystemPrompt(arg); err != nil {
+		fmt.Fprintln(r.stderr, "error:", err)
+		return
+	}
+	fmt.Fprintln(r.stderr, "系统提示词已更新")
+}
+
+// save 把对话历史保存为 Markdown 或 HTML
+func (r *repl) save(path string) error {
+	opts := transcript.Options{Title: r.title}
+	history := r.client.GetHistory()
+	if strings.EqualFold(filepath.Ext(path), ".html") {
+		out, err := transcript.HTML(history, opts)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(out), 0o644)
+	}
+	return os.WriteFile(path, []byte(transcript.Markdown(history, opts)), 0o644)
+}
@@ -0,0 +1,124 @@
+// Package cost 根据模型单价和 spec.Usage 计算并累计调用费用。
+package cost
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Currency 表示计价货币
+type Currency string
+
+const (
+	USD Currency = "USD"
+	CNY Currency = "CNY"
+)
+
+// Price 是模型的单价，单位为每百万 token。
+type Price struct {
+	// Input 输入单价
+	Input float64
+	// Output 输出单价（包含思考过程）
+	Output float64
+	// CachedInput 命中上下文缓存的输入单价，为 0 时按 Input 计价
+	CachedInput float64
+	// Currency 计价货币
+	Currency Currency
+}
+
+// Cost 计算一次调用的费用
+func (p Price) Cost(u spec.Usage) float64 {
+	cached := min(u.CachedTokens, u.PromptTokens)
+	cachedPrice := p.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = p.Input
+	}
+	total := float64(u.PromptTokens-cached)*p.Input +
+		float64(cached)*cachedPrice +
+		float64(u.CompletionTokens)*p.Output
+	return total / 1e6
+}
+
+// defaultPrices 是内置的参考价格（各提供商官网公开的标准价格，不含阶梯与折扣），
+// 价格会随时调整，生产环境请通过 Table.Set 覆盖。
+var defaultPrices = map[string]Price{
+	// OpenAI
+	"gpt-4o":       {Input: 2.5, Output: 10, CachedInput: 1.25, Currency: USD},
+	"gpt-4o-mini":  {Input: 0.15, Output: 0.6, CachedInput: 0.075, Currency: USD},
+	"gpt-4.1":      {Input: 2, Output: 8, CachedInput: 0.5, Currency: USD},
+	"gpt-4.1-mini": {Input: 0.4, Output: 1.6, CachedInput: 0.1, Currency: USD},
+	"gpt-4.1-nano": {Input: 0.1, Output: 0.4, CachedInput: 0.025, Currency: USD},
+	"o3-mini":      {Input: 1.1, Output: 4.4, CachedInput: 0.55, Currency: USD},
+
+	// 阿里云百炼 (DashScope)
+	"qwen-max":   {Input: 2.4, Output: 9.6, Currency: CNY},
+	"qwen-plus":  {Input: 0.8, Output: 2, Currency: CNY},
+	"qwen-turbo": {Input: 0.3, Output: 0.6, Currency: CNY},
+	"qwen-flash": {Input: 0.15, Output: 1.5, Currency: CNY},
+	"qwen3-max":  {Input: 6, Output: 24, Currency: CNY},
+
+	// DeepSeek
+	"deepseek-chat":     {Input: 2, Output: 3, CachedInput: 0.2, Currency: CNY},
+	"deepseek-reasoner": {Input: 2, Output: 3, CachedInput: 0.2, Currency: CNY},
+}
+
+// Table 是可并发访问的模型价格表
+type Table struct {
+	mu     sync.RWMutex
+	prices map[string]Price
+}
+
+// NewTable 创建一个空的价格表
+func NewTable() *Table {
+	return &Table{prices: make(map[string]Price)}
+}
+
+// DefaultTable 返回包含内置参考价格的价格表副本，可以放心修改
+func DefaultTable() *Table {
+	t := NewTable()
+	for model, p := range defaultPrices {
+		t.prices[model] = p
+	}
+	return t
+}
+
+// Set 设置或覆盖模型的单价
+func (t *Table) Set(model string, p Price) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prices[strings.ToLower(model)] = p
+}
+
+// Lookup 查找模型的单价。
+// 依次尝试完整名称、去掉 "openai/" 这类路由前缀后的名称，以及最长前缀匹配
+// （例如 "gpt-4o-2024-08-06" 匹配 "gpt-4o"，"gpt-4o-mini-2024-07-18" 匹配 "gpt-4o-mini"）。
+func (t *Table) Lookup(model string) (Price, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	model = strings.ToLower(model)
+	candidates := []string{model}
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		candidates = append(candidates, model[i+1:])
+	}
+	for _, name := range candidates {
+		if p, ok := t.prices[name]; ok {
+			return p, true
+		}
+	}
+
+	var best string
+	for _, name := range candidates {
+		for prefix := range t.prices {
+			if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+				best = prefix
+			}
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t.prices[best], true
+}
@@ -0,0 +1,120 @@
+package cost
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Summary 是某个客户端或会话的累计用量与费用
+type Summary struct {
+	// Requests 已记录的调用次数
+	Requests int
+	// Usage 累计 token 用量
+	Usage spec.Usage
+	// Cost 按货币分别累计的费用
+	Cost map[Currency]float64
+	// Unpriced 价格表中找不到单价的调用次数，这些调用未计入 Cost
+	Unpriced int
+}
+
+// Tracker 按键（客户端名、会话 ID 等）累计调用费用，可并发使用。
+type Tracker struct {
+	table *Table
+
+	mu     sync.Mutex
+	totals map[string]*Summary
+}
+
+// NewTracker 使用指定价格表创建 Tracker，table 为 nil 时使用 DefaultTable()
+func NewTracker(table *Table) *Tracker {
+	if table == nil {
+		table = DefaultTable()
+	}
+	return &Tracker{table: table, totals: make(map[string]*Summary)}
+}
+
+// Table 返回 Tracker 使用的价格表，可用于覆盖单价
+func (t *Tracker) Table() *Table {
+	return t.table
+}
+
+// Record 记录一次调用的用量，返回本次费用及其货币；找不到单价时 ok 为 false。
+func (t *Tracker) Record(key, model string, usage spec.Usage) (amount float64, currency Currency, ok bool) {
+	price, ok := t.table.Lookup(model)
+	if ok {
+		amount, currency = price.Cost(usage), price.Currency
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, exists := t.totals[key]
+	if !exists {
+		s = &Summary{Cost: make(map[Currency]float64)}
+		t.totals[key] = s
+	}
+	s.Requests++
+	s.Usage = s.Usage.Add(usage)
+	if ok {
+		s.Cost[currency] += amount
+	} else {
+		s.Unpriced++
+	}
+	return amount, currency, ok
+}
+
+// RecordResponse 使用响应中的模型名和用量记录一次调用，响应未携带模型名时使用 model
+func (t *Tracker) RecordResponse(key, model string, resp *spec.Response) (float64, Currency, bool) {
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	return t.Record(key, model, resp.Usage)
+}
+
+// Summary 返回 key 的累计结果
+func (t *Tracker) Summary(key string) Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.totals[key]
+	if !ok {
+		return Summary{Cost: map[Currency]float64{}}
+	}
+	out := *s
+	out.Cost = maps.Clone(s.Cost)
+	return out
+}
+
+// Total 返回所有键的累计结果之和
+func (t *Tracker) Total() Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := Summary{Cost: make(map[Currency]float64)}
+	for _, s := range t.totals {
+		total.Requests += s.Requests
+		total.Unpriced += s.Unpriced
+		total.Usage = total.Usage.Add(s.Usage)
+		for c, v := range s.Cost {
+			total.Cost[c] += v
+		}
+	}
+	return total
+}
+
+// Keys 返回所有已记录的键
+func (t *Tracker) Keys() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	keys := make([]string, 0, len(t.totals))
+	for k := range t.totals {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Reset 清除 key 的累计结果
+func (t *Tracker) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.totals, key)
+}
@@ -0,0 +1,203 @@
+// Package eval 用测试用例评估模型和提示词：对一个或多个配置运行用例，
+// 用精确匹配、正则或评审模型打分，并生成对比报告，用于回归测试提示词和提供商的改动。
+//
+//	cases := []eval.Case{
+//		{Name: "capital", Prompt: "法国的首都是哪里？只回答城市名", Expected: "巴黎", Graders: []eval.Grader{eval.Contains(false)}},
+//		{Name: "poem", Prompt: "写一首关于秋天的五言绝句", Expected: "符合五言绝句格式，主题为秋天"},
+//	}
+//	report, err := eval.Run(ctx, cases, []llm.Config{cfgA, cfgB}, eval.WithGraders(eval.Judge(judgeCfg, 0.7)))
+//	report.WriteText(os.Stdout)
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultConcurrency 是默认的并发数
+const defaultConcurrency = 4
+
+// Case 是一个测试用例
+type Case struct {
+	// Name 用例名称，在报告中标识用例
+	Name string
+	// Prompt 用户提示词，Messages 非空时忽略
+	Prompt string
+	// Messages 完整的输入消息，用于多轮或带系统提示词的用例
+	Messages []spec.Message
+	// Expected 期望的输出或评分标准，具体含义由评分器决定
+	Expected string
+	// Graders 该用例的评分器，nil 时使用 WithGraders 设置的默认评分器
+	Graders []Grader
+}
+
+// messages 返回用例的输入消息
+func (c Case) messages() []spec.Message {
+	if len(c.Messages) > 0 {
+		return c.Messages
+	}
+	return []spec.Message{spec.NewUserMessage(c.Prompt)}
+}
+
+// input 返回用例的问题文本，用于评审模型
+func (c Case) input() string {
+	messages := c.messages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == spec.RoleUser {
+			return messages[i].PlainText()
+		}
+	}
+	return c.Prompt
+}
+
+// Result 是一个用例在一个配置上的运行结果
+type Result struct {
+	Case   string `json:"case"`
+	Target string `json:"target"`
+	Output string `json:"output"`
+	// Scores 各评分器的评分
+	Scores []Score `json:"scores"`
+	// Score 各评分器分数的平均值
+	Score float64 `json:"score"`
+	// Pass 是否通过全部评分器，调用或评分出错时为 false
+	Pass bool `json:"pass"`
+	// Error 调用模型或评分时的错误
+	Error   string        `json:"error,omitempty"`
+	Latency time.Duration `json:"latency"`
+	Usage   spec.Usage    `json:"usage"`
+}
+
+// options 是 Run 的配置
+type options struct {
+	graders     []Grader
+	concurrency int
+	hooks       []func(Result)
+}
+
+// Option 用于配置 Run
+type Option func(o *options)
+
+// WithGraders 设置没有指定 Graders 的用例使用的评分器，默认为 Exact
+func WithGraders(graders ...Grader) Option {
+	return func(o *options) {
+		o.graders = graders
+	}
+}
+
+// WithConcurrency 设置并发运行的用例数，默认为 4
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithResultHook 注册在每个结果完成后调用的回调，可用于显示进度，回调可能被并发调用
+func WithResultHook(hook func(Result)) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// Target 返回配置在报告中的名称，形如 "dashscope/qwen-plus"，重复的名称在报告中会加上 "#2" 等序号
+func Target(cfg llm.Config) string {
+	return cfg.Provider + "/" + cfg.Model
+}
+
+// Run 在每个配置上运行全部用例并评分。单个用例调用失败只记录在结果中，不会中止运行；
+// 只有 ctx 被取消时返回错误，此时报告包含已完成的结果
+func Run(ctx context.Context, cases []Case, cfgs []llm.Config, opts ...Option) (*Report, error) {
+	o := options{graders: []Grader{Exact()}, concurrency: defaultConcurrency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency <= 0 {
+		o.concurrency = defaultConcurrency
+	}
+
+	report := &Report{Results: make([]Result, 0, len(cases)*len(cfgs))}
+	seen := make(map[string]int)
+	for _, cfg := range cfgs {
+		// 同一模型以不同参数参与对比时，用序号区分
+		name := Target(cfg)
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		report.Targets = append(report.Targets, name)
+	}
+	results := make([]*Result, len(cases)*len(cfgs))
+	sem := make(chan struct{}, o.concurrency)
+	var wg sync.WaitGroup
+	for ci, cfg := range cfgs {
+		cfg.StreamCallback = nil
+		for i, c := range cases {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				r := runCase(ctx, c, cfg, o.graders)
+				r.Target = report.Targets[ci]
+				for _, hook := range o.hooks {
+					hook(r)
+				}
+				results[ci*len(cases)+i] = &r
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r != nil {
+			report.Results = append(report.Results, *r)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("eval: %w", err)
+	}
+	return report, nil
+}
+
+// runCase 在一个配置上运行一个用例并评分
+func runCase(ctx context.Context, c Case, cfg llm.Config, graders []Grader) Result {
+	r := Result{Case: c.Name}
+	start := time.Now()
+	resp, err := llm.ChatMessages(ctx, c.messages(), cfg)
+	r.Latency = time.Since(start)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Output = resp.Message.PlainText()
+	r.Usage = resp.Usage
+
+	if c.Graders != nil {
+		graders = c.Graders
+	}
+	r.Pass = true
+	for _, g := range graders {
+		s, err := g.Grade(ctx, c, r.Output)
+		if err != nil {
+			r.Error = fmt.Sprintf("%s: %v", g.Name(), err)
+			r.Pass = false
+			s = Score{Grader: g.Name()}
+		}
+		r.Scores = append(r.Scores, s)
+		r.Score += s.Value
+		r.Pass = r.Pass && s.Pass
+	}
+	if len(r.Scores) > 0 {
+		r.Score /= float64(len(r.Scores))
+	}
+	return r
+}
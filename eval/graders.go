@@ -0,0 +1,131 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/outputs"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Score 是一个评分器对一次输出的评分
+type Score struct {
+	// Grader 评分器名称
+	Grader string `json:"grader"`
+	// Value 分数，范围 0 到 1
+	Value float64 `json:"value"`
+	// Pass 是否通过
+	Pass bool `json:"pass"`
+	// Reason 评分说明
+	Reason string `json:"reason,omitempty"`
+}
+
+// Grader 评估模型对一个用例的输出
+type Grader interface {
+	Name() string
+	Grade(ctx context.Context, c Case, output string) (Score, error)
+}
+
+// GraderFunc 用函数创建 Grader，fn 返回的 Score.Grader 为空时使用 name
+func GraderFunc(name string, fn func(ctx context.Context, c Case, output string) (Score, error)) Grader {
+	return funcGrader{name: name, fn: fn}
+}
+
+type funcGrader struct {
+	name string
+	fn   func(ctx context.Context, c Case, output string) (Score, error)
+}
+
+func (g funcGrader) Name() string { return g.name }
+
+func (g funcGrader) Grade(ctx context.Context, c Case, output string) (Score, error) {
+	s, err := g.fn(ctx, c, output)
+	if s.Grader == "" {
+		s.Grader = g.name
+	}
+	return s, err
+}
+
+// boolScore 把通过与否转换为 0 或 1 分
+func boolScore(name string, pass bool, reason string) Score {
+	s := Score{Grader: name, Pass: pass}
+	if pass {
+		s.Value = 1
+	} else {
+		s.Reason = reason
+	}
+	return s
+}
+
+// Exact 要求输出（去除首尾空白后）与 Case.Expected 完全相同
+func Exact() Grader {
+	return GraderFunc("exact", func(ctx context.Context, c Case, output string) (Score, error) {
+		got := strings.TrimSpace(output)
+		return boolScore("exact", got == strings.TrimSpace(c.Expected), fmt.Sprintf("expected %q, got %q", c.Expected, got)), nil
+	})
+}
+
+// Contains 要求输出包含 Case.Expected，ignoreCase 为 true 时忽略大小写
+func Contains(ignoreCase bool) Grader {
+	return GraderFunc("contains", func(ctx context.Context, c Case, output string) (Score, error) {
+		got, want := output, c.Expected
+		if ignoreCase {
+			got, want = strings.ToLower(got), strings.ToLower(want)
+		}
+		return boolScore("contains", strings.Contains(got, want), fmt.Sprintf("output does not contain %q", c.Expected)), nil
+	})
+}
+
+// Regex 要求输出匹配 re
+func Regex(re *regexp.Regexp) Grader {
+	name := "regex"
+	return GraderFunc(name, func(ctx context.Context, c Case, output string) (Score, error) {
+		return boolScore(name, re.MatchString(output), fmt.Sprintf("output does not match %s", re)), nil
+	})
+}
+
+// judgePrompt 是评审模型的提示词
+const judgePrompt = `你是一名严格的评审，请根据评分标准评估模型对问题的回答。
+
+问题：
+%s
+
+评分标准：
+%s
+
+模型的回答：
+%s
+
+请给出 0 到 10 的整数分数和简短的理由。`
+
+// judgeVerdict 是评审模型的输出格式
+type judgeVerdict struct {
+	Score  float64 `json:"score" description:"0 到 10 的分数"`
+	Reason string  `json:"reason" description:"评分理由"`
+}
+
+// Judge 让 cfg 指定的评审模型按 Case.Expected 描述的评分标准为输出打 0 到 10 分，
+// 归一化后不低于 threshold（如 0.7）即通过。评审模型的回复格式错误时会重试一次
+func Judge(cfg llm.Config, threshold float64) Grader {
+	name := "judge"
+	return GraderFunc(name, func(ctx context.Context, c Case, output string) (Score, error) {
+		criteria := c.Expected
+		if criteria == "" {
+			criteria = "回答准确、完整、切题"
+		}
+		p := outputs.JSON[judgeVerdict]()
+		prompt := outputs.WithInstructions(fmt.Sprintf(judgePrompt, c.input(), criteria, output), p)
+		chat := func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+			return llm.ChatMessages(ctx, messages, cfg)
+		}
+		v, _, err := outputs.Run(ctx, chat, []spec.Message{spec.NewUserMessage(prompt)}, p, outputs.DefaultRetries)
+		if err != nil {
+			return Score{Grader: name}, fmt.Errorf("eval: judge failed: %w", err)
+		}
+		value := min(max(v.Score/10, 0), 1)
+		return Score{Grader: name, Value: value, Pass: value >= threshold, Reason: v.Reason}, nil
+	})
+}
@@ -0,0 +1,127 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Report 是一次评估的结果
+type Report struct {
+	// Targets 参与评估的配置名称，顺序与传入的配置一致
+	Targets []string `json:"targets"`
+	// Results 按配置、用例顺序排列的结果
+	Results []Result `json:"results"`
+}
+
+// Summary 是一个配置的汇总结果
+type Summary struct {
+	Target string `json:"target"`
+	Cases  int    `json:"cases"`
+	Passed int    `json:"passed"`
+	// Errors 调用或评分出错的用例数
+	Errors int `json:"errors"`
+	// PassRate 通过率，范围 0 到 1
+	PassRate float64 `json:"pass_rate"`
+	// AvgScore 平均分
+	AvgScore float64 `json:"avg_score"`
+	// AvgLatency 平均耗时
+	AvgLatency time.Duration `json:"avg_latency"`
+	Usage      spec.Usage    `json:"usage"`
+}
+
+// Summaries 返回每个配置的汇总结果，顺序与 Targets 一致
+func (r *Report) Summaries() []Summary {
+	out := make([]Summary, len(r.Targets))
+	index := make(map[string]int, len(r.Targets))
+	for i, t := range r.Targets {
+		out[i].Target = t
+		index[t] = i
+	}
+	latency := make([]time.Duration, len(r.Targets))
+	for _, res := range r.Results {
+		i, ok := index[res.Target]
+		if !ok {
+			continue
+		}
+		s := &out[i]
+		s.Cases++
+		if res.Pass {
+			s.Passed++
+		}
+		if res.Error != "" {
+			s.Errors++
+		}
+		s.AvgScore += res.Score
+		latency[i] += res.Latency
+		s.Usage = s.Usage.Add(res.Usage)
+	}
+	for i := range out {
+		if n := out[i].Cases; n > 0 {
+			out[i].PassRate = float64(out[i].Passed) / float64(n)
+			out[i].AvgScore /= float64(n)
+			out[i].AvgLatency = latency[i] / time.Duration(n)
+		}
+	}
+	return out
+}
+
+// Failures 返回未通过的结果
+func (r *Report) Failures() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if !res.Pass {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// WriteText 以文本表格输出各配置的汇总结果，之后列出未通过的用例及原因
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tPASSED\tPASS RATE\tAVG SCORE\tERRORS\tAVG LATENCY\tTOKENS")
+	for _, s := range r.Summaries() {
+		fmt.Fprintf(tw, "%s\t%d/%d\t%.1f%%\t%.2f\t%d\t%s\t%d\n",
+			s.Target, s.Passed, s.Cases, s.PassRate*100, s.AvgScore, s.Errors, s.AvgLatency.Round(time.Millisecond), s.Usage.TotalTokens)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	failures := r.Failures()
+	if len(failures) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("\nFAILURES\n")
+	for _, res := range failures {
+		fmt.Fprintf(&b, "- %s [%s]", res.Case, res.Target)
+		if res.Error != "" {
+			fmt.Fprintf(&b, " error: %s", res.Error)
+		}
+		b.WriteString("\n")
+		for _, s := range res.Scores {
+			if !s.Pass {
+				fmt.Fprintf(&b, "    %s (%.2f): %s\n", s.Grader, s.Value, s.Reason)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON 以 JSON 输出汇总结果和全部明细，便于保存后与下一次评估比较
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Summaries []Summary `json:"summaries"`
+		*Report
+	}{r.Summaries(), r})
+}
@@ -0,0 +1,248 @@
+// Package extract 从长文档中抽取结构化记录：按 token 数切分文档，并发地让模型按 T 的 JSON Schema
+// 从每块中抽取记录，再去重、合并为最终结果。格式错误的回复会自动要求模型修正。
+//
+//	type Contact struct {
+//		Name  string `json:"name" description:"姓名"`
+//		Phone string `json:"phone,omitempty" description:"电话号码"`
+//		Email string `json:"email,omitempty"`
+//	}
+//
+//	contacts, err := extract.Run[Contact](ctx, doc, cfg,
+//		extract.WithInstructions("文中出现的所有联系人"),
+//		extract.WithKey(func(c Contact) string { return c.Name }))
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/outputs"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/textsplit"
+)
+
+// 抽取的默认参数
+const (
+	defaultChunkSize   = 2000
+	defaultOverlap     = 100
+	defaultConcurrency = 4
+)
+
+// systemPrompt 是抽取使用的系统提示词，%s 为要抽取的内容
+const systemPrompt = "你是一个信息抽取助手。请从用户提供的文本片段中抽取%s。只抽取文本中明确出现的信息，不要推测或编造；片段中没有相关信息时返回空的 records 数组。"
+
+// records 是每块文本的抽取结果，包一层对象便于模型输出并生成 Schema
+type records[T any] struct {
+	Records []T `json:"records" description:"抽取到的记录"`
+}
+
+// options 是 Run 的配置
+type options struct {
+	instructions string
+	chunkSize    int
+	overlap      int
+	concurrency  int
+	retries      int
+	key          any
+	merge        any
+	chatOpts     []spec.Option
+}
+
+// Option 用于配置 Run
+type Option func(o *options)
+
+// WithInstructions 描述要抽取的内容，如 "文中提到的所有公司及其成立年份"，默认为 "所有相关记录"
+func WithInstructions(instructions string) Option {
+	return func(o *options) {
+		o.instructions = instructions
+	}
+}
+
+// WithChunkSize 设置每块的 token 数和相邻块的重叠 token 数，默认为 2000 和 100
+func WithChunkSize(size, overlap int) Option {
+	return func(o *options) {
+		o.chunkSize, o.overlap = size, overlap
+	}
+}
+
+// WithConcurrency 设置并发调用模型的数量，默认为 4
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithRetries 设置回复格式错误时的重试次数，默认为 outputs.DefaultRetries
+func WithRetries(n int) Option {
+	return func(o *options) {
+		o.retries = n
+	}
+}
+
+// WithKey 设置记录的去重键，键相同的记录通过 WithMerge 的函数合并，默认按记录的 JSON 编码去除完全相同的记录。
+// key 返回空字符串的记录不参与去重。T 必须与 Run 的类型参数一致
+func WithKey[T any](key func(T) string) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// WithMerge 设置键相同的两条记录的合并方式，默认为 FillZero。T 必须与 Run 的类型参数一致
+func WithMerge[T any](merge func(a, b T) T) Option {
+	return func(o *options) {
+		o.merge = merge
+	}
+}
+
+// WithChatOptions 为每次模型调用附加选项，如 spec.WithTemperature(0)
+func WithChatOptions(opts ...spec.Option) Option {
+	return func(o *options) {
+		o.chatOpts = append(o.chatOpts, opts...)
+	}
+}
+
+// Run 从 document 中抽取 T 类型的记录，结果按记录在文档中首次出现的顺序排列。
+// 任一块抽取失败时返回错误
+func Run[T any](ctx context.Context, document string, cfg llm.Config, opts ...Option) ([]T, error) {
+	o := options{
+		instructions: "所有相关记录",
+		chunkSize:    defaultChunkSize,
+		overlap:      defaultOverlap,
+		concurrency:  defaultConcurrency,
+		retries:      outputs.DefaultRetries,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	key, merge, err := dedupFuncs[T](o)
+	if err != nil {
+		return nil, err
+	}
+
+	splitter := textsplit.NewTokenSplitter(cfg.Model, o.chunkSize, o.overlap)
+	chunks := splitter.Split(document)
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	pc, release, err := llm.AcquireClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	callOpts := append(chatOptions(cfg), o.chatOpts...)
+	chat := func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+		return llm.RunFailover(ctx, cfg, pc, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+			return pc.Model(cfg.Model).Chat(ctx, messages, callOpts...)
+		})
+	}
+
+	parser := outputs.JSON[records[T]]()
+	system := outputs.WithInstructions(fmt.Sprintf(systemPrompt, o.instructions), parser)
+	if cfg.SystemPrompt != "" {
+		system = cfg.SystemPrompt + "\n\n" + system
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]T, len(chunks))
+	sem := make(chan struct{}, max(o.concurrency, 1))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			messages := []spec.Message{spec.NewSystemMessage(system), spec.NewUserMessage(chunk)}
+			out, _, err := outputs.Run(ctx, chat, messages, parser, o.retries)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("extract: chunk %d: %w", i, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = out.Records
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("extract: %w", err)
+	}
+	return dedup(results, key, merge), nil
+}
+
+// dedupFuncs 返回生效的去重键和合并函数
+func dedupFuncs[T any](o options) (func(T) string, func(a, b T) T, error) {
+	key := func(v T) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+	merge := FillZero[T]
+	if o.key != nil {
+		k, ok := o.key.(func(T) string)
+		if !ok {
+			return nil, nil, fmt.Errorf("extract: WithKey function has type %T, want func(%T) string", o.key, *new(T))
+		}
+		key = k
+	}
+	if o.merge != nil {
+		m, ok := o.merge.(func(a, b T) T)
+		if !ok {
+			return nil, nil, fmt.Errorf("extract: WithMerge function has type %T, want func(a, b %T) %T", o.merge, *new(T), *new(T))
+		}
+		merge = m
+	}
+	return key, merge, nil
+}
+
+// dedup 按块的顺序合并记录，键相同的记录用 merge 合并到首次出现的位置
+func dedup[T any](chunks [][]T, key func(T) string, merge func(a, b T) T) []T {
+	var out []T
+	index := make(map[string]int)
+	for _, records := range chunks {
+		for _, r := range records {
+			k := key(r)
+			if k == "" {
+				out = append(out, r)
+				continue
+			}
+			if i, ok := index[k]; ok {
+				out[i] = merge(out[i], r)
+				continue
+			}
+			index[k] = len(out)
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// chatOptions 构建每次模型调用的选项
+func chatOptions(cfg llm.Config) []spec.Option {
+	var opts []spec.Option
+	if cfg.Parameters != nil {
+		opts = append(opts, spec.WithParameters(cfg.Parameters))
+	}
+	if cfg.Thinking != nil {
+		opts = append(opts, spec.WithThinking(*cfg.Thinking))
+	}
+	return opts
+}
@@ -0,0 +1,64 @@
+package extract
+
+import "reflect"
+
+// FillZero 是默认的合并方式：T 为结构体（或结构体指针）时用 b 中的非零字段补全 a 中的零值字段，
+// 切片字段按元素去重追加；其他类型保留 a
+func FillZero[T any](a, b T) T {
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(b)
+	if va.Kind() == reflect.Pointer {
+		if va.IsNil() {
+			return b
+		}
+		if vb.IsNil() {
+			return a
+		}
+		// 不修改调用方持有的指针指向的值
+		c := reflect.New(va.Elem().Type())
+		c.Elem().Set(va.Elem())
+		va.Set(c)
+		va, vb = va.Elem(), vb.Elem()
+	}
+	if va.Kind() != reflect.Struct {
+		return a
+	}
+	fillStruct(va, vb)
+	return a
+}
+
+// fillStruct 用 src 的字段补全 dst
+func fillStruct(dst, src reflect.Value) {
+	for i := range dst.NumField() {
+		if !dst.Type().Field(i).IsExported() {
+			continue
+		}
+		df, sf := dst.Field(i), src.Field(i)
+		switch {
+		case df.IsZero():
+			df.Set(sf)
+		case df.Kind() == reflect.Slice && sf.Len() > 0:
+			df.Set(appendUnique(df, sf))
+		case df.Kind() == reflect.Struct:
+			fillStruct(df, sf)
+		}
+	}
+}
+
+// appendUnique 把 src 中 dst 没有的元素追加到 dst 的副本
+func appendUnique(dst, src reflect.Value) reflect.Value {
+	out := reflect.AppendSlice(reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len()), dst)
+	for j := range src.Len() {
+		e := src.Index(j)
+		found := false
+		for k := range out.Len() {
+			if reflect.DeepEqual(out.Index(k).Interface(), e.Interface()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = reflect.Append(out, e)
+		}
+	}
+	return out
+}
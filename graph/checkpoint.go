@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// ErrCheckpointNotFound 表示没有指定执行 ID 的检查点
+var ErrCheckpointNotFound = errors.New("graph: checkpoint not found")
+
+// Checkpoint 是某个节点执行完成后的快照
+type Checkpoint[S any] struct {
+	// Next 下一个要执行的节点，为 End 表示已经结束
+	Next string `json:"next"`
+	// State 当前状态
+	State S `json:"state"`
+	// Path 已执行的节点
+	Path []string `json:"path"`
+	// Visits 各节点的执行次数
+	Visits map[string]int `json:"visits"`
+}
+
+// Checkpointer 保存和读取检查点
+type Checkpointer[S any] interface {
+	Save(ctx context.Context, runID string, cp Checkpoint[S]) error
+	// Load 读取检查点，不存在时返回 ErrCheckpointNotFound
+	Load(ctx context.Context, runID string) (*Checkpoint[S], error)
+}
+
+// MemoryCheckpointer 把检查点保存在内存中，可并发使用。
+// 状态按值保存，S 中的引用类型与执行中的状态共享
+type MemoryCheckpointer[S any] struct {
+	mu  sync.Mutex
+	cps map[string]Checkpoint[S]
+}
+
+// NewMemoryCheckpointer 创建 MemoryCheckpointer
+func NewMemoryCheckpointer[S any]() *MemoryCheckpointer[S] {
+	return &MemoryCheckpointer[S]{cps: make(map[string]Checkpoint[S])}
+}
+
+func (m *MemoryCheckpointer[S]) Save(ctx context.Context, runID string, cp Checkpoint[S]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp.Path = slices.Clone(cp.Path)
+	cp.Visits = maps.Clone(cp.Visits)
+	m.cps[runID] = cp
+	return nil
+}
+
+func (m *MemoryCheckpointer[S]) Load(ctx context.Context, runID string) (*Checkpoint[S], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.cps[runID]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	cp.Path = slices.Clone(cp.Path)
+	cp.Visits = maps.Clone(cp.Visits)
+	return &cp, nil
+}
+
+// FileCheckpointer 把每次执行的检查点以 JSON 保存为目录下的 <runID>.json，S 必须可以 JSON 编码
+type FileCheckpointer[S any] struct {
+	dir string
+}
+
+// NewFileCheckpointer 创建 FileCheckpointer，目录不存在时会被创建
+func NewFileCheckpointer[S any](dir string) (*FileCheckpointer[S], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("graph: failed to create checkpoint dir: %w", err)
+	}
+	return &FileCheckpointer[S]{dir: dir}, nil
+}
+
+// path 返回执行 ID 对应的文件路径
+func (f *FileCheckpointer[S]) path(runID string) string {
+	return filepath.Join(f.dir, filepath.Base(runID)+".json")
+}
+
+// Save 先写入临时文件再重命名，避免中断时留下不完整的检查点
+func (f *FileCheckpointer[S]) Save(ctx context.Context, runID string, cp Checkpoint[S]) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := f.path(runID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(runID))
+}
+
+func (f *FileCheckpointer[S]) Load(ctx context.Context, runID string) (*Checkpoint[S], error) {
+	data, err := os.ReadFile(f.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint[S]
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
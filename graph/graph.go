@@ -0,0 +1,256 @@
+// Package graph 用有向图编排工作流：节点可以是模型调用、工具或任意 Go 函数，
+// 边可以带条件，允许带次数上限的循环；每个节点执行后可以保存检查点，中断后从断点恢复。
+//
+//	type State struct {
+//		Draft   string
+//		Review  string
+//		Rounds  int
+//	}
+//
+//	g := graph.New[State]()
+//	g.AddNode("write", write)
+//	g.AddNode("review", review)
+//	g.SetEntry("write")
+//	g.AddEdge("write", "review")
+//	g.AddConditionalEdge("review", "write", func(s State) bool { return s.Review != "通过" })
+//	g.AddEdge("review", graph.End)
+//	g.SetMaxVisits("write", 3)
+//	result, err := g.Run(ctx, State{})
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// End 是表示工作流结束的特殊节点名
+const End = "__end__"
+
+// defaultMaxSteps 是默认最多执行的节点数
+const defaultMaxSteps = 25
+
+// ErrMaxSteps 表示执行的节点数达到上限，通常意味着循环没有正常退出
+var ErrMaxSteps = errors.New("graph: reached the maximum number of steps")
+
+// NodeFunc 是节点的处理函数，接收当前状态并返回新的状态。
+// 状态按值传递，S 中的 map、切片等引用类型会在节点之间共享
+type NodeFunc[S any] func(ctx context.Context, state S) (S, error)
+
+// edge 是一条边，cond 为 nil 表示无条件
+type edge[S any] struct {
+	to   string
+	cond func(S) bool
+}
+
+// Graph 是由节点和边组成的工作流，构建完成后可以并发执行多次
+type Graph[S any] struct {
+	nodes map[string]NodeFunc[S]
+	edges map[string][]edge[S]
+	entry string
+	// maxSteps 一次执行最多执行的节点数
+	maxSteps int
+	// maxVisits 单个节点的最大执行次数，0 表示只受 maxSteps 限制
+	maxVisits map[string]int
+}
+
+// New 创建空的 Graph
+func New[S any]() *Graph[S] {
+	return &Graph[S]{
+		nodes:     make(map[string]NodeFunc[S]),
+		edges:     make(map[string][]edge[S]),
+		maxSteps:  defaultMaxSteps,
+		maxVisits: make(map[string]int),
+	}
+}
+
+// AddNode 添加节点，名称重复时覆盖原有节点
+func (g *Graph[S]) AddNode(name string, fn NodeFunc[S]) *Graph[S] {
+	g.nodes[name] = fn
+	return g
+}
+
+// SetEntry 设置起始节点
+func (g *Graph[S]) SetEntry(name string) *Graph[S] {
+	g.entry = name
+	return g
+}
+
+// AddEdge 添加从 from 到 to 的无条件边。一个节点的边按添加顺序检查，
+// 无条件边应在条件边之后添加，作为条件都不满足时的默认去向
+func (g *Graph[S]) AddEdge(from, to string) *Graph[S] {
+	g.edges[from] = append(g.edges[from], edge[S]{to: to})
+	return g
+}
+
+// AddConditionalEdge 添加条件边，节点执行后 cond 对新状态返回 true 时走向 to
+func (g *Graph[S]) AddConditionalEdge(from, to string, cond func(state S) bool) *Graph[S] {
+	g.edges[from] = append(g.edges[from], edge[S]{to: to, cond: cond})
+	return g
+}
+
+// SetMaxSteps 设置一次执行最多执行的节点数，默认为 25，超过时返回 ErrMaxSteps
+func (g *Graph[S]) SetMaxSteps(n int) *Graph[S] {
+	g.maxSteps = n
+	return g
+}
+
+// SetMaxVisits 限制节点在一次执行中的最大执行次数，用于约束循环，超过时返回 ErrMaxSteps
+func (g *Graph[S]) SetMaxVisits(name string, n int) *Graph[S] {
+	g.maxVisits[name] = n
+	return g
+}
+
+// Validate 检查起始节点和所有边引用的节点是否存在
+func (g *Graph[S]) Validate() error {
+	if g.entry == "" {
+		return errors.New("graph: entry node is not set")
+	}
+	if _, ok := g.nodes[g.entry]; !ok {
+		return fmt.Errorf("graph: entry node %q does not exist", g.entry)
+	}
+	for from, edges := range g.edges {
+		if _, ok := g.nodes[from]; !ok {
+			return fmt.Errorf("graph: edge from unknown node %q", from)
+		}
+		for _, e := range edges {
+			if _, ok := g.nodes[e.to]; !ok && e.to != End {
+				return fmt.Errorf("graph: edge from %q to unknown node %q", from, e.to)
+			}
+		}
+	}
+	return nil
+}
+
+// next 返回节点执行后的下一个节点：第一条满足条件的边；节点没有出边时结束
+func (g *Graph[S]) next(from string, state S) (string, error) {
+	edges := g.edges[from]
+	if len(edges) == 0 {
+		return End, nil
+	}
+	for _, e := range edges {
+		if e.cond == nil || e.cond(state) {
+			return e.to, nil
+		}
+	}
+	return "", fmt.Errorf("graph: no edge from %q matches the state", from)
+}
+
+// Result 是一次执行的结果
+type Result[S any] struct {
+	// State 最终状态，出错时为出错前最后一个成功节点的状态
+	State S
+	// Path 依次执行过的节点，从检查点恢复时包含恢复前的部分
+	Path []string
+	// RunID 执行 ID，用于 Resume
+	RunID string
+}
+
+// runOptions 是 Run 的配置
+type runOptions[S any] struct {
+	runID        string
+	checkpointer Checkpointer[S]
+	hooks        []func(node string, state S)
+}
+
+// RunOption 用于配置 Run 和 Resume，类型参数可以从参数推断
+type RunOption[S any] func(o *runOptions[S])
+
+// WithCheckpointer 在每个节点执行后把状态保存到 cp，runID 标识本次执行，之后可用 Resume 从断点继续
+func WithCheckpointer[S any](cp Checkpointer[S], runID string) RunOption[S] {
+	return func(o *runOptions[S]) {
+		o.checkpointer, o.runID = cp, runID
+	}
+}
+
+// WithNodeHook 注册在每个节点成功执行后调用的回调，可用于记录中间状态
+func WithNodeHook[S any](hook func(node string, state S)) RunOption[S] {
+	return func(o *runOptions[S]) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// Run 从起始节点开始执行工作流，直到到达 End
+func (g *Graph[S]) Run(ctx context.Context, state S, opts ...RunOption[S]) (*Result[S], error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	o := g.options(opts)
+	cp := Checkpoint[S]{Next: g.entry, State: state, Visits: make(map[string]int)}
+	// 保存初始检查点，使第一个节点失败后也能恢复
+	if o.checkpointer != nil {
+		if err := o.checkpointer.Save(ctx, o.runID, cp); err != nil {
+			return nil, fmt.Errorf("graph: failed to save checkpoint: %w", err)
+		}
+	}
+	return g.run(ctx, cp, o)
+}
+
+// Resume 从 WithCheckpointer 保存的检查点继续执行 runID 对应的工作流，
+// 必须传入同样的 WithCheckpointer 选项。已经结束的执行直接返回最终状态
+func (g *Graph[S]) Resume(ctx context.Context, opts ...RunOption[S]) (*Result[S], error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	o := g.options(opts)
+	if o.checkpointer == nil {
+		return nil, errors.New("graph: Resume requires WithCheckpointer")
+	}
+	cp, err := o.checkpointer.Load(ctx, o.runID)
+	if err != nil {
+		return nil, fmt.Errorf("graph: failed to load checkpoint %q: %w", o.runID, err)
+	}
+	if cp.Visits == nil {
+		cp.Visits = make(map[string]int)
+	}
+	return g.run(ctx, *cp, o)
+}
+
+// options 应用执行选项
+func (g *Graph[S]) options(opts []RunOption[S]) runOptions[S] {
+	var o runOptions[S]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// run 从检查点 cp 开始执行
+func (g *Graph[S]) run(ctx context.Context, cp Checkpoint[S], o runOptions[S]) (*Result[S], error) {
+	result := &Result[S]{State: cp.State, Path: cp.Path, RunID: o.runID}
+	for cp.Next != End {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		node := cp.Next
+		if len(cp.Path) >= g.maxSteps {
+			return result, fmt.Errorf("%w (%d)", ErrMaxSteps, g.maxSteps)
+		}
+		if limit := g.maxVisits[node]; limit > 0 && cp.Visits[node] >= limit {
+			return result, fmt.Errorf("%w: node %q visited %d times", ErrMaxSteps, node, limit)
+		}
+
+		state, err := g.nodes[node](ctx, cp.State)
+		if err != nil {
+			return result, fmt.Errorf("graph: node %s: %w", node, err)
+		}
+		next, err := g.next(node, state)
+		if err != nil {
+			return result, err
+		}
+		cp.State, cp.Next = state, next
+		cp.Path = append(cp.Path, node)
+		cp.Visits[node]++
+		result.State, result.Path = cp.State, cp.Path
+
+		if o.checkpointer != nil {
+			if err := o.checkpointer.Save(ctx, o.runID, cp); err != nil {
+				return result, fmt.Errorf("graph: failed to save checkpoint after %s: %w", node, err)
+			}
+		}
+		for _, hook := range o.hooks {
+			hook(node, state)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/agent"
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// ChatNode 返回调用模型的节点：prompt 根据状态构建消息，store 把回复写回状态
+func ChatNode[S any](cfg llm.Config, prompt func(state S) []spec.Message, store func(state S, resp *spec.Response) S) NodeFunc[S] {
+	return func(ctx context.Context, state S) (S, error) {
+		resp, err := llm.ChatMessages(ctx, prompt(state), cfg)
+		if err != nil {
+			return state, err
+		}
+		return store(state, resp), nil
+	}
+}
+
+// ToolNode 返回执行工具的节点：args 根据状态构建参数（会被编码为 JSON），store 把工具输出写回状态
+func ToolNode[S any](tool agent.Tool, args func(state S) any, store func(state S, output string) S) NodeFunc[S] {
+	return func(ctx context.Context, state S) (S, error) {
+		raw, err := json.Marshal(args(state))
+		if err != nil {
+			return state, fmt.Errorf("failed to encode arguments for tool %s: %w", tool.Name, err)
+		}
+		output, err := tool.Handler(ctx, raw)
+		if err != nil {
+			return state, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		return store(state, output), nil
+	}
+}
+
+// AgentNode 返回运行 Agent 的节点：prompt 根据状态构建任务，store 把最终回答写回状态
+func AgentNode[S any](a *agent.Agent, prompt func(state S) string, store func(state S, result *agent.Result) S) NodeFunc[S] {
+	return func(ctx context.Context, state S) (S, error) {
+		result, err := a.Run(ctx, prompt(state))
+		if err != nil {
+			return state, err
+		}
+		return store(state, result), nil
+	}
+}
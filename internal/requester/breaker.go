@@ -0,0 +1,165 @@
+package requester
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// breaker 是单个主机的熔断器
+type breaker struct {
+	cfg spec.CircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       breakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // 半开状态下已放行的探测请求数
+	successes   int // 半开状态下成功的探测请求数
+}
+
+// allow 判断是否放行请求
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if now.Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.state, b.probes, b.successes = stateHalfOpen, 0, 0
+		fallthrough
+	case stateHalfOpen:
+		if b.probes >= max(b.cfg.HalfOpenProbes, 1) {
+			return false
+		}
+		b.probes++
+		return true
+	}
+
+	if b.cfg.Window > 0 && now.Sub(b.windowStart) >= b.cfg.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	return true
+}
+
+// record 记录一次请求结果，返回熔断器是否因此打开
+func (b *breaker) record(now time.Time, failed bool) (tripped bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateHalfOpen:
+		if failed {
+			b.trip(now)
+			return true
+		}
+		b.successes++
+		if b.successes >= max(b.cfg.HalfOpenProbes, 1) {
+			b.state, b.windowStart, b.requests, b.failures = stateClosed, now, 0, 0
+		}
+	case stateClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures) >= b.cfg.FailureRate*float64(b.requests) && b.failures > 0 {
+			b.trip(now)
+			return true
+		}
+	}
+	return false
+}
+
+// release 归还一次未产生结论的放行（例如调用方主动取消），不计入统计
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == stateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *breaker) trip(now time.Time) {
+	b.state, b.openedAt = stateOpen, now
+}
+
+// maxBreakers 是单个 Requester 保留的熔断器数量上限，超过时清理处于关闭状态的熔断器
+const maxBreakers = 256
+
+// breakerKey 返回 rawURL 对应的熔断器键（协议和主机）。路径中常包含资源 ID（如 /files/{id}），
+// 按完整地址划分会导致熔断器无限增长且每个熔断器都达不到 MinRequests
+func breakerKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// breakerFor 返回 url 所在主机的熔断器，未启用熔断时返回 nil
+func (r *Requester) breakerFor(url string) *breaker {
+	if r.Breaker == nil {
+		return nil
+	}
+	key := breakerKey(url)
+	r.breakersMu.Lock()
+	defer r.breakersMu.Unlock()
+	if r.breakers == nil {
+		r.breakers = make(map[string]*breaker)
+	}
+	b, ok := r.breakers[key]
+	if !ok {
+		if len(r.breakers) >= maxBreakers {
+			r.evictBreakers()
+		}
+		b = &breaker{cfg: *r.Breaker, windowStart: time.Now()}
+		r.breakers[key] = b
+	}
+	return b
+}
+
+// evictBreakers 删除处于关闭状态的熔断器，它们只保存窗口内的计数，丢弃后不影响熔断判断。
+// 调用方需持有 breakersMu
+func (r *Requester) evictBreakers() {
+	for key, b := range r.breakers {
+		b.mu.Lock()
+		closed := b.state == stateClosed
+		b.mu.Unlock()
+		if closed {
+			delete(r.breakers, key)
+		}
+	}
+}
+
+// isBreakerFailure 判断错误是否应计入熔断失败：网络错误、超时和 5xx
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se *spec.APIError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500
+	}
+	return true
+}
+
+// circuitOpenError 包装 spec.ErrCircuitOpen 并附带接口地址
+func circuitOpenError(url string) error {
+	return fmt.Errorf("requester: %s: %w", url, spec.ErrCircuitOpen)
+}
@@ -0,0 +1,93 @@
+package requester
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// ErrorParser 从提供商的错误响应体中补充 APIError 的 Code、Message、RequestID 等字段。
+// 解析失败时应保持 APIError 不变，Kind 会在解析后重新计算。
+type ErrorParser func(e *spec.APIError)
+
+// requestIDHeaders 是常见的请求 ID 响应头
+var requestIDHeaders = []string{"X-Request-Id", "X-Dashscope-Request-Id", "Request-Id", "X-Trace-Id"}
+
+// WithErrorParser 设置提供商专用的错误解析函数，返回 r 本身便于链式调用
+func (r *Requester) WithErrorParser(parse ErrorParser) *Requester {
+	r.ParseError = parse
+	return r
+}
+
+// newAPIError 创建 APIError，并依次应用通用的请求 ID 提取和提供商的错误解析。
+// clientRequestID 为本次请求发送的 X-Request-ID。
+func (r *Requester) newAPIError(clientRequestID string, statusCode int, body []byte, header http.Header) *spec.APIError {
+	e := spec.NewAPIError(statusCode, body, header)
+	e.ClientRequestID = clientRequestID
+	for _, h := range requestIDHeaders {
+		if v := e.Header.Get(h); v != "" {
+			e.RequestID = v
+			break
+		}
+	}
+	if r.ParseError != nil {
+		r.ParseError(e)
+		e.Classify()
+	}
+	return e
+}
+
+// ParseOpenAIError 解析 OpenAI 兼容格式的错误：
+//
+//	{"error": {"message": "...", "type": "...", "code": "..."}}
+//
+// 同时兼容 vLLM 等部署返回的扁平格式 {"object": "error", "message": "...", "type": "...", "code": 400}，
+// 响应体不是 JSON（例如网关返回的纯文本）时保持原样。
+func ParseOpenAIError(e *spec.APIError) {
+	var body struct {
+		Error *openAIErrorBody `json:"error"`
+		openAIErrorBody
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return
+	}
+	detail := body.openAIErrorBody
+	if body.Error != nil {
+		detail = *body.Error
+	}
+	if detail.Message != "" {
+		e.Message = detail.Message
+	}
+	if code := JSONString(detail.Code); code != "" {
+		e.Code = code
+	}
+	if detail.Type != "" {
+		e.Type = detail.Type
+	}
+	if body.RequestID != "" {
+		e.RequestID = body.RequestID
+	}
+}
+
+// openAIErrorBody 是 OpenAI 兼容错误的主体，code 可能是字符串也可能是数字
+type openAIErrorBody struct {
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Code    json.RawMessage `json:"code"`
+}
+
+// JSONString 将 JSON 字符串或数字转换为字符串，null 或空值返回 ""
+func JSONString(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,148 @@
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// filesPageSize 是分页列出文件时每页的数量
+const filesPageSize = 100
+
+// OpenAIFiles 实现了 OpenAI 兼容的 /files 接口，OpenAI、DashScope 兼容模式、Moonshot 等提供商共用
+type OpenAIFiles struct {
+	requester *Requester
+	url       string
+	apiKey    string
+}
+
+// NewOpenAIFiles 创建 OpenAI 兼容的 spec.FileStore，url 为 /files 端点
+func NewOpenAIFiles(r *Requester, url, apiKey string) *OpenAIFiles {
+	return &OpenAIFiles{requester: r, url: url, apiKey: apiKey}
+}
+
+// openAIFile 是 OpenAI 兼容接口返回的文件对象
+type openAIFile struct {
+	ID        string `json:"id"`
+	Filename  string `json:"filename"`
+	Bytes     int64  `json:"bytes"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// headers 返回带鉴权信息的请求头
+func (f *OpenAIFiles) headers() http.Header {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+f.apiKey)
+	return headers
+}
+
+// Upload 实现了 spec.FileStore 接口
+func (f *OpenAIFiles) Upload(ctx context.Context, filename string, data []byte, purpose string) (*spec.File, error) {
+	form := NewForm()
+	form.AddField("purpose", purpose)
+	form.AddFile("file", filename, "", data)
+
+	rawBody, err := f.requester.PostForm(ctx, f.url, f.headers(), form)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(rawBody)
+}
+
+// List 实现了 spec.FileStore 接口，自动翻页直到取回全部文件
+func (f *OpenAIFiles) List(ctx context.Context, purpose string) ([]spec.File, error) {
+	var files []spec.File
+	after := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprint(filesPageSize))
+		if purpose != "" {
+			query.Set("purpose", purpose)
+		}
+		if after != "" {
+			query.Set("after", after)
+		}
+		rawBody, err := f.requester.Get(ctx, f.url+"?"+query.Encode(), f.headers())
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Data    []json.RawMessage `json:"data"`
+			HasMore bool              `json:"has_more"`
+		}
+		if err := json.Unmarshal(rawBody, &page); err != nil {
+			return nil, fmt.Errorf("requester: failed to unmarshal file list: %w", err)
+		}
+		for _, item := range page.Data {
+			file, err := parseFile(item)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, *file)
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return files, nil
+		}
+		after = files[len(files)-1].ID
+	}
+}
+
+// Get 实现了 spec.FileStore 接口
+func (f *OpenAIFiles) Get(ctx context.Context, id string) (*spec.File, error) {
+	rawBody, err := f.requester.Get(ctx, f.url+"/"+url.PathEscape(id), f.headers())
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(rawBody)
+}
+
+// Delete 实现了 spec.FileStore 接口
+func (f *OpenAIFiles) Delete(ctx context.Context, id string) error {
+	rawBody, err := f.requester.Delete(ctx, f.url+"/"+url.PathEscape(id), f.headers())
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Deleted bool `json:"deleted"`
+	}
+	if err := json.Unmarshal(rawBody, &result); err != nil {
+		return fmt.Errorf("requester: failed to unmarshal file deletion response: %w", err)
+	}
+	if !result.Deleted {
+		return fmt.Errorf("requester: file %s was not deleted", id)
+	}
+	return nil
+}
+
+// Content 实现了 spec.FileStore 接口
+func (f *OpenAIFiles) Content(ctx context.Context, id string) ([]byte, error) {
+	return f.requester.Get(ctx, f.url+"/"+url.PathEscape(id)+"/content", f.headers())
+}
+
+// parseFile 将文件对象转换为 spec.File
+func parseFile(raw []byte) (*spec.File, error) {
+	var f openAIFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("requester: failed to unmarshal file: %w", err)
+	}
+	file := &spec.File{
+		ID:       f.ID,
+		Filename: f.Filename,
+		Bytes:    f.Bytes,
+		Purpose:  f.Purpose,
+		Status:   f.Status,
+		Raw:      raw,
+	}
+	if f.CreatedAt > 0 {
+		file.CreatedAt = time.Unix(f.CreatedAt, 0)
+	}
+	return file, nil
+}
@@ -0,0 +1,70 @@
+package requester
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// rawSizeKey 是压缩前请求体大小在 context 中的键
+type rawSizeKey struct{}
+
+// withRawSize 记录压缩前的请求体大小，限流按它估算 token 数
+func withRawSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, rawSizeKey{}, size)
+}
+
+// rawSize 返回压缩前的请求体大小，未压缩时为 len(body)
+func rawSize(ctx context.Context, body []byte) int {
+	if size, ok := ctx.Value(rawSizeKey{}).(int); ok {
+		return size
+	}
+	return len(body)
+}
+
+// gzipBody 压缩请求体
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, fmt.Errorf("requester: failed to compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("requester: failed to compress request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeResponse 透明解压 gzip 响应体。
+// http.Transport 自动解压时会清除 Content-Encoding，这里只处理调用方自行声明 Accept-Encoding 的情况。
+func decodeResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("requester: failed to decompress response: %w", err)
+	}
+	resp.Body = &gzipReadCloser{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipReadCloser 在关闭时同时关闭解压器和原始响应体
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.body.Close()
+}
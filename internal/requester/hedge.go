@@ -0,0 +1,124 @@
+package requester
+
+import (
+	"context"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// hedgeableKey 标记 context 上的请求可以对冲
+type hedgeableKey struct{}
+
+// Hedgeable 返回标记为可对冲的 context。只有幂等的对话补全请求应设置该标记，
+// 文件上传、任务提交等请求重复发送会产生重复的资源和费用
+func Hedgeable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, hedgeableKey{}, true)
+}
+
+// isHedgeable 判断 context 上的请求是否可以对冲
+func isHedgeable(ctx context.Context) bool {
+	ok, _ := ctx.Value(hedgeableKey{}).(bool)
+	return ok
+}
+
+// hedgeURL 把原请求地址的路径和查询参数拼接到备份地址 base 之后
+func hedgeURL(base, url string) string {
+	b, err := neturl.Parse(base)
+	if err != nil {
+		return url
+	}
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return url
+	}
+	b.Path = strings.TrimSuffix(b.Path, "/") + u.Path
+	b.RawPath = ""
+	b.RawQuery = u.RawQuery
+	return b.String()
+}
+
+// hedgeResult 是单个对冲请求的结果
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// hedged 发送一次请求；开启对冲且 ctx 通过 Hedgeable 标记时，若 Delay 内没有收到响应头则并发发送备份请求，
+// 采用最先成功的响应并取消其余请求。所有请求都失败时返回第一个错误，交由重试逻辑处理。
+func (r *Requester) hedged(ctx context.Context, url string, headers http.Header, jsonBody []byte) (*http.Response, error) {
+	h := r.Hedge
+	if h == nil || h.Delay <= 0 || !isHedgeable(ctx) {
+		return r.sendOnce(ctx, url, headers, jsonBody)
+	}
+	total := 1 + max(h.MaxHedges, 1)
+
+	results := make(chan hedgeResult, total)
+	var cancels []context.CancelFunc
+	launch := func(i int) {
+		target := url
+		if i > 0 && len(h.Endpoints) > 0 {
+			target = hedgeURL(h.Endpoints[(i-1)%len(h.Endpoints)], url)
+		}
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := r.sendOnce(attemptCtx, target, headers, jsonBody)
+			results <- hedgeResult{index: i, resp: resp, err: err}
+		}()
+	}
+	// abandon 取消除 keep 以外的所有请求，并在后台回收未完成请求的结果
+	abandon := func(pending, keep int) {
+		for i, cancel := range cancels {
+			if i != keep {
+				cancel()
+			}
+		}
+		go func() {
+			for ; pending > 0; pending-- {
+				if res := <-results; res.resp != nil {
+					res.resp.Body.Close()
+				}
+			}
+		}()
+	}
+
+	launch(0)
+	launched, pending := 1, 1
+	timer := time.NewTimer(h.Delay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			r.logger().Debug("llm request hedged", "url", url, "hedge", launched)
+			launch(launched)
+			launched++
+			pending++
+			if launched < total {
+				timer.Reset(h.Delay)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				// 胜出请求的上下文需要保持到响应体关闭
+				res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.index]}
+				abandon(pending, res.index)
+				return res.resp, nil
+			}
+			cancels[res.index]()
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			if pending == 0 {
+				return nil, firstErr
+			}
+		case <-ctx.Done():
+			abandon(pending, -1)
+			return nil, ctx.Err()
+		}
+	}
+}
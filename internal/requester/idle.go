@@ -0,0 +1,46 @@
+package requester
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// IdleTimeoutReader 包装流式响应体，如果在 timeout 内没有读到任何数据，
+// 则主动关闭底层连接，使阻塞中的 Read 立即返回 *spec.StreamIdleTimeoutError。
+type IdleTimeoutReader struct {
+	rc      io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// NewIdleTimeoutReader 创建一个空闲超时读取器，计时从创建时开始。
+func NewIdleTimeoutReader(rc io.ReadCloser, timeout time.Duration) *IdleTimeoutReader {
+	r := &IdleTimeoutReader{rc: rc, timeout: timeout}
+	r.timer = time.AfterFunc(timeout, func() {
+		r.expired.Store(true)
+		rc.Close()
+	})
+	return r
+}
+
+// Read 读取数据，每次成功读到数据都会重置空闲计时器。
+func (r *IdleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	if r.expired.Load() {
+		return n, &spec.StreamIdleTimeoutError{Timeout: r.timeout}
+	}
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// Close 停止计时器并关闭底层响应体。
+func (r *IdleTimeoutReader) Close() error {
+	r.timer.Stop()
+	return r.rc.Close()
+}
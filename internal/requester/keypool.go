@@ -0,0 +1,98 @@
+package requester
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultKeyCooldown 是 Key 收到 429 后的默认冷却时间
+const defaultKeyCooldown = time.Minute
+
+// keyState 记录单个 Key 的限流状态
+type keyState struct {
+	value         string
+	throttledAt   time.Time
+	cooldownUntil time.Time
+}
+
+// keyPool 在多个 API Key 之间选择，并对被限流的 Key 进行冷却
+type keyPool struct {
+	strategy spec.KeySelection
+	cooldown time.Duration
+
+	mu   sync.Mutex
+	keys []keyState
+	next int
+}
+
+// newKeyPool 根据配置创建 Key 池，未配置或没有 Key 时返回 nil
+func newKeyPool(cfg *spec.APIKeyPool) *keyPool {
+	if cfg == nil || len(cfg.Keys) == 0 {
+		return nil
+	}
+	p := &keyPool{strategy: cfg.Strategy, cooldown: cfg.Cooldown}
+	if p.cooldown <= 0 {
+		p.cooldown = defaultKeyCooldown
+	}
+	for _, k := range cfg.Keys {
+		p.keys = append(p.keys, keyState{value: k})
+	}
+	return p
+}
+
+// pick 选择一个 Key，返回其下标和取值。
+// 所有 Key 都在冷却时选择最早结束冷却的那个。
+func (p *keyPool) pick(now time.Time) (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best := -1
+	n := len(p.keys)
+	for i := 0; i < n; i++ {
+		idx := (p.next + i) % n
+		k := &p.keys[idx]
+		if now.Before(k.cooldownUntil) {
+			continue
+		}
+		if p.strategy == spec.KeyRoundRobin {
+			best = idx
+			break
+		}
+		if best < 0 || k.throttledAt.Before(p.keys[best].throttledAt) {
+			best = idx
+		}
+	}
+	if best < 0 {
+		best = 0
+		for i := range p.keys {
+			if p.keys[i].cooldownUntil.Before(p.keys[best].cooldownUntil) {
+				best = i
+			}
+		}
+	}
+	p.next = (best + 1) % n
+	return best, p.keys[best].value
+}
+
+// throttle 将 Key 标记为被限流，wait 为服务端建议的等待时间
+func (p *keyPool) throttle(idx int, now time.Time, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := &p.keys[idx]
+	k.throttledAt = now
+	k.cooldownUntil = now.Add(max(wait, p.cooldown))
+}
+
+// available 判断当前是否有不在冷却期的 Key
+func (p *keyPool) available(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, k := range p.keys {
+		if !now.Before(k.cooldownUntil) {
+			return true
+		}
+	}
+	return false
+}
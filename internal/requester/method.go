@@ -0,0 +1,44 @@
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// methodKey 是请求方法在 context 中的键，未设置时为 POST
+type methodKey struct{}
+
+// withMethod 返回携带请求方法的 context，发送链路上的各层据此构造请求
+func withMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, methodKey{}, method)
+}
+
+// methodFrom 返回 context 中的请求方法，默认为 POST
+func methodFrom(ctx context.Context) string {
+	if method, ok := ctx.Value(methodKey{}).(string); ok {
+		return method
+	}
+	return http.MethodPost
+}
+
+// Get 发送一个不带请求体的 GET 请求并返回原始响应体，
+// 与 Post 共用重试、限流、Key 池、熔断和请求 ID 等处理。
+func (r *Requester) Get(ctx context.Context, url string, headers http.Header) ([]byte, error) {
+	return r.post(withMethod(ctx, http.MethodGet), url, headers, nil)
+}
+
+// Delete 发送一个不带请求体的 DELETE 请求并返回原始响应体
+func (r *Requester) Delete(ctx context.Context, url string, headers http.Header) ([]byte, error) {
+	return r.post(withMethod(ctx, http.MethodDelete), url, headers, nil)
+}
+
+// Put 发送一个 JSON 请求体的 PUT 请求并返回原始响应体
+func (r *Requester) Put(ctx context.Context, url string, headers http.Header, requestBody any) ([]byte, error) {
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("requester: failed to marshal request body: %w", err)
+	}
+	return r.post(withMethod(ctx, http.MethodPut), url, headers, jsonBody)
+}
@@ -0,0 +1,168 @@
+package requester
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Form 构造 multipart/form-data 请求体，用于文件上传类接口（图像编辑、语音转写等）。
+// 写入过程中的错误会被记录下来，在 PostForm 时统一返回。
+type Form struct {
+	buf    bytes.Buffer
+	writer *multipart.Writer
+	err    error
+}
+
+// NewForm 创建一个空表单
+func NewForm() *Form {
+	f := &Form{}
+	f.writer = multipart.NewWriter(&f.buf)
+	return f
+}
+
+// AddField 写入文本字段
+func (f *Form) AddField(name, value string) {
+	if f.err == nil {
+		f.err = f.writer.WriteField(name, value)
+	}
+}
+
+// AddFile 写入文件字段，mimeType 为空时根据内容推断
+func (f *Form) AddFile(field, filename, mimeType string, data []byte) {
+	if f.err != nil {
+		return
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filename))
+	h.Set("Content-Type", mimeType)
+	part, err := f.writer.CreatePart(h)
+	if err != nil {
+		f.err = err
+		return
+	}
+	_, f.err = part.Write(data)
+}
+
+// encode 结束表单并返回请求体和 Content-Type
+func (f *Form) encode() ([]byte, string, error) {
+	if f.err == nil {
+		f.err = f.writer.Close()
+	}
+	if f.err != nil {
+		return nil, "", fmt.Errorf("requester: failed to build multipart form: %w", f.err)
+	}
+	return f.buf.Bytes(), f.writer.FormDataContentType(), nil
+}
+
+// PostForm 以 multipart/form-data 发送表单并返回原始响应体，Content-Type 会被自动设置
+func (r *Requester) PostForm(ctx context.Context, url string, headers http.Header, form *Form) ([]byte, error) {
+	body, contentType, err := form.encode()
+	if err != nil {
+		return nil, err
+	}
+	headers = headers.Clone()
+	headers.Set("Content-Type", contentType)
+	return r.post(ctx, url, headers, body)
+}
+
+// Fetch 读取媒体内容：data URL 直接解码，http(s) URL 使用 Requester 的 HTTP 连接（代理、TLS 配置）下载。
+// 媒体地址通常指向第三方主机，下载不经过拦截器、也不携带 Provider 的请求头，避免鉴权信息泄露给这些主机。
+// 内容超过 limit 字节时返回错误，不会截断。返回内容和 MIME 类型。
+func (r *Requester) Fetch(ctx context.Context, ref string, limit int64) ([]byte, string, error) {
+	if rest, ok := strings.CutPrefix(ref, "data:"); ok {
+		meta, payload, ok := strings.Cut(rest, ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil, "", fmt.Errorf("requester: unsupported data URL, expected base64 encoding")
+		}
+		if int64(base64.StdEncoding.DecodedLen(len(payload))) > limit+2 {
+			return nil, "", fmt.Errorf("requester: data URL exceeds the %d bytes limit", limit)
+		}
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, "", fmt.Errorf("requester: invalid base64 data URL: %w", err)
+		}
+		if int64(len(data)) > limit {
+			return nil, "", fmt.Errorf("requester: data URL exceeds the %d bytes limit", limit)
+		}
+		return data, strings.TrimSuffix(meta, ";base64"), nil
+	}
+
+	ctx, cancel, err := r.bind(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("requester: invalid URL: %w", err)
+	}
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("requester: failed to download %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("requester: failed to download %s: status %d", ref, resp.StatusCode)
+	}
+	// 多读一个字节以区分恰好达到上限和超过上限
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("requester: failed to download %s: %w", ref, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("requester: %s exceeds the %d bytes limit", ref, limit)
+	}
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	if mt, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mt
+	}
+	return data, mimeType, nil
+}
+
+// commonExtensions 是常见媒体类型的扩展名，mime 包对部分类型给出的首选扩展名不被提供商识别（如 audio/mpeg 的 .m2a）
+var commonExtensions = map[string]string{
+	"image/png":    ".png",
+	"image/jpeg":   ".jpg",
+	"image/webp":   ".webp",
+	"audio/mpeg":   ".mp3",
+	"audio/mp3":    ".mp3",
+	"audio/wav":    ".wav",
+	"audio/x-wav":  ".wav",
+	"audio/wave":   ".wav",
+	"audio/webm":   ".webm",
+	"audio/mp4":    ".m4a",
+	"audio/x-m4a":  ".m4a",
+	"audio/ogg":    ".ogg",
+	"audio/flac":   ".flac",
+	"audio/x-flac": ".flac",
+	"video/mp4":    ".mp4",
+}
+
+// FileName 根据 MIME 类型生成上传使用的文件名，如 "image1.png"
+func FileName(base string, mimeType string) string {
+	if ext, ok := commonExtensions[mimeType]; ok {
+		return base + ext
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		return base + exts[0]
+	}
+	return base
+}
@@ -0,0 +1,107 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// bytesPerToken 用于按请求体大小估算 token 数
+const bytesPerToken = 4
+
+// bucket 是一个支持预留的令牌桶
+type bucket struct {
+	rate  float64 // 每秒补充的令牌数
+	burst float64 // 桶容量
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate, burst float64) *bucket {
+	return &bucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// reserve 预留 n 个令牌，返回需要等待的时间。令牌不足时余额可以为负，由后续补充抵消。
+func (b *bucket) reserve(n float64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	b.tokens -= min(n, b.burst)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refund 归还未使用的预留
+func (b *bucket) refund(n float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+min(n, b.burst))
+}
+
+// limiter 组合请求数与 token 数两个令牌桶
+type limiter struct {
+	requests *bucket
+	tokens   *bucket
+}
+
+// newLimiter 根据配置创建限流器，未配置任何限制时返回 nil
+func newLimiter(cfg *spec.RateLimit) *limiter {
+	if cfg == nil {
+		return nil
+	}
+	l := &limiter{}
+	if cfg.RPS > 0 {
+		l.requests = newBucket(cfg.RPS, float64(max(cfg.Burst, 1)))
+	}
+	if cfg.TokensPerMinute > 0 {
+		tpm := float64(cfg.TokensPerMinute)
+		l.tokens = newBucket(tpm/60, tpm)
+	}
+	if l.requests == nil && l.tokens == nil {
+		return nil
+	}
+	return l
+}
+
+// wait 阻塞直到可以发送一个大小为 bodySize 字节的请求，ctx 结束时归还预留并返回错误
+func (l *limiter) wait(ctx context.Context, bodySize int) error {
+	now := time.Now()
+	estimated := float64(bodySize/bytesPerToken + 1)
+
+	var delay time.Duration
+	if l.requests != nil {
+		delay = max(delay, l.requests.reserve(1, now))
+	}
+	if l.tokens != nil {
+		delay = max(delay, l.tokens.reserve(estimated, now))
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		if l.requests != nil {
+			l.requests.refund(1)
+		}
+		if l.tokens != nil {
+			l.tokens.refund(estimated)
+		}
+		return fmt.Errorf("requester: rate limit wait: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -4,35 +4,177 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
 )
 
 // Requester 封装了执行HTTP请求的通用逻辑。
 type Requester struct {
 	HTTPClient *http.Client
+	// Retry 失败重试策略，nil 表示不重试
+	Retry *spec.RetryPolicy
+	// Breaker 熔断配置，nil 表示不启用
+	Breaker *spec.CircuitBreakerConfig
+	// Hedge 对冲请求策略，nil 表示不启用
+	Hedge *spec.HedgePolicy
+
+	breakersMu sync.Mutex
+	breakers   map[string]*breaker
+
+	// Interceptors 请求/响应拦截器，按顺序由外到内包装 HTTPClient.Do
+	Interceptors []spec.Interceptor
+	// Logger 日志记录器，nil 表示使用 slog.Default()
+	Logger spec.Logger
+	// ParseError 提供商专用的错误解析，nil 表示只做通用解析
+	ParseError ErrorParser
+
+	// CompressionMinSize 开启 gzip 请求压缩的最小请求体大小，0 表示不压缩
+	CompressionMinSize int
+	// TotalTimeout 非流式请求的整体超时（包含重试），0 表示不限制
+	TotalTimeout time.Duration
+	// Singleflight 是否合并相同的并发非流式请求
+	Singleflight bool
+	flights      flightGroup
+
+	// 多 Key 负载均衡，nil 表示使用调用方设置的 Authorization 请求头
+	keys *keyPool
+	// 客户端侧限流，nil 表示不限流
+	limiter *limiter
+
+	// 生命周期控制，Close 后所有进行中的请求都会被取消
+	once     sync.Once
+	lifetime context.Context
+	shutdown context.CancelFunc
+}
+
+// New 根据客户端配置创建 Requester，代理等网络配置无效时返回错误
+func New(config *spec.ClientConfig) (*Requester, error) {
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Requester{
+		HTTPClient:   httpClient,
+		Retry:        config.RetryPolicy,
+		Breaker:      config.CircuitBreaker,
+		Hedge:        config.Hedge,
+		Interceptors: config.Interceptors,
+		Logger:       newLogger(config),
+		Singleflight: config.Singleflight,
+		keys:         newKeyPool(config.APIKeyPool),
+		limiter:      newLimiter(config.RateLimit),
+
+		CompressionMinSize: config.CompressionMinSize,
+		TotalTimeout:       config.Timeouts.Total,
+	}, nil
+}
+
+// newLogger 创建自动遮盖 API Key 的日志记录器
+func newLogger(config *spec.ClientConfig) spec.Logger {
+	secrets := []string{config.APIKey}
+	if config.APIKeyPool != nil {
+		secrets = append(secrets, config.APIKeyPool.Keys...)
+	}
+	return spec.NewRedactingLogger(config.Logger, secrets...)
+}
+
+// logger 返回日志记录器，保证零值 Requester 可用
+func (r *Requester) logger() spec.Logger {
+	if r.Logger == nil {
+		return slog.Default()
+	}
+	return r.Logger
+}
+
+// init 延迟初始化生命周期上下文，保证零值 Requester 可用
+func (r *Requester) init() {
+	r.once.Do(func() {
+		r.lifetime, r.shutdown = context.WithCancel(context.Background())
+	})
+}
+
+// bind 派生一个在 Requester 关闭时会被取消的上下文
+func (r *Requester) bind(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	r.init()
+	if r.lifetime.Err() != nil {
+		return nil, nil, spec.ErrClientClosed
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(r.lifetime, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}, nil
+}
+
+// Close 取消所有进行中的请求并释放空闲连接，之后的请求会返回 spec.ErrClientClosed。
+func (r *Requester) Close() error {
+	r.init()
+	r.shutdown()
+	r.CloseIdleConnections()
+	return nil
+}
+
+// CloseIdleConnections 释放底层 HTTP 客户端的空闲连接，不影响进行中的请求。
+func (r *Requester) CloseIdleConnections() {
+	if r.HTTPClient != nil {
+		r.HTTPClient.CloseIdleConnections()
+	}
+}
+
+// cancelOnClose 在响应体关闭时释放与之绑定的上下文
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
 }
 
 // Post 方法发送一个POST请求并返回原始响应体。
+// 开启 Singleflight 时，相同的并发请求只会向上游发送一次。
 func (r *Requester) Post(ctx context.Context, url string, headers http.Header, requestBody any) ([]byte, error) {
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, fmt.Errorf("requester: failed to marshal request body: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("requester: failed to create request: %w", err)
+	if r.Singleflight && !spec.IsDryRun(ctx) {
+		key := flightKey(url, headers, jsonBody)
+		return r.flights.do(ctx, key, func(ctx context.Context) ([]byte, error) {
+			return r.post(ctx, url, headers, jsonBody)
+		})
 	}
+	return r.post(ctx, url, headers, jsonBody)
+}
 
-	// 设置请求头
-	httpReq.Header = headers
+// post 发送已序列化的请求体并读取完整响应
+func (r *Requester) post(ctx context.Context, url string, headers http.Header, jsonBody []byte) ([]byte, error) {
+	ctx, cancel, err := r.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	if r.TotalTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, r.TotalTimeout)
+		defer cancelTimeout()
+	}
 
-	// 发送请求
-	resp, err := r.HTTPClient.Do(httpReq)
+	resp, err := r.send(ctx, url, headers, jsonBody)
 	if err != nil {
-		return nil, fmt.Errorf("requester: request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -42,11 +184,6 @@ func (r *Requester) Post(ctx context.Context, url string, headers http.Header, r
 		return nil, fmt.Errorf("requester: failed to read response body: %w", err)
 	}
 
-	// 检查状态码
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("requester: API error (status %d): %s", resp.StatusCode, string(rawBody))
-	}
-
 	return rawBody, nil
 }
 
@@ -58,25 +195,258 @@ func (r *Requester) PostStream(ctx context.Context, url string, headers http.Hea
 		return nil, fmt.Errorf("requester: failed to marshal request body: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	// 流式请求的上下文需要一直存活到响应体被关闭
+	ctx, cancel, err := r.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.send(ctx, url, headers, jsonBody)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// 注意：这里不读取也不关闭 Body，交给上层处理
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// send 发送请求并在失败时按重试策略重试，返回状态码为 2xx 的响应（Body 未读取）。
+// 服务端通过 Retry-After 等响应头给出等待时间时优先使用该时间，超过 MaxDelay 则直接放弃；
+// 可重试的错误在用尽重试次数后包装为 *spec.RetryExhaustedError 返回。
+func (r *Requester) send(ctx context.Context, url string, headers http.Header, jsonBody []byte) (*http.Response, error) {
+	// 所有重试共用同一个请求 ID，便于在服务端日志中关联
+	id := spec.RequestIDFromContext(ctx)
+	if id == "" {
+		id = spec.NewRequestID()
+		ctx = spec.ContextWithRequestID(ctx, id)
+	}
+	if headers.Get(spec.RequestIDHeader) == "" {
+		headers = headers.Clone()
+		headers.Set(spec.RequestIDHeader, id)
+	}
+	if spec.IsDryRun(ctx) {
+		return nil, r.dryRun(methodFrom(ctx), url, headers, jsonBody)
+	}
+
+	// 请求体只压缩一次，所有重试复用
+	if r.CompressionMinSize > 0 {
+		headers = headers.Clone()
+		headers.Set("Accept-Encoding", "gzip")
+		if len(jsonBody) >= r.CompressionMinSize {
+			compressed, err := gzipBody(jsonBody)
+			if err != nil {
+				return nil, err
+			}
+			// 限流需要按压缩前的大小估算 token 数
+			ctx = withRawSize(ctx, len(jsonBody))
+			jsonBody = compressed
+			headers.Set("Content-Encoding", "gzip")
+		}
+	}
+
+	policy := r.Retry
+	for attempt := 1; ; attempt++ {
+		resp, err := r.hedged(ctx, url, headers, jsonBody)
+		if err == nil {
+			return resp, nil
+		}
+		if policy == nil || !shouldRetry(ctx, policy, err) {
+			return nil, err
+		}
+
+		var hint time.Duration
+		var se *spec.APIError
+		if errors.As(err, &se) {
+			hint = retryAfter(se.Header, time.Now())
+		}
+		if attempt >= policy.MaxAttempts {
+			return nil, &spec.RetryExhaustedError{Attempts: attempt, RetryAfter: hint, Err: err}
+		}
+
+		// 配置了 Key 池且还有可用 Key 时，限流错误立即换 Key 重试
+		if se != nil && se.StatusCode == http.StatusTooManyRequests && r.keys != nil && r.keys.available(time.Now()) {
+			continue
+		}
+		if policy.MaxDelay > 0 && hint > policy.MaxDelay {
+			return nil, &spec.RetryExhaustedError{Attempts: attempt, RetryAfter: hint, Err: err}
+		}
+
+		delay := hint
+		if delay <= 0 {
+			delay = jittered(policy.Backoff(attempt), policy.Jitter)
+		}
+		r.logger().Warn("llm request failed, retrying", "request_id", id, "url", url, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// dryRun 构造 dry run 模式下返回的错误，请求头中的鉴权信息会被脱敏
+func (r *Requester) dryRun(method, url string, headers http.Header, jsonBody []byte) error {
+	if r.keys != nil {
+		// 实际发送时会使用 Key 池中的 Key
+		headers = headers.Clone()
+		headers.Set("Authorization", "Bearer "+r.keys.keys[0].value)
+	}
+	return &spec.DryRunError{Request: &spec.DryRunRequest{
+		Method: method,
+		URL:    url,
+		Header: spec.RedactHeaders(headers),
+		Body:   json.RawMessage(jsonBody),
+	}}
+}
+
+// sendOnce 发送一次请求：先按限流等待，配置了 Key 池时选择本次使用的 Key，开启熔断时检查并记录结果。
+func (r *Requester) sendOnce(ctx context.Context, url string, headers http.Header, jsonBody []byte) (*http.Response, error) {
+	if r.limiter != nil {
+		if err := r.limiter.wait(ctx, rawSize(ctx, jsonBody)); err != nil {
+			return nil, err
+		}
+	}
+	if r.keys != nil {
+		idx, key := r.keys.pick(time.Now())
+		headers = headers.Clone()
+		headers.Set("Authorization", "Bearer "+key)
+		resp, err := r.guarded(ctx, url, headers, jsonBody)
+		var se *spec.APIError
+		if errors.As(err, &se) && se.StatusCode == http.StatusTooManyRequests {
+			now := time.Now()
+			r.keys.throttle(idx, now, retryAfter(se.Header, now))
+			r.logger().Info("llm api key throttled", "request_id", spec.RequestIDFromContext(ctx), "key", spec.RedactSecret(key))
+		}
+		return resp, err
+	}
+	return r.guarded(ctx, url, headers, jsonBody)
+}
+
+// guarded 在熔断器的保护下发送一次请求
+func (r *Requester) guarded(ctx context.Context, url string, headers http.Header, jsonBody []byte) (*http.Response, error) {
+	b := r.breakerFor(url)
+	if b == nil {
+		return r.roundTrip(ctx, url, headers, jsonBody)
+	}
+	if !b.allow(time.Now()) {
+		r.logger().Debug("llm request rejected by open circuit breaker", "url", url)
+		return nil, circuitOpenError(url)
+	}
+	resp, err := r.roundTrip(ctx, url, headers, jsonBody)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		// 调用方主动取消不代表服务端异常
+		b.release()
+	} else {
+		if b.record(time.Now(), isBreakerFailure(err)) {
+			r.logger().Warn("llm circuit breaker opened", "url", url, "error", err)
+		}
+	}
+	return resp, err
+}
+
+// roundTrip 发送一次请求，非 2xx 响应会读取响应体并转换为 *spec.APIError
+func (r *Requester) roundTrip(ctx context.Context, url string, headers http.Header, jsonBody []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, methodFrom(ctx), url, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("requester: failed to create request: %w", err)
 	}
 
-	httpReq.Header = headers
+	// 设置请求头
+	httpReq.Header = headers.Clone()
 
-	resp, err := r.HTTPClient.Do(httpReq)
+	// 经过拦截器链发送请求
+	log := r.logger()
+	id := spec.RequestIDFromContext(ctx)
+	log.Debug("llm request", "request_id", id, "url", url, "headers", httpReq.Header, "bytes", len(jsonBody))
+	start := time.Now()
+	resp, err := spec.ChainInterceptors(r.HTTPClient.Do, r.Interceptors...)(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("requester: request failed: %w", err)
+		log.Debug("llm request failed", "request_id", id, "url", url, "duration", time.Since(start), "error", err)
+		return nil, &networkError{requestID: id, err: err}
+	}
+	log.Debug("llm response", "request_id", id, "url", url, "status", resp.StatusCode, "duration", time.Since(start))
+	if err := decodeResponse(resp); err != nil {
+		return nil, err
 	}
 
-	// 注意：这里不读取也不关闭 Body，交给上层处理
+	// 检查状态码
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// 如果请求出错，尽力读取错误信息
 		defer resp.Body.Close()
 		rawBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("requester: API error (status %d): %s", resp.StatusCode, string(rawBody))
+		return nil, fmt.Errorf("requester: %w", r.newAPIError(id, resp.StatusCode, rawBody, resp.Header))
 	}
-
 	return resp, nil
 }
+
+// shouldRetry 判断错误是否满足重试条件
+func shouldRetry(ctx context.Context, policy *spec.RetryPolicy, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *spec.APIError
+	if errors.As(err, &se) {
+		switch {
+		case se.StatusCode == http.StatusTooManyRequests:
+			return policy.RetryOn&spec.RetryOnRateLimit != 0
+		case se.StatusCode >= 500:
+			return policy.RetryOn&spec.RetryOnServerError != 0
+		}
+		return false
+	}
+	var ne *networkError
+	if errors.As(err, &ne) {
+		return policy.RetryOn&spec.RetryOnNetworkError != 0
+	}
+	return false
+}
+
+// jittered 在 [d*(1-jitter), d] 区间内随机取值
+func jittered(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || d <= 0 {
+		return d
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	return d - time.Duration(rand.Float64()*jitter*float64(d))
+}
+
+// networkError 表示请求未能得到任何 HTTP 响应
+type networkError struct {
+	requestID string
+	err       error
+}
+
+func (e *networkError) Error() string {
+	if e.requestID != "" {
+		return fmt.Sprintf("requester: request %s failed: %v", e.requestID, e.err)
+	}
+	return fmt.Sprintf("requester: request failed: %v", e.err)
+}
+
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+// IsTransient 判断错误是否为暂时性故障：限流、5xx、网络错误、重试耗尽或熔断打开。
+// 这类错误换一个后端或稍后再试通常可以成功。
+func IsTransient(err error) bool {
+	if errors.Is(err, spec.ErrCircuitOpen) {
+		return true
+	}
+	var re *spec.RetryExhaustedError
+	if errors.As(err, &re) {
+		return true
+	}
+	var se *spec.APIError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	var ne *networkError
+	return errors.As(err, &ne)
+}
@@ -0,0 +1,59 @@
+package requester
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryAfter 从响应头中解析服务端建议的等待时间，依次支持：
+//   - retry-after-ms: 毫秒数
+//   - Retry-After: 秒数或 HTTP 日期
+//   - x-ratelimit-reset-requests / x-ratelimit-reset-tokens: 时长 ("6m0s"、"20ms")、秒数或 Unix 时间戳
+//
+// 同时存在多个 x-ratelimit-reset-* 时取最大值，没有可用信息时返回 0。
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if header == nil {
+		return 0
+	}
+	if v := header.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return max(time.Duration(secs)*time.Second, 0)
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0)
+		}
+	}
+
+	var wait time.Duration
+	for key, values := range header {
+		if !strings.HasPrefix(strings.ToLower(key), "x-ratelimit-reset") || len(values) == 0 {
+			continue
+		}
+		wait = max(wait, parseReset(values[0], now))
+	}
+	return wait
+}
+
+// parseReset 解析 x-ratelimit-reset-* 的取值
+func parseReset(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if d, err := time.ParseDuration(v); err == nil {
+		return max(d, 0)
+	}
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	// 足够大的数值视为 Unix 时间戳
+	if secs > 1e9 {
+		return max(time.Unix(int64(secs), 0).Sub(now), 0)
+	}
+	return time.Duration(secs * float64(time.Second))
+}
@@ -0,0 +1,83 @@
+package requester
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+)
+
+// flightCall 是一次正在进行的共享调用
+type flightCall struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightGroup 合并相同的并发请求。
+// 共享调用使用独立的上下文，发起者取消不会影响其他等待者；只有当所有等待者都放弃时才会被取消，
+// 此时调用会立即从 calls 中移除，之后到达的相同请求发起新的调用，不会拿到已取消调用的错误。
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightKey 根据地址、鉴权请求头和请求体计算合并键
+func flightKey(url string, headers http.Header, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write([]byte(headers.Get("Authorization")))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// do 执行 fn 或等待已在进行中的相同调用，返回的响应体是调用方独享的副本
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		shared, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+		go func() {
+			defer cancel()
+			c.body, c.err = fn(shared)
+			g.mu.Lock()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			close(c.done)
+		}()
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		if c.err != nil {
+			return nil, c.err
+		}
+		return bytes.Clone(c.body), nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,21 @@
+package requester
+
+import (
+	"bufio"
+	"io"
+)
+
+// DefaultStreamBufferSize 是流式(SSE)响应单行数据的默认最大长度。
+// bufio.Scanner 默认只允许 64KB，较长的工具调用参数块会触发 "token too long"。
+const DefaultStreamBufferSize = 4 * 1024 * 1024
+
+// NewSSEScanner 创建一个用于逐行读取 SSE 响应的 Scanner。
+// maxSize 为单行最大字节数，<= 0 时使用 DefaultStreamBufferSize。
+func NewSSEScanner(r io.Reader, maxSize int) *bufio.Scanner {
+	if maxSize <= 0 {
+		maxSize = DefaultStreamBufferSize
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSize)
+	return scanner
+}
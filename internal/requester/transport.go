@@ -0,0 +1,147 @@
+package requester
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// newHTTPClient 根据代理、TLS、超时等网络配置返回实际使用的 http.Client。
+// 没有需要调整的网络配置时直接返回 config.HTTPClient，否则复制一份，不修改调用方传入的客户端。
+func newHTTPClient(config *spec.ClientConfig) (*http.Client, error) {
+	base := config.HTTPClient
+	if base == nil {
+		base = &http.Client{}
+	}
+	hasCert := config.ClientCert != "" || config.ClientKey != ""
+	if config.ProxyURL == "" && config.TLSConfig == nil && !hasCert {
+		// 超时有默认值，自定义 Transport 无法设置时保持原样
+		if !hasTransportTimeouts(config.Timeouts) || !isStdTransport(base) {
+			return base, nil
+		}
+	}
+
+	transport, err := cloneTransport(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ProxyURL != "" {
+		proxy, err := parseProxyURL(config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig.Clone()
+	}
+	if hasCert {
+		cert, err := loadClientCertificate(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = append(transport.TLSClientConfig.Certificates, cert)
+	}
+
+	applyTimeouts(transport, config.Timeouts)
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
+
+// hasTransportTimeouts 判断是否配置了作用于 Transport 的超时
+func hasTransportTimeouts(t spec.Timeouts) bool {
+	return t.Dial > 0 || t.TLSHandshake > 0 || t.ResponseHeader > 0
+}
+
+// isStdTransport 判断客户端是否使用（或默认使用）*http.Transport
+func isStdTransport(client *http.Client) bool {
+	switch client.Transport.(type) {
+	case nil, *http.Transport:
+		return true
+	}
+	return false
+}
+
+// applyTimeouts 将连接、握手和响应头超时设置到 Transport 上
+func applyTimeouts(transport *http.Transport, t spec.Timeouts) {
+	if t.Dial > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: t.Dial, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if t.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshake
+	}
+	if t.ResponseHeader > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeader
+	}
+}
+
+// cloneTransport 复制客户端的 *http.Transport，未设置时基于 http.DefaultTransport
+func cloneTransport(client *http.Client) (*http.Transport, error) {
+	switch t := client.Transport.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone(), nil
+	case *http.Transport:
+		return t.Clone(), nil
+	default:
+		return nil, fmt.Errorf("requester: cannot apply network options to custom transport %T, configure it on your http.Client instead", t)
+	}
+}
+
+// loadClientCertificate 加载 mTLS 客户端证书，参数可以是 PEM 文件路径或 PEM 内容
+func loadClientCertificate(cert, key string) (tls.Certificate, error) {
+	if cert == "" || key == "" {
+		return tls.Certificate{}, fmt.Errorf("requester: client certificate requires both cert and key")
+	}
+	certPEM, err := readPEM(cert)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("requester: failed to read client certificate: %w", err)
+	}
+	keyPEM, err := readPEM(key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("requester: failed to read client key: %w", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("requester: invalid client certificate: %w", err)
+	}
+	return pair, nil
+}
+
+// readPEM 返回 PEM 内容；以 "-----BEGIN" 开头时视为内容本身，否则视为文件路径
+func readPEM(v string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+		return []byte(v), nil
+	}
+	return os.ReadFile(v)
+}
+
+// parseProxyURL 解析并校验代理地址
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("requester: invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("requester: unsupported proxy scheme %q, use http, https, socks5 or socks5h", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("requester: invalid proxy URL %q: missing host", raw)
+	}
+	return u, nil
+}
@@ -0,0 +1,251 @@
+// Package websocket 是一个最小化的 WebSocket (RFC 6455) 客户端，只实现实时接口需要的部分：
+// 握手、文本/二进制消息收发、分片重组，以及 ping/pong/close 控制帧的自动处理。
+// 握手通过 http.Client 完成，因此代理、TLS 和 mTLS 配置与普通请求一致。
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// 消息类型（帧的 opcode）
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+)
+
+// maxMessageSize 单条消息的最大字节数，防止异常数据耗尽内存
+const maxMessageSize = 64 << 20
+
+// acceptGUID 是计算 Sec-WebSocket-Accept 使用的固定 GUID
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// CloseError 表示服务端关闭了连接
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed by server (code %d): %s", e.Code, e.Reason)
+}
+
+// Conn 是一条 WebSocket 连接。ReadMessage 只能在一个 goroutine 中调用，WriteMessage 可以并发调用
+type Conn struct {
+	rwc io.ReadWriteCloser
+	br  *bufio.Reader
+
+	wmu       sync.Mutex
+	closeOnce sync.Once
+}
+
+// Dial 通过 client 向 url（ws/wss/http/https）发起握手。ctx 只约束握手阶段，连接建立后由 Close 关闭
+func Dial(ctx context.Context, client *http.Client, url string, header http.Header) (*Conn, error) {
+	switch {
+	case strings.HasPrefix(url, "wss://"):
+		url = "https://" + strings.TrimPrefix(url, "wss://")
+	case strings.HasPrefix(url, "ws://"):
+		url = "http://" + strings.TrimPrefix(url, "ws://")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("websocket: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: handshake failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("websocket: handshake failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket: transport does not support protocol upgrade")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		rwc.Close()
+		return nil, fmt.Errorf("websocket: handshake failed: invalid Sec-WebSocket-Accept")
+	}
+	return &Conn{rwc: rwc, br: bufio.NewReader(rwc)}, nil
+}
+
+// acceptKey 计算握手响应中期望的 Sec-WebSocket-Accept
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// ReadMessage 读取下一条完整的数据消息，ping 会自动回复 pong。
+// 服务端关闭连接时返回 *CloseError
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	var msg []byte
+	msgType := 0
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := &CloseError{Code: 1005}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			// 回应关闭帧后断开连接
+			c.Close()
+			return 0, nil, closeErr
+		case opContinuation:
+			if msgType == 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			if msgType != 0 {
+				return 0, nil, errors.New("websocket: expected continuation frame")
+			}
+			msgType = opcode
+		}
+
+		if len(msg)+len(payload) > maxMessageSize {
+			return 0, nil, fmt.Errorf("websocket: message exceeds %d bytes", maxMessageSize)
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msgType, msg, nil
+		}
+	}
+}
+
+// readFrame 读取一个帧
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0f)
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, fmt.Errorf("websocket: frame exceeds %d bytes", maxMessageSize)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage 发送一条完整的消息，messageType 为 TextMessage 或 BinaryMessage
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	return c.writeFrame(messageType, data)
+}
+
+// writeFrame 发送一个不分片的帧，客户端发送的帧必须加掩码
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+14)
+	frame = append(frame, 0x80|byte(opcode))
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	frame = append(frame, mask[:]...)
+	start := len(frame)
+	frame = append(frame, payload...)
+	for i := range payload {
+		frame[start+i] ^= mask[i%4]
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err := c.rwc.Write(frame)
+	return err
+}
+
+// Close 发送关闭帧并断开连接，可以重复调用
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		_ = c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, 1000))
+		err = c.rwc.Close()
+	})
+	return err
+}
@@ -0,0 +1,518 @@
+// Package yamlite 解析配置文件常用的 YAML 子集，结果由 map[string]any、[]any、string、Scalar 和 nil 组成，
+// 经 json.Marshal 后与等价的 JSON 一致，可以再经 JSON 转换为结构体。
+//
+// 支持：缩进表示的映射和序列、"- key: value" 形式的映射序列、# 注释、
+// 单双引号字符串、| 和 > 块标量（含 - 修饰）、[a, b] 和 {k: v} 流式集合，以及 null、布尔和数字。
+// 不支持锚点、别名、标签、多文档和复杂键。缩进只能使用空格。
+//
+//	输入                      结果
+//	key: value                "value"
+//	key: 'a: b # c'           "a: b # c"
+//	key: 0123456              Scalar{Text: "0123456", Value: 123456}
+//	key: 1e3                  Scalar{Text: "1e3", Value: 1000}
+//	key: 0x1F                 "0x1F"（非十进制数字按字符串处理）
+//	key: yes                  "yes"（只有 true/false 是布尔值）
+//	key: ~                    nil
+//	key: [a, 1, {b: c}]       []any{"a", Scalar{...}, map[string]any{"b": "c"}}
+//	key: |                    多行字符串，保留换行
+//	<Tab>key: value           错误：tabs are not allowed in indentation
+//	key: &anchor value        "&anchor value"（锚点不被识别）
+//	同一映射中重复的键           错误：duplicate key
+package yamlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parse 解析 YAML 文本，空文档返回 nil
+func Parse(data []byte) (any, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p := &parser{lines: strings.Split(text, "\n")}
+	// 跳过文档开始标记
+	if i := p.next(); i >= 0 && strings.TrimSpace(p.lines[i]) == "---" {
+		p.pos = i + 1
+	}
+	i := p.next()
+	if i < 0 {
+		return nil, nil
+	}
+	v, err := p.block(indentOf(p.lines[i]))
+	if i := p.next(); err == nil && i >= 0 {
+		err = p.errorf(i, "unexpected content")
+	}
+	// 缩进中的 Tab 会导致后续的缩进判断出错，优先报告
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Scalar 是不带引号的数字或布尔值，保留原始文本。转换为 JSON 时输出 Value，
+// 需要字符串的字段可以使用 Text，避免 api_key: 0123456 这类值被当作数字
+type Scalar struct {
+	// Text 原始文本
+	Text string
+	// Value 解析后的值，float64 或 bool
+	Value any
+}
+
+// MarshalJSON 输出解析后的值
+func (s Scalar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value)
+}
+
+// parser 逐行解析，pos 为下一个未处理的行，err 记录第一个缩进中包含 Tab 的行
+type parser struct {
+	lines []string
+	pos   int
+	err   error
+}
+
+// errorf 返回带行号的错误
+func (p *parser) errorf(line int, format string, args ...any) error {
+	return fmt.Errorf("yaml: line %d: %s", line+1, fmt.Sprintf(format, args...))
+}
+
+// next 返回下一个有内容（非空、非注释）的行号，没有时返回 -1
+func (p *parser) next() int {
+	for i := p.pos; i < len(p.lines); i++ {
+		t := strings.TrimSpace(p.lines[i])
+		if t != "" && !strings.HasPrefix(t, "#") {
+			if p.err == nil && strings.ContainsRune(p.lines[i][:len(p.lines[i])-len(strings.TrimLeft(p.lines[i], " \t"))], '\t') {
+				p.err = p.errorf(i, "tabs are not allowed in indentation, use spaces")
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// indentOf 返回行首空格数
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isSeqItem 判断去掉缩进后的行是否为序列项
+func isSeqItem(t string) bool {
+	return t == "-" || strings.HasPrefix(t, "- ")
+}
+
+// block 解析从下一行开始、缩进为 indent 的映射或序列
+func (p *parser) block(indent int) (any, error) {
+	i := p.next()
+	if isSeqItem(strings.TrimSpace(p.lines[i])) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+// mapping 解析缩进为 indent 的映射
+func (p *parser) mapping(indent int) (any, error) {
+	m := make(map[string]any)
+	for {
+		i := p.next()
+		if i < 0 || indentOf(p.lines[i]) < indent {
+			return m, nil
+		}
+		line := p.lines[i]
+		if indentOf(line) > indent {
+			return nil, p.errorf(i, "unexpected indentation")
+		}
+		t := strings.TrimSpace(line)
+		if isSeqItem(t) {
+			return nil, p.errorf(i, "unexpected sequence item")
+		}
+		key, rest, ok := splitKey(t)
+		if !ok {
+			return nil, p.errorf(i, "expected \"key: value\"")
+		}
+		k, err := scalarKey(key)
+		if err != nil {
+			return nil, p.errorf(i, "%v", err)
+		}
+		if _, dup := m[k]; dup {
+			return nil, p.errorf(i, "duplicate key %q", k)
+		}
+		p.pos = i + 1
+		v, err := p.value(i, indent, rest)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+}
+
+// sequence 解析缩进为 indent 的序列
+func (p *parser) sequence(indent int) (any, error) {
+	s := []any{}
+	for {
+		i := p.next()
+		if i < 0 || indentOf(p.lines[i]) < indent {
+			return s, nil
+		}
+		line := p.lines[i]
+		t := strings.TrimSpace(line)
+		if indentOf(line) > indent || !isSeqItem(t) {
+			return s, nil
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(t, "-"))
+		if _, _, ok := splitKey(rest); ok && rest[0] != '[' && rest[0] != '{' {
+			// "- key: value" 开始一个映射，把 "- " 替换为空格后按映射解析
+			p.lines[i] = strings.Repeat(" ", len(line)-len(rest)) + rest
+			v, err := p.mapping(len(line) - len(rest))
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+			continue
+		}
+		p.pos = i + 1
+		v, err := p.value(i, indent, rest)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+	}
+}
+
+// value 解析第 line 行中 rest 表示的值，parent 为所在集合的缩进；
+// rest 为空时值为下一行开始的嵌套块
+func (p *parser) value(line, parent int, rest string) (any, error) {
+	rest = stripComment(rest)
+	switch {
+	case rest == "":
+		i := p.next()
+		if i < 0 {
+			return nil, nil
+		}
+		n := indentOf(p.lines[i])
+		// 映射的值可以是与键同缩进的序列
+		if n > parent || (n == parent && isSeqItem(strings.TrimSpace(p.lines[i])) && !isSeqItem(strings.TrimSpace(p.lines[line]))) {
+			return p.block(n)
+		}
+		return nil, nil
+	case rest[0] == '|' || rest[0] == '>':
+		return p.blockScalar(line, parent, rest)
+	default:
+		v, err := scalar(rest)
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		return v, nil
+	}
+}
+
+// blockScalar 解析 | 或 > 开始的多行字符串
+func (p *parser) blockScalar(line, parent int, header string) (any, error) {
+	folded := header[0] == '>'
+	chomp := strings.TrimSpace(header[1:])
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, p.errorf(line, "unsupported block scalar header %q", header)
+	}
+	var body []string
+	indent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l) == "" {
+			body = append(body, "")
+			continue
+		}
+		n := indentOf(l)
+		if n <= parent || (indent >= 0 && n < indent) {
+			break
+		}
+		if indent < 0 {
+			indent = n
+		}
+		body = append(body, l[indent:])
+	}
+	// 末尾的空行不属于内容，按修饰符处理
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+	var text string
+	if folded {
+		var b strings.Builder
+		for i, l := range body {
+			switch {
+			case i == 0:
+			case l == "" || body[i-1] == "" || strings.HasPrefix(l, " "):
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(body, "\n")
+	}
+	switch chomp {
+	case "-":
+	case "+":
+		text += "\n" + strings.Repeat("\n", trailing)
+	default:
+		if len(body) > 0 {
+			text += "\n"
+		}
+	}
+	return text, nil
+}
+
+// splitKey 把 "key: value" 拆分为键和值，键可以带引号
+func splitKey(t string) (key, rest string, ok bool) {
+	start := 0
+	if t != "" && (t[0] == '"' || t[0] == '\'') {
+		end := closingQuote(t)
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 1
+	}
+	for i := start; i < len(t); i++ {
+		if t[i] == ':' && (i == len(t)-1 || t[i+1] == ' ' || t[i+1] == '\t') {
+			return strings.TrimSpace(t[:i]), strings.TrimSpace(t[i+1:]), true
+		}
+		if t[i] == '#' && i > 0 && t[i-1] == ' ' {
+			break
+		}
+	}
+	return "", "", false
+}
+
+// closingQuote 返回 s 开头的引号字符串的结束位置，未闭合时返回 -1
+func closingQuote(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q:
+			// 单引号字符串中 '' 表示一个单引号
+			if q == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComment 去掉不在引号内的行尾注释
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,:", s[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// scalarKey 解析映射的键
+func scalarKey(s string) (string, error) {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		v, err := quoted(s)
+		if err != nil {
+			return "", err
+		}
+		return v, nil
+	}
+	if s == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	return s, nil
+}
+
+// quoted 解析完整的引号字符串
+func quoted(s string) (string, error) {
+	if s[0] == '\'' {
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return v, nil
+}
+
+// scalar 解析单行的值
+func scalar(s string) (any, error) {
+	f := &flow{s: s}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	if f.skipSpace(); f.i < len(f.s) {
+		return nil, fmt.Errorf("unexpected %q after value", f.s[f.i:])
+	}
+	return v, nil
+}
+
+// plain 解析不带引号的标量，数字和布尔值返回 Scalar
+func plain(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return Scalar{Text: s, Value: true}
+	case "false", "False", "FALSE":
+		return Scalar{Text: s, Value: false}
+	}
+	if number.MatchString(s) {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return Scalar{Text: s, Value: n}
+		}
+	}
+	return s
+}
+
+// number 匹配十进制数字，其余形式（如 0x1F、1_000、inf）按字符串处理
+var number = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// flow 解析单行内的值，包括 [a, b] 和 {k: v} 流式集合
+type flow struct {
+	s     string
+	i     int
+	depth int
+}
+
+func (f *flow) skipSpace() {
+	for f.i < len(f.s) && (f.s[f.i] == ' ' || f.s[f.i] == '\t') {
+		f.i++
+	}
+}
+
+// value 解析一个值；在流式集合内时，不带引号的标量在 , ] } 处结束
+func (f *flow) value() (any, error) {
+	f.skipSpace()
+	if f.i >= len(f.s) {
+		return nil, nil
+	}
+	switch c := f.s[f.i]; c {
+	case '[':
+		return f.seq()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		end := closingQuote(f.s[f.i:])
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string %s", f.s[f.i:])
+		}
+		v, err := quoted(f.s[f.i : f.i+end+1])
+		f.i += end + 1
+		return v, err
+	}
+	start := f.i
+	for f.i < len(f.s) {
+		if f.depth > 0 && strings.IndexByte(",]}", f.s[f.i]) >= 0 {
+			break
+		}
+		if f.depth > 0 && f.s[f.i] == ':' && (f.i+1 == len(f.s) || f.s[f.i+1] == ' ') {
+			break
+		}
+		f.i++
+	}
+	return plain(strings.TrimSpace(f.s[start:f.i])), nil
+}
+
+func (f *flow) seq() (any, error) {
+	f.i++
+	f.depth++
+	defer func() { f.depth-- }()
+	s := []any{}
+	for {
+		f.skipSpace()
+		if f.i < len(f.s) && f.s[f.i] == ']' {
+			f.i++
+			return s, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+		f.skipSpace()
+		if f.i >= len(f.s) {
+			return nil, fmt.Errorf("unterminated sequence")
+		}
+		switch f.s[f.i] {
+		case ',':
+			f.i++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected ',' or ']' in sequence")
+		}
+	}
+}
+
+func (f *flow) mapping() (any, error) {
+	f.i++
+	f.depth++
+	defer func() { f.depth-- }()
+	m := make(map[string]any)
+	for {
+		f.skipSpace()
+		if f.i < len(f.s) && f.s[f.i] == '}' {
+			f.i++
+			return m, nil
+		}
+		k, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		switch k := k.(type) {
+		case string:
+			key = k
+		case Scalar:
+			key = k.Text
+		default:
+			key = fmt.Sprint(k)
+		}
+		f.skipSpace()
+		if f.i >= len(f.s) || f.s[f.i] != ':' {
+			return nil, fmt.Errorf("expected ':' after key %q", key)
+		}
+		f.i++
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+		f.skipSpace()
+		if f.i >= len(f.s) {
+			return nil, fmt.Errorf("unterminated mapping")
+		}
+		switch f.s[f.i] {
+		case ',':
+			f.i++
+		case '}':
+		default:
+			return nil, fmt.Errorf("expected ',' or '}' in mapping")
+		}
+	}
+}
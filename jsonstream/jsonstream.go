@@ -0,0 +1,267 @@
+// Package jsonstream 提供流式 JSON 输出的增量解析能力。
+//
+// 在 JSON 模式下流式调用模型时，每收到一个数据块，Parser 都会尝试将目前为止
+// 不完整的 JSON 补全为合法文档，并产出发生变化的字段更新事件，
+// 用于在结构化结果生成过程中渐进式地渲染界面。
+package jsonstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// FieldUpdate 表示某个叶子字段的值发生了变化。
+type FieldUpdate struct {
+	// Path 字段路径，例如 "title"、"items[0].name"
+	Path string
+	// Value 字段的当前值，类型为 string、json.Number、bool 或 nil。
+	// 字符串值在生成过程中可能是不完整的，后续会以更长的值再次更新。
+	Value any
+}
+
+// Parser 累积流式数据块并产出字段更新事件。
+// Parser 不是并发安全的，一个 Parser 只应用于一次流式调用。
+type Parser struct {
+	buf  strings.Builder
+	last map[string]any
+}
+
+// NewParser 创建一个新的增量解析器。
+func NewParser() *Parser {
+	return &Parser{last: make(map[string]any)}
+}
+
+// Write 追加一个数据块，返回本次新增或发生变化的字段（按文档顺序）。
+// 当累积内容还不足以构成任何可解析的片段时，返回空切片。
+func (p *Parser) Write(chunk string) ([]FieldUpdate, error) {
+	p.buf.WriteString(chunk)
+	repaired, ok := repair(p.buf.String())
+	if !ok {
+		return nil, nil
+	}
+
+	var updates []FieldUpdate
+	err := flatten(repaired, func(path string, value any) {
+		if prev, seen := p.last[path]; seen && prev == value {
+			return
+		}
+		p.last[path] = value
+		updates = append(updates, FieldUpdate{Path: path, Value: value})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jsonstream: failed to parse partial json: %w", err)
+	}
+	return updates, nil
+}
+
+// Raw 返回目前为止累积的原始文本。
+func (p *Parser) Raw() string {
+	return p.buf.String()
+}
+
+// Snapshot 将目前为止的部分 JSON 补全后解码到 v 中，
+// 便于直接以强类型结构体渲染尚未生成完毕的结果。
+func (p *Parser) Snapshot(v any) error {
+	repaired, ok := repair(p.buf.String())
+	if !ok {
+		return fmt.Errorf("jsonstream: no json object found yet")
+	}
+	return json.Unmarshal([]byte(repaired), v)
+}
+
+// StreamCallback 返回一个可直接传给 spec.WithStreamCallback 的回调，
+// 每当有字段更新时调用 fn，fn 返回错误会中断流式接收。
+func (p *Parser) StreamCallback(fn func(ctx context.Context, update FieldUpdate) error) spec.StreamCallback {
+	return func(ctx context.Context, chunk string) error {
+		updates, err := p.Write(chunk)
+		if err != nil {
+			return err
+		}
+		for _, u := range updates {
+			if err := fn(ctx, u); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// frame 记录一层未闭合的容器
+type frame struct {
+	kind      byte // '{' 或 '['
+	expectKey bool // 对象中下一个字符串是否为键名
+}
+
+// repair 将不完整的 JSON 补全为合法文档。
+// 它会跳过第一个 '{' 或 '[' 之前的内容（例如 markdown 代码块标记），
+// 丢弃不完整的键和字面量，并为未闭合的字符串值和容器补上结尾。
+func repair(s string) (string, bool) {
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return "", false
+	}
+	s = s[start:]
+
+	var stack []frame
+	inString, escaped, stringIsKey := false, false, false
+	stringStart := 0
+
+	// safe 记录最近一个可以直接截断并补全的位置
+	safe := 0
+	var safeStack []frame
+	mark := func(pos int) {
+		safe = pos
+		safeStack = append(safeStack[:0], stack...)
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				if !stringIsKey {
+					mark(i + 1)
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			stringStart = i
+			stringIsKey = len(stack) > 0 && stack[len(stack)-1].kind == '{' && stack[len(stack)-1].expectKey
+		case ':':
+			if len(stack) > 0 {
+				stack[len(stack)-1].expectKey = false
+			}
+		case ',':
+			mark(i)
+			if len(stack) > 0 && stack[len(stack)-1].kind == '{' {
+				stack[len(stack)-1].expectKey = true
+			}
+		case '{':
+			stack = append(stack, frame{kind: '{', expectKey: true})
+			mark(i + 1)
+		case '[':
+			stack = append(stack, frame{kind: '['})
+			mark(i + 1)
+		case '}', ']':
+			if len(stack) == 0 {
+				return "", false
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				// 顶层文档已经完整，忽略之后的内容
+				return s[:i+1], true
+			}
+			mark(i + 1)
+		}
+	}
+
+	// 未闭合的字符串值：补上引号后直接闭合
+	if inString && !stringIsKey {
+		body := trimPartialEscape(s[stringStart+1:])
+		if candidate := s[:stringStart+1] + body + `"` + closers(stack); json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+	}
+
+	candidate := s[:safe] + closers(safeStack)
+	if !json.Valid([]byte(candidate)) {
+		return "", false
+	}
+	return candidate, true
+}
+
+// trimPartialEscape 去掉字符串末尾不完整的转义序列，例如 `\` 或 `\u00`
+func trimPartialEscape(s string) string {
+	if i := strings.LastIndex(s, `\u`); i >= 0 && len(s)-i < 6 && !isEscaped(s, i) {
+		s = s[:i]
+	}
+	if strings.HasSuffix(s, `\`) && !isEscaped(s, len(s)-1) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// isEscaped 判断 s[i] 处的反斜杠本身是否被转义
+func isEscaped(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// closers 按栈的逆序生成闭合符
+func closers(stack []frame) string {
+	var sb strings.Builder
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].kind == '{' {
+			sb.WriteByte('}')
+		} else {
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// flatten 按文档顺序遍历 JSON 的所有叶子节点
+func flatten(doc string, emit func(path string, value any)) error {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	return walk(dec, "", emit)
+}
+
+func walk(dec *json.Decoder, path string, emit func(path string, value any)) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				if err := walk(dec, child, emit); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				if err := walk(dec, path+"["+strconv.Itoa(i)+"]", emit); err != nil {
+					return err
+				}
+			}
+		}
+		// 消费结尾的 '}' 或 ']'
+		if _, err := dec.Token(); err != nil && err != io.EOF {
+			return err
+		}
+	default:
+		emit(path, t)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package lang
+
+import (
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// LastUser 识别最后一条用户消息的语言，没有用户消息时返回空的 Detection
+func LastUser(messages []spec.Message) Detection {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == spec.RoleUser {
+			return Detect(messages[i].PlainText())
+		}
+	}
+	return Detection{}
+}
+
+// Instruction 返回要求模型使用 code 对应语言回复的提示词
+func Instruction(code string) string {
+	return fmt.Sprintf("无论之前的对话使用何种语言，请使用%s（%s）回复用户，除非用户明确要求使用其他语言。代码、专有名词和引用的原文保持不变。", Name(code), code)
+}
+
+// Instruct 把回复语言要求追加到第一条系统消息末尾，没有系统消息时在开头插入一条。
+// 返回新的切片，不修改 messages；code 为空时原样返回
+func Instruct(messages []spec.Message, code string) []spec.Message {
+	if code == "" {
+		return messages
+	}
+	instruction := Instruction(code)
+	if len(messages) > 0 && messages[0].Role == spec.RoleSystem && len(messages[0].Parts) == 0 {
+		out := append([]spec.Message(nil), messages...)
+		out[0].Content += "\n\n" + instruction
+		return out
+	}
+	return append([]spec.Message{spec.NewSystemMessage(instruction)}, messages...)
+}
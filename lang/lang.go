@@ -0,0 +1,216 @@
+// Package lang 识别文本的语言，并提供让模型使用指定语言回复的辅助函数。
+// 识别基于文字系统和常用词，不依赖模型和外部词典，适合对话消息这类短文本：
+//
+//	d := lang.Detect("¿Dónde está la estación?")
+//	// d.Code == "es"
+//	messages = lang.Instruct(messages, d.Code)
+package lang
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Detection 是语言识别的结果
+type Detection struct {
+	// Code ISO 639-1 语言代码，如 "zh"、"en"，无法识别时为空
+	Code string
+	// Confidence 置信度，范围 0~1
+	Confidence float64
+}
+
+// names 是支持识别的语言代码及其英文名称，英文名称可直接用于 spec.TranslationOptions
+var names = map[string]string{
+	"zh": "Chinese",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"pt": "Portuguese",
+	"it": "Italian",
+	"nl": "Dutch",
+	"id": "Indonesian",
+	"vi": "Vietnamese",
+	"tr": "Turkish",
+	"ru": "Russian",
+	"uk": "Ukrainian",
+	"ar": "Arabic",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"th": "Thai",
+	"el": "Greek",
+}
+
+// Name 返回语言代码对应的英文名称，未知代码原样返回
+func Name(code string) string {
+	if name, ok := names[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// Codes 返回所有支持识别的语言代码
+func Codes() []string {
+	codes := make([]string, 0, len(names))
+	for code := range names {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// noise 匹配识别时忽略的内容：代码块、行内代码和链接
+var noise = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`|https?://\\S+")
+
+// scripts 是按文字系统直接确定语言的 Unicode 区块，汉字、假名和拉丁字母单独处理
+var scripts = []struct {
+	code  string
+	table *unicode.RangeTable
+}{
+	{"ko", unicode.Hangul},
+	{"ru", unicode.Cyrillic},
+	{"ar", unicode.Arabic},
+	{"he", unicode.Hebrew},
+	{"hi", unicode.Devanagari},
+	{"th", unicode.Thai},
+	{"el", unicode.Greek},
+}
+
+// Detect 识别 text 的主要语言。先按文字系统统计字母：含假名的汉字文本为日语，
+// 拉丁字母文本再按常用词和特殊字母区分具体语言；字母过少时返回空的 Code
+func Detect(text string) Detection {
+	text = noise.ReplaceAllString(text, " ")
+	counts := make(map[string]int)
+	total := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		total++
+		switch {
+		case unicode.Is(unicode.Han, r):
+			counts["han"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts["kana"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["latin"]++
+		default:
+			for _, s := range scripts {
+				if unicode.Is(s.table, r) {
+					counts[s.code]++
+					break
+				}
+			}
+		}
+	}
+	if total < 2 {
+		return Detection{}
+	}
+
+	// 汉字和假名合并计算，避免日语被识别为中文
+	cjk := counts["han"] + counts["kana"]
+	best, bestCount := "", 0
+	for script, n := range counts {
+		if script == "han" || script == "kana" {
+			continue
+		}
+		if n > bestCount || (n == bestCount && script < best) {
+			best, bestCount = script, n
+		}
+	}
+	// 一个汉字的信息量约等于一个拉丁单词，按 3 倍计算，使中英混排的文本倾向于中文
+	if cjk*3 >= bestCount && cjk > 0 {
+		confidence := min(1, float64(cjk*3)/float64(cjk*3+total-cjk))
+		if counts["kana"] > 0 && counts["kana"]*5 >= cjk {
+			return Detection{Code: "ja", Confidence: confidence}
+		}
+		return Detection{Code: "zh", Confidence: confidence}
+	}
+
+	confidence := float64(bestCount) / float64(total)
+	switch best {
+	case "latin":
+		code, score := detectLatin(text)
+		return Detection{Code: code, Confidence: confidence * score}
+	case "ru":
+		if strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			best = "uk"
+		}
+	}
+	return Detection{Code: best, Confidence: confidence}
+}
+
+// latinWords 是各拉丁字母语言最常用的虚词
+var latinWords = map[string][]string{
+	"en": {"the", "and", "is", "are", "to", "of", "in", "it", "you", "that", "what", "how", "this", "for", "with", "can", "do", "i", "my", "please"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "en", "es", "por", "para", "con", "una", "un", "cómo", "qué", "está", "dónde", "mi", "gracias"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "que", "en", "un", "une", "pour", "dans", "je", "vous", "pas", "qui", "avec", "ce", "comment"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "du", "sie", "ein", "eine", "zu", "mit", "auf", "für", "wie", "was", "den", "es", "bitte"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "é", "em", "um", "uma", "para", "com", "não", "como", "você", "do", "da", "está", "obrigado"},
+	"it": {"il", "la", "di", "che", "e", "è", "un", "una", "per", "non", "con", "sono", "come", "del", "della", "questo", "cosa", "mi", "ciao", "grazie"},
+	"nl": {"de", "het", "een", "en", "is", "van", "dat", "niet", "ik", "je", "met", "op", "voor", "zijn", "wat", "hoe", "naar", "ook", "maar", "bedankt"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "saya", "anda", "ada", "dari", "apa", "bagaimana", "bisa", "akan", "ke", "juga", "terima", "kasih"},
+	"tr": {"ve", "bir", "bu", "için", "ne", "ile", "mi", "da", "de", "ben", "sen", "nasıl", "var", "yok", "çok", "değil", "gibi", "ama", "teşekkürler", "lütfen"},
+}
+
+// latinLetters 是各语言特有的字母，出现时额外加分
+var latinLetters = map[string]string{
+	"es": "ñ¿¡",
+	"fr": "çœêèà",
+	"de": "ßäöü",
+	"pt": "ãõç",
+	"tr": "ğış",
+	"vi": "ăơưđạảấầẩẫậắằẳẵặẹẻẽếềểễệỉịọỏốồổỗộớờởỡợụủứừửữựỳỵỷỹ",
+}
+
+// latinWord 匹配拉丁字母单词
+var latinWord = regexp.MustCompile(`[\p{Latin}']+`)
+
+// detectLatin 按常用词和特有字母区分拉丁字母语言，返回语言代码和相对得分（0~1）。
+// 没有任何特征时视为英语
+func detectLatin(text string) (string, float64) {
+	lower := strings.ToLower(text)
+	words := latinWord.FindAllString(lower, -1)
+	scores := make(map[string]float64)
+	for code, list := range latinWords {
+		set := make(map[string]bool, len(list))
+		for _, w := range list {
+			set[w] = true
+		}
+		for _, w := range words {
+			if set[w] {
+				scores[code]++
+			}
+		}
+	}
+	for code, letters := range latinLetters {
+		for _, r := range lower {
+			if strings.ContainsRune(letters, r) {
+				// 越南语几乎没有独有的常用词，依赖声调字母
+				if code == "vi" {
+					scores[code] += 2
+				} else {
+					scores[code] += 0.5
+				}
+			}
+		}
+	}
+
+	best, second := "", 0.0
+	for _, code := range Codes() {
+		s := scores[code]
+		if best == "" || s > scores[best] {
+			best, second = code, scores[best]
+		} else if s > second {
+			second = s
+		}
+	}
+	if scores[best] == 0 {
+		return "en", 0.5
+	}
+	return best, scores[best] / (scores[best] + second)
+}
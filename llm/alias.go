@@ -0,0 +1,124 @@
+package llm
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxAliasDepth 是别名链的最大长度，用于避免循环引用
+const maxAliasDepth = 8
+
+// aliases 是已注册的模型别名
+var (
+	aliases   = make(map[string]Config)
+	aliasesMu sync.RWMutex
+)
+
+// RegisterAlias 注册模型别名，使业务代码引用能力而不是具体的模型 ID：
+//
+//	llm.RegisterAlias("fast", "dashscope/qwen-turbo")
+//	llm.RegisterAlias("smart", "openai/gpt-4o")
+//	cfg := llm.Config{Model: "fast"}
+//
+// target 为 "provider/model" 或只有模型名（沿用调用时配置的 Provider）；
+// "/" 之前的部分不是已注册的 Provider 时整体作为模型名，如 OpenRouter 的 "openai/gpt-4o"。
+// 别名在每次调用时解析，重新注册后立即生效。切换到其他 Provider 时 API Key 从环境变量
+// <PROVIDER>_API_KEY（如 OPENAI_API_KEY）读取，需要显式指定时使用 RegisterAliasConfig
+func RegisterAlias(name, target string) {
+	t := Config{Model: target}
+	if provider, model, ok := strings.Cut(target, "/"); ok && slices.Contains(SupportedProviders(), provider) {
+		t.Provider, t.Model = provider, model
+	}
+	RegisterAliasConfig(name, t)
+}
+
+// RegisterAliasConfig 使用完整配置注册模型别名：target 中的 Provider、Model、APIKey、APIURL、APIKeyPool
+// 非空时覆盖调用时的配置，Parameters 作为默认参数与调用时的参数合并（调用时的参数优先）
+func RegisterAliasConfig(name string, target Config) {
+	if name == "" || target.Model == "" {
+		panic("llm: RegisterAlias requires a name and a target model")
+	}
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	aliases[name] = target
+}
+
+// RemoveAlias 删除模型别名
+func RemoveAlias(name string) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	delete(aliases, name)
+}
+
+// Aliases 返回所有别名及其目标，目标格式为 "provider/model"（未指定 Provider 时只有模型名）
+func Aliases() map[string]string {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	out := make(map[string]string, len(aliases))
+	for name, t := range aliases {
+		out[name] = t.Model
+		if t.Provider != "" {
+			out[name] = t.Provider + "/" + t.Model
+		}
+	}
+	return out
+}
+
+// ResolveAlias 返回解析了模型别名的配置，cfg.Model 不是别名时原样返回。
+// GetClient 和 RunFailover 会自动解析，通常不需要直接调用
+func ResolveAlias(cfg Config) Config {
+	cfg, _ = resolveAlias(cfg)
+	return cfg
+}
+
+// resolveAlias 解析别名，别名链过长（通常是循环引用）时返回错误
+func resolveAlias(cfg Config) (Config, error) {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	if len(aliases) == 0 {
+		return cfg, nil
+	}
+	for range maxAliasDepth {
+		t, ok := aliases[cfg.Model]
+		if !ok {
+			return cfg, nil
+		}
+		cfg = applyAlias(cfg, t)
+	}
+	if _, ok := aliases[cfg.Model]; ok {
+		return cfg, fmt.Errorf("llm: model alias %q is nested too deeply or is circular", cfg.Model)
+	}
+	return cfg, nil
+}
+
+// applyAlias 把别名目标 t 应用到 cfg
+func applyAlias(cfg, t Config) Config {
+	if t.Provider != "" && t.Provider != cfg.Provider {
+		// 切换 Provider 时原有的连接配置不再适用
+		cfg.Provider = t.Provider
+		cfg.APIKey, cfg.APIURL, cfg.APIKeyPool = "", "", nil
+		if t.APIKey == "" && t.APIKeyPool == nil {
+			cfg.APIKey = os.Getenv(strings.ToUpper(t.Provider) + "_API_KEY")
+		}
+	}
+	cfg.Model = t.Model
+	if t.APIKey != "" {
+		cfg.APIKey = t.APIKey
+	}
+	if t.APIURL != "" {
+		cfg.APIURL = t.APIURL
+	}
+	if t.APIKeyPool != nil {
+		cfg.APIKeyPool = t.APIKeyPool
+	}
+	if len(t.Parameters) > 0 {
+		params := maps.Clone(t.Parameters)
+		maps.Copy(params, cfg.Parameters)
+		cfg.Parameters = params
+	}
+	return cfg
+}
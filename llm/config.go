@@ -1,6 +1,10 @@
 package llm
 
-import "github.com/iEvan-lhr/go-llm-client/spec"
+import (
+	"crypto/tls"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
 
 // Config 包含了执行一次Chat调用所需的所有配置。
 type Config struct {
@@ -22,6 +26,65 @@ type Config struct {
 	WebExtractor *WebExtractorOptions
 
 	ProviderOpts map[string]any
+
+	// APIKeyPool 多 Key 负载均衡配置，设置后 APIKey 可以为空
+	APIKeyPool *spec.APIKeyPool
+
+	// Interceptors 请求/响应拦截器，作用于底层的每一次 HTTP 请求
+	Interceptors []spec.Interceptor
+
+	// ClientID 底层客户端的标识。配置了 Interceptors、Logger 或 TLSConfig 时，只有 ClientID 相同的配置才会共享
+	// 缓存的客户端；未设置时不缓存，每次 GetClient 都会创建新的客户端
+	ClientID string
+
+	// Logger 日志记录器，nil 表示使用 slog.Default()；输出前会自动遮盖 API Key
+	Logger spec.Logger
+
+	// Proxy 代理地址，支持 http/https/socks5，例如 "socks5://127.0.0.1:1080"
+	Proxy string
+
+	// TLSConfig 自定义 TLS 配置，ClientCert / ClientKey 为 mTLS 客户端证书（PEM 文件路径或内容）
+	TLSConfig  *tls.Config
+	ClientCert string
+	ClientKey  string
+
+	// Timeouts 分阶段的超时配置，nil 表示使用 spec.DefaultTimeouts()
+	Timeouts *spec.Timeouts
+
+	// CompressRequests 是否对较大的请求体（>= 1KB）进行 gzip 压缩
+	CompressRequests bool
+
+	// Singleflight 是否合并相同的并发非流式请求，共享同一份响应
+	Singleflight bool
+
+	// RateLimit 客户端侧限流配置，nil 表示不限流。
+	// 限流状态保存在缓存的客户端中；设置了 Interceptors、Logger 或 TLSConfig 而未设置 ClientID 时客户端不会被缓存，
+	// 此时每次 llm.Chat 都会创建新客户端，限流只在单次调用内生效
+	RateLimit *spec.RateLimit
+
+	// RetryPolicy 请求失败重试策略，nil 表示不重试
+	RetryPolicy *spec.RetryPolicy
+	// CircuitBreaker 熔断配置，nil 表示不启用。
+	// 与 RateLimit 相同，未缓存的客户端（见上）在每次 llm.Chat 中都从闭合状态开始，需要跨调用熔断时请设置 ClientID
+	CircuitBreaker *spec.CircuitBreakerConfig
+
+	// Hedge 对冲请求策略，慢请求超过 Delay 时并发发出备份请求，nil 表示不启用
+	Hedge *spec.HedgePolicy
+
+	// ResponsesModels 默认通过 OpenAI Responses API 调用的模型，支持 "o3-pro*" 形式的前缀匹配
+	ResponsesModels []string
+
+	// NativeProtocol 使用提供商的原生协议而不是 OpenAI 兼容模式，目前仅 DashScope 支持
+	NativeProtocol bool
+
+	// Failover 故障转移配置，主后端失败时依次尝试备用后端，nil 表示不启用
+	Failover *FailoverConfig
+
+	// DebugDump 非空时把每次 HTTP 请求和响应写入该目录，见 spec.DebugDump
+	DebugDump string
+
+	// Offline 离线模式，设置后根据提示词返回预置回答而不调用 Provider，见 Offline
+	Offline *Offline
 }
 
 var (
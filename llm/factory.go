@@ -1,8 +1,11 @@
 package llm
 
 import (
+	"errors"
 	"fmt"
 	"github.com/iEvan-lhr/go-llm-client/providers/deepseek"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/iEvan-lhr/go-llm-client/providers/dashscope"
@@ -18,56 +21,238 @@ var (
 	cacheMutex  = &sync.RWMutex{}
 )
 
+// ProviderFactory 使用客户端选项创建 Provider 的客户端
+type ProviderFactory func(opts ...spec.ClientOption) (spec.Client, error)
+
+// providers 是已注册的 Provider，内置 Provider 在初始化时注册
+var (
+	providers   = make(map[string]ProviderFactory)
+	providersMu sync.RWMutex
+)
+
+func init() {
+	RegisterProvider("dashscope", dashscope.NewClient)
+	RegisterProvider("generic", generic.NewClient)
+	RegisterProvider("openai", openai.NewClient)
+	RegisterProvider("openrouter", openrouter.NewClient)
+	RegisterProvider("deepseek", deepseek.NewClient)
+}
+
+// RegisterProvider 注册一个 Provider，之后 Config.Provider 为 name 时使用 factory 创建客户端，
+// 第三方包通常在 init 中调用。同名 Provider 以后注册的为准，可用于替换内置实现；
+// 已缓存的客户端不受影响，需要时先调用 CloseAll
+func RegisterProvider(name string, factory ProviderFactory) {
+	if name == "" || factory == nil {
+		panic("llm: RegisterProvider requires a name and a factory")
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = factory
+}
+
+// SupportedProviders 返回已注册的 Provider 名称列表，按字母顺序排列
+func SupportedProviders() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetClient 负责创建和缓存客户端实例。
 // 它是导出的，因此 client 包可以使用它。
+//
+// 配置了 Interceptors、Logger、TLSConfig 这类只能按引用区分的选项时，不同闭包或实例无法可靠地判断是否相同，
+// 此时只有设置了 Config.ClientID 才会缓存，否则每次返回新的客户端，由调用方负责复用。
 func GetClient(cfg Config) (spec.Client, error) {
-	cacheKey := fmt.Sprintf("%s|%s|%s", cfg.Provider, cfg.APIURL, cfg.APIKey)
+	c, _, err := getClient(cfg)
+	return c, err
+}
+
+// AcquireClient 与 GetClient 相同，返回的 release 会关闭不缓存的客户端，用于只在一次调用中使用客户端的场景，
+// 调用结束后必须调用 release
+func AcquireClient(cfg Config) (spec.Client, func(), error) {
+	c, cached, err := getClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cached {
+		return c, func() {}, nil
+	}
+	return c, func() { c.Close() }, nil
+}
+
+// getClient 返回 cfg 对应的客户端，cached 表示客户端位于缓存中，调用方不应关闭
+func getClient(cfg Config) (client spec.Client, cached bool, err error) {
+	cfg, err = resolveAlias(cfg)
+	if err != nil {
+		return nil, false, err
+	}
+	if o, err := offlineFor(cfg); err != nil || o != nil {
+		if err != nil {
+			return nil, false, err
+		}
+		return offlineClient{offline: o}, true, nil
+	}
+	cacheKey, cacheable := clientCacheKey(cfg)
+	if !cacheable {
+		client, err := newClient(cfg)
+		return client, false, err
+	}
 
 	cacheMutex.RLock()
 	client, found := clientCache[cacheKey]
 	cacheMutex.RUnlock()
-
 	if found {
-		return client, nil
+		return client, true, nil
 	}
 
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
-
-	client, found = clientCache[cacheKey]
-	if found {
-		return client, nil
+	if client, found = clientCache[cacheKey]; found {
+		return client, true, nil
 	}
+	if client, err = newClient(cfg); err != nil {
+		return nil, false, err
+	}
+	clientCache[cacheKey] = client
+	return client, true, nil
+}
 
+// newClient 根据配置创建新的 provider 客户端
+func newClient(cfg Config) (spec.Client, error) {
 	clientOpts := []spec.ClientOption{
 		spec.WithAPIKey(cfg.APIKey),
 	}
 	if cfg.APIURL != "" {
 		clientOpts = append(clientOpts, spec.WithAPIURL(cfg.APIURL))
 	}
+	if cfg.APIKeyPool != nil {
+		clientOpts = append(clientOpts, spec.WithAPIKeyPool(*cfg.APIKeyPool))
+	}
+	if len(cfg.Interceptors) > 0 {
+		clientOpts = append(clientOpts, spec.WithInterceptors(cfg.Interceptors...))
+	}
+	if cfg.DebugDump != "" {
+		clientOpts = append(clientOpts, spec.WithDebugDump(cfg.DebugDump))
+	}
+	if cfg.Logger != nil {
+		clientOpts = append(clientOpts, spec.WithLogger(cfg.Logger))
+	}
+	if cfg.Proxy != "" {
+		clientOpts = append(clientOpts, spec.WithProxy(cfg.Proxy))
+	}
+	if cfg.TLSConfig != nil {
+		clientOpts = append(clientOpts, spec.WithTLSConfig(cfg.TLSConfig))
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		clientOpts = append(clientOpts, spec.WithClientCertificate(cfg.ClientCert, cfg.ClientKey))
+	}
+	if cfg.Timeouts != nil {
+		clientOpts = append(clientOpts, spec.WithTimeouts(*cfg.Timeouts))
+	}
+	if cfg.CompressRequests {
+		clientOpts = append(clientOpts, spec.WithRequestCompression(0))
+	}
+	if cfg.Singleflight {
+		clientOpts = append(clientOpts, spec.WithSingleflight())
+	}
+	if cfg.RateLimit != nil {
+		clientOpts = append(clientOpts, spec.WithRateLimit(cfg.RateLimit.RPS, cfg.RateLimit.Burst), spec.WithTokensPerMinute(cfg.RateLimit.TokensPerMinute))
+	}
+	if cfg.RetryPolicy != nil {
+		clientOpts = append(clientOpts, spec.WithRetryPolicy(*cfg.RetryPolicy))
+	}
+	if cfg.CircuitBreaker != nil {
+		clientOpts = append(clientOpts, spec.WithCircuitBreaker(*cfg.CircuitBreaker))
+	}
+	if cfg.Hedge != nil {
+		clientOpts = append(clientOpts, spec.WithHedging(*cfg.Hedge))
+	}
+	if len(cfg.ResponsesModels) > 0 {
+		clientOpts = append(clientOpts, spec.WithResponsesModels(cfg.ResponsesModels...))
+	}
+	if cfg.NativeProtocol {
+		clientOpts = append(clientOpts, spec.WithNativeProtocol())
+	}
 
-	var newClient spec.Client
-	var err error
-
-	switch cfg.Provider {
-	case "dashscope":
-		newClient, err = dashscope.NewClient(clientOpts...)
-	case "generic":
-		newClient, err = generic.NewClient(clientOpts...)
-	case "openai":
-		newClient, err = openai.NewClient(clientOpts...)
-	case "openrouter": // ✅ 新增 openrouter 匹配分支
-		newClient, err = openrouter.NewClient(clientOpts...)
-	case "deepseek":
-		newClient, err = deepseek.NewClient(clientOpts...)
-	default:
+	providersMu.RLock()
+	factory, ok := providers[cfg.Provider]
+	providersMu.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unknown provider: %s", cfg.Provider)
 	}
+	return factory(clientOpts...)
+}
 
-	if err != nil {
-		return nil, err
+// clientCacheKey 生成客户端缓存键，影响底层客户端行为的配置都需要参与。
+// 函数和指针类型的选项（Interceptors、Logger、TLSConfig）无法按值比较，此时使用 ClientID 作为标识，
+// 未设置 ClientID 时返回 false，表示不缓存
+func clientCacheKey(cfg Config) (string, bool) {
+	key := fmt.Sprintf("%s|%s|%s", cfg.Provider, cfg.APIURL, cfg.APIKey)
+	if cfg.APIKeyPool != nil {
+		key += fmt.Sprintf("|keys:%+v", *cfg.APIKeyPool)
+	}
+	if len(cfg.Interceptors) > 0 || cfg.Logger != nil || cfg.TLSConfig != nil {
+		if cfg.ClientID == "" {
+			return "", false
+		}
+		key += "|id:" + cfg.ClientID
+	}
+	if cfg.DebugDump != "" {
+		key += "|dump:" + cfg.DebugDump
 	}
+	if cfg.Proxy != "" {
+		key += "|proxy:" + cfg.Proxy
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		key += "|cert:" + cfg.ClientCert + "|" + cfg.ClientKey
+	}
+	if cfg.Timeouts != nil {
+		key += fmt.Sprintf("|timeouts:%+v", *cfg.Timeouts)
+	}
+	if cfg.CompressRequests {
+		key += "|gzip"
+	}
+	if cfg.Singleflight {
+		key += "|singleflight"
+	}
+	if cfg.RateLimit != nil {
+		key += fmt.Sprintf("|rate:%+v", *cfg.RateLimit)
+	}
+	if cfg.RetryPolicy != nil {
+		key += fmt.Sprintf("|retry:%+v", *cfg.RetryPolicy)
+	}
+	if cfg.CircuitBreaker != nil {
+		key += fmt.Sprintf("|breaker:%+v", *cfg.CircuitBreaker)
+	}
+	if cfg.Hedge != nil {
+		key += fmt.Sprintf("|hedge:%+v", *cfg.Hedge)
+	}
+	if len(cfg.ResponsesModels) > 0 {
+		key += "|responses:" + strings.Join(cfg.ResponsesModels, ",")
+	}
+	if cfg.NativeProtocol {
+		key += "|native"
+	}
+	return key, true
+}
 
-	clientCache[cacheKey] = newClient
-	return newClient, nil
+// CloseAll 关闭所有已缓存的客户端并清空缓存，通常在进程退出前调用。
+// 进行中的请求会被取消，之后 GetClient 会重新创建新的客户端实例。
+func CloseAll() error {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	var errs []error
+	for key, c := range clientCache {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(clientCache, key)
+	}
+	return errors.Join(errs...)
 }
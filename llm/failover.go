@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// FailoverConfig 配置故障转移链。
+// 主配置调用失败且错误满足 ShouldFailover 时，按顺序尝试 Fallbacks 中的备用后端，
+// 实际应答的后端记录在 Response.Provider / Response.Model 中。
+type FailoverConfig struct {
+	// Fallbacks 备用后端配置，按顺序尝试；其中的 Failover 字段会被忽略。
+	// 备用配置未设置 StreamCallback 时沿用主配置的回调。
+	Fallbacks []Config
+	// AttemptTimeout 单个后端的超时时间，超时后切换到下一个后端，0 表示不限制
+	AttemptTimeout time.Duration
+	// ShouldFailover 判断错误是否需要切换后端，为 nil 时使用 IsFailoverError
+	ShouldFailover func(err error) bool
+}
+
+// IsFailoverError 判断错误是否适合切换到备用后端：
+// 限流、5xx、网络错误、重试耗尽、熔断打开、流式空闲超时以及单个后端超时。
+// 鉴权失败、参数错误等 4xx 错误换后端通常也无法解决，不会触发切换。
+func IsFailoverError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var idle *spec.StreamIdleTimeoutError
+	if errors.As(err, &idle) {
+		return true
+	}
+	return requester.IsTransient(err)
+}
+
+// ChatFunc 使用指定的底层客户端和配置执行一次调用
+type ChatFunc func(ctx context.Context, client spec.Client, cfg Config) (*spec.Response, error)
+
+// RunFailover 使用 cfg 执行 call，失败时按 cfg.Failover 依次尝试备用后端。
+// primary 为主配置对应的客户端，为 nil 时通过 GetClient 获取。
+// 调用方的 ctx 被取消或超时后不会再切换后端。
+func RunFailover(ctx context.Context, cfg Config, primary spec.Client, call ChatFunc) (*spec.Response, error) {
+	resolved, err := resolveAlias(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// 别名指向其他后端时，调用方传入的客户端不再适用
+	if resolved.Provider != cfg.Provider || resolved.APIKey != cfg.APIKey || resolved.APIURL != cfg.APIURL {
+		primary = nil
+	}
+	backends := []Config{resolved}
+	var fc FailoverConfig
+	if cfg.Failover != nil {
+		fc = *cfg.Failover
+		for _, fb := range fc.Fallbacks {
+			fb.Failover = nil
+			if fb.StreamCallback == nil {
+				fb.StreamCallback = cfg.StreamCallback
+			}
+			if fb, err = resolveAlias(fb); err != nil {
+				return nil, err
+			}
+			backends = append(backends, fb)
+		}
+	}
+	shouldFailover := fc.ShouldFailover
+	if shouldFailover == nil {
+		shouldFailover = IsFailoverError
+	}
+
+	var errs []error
+	for i, backend := range backends {
+		client, release := primary, func() {}
+		if i > 0 || client == nil {
+			var err error
+			if client, release, err = AcquireClient(backend); err != nil {
+				return nil, fmt.Errorf("failed to get client for provider '%s': %w", backend.Provider, err)
+			}
+		}
+
+		resp, err := callWithTimeout(ctx, fc.AttemptTimeout, client, backend, call)
+		release()
+		if err == nil {
+			resp.Provider, resp.Model = backend.Provider, backend.Model
+			return resp, nil
+		}
+		if i == 0 && (len(backends) == 1 || ctx.Err() != nil || !shouldFailover(err)) {
+			// 主后端的错误不需要切换时原样返回
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s/%s: %w", backend.Provider, backend.Model, err))
+		if ctx.Err() != nil || !shouldFailover(err) {
+			break
+		}
+	}
+	return nil, fmt.Errorf("llm: failover exhausted after %d backend(s): %w", len(errs), errors.Join(errs...))
+}
+
+// callWithTimeout 在单个后端的超时限制内执行 call
+func callWithTimeout(ctx context.Context, timeout time.Duration, client spec.Client, cfg Config, call ChatFunc) (*spec.Response, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return call(ctx, client, cfg)
+}
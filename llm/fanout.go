@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// FanOutStrategy 决定 FanOut 如何从多个模型的回复中选出最终结果
+type FanOutStrategy struct {
+	kind fanOutKind
+	// judge 评审模型的配置，仅 JudgeBy 使用
+	judge    *Config
+	criteria string
+}
+
+type fanOutKind int
+
+const (
+	firstSuccess fanOutKind = iota
+	fastest
+	majorityVote
+	judgeModel
+)
+
+// FirstSuccess 按 cfgs 的顺序选择第一个成功的回复，排在前面的模型失败时才使用后面的结果。
+// 选定结果后会取消仍在进行的调用
+func FirstSuccess() FanOutStrategy {
+	return FanOutStrategy{kind: firstSuccess}
+}
+
+// Fastest 选择最先成功返回的回复，并取消其余调用
+func Fastest() FanOutStrategy {
+	return FanOutStrategy{kind: fastest}
+}
+
+// MajorityVote 等待全部调用完成，选择内容相同（忽略大小写、首尾空白和末尾标点）次数最多的回复，
+// 票数相同时选择 cfgs 中靠前的。适合分类、判断等答案较短的任务
+func MajorityVote() FanOutStrategy {
+	return FanOutStrategy{kind: majorityVote}
+}
+
+// JudgeBy 等待全部调用完成，由 judge 配置的模型比较各回复并选出最佳的一个。
+// criteria 为评判标准，为空时按准确性、完整性和表达清晰程度评判
+func JudgeBy(judge Config, criteria string) FanOutStrategy {
+	return FanOutStrategy{kind: judgeModel, judge: &judge, criteria: criteria}
+}
+
+// Candidate 是一个模型的调用结果
+type Candidate struct {
+	Config   Config
+	Response *spec.Response
+	Err      error
+	// Latency 调用耗时
+	Latency time.Duration
+}
+
+// FanOutResult 是 FanOut 的结果
+type FanOutResult struct {
+	// Candidates 与 cfgs 一一对应的调用结果，被取消的调用 Err 为 context.Canceled
+	Candidates []Candidate
+	// Chosen 选中的候选在 Candidates 中的下标
+	Chosen int
+	// Response 选中的回复
+	Response *spec.Response
+	// Judgement 评审模型的回复，仅 JudgeBy 策略有值
+	Judgement *spec.Response
+}
+
+// FanOut 把同一组消息并发发送给 cfgs 中的每个模型，按 strategy 选出最终回复。
+// 各配置的 StreamCallback 会被忽略，以免并发的流式输出交错。
+// 所有调用都失败时返回合并后的错误，此时结果中仍包含各候选的错误
+func FanOut(ctx context.Context, messages []spec.Message, cfgs []Config, strategy FanOutStrategy) (*FanOutResult, error) {
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("fanout: no configs provided")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &FanOutResult{Candidates: make([]Candidate, len(cfgs)), Chosen: -1}
+	done := make(chan int, len(cfgs))
+	for i, cfg := range cfgs {
+		cfg.StreamCallback = nil
+		result.Candidates[i].Config = cfg
+		go func() {
+			start := time.Now()
+			resp, err := ChatMessages(ctx, messages, cfg)
+			result.Candidates[i].Response, result.Candidates[i].Err = resp, err
+			result.Candidates[i].Latency = time.Since(start)
+			done <- i
+		}()
+	}
+
+	// 收集结果：FirstSuccess 和 Fastest 在选定后取消其余调用，但仍等待它们退出
+	finished := make([]bool, len(cfgs))
+	next := 0
+	for range cfgs {
+		i := <-done
+		finished[i] = true
+		if result.Chosen >= 0 {
+			continue
+		}
+		switch strategy.kind {
+		case fastest:
+			if result.Candidates[i].Err == nil {
+				result.Chosen = i
+				cancel()
+			}
+		case firstSuccess:
+			for next < len(cfgs) && finished[next] {
+				if result.Candidates[next].Err == nil {
+					result.Chosen = next
+					cancel()
+					break
+				}
+				next++
+			}
+		}
+	}
+
+	if !result.anySucceeded() {
+		errs := make([]error, 0, len(cfgs))
+		for _, c := range result.Candidates {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", c.Config.Provider, c.Config.Model, c.Err))
+		}
+		return result, fmt.Errorf("fanout: all %d models failed: %w", len(cfgs), errors.Join(errs...))
+	}
+
+	switch strategy.kind {
+	case majorityVote:
+		result.Chosen = result.vote()
+	case judgeModel:
+		chosen, judgement, err := result.judge(ctx, messages, strategy)
+		result.Judgement = judgement
+		if err != nil {
+			return result, err
+		}
+		result.Chosen = chosen
+	}
+	result.Response = result.Candidates[result.Chosen].Response
+	return result, nil
+}
+
+// anySucceeded 判断是否至少有一个调用成功
+func (r *FanOutResult) anySucceeded() bool {
+	for _, c := range r.Candidates {
+		if c.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// vote 返回票数最多的成功候选，票数相同时返回靠前的
+func (r *FanOutResult) vote() int {
+	counts := make(map[string]int)
+	first := make(map[string]int)
+	best, bestCount := -1, 0
+	for i, c := range r.Candidates {
+		if c.Err != nil {
+			continue
+		}
+		key := normalizeAnswer(c.Response.Message.PlainText())
+		if _, ok := first[key]; !ok {
+			first[key] = i
+		}
+		counts[key]++
+		if n := counts[key]; n > bestCount || (n == bestCount && first[key] < best) {
+			best, bestCount = first[key], n
+		}
+	}
+	return best
+}
+
+// normalizeAnswer 规范化回复以便比较：合并空白、转为小写并去掉末尾的标点
+func normalizeAnswer(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return strings.TrimRight(text, ".。!！")
+}
+
+// judgeNumber 匹配评审回复中的候选编号
+var judgeNumber = regexp.MustCompile(`\d+`)
+
+// judge 让评审模型从成功的候选中选出最佳回复，返回其下标
+func (r *FanOutResult) judge(ctx context.Context, messages []spec.Message, strategy FanOutStrategy) (int, *spec.Response, error) {
+	var ids []int
+	for i, c := range r.Candidates {
+		if c.Err == nil {
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) == 1 {
+		return ids[0], nil, nil
+	}
+
+	criteria := strategy.criteria
+	if criteria == "" {
+		criteria = "准确性、完整性和表达清晰程度"
+	}
+	var b strings.Builder
+	b.WriteString("请比较以下针对同一个问题的多个候选回答，按照" + criteria + "选出最好的一个。\n\n")
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == spec.RoleUser {
+			b.WriteString("问题：\n" + messages[i].PlainText() + "\n\n")
+			break
+		}
+	}
+	for n, i := range ids {
+		fmt.Fprintf(&b, "候选回答 %d：\n%s\n\n", n+1, r.Candidates[i].Response.Message.PlainText())
+	}
+	fmt.Fprintf(&b, "只输出最佳候选回答的编号（1 到 %d），不要输出其他内容。", len(ids))
+
+	judge := *strategy.judge
+	judge.StreamCallback = nil
+	resp, err := Chat(ctx, b.String(), judge)
+	if err != nil {
+		return -1, nil, fmt.Errorf("fanout: judge failed: %w", err)
+	}
+	reply := resp.Message.PlainText()
+	n, err := strconv.Atoi(judgeNumber.FindString(reply))
+	if err != nil || n < 1 || n > len(ids) {
+		return -1, resp, fmt.Errorf("fanout: judge returned an invalid choice %q", reply)
+	}
+	return ids[n-1], resp, nil
+}
@@ -8,24 +8,30 @@ import (
 
 // ChatMessages 是最核心的无状态调用函数，适用于多轮对话场景。
 func ChatMessages(ctx context.Context, messages []spec.Message, cfg Config) (*spec.Response, error) {
-	client, err := GetClient(cfg)
+	client, release, err := AcquireClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client for provider '%s': %w", cfg.Provider, err)
 	}
+	defer release()
 
-	var opts []spec.Option
-	if cfg.Parameters != nil {
-		opts = append(opts, spec.WithParameters(cfg.Parameters))
-	}
-	if cfg.Thinking != nil {
-		opts = append(opts, spec.WithThinking(*cfg.Thinking))
-	}
-	if cfg.StreamCallback != nil {
-		opts = append(opts, spec.WithStreamCallback(cfg.StreamCallback))
-	}
+	return RunFailover(ctx, cfg, client, func(ctx context.Context, client spec.Client, cfg Config) (*spec.Response, error) {
+		var opts []spec.Option
+		if cfg.Parameters != nil {
+			opts = append(opts, spec.WithParameters(cfg.Parameters))
+		}
+		if cfg.Thinking != nil {
+			opts = append(opts, spec.WithThinking(*cfg.Thinking))
+		}
+		if cfg.Translation != nil {
+			opts = append(opts, spec.WithTranslationOptions(*cfg.Translation))
+		}
+		if cfg.StreamCallback != nil {
+			opts = append(opts, spec.WithStreamCallback(cfg.StreamCallback))
+		}
 
-	model := client.Model(cfg.Model)
-	return model.Chat(ctx, messages, opts...)
+		model := client.Model(cfg.Model)
+		return model.Chat(ctx, messages, opts...)
+	})
 }
 
 // Chat 是一个便捷的无状态调用函数，适用于简单的单轮问答。
@@ -0,0 +1,314 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/yamlite"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// OfflineEnv 是启用离线模式的环境变量，值为预置回答文件的路径
+const OfflineEnv = "LLM_OFFLINE"
+
+// ErrNoCannedResponse 表示离线模式下没有与请求对应的预置回答
+var ErrNoCannedResponse = errors.New("llm: no canned response for prompt")
+
+// CannedResponse 是离线模式下的一条预置回答
+type CannedResponse struct {
+	Content   string          `json:"content"`
+	Reasoning string          `json:"reasoning,omitempty"`
+	ToolCalls []spec.ToolCall `json:"tool_calls,omitempty"`
+	// Usage 为 nil 时按 tokens 包估算
+	Usage *spec.Usage `json:"usage,omitempty"`
+}
+
+// UnmarshalJSON 允许回答直接写成字符串
+func (c *CannedResponse) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*c = CannedResponse{Content: s}
+		return nil
+	}
+	type plain CannedResponse
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*plain)(c))
+}
+
+// Offline 在离线模式下代替真实的 Provider，根据提示词的哈希返回预置回答，不发出任何网络请求，
+// 用于前端联调和端到端测试。查找顺序为：完整对话的哈希（HashMessages）、最后一条用户消息的哈希（HashPrompt）、
+// Default；都没有时返回 ErrNoCannedResponse，错误信息中包含两个哈希，便于补充到文件中。
+//
+// 预置回答文件为 JSON 或 YAML，回答可以是字符串或包含 content、reasoning、tool_calls、usage 的对象：
+//
+//	default: 离线模式下的默认回答
+//	responses:
+//	  9f86d081884c7d65: 你好，我是测试助手
+//	  3a7bd3e2360a3d29:
+//	    content: ""
+//	    tool_calls:
+//	      - {id: call_1, type: function, function: {name: get_weather, arguments: '{"city":"杭州"}'}}
+type Offline struct {
+	// Default 没有匹配的回答时使用，为 nil 时返回 ErrNoCannedResponse
+	Default *CannedResponse
+
+	mu        sync.RWMutex
+	responses map[string]CannedResponse
+}
+
+// NewOffline 创建空的 Offline，之后通过 Add、AddMessages、Set 添加回答
+func NewOffline() *Offline {
+	return &Offline{responses: make(map[string]CannedResponse)}
+}
+
+// offlineFile 是预置回答文件的结构
+type offlineFile struct {
+	Default   *CannedResponse           `json:"default"`
+	Responses map[string]CannedResponse `json:"responses"`
+}
+
+// ParseOffline 解析预置回答文件的内容，以 { 开头时按 JSON 解析，否则按 YAML 解析
+func ParseOffline(data []byte) (*Offline, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		doc, err := yamlite.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("llm: invalid offline responses: %w", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("llm: invalid offline responses: %w", err)
+		}
+	}
+	var file offlineFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("llm: invalid offline responses: %w", err)
+	}
+	o := NewOffline()
+	o.Default = file.Default
+	for hash, resp := range file.Responses {
+		o.responses[hash] = resp
+	}
+	return o, nil
+}
+
+// LoadOffline 从 JSON 或 YAML 文件加载预置回答
+func LoadOffline(path string) (*Offline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("llm: failed to read offline responses: %w", err)
+	}
+	o, err := ParseOffline(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return o, nil
+}
+
+// Add 为最后一条用户消息为 prompt 的请求设置回答
+func (o *Offline) Add(prompt string, resp CannedResponse) *Offline {
+	return o.Set(HashPrompt(prompt), resp)
+}
+
+// AddMessages 为与 messages 完全一致的对话设置回答，优先于 Add 设置的回答
+func (o *Offline) AddMessages(messages []spec.Message, resp CannedResponse) *Offline {
+	return o.Set(HashMessages(messages), resp)
+}
+
+// Set 按哈希设置回答
+func (o *Offline) Set(hash string, resp CannedResponse) *Offline {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.responses == nil {
+		o.responses = make(map[string]CannedResponse)
+	}
+	o.responses[hash] = resp
+	return o
+}
+
+// Lookup 返回 messages 对应的回答
+func (o *Offline) Lookup(messages []spec.Message) (CannedResponse, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	full := HashMessages(messages)
+	if resp, ok := o.responses[full]; ok {
+		return resp, nil
+	}
+	prompt := HashPrompt(lastUserText(messages))
+	if resp, ok := o.responses[prompt]; ok {
+		return resp, nil
+	}
+	if o.Default != nil {
+		return *o.Default, nil
+	}
+	return CannedResponse{}, fmt.Errorf("%w: prompt hash %s, conversation hash %s", ErrNoCannedResponse, prompt, full)
+}
+
+// HashPrompt 返回提示词文本的哈希（SHA-256 的前 16 位十六进制）
+func HashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+// HashMessages 返回对话的哈希，只包含角色、文本、工具调用和工具调用 ID，不包含思考过程
+func HashMessages(messages []spec.Message) string {
+	type call struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	}
+	type message struct {
+		Role       spec.Role `json:"role"`
+		Content    string    `json:"content"`
+		ToolCalls  []call    `json:"tool_calls,omitempty"`
+		ToolCallID string    `json:"tool_call_id,omitempty"`
+	}
+	normalized := make([]message, len(messages))
+	for i, m := range messages {
+		normalized[i] = message{Role: m.Role, Content: messageText(m), ToolCallID: m.ToolCallID}
+		for _, tc := range m.ToolCalls {
+			normalized[i].ToolCalls = append(normalized[i].ToolCalls, call{tc.Function.Name, tc.Function.Arguments})
+		}
+	}
+	data, _ := json.Marshal(normalized)
+	return HashPrompt(string(data))
+}
+
+// messageText 返回消息的文本，多模态消息取各文本片段
+func messageText(m spec.Message) string {
+	if m.Content != "" || len(m.Parts) == 0 {
+		return m.Content
+	}
+	var text string
+	for _, p := range m.Parts {
+		text += p.Text
+	}
+	return text
+}
+
+// lastUserText 返回最后一条用户消息的文本
+func lastUserText(messages []spec.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == spec.RoleUser {
+			return messageText(messages[i])
+		}
+	}
+	return ""
+}
+
+// offline 是 SetOffline 设置的全局离线模式，offlineLoaded 缓存从 offlinePath（LLM_OFFLINE）加载的回答
+var (
+	offlineMu     sync.Mutex
+	offline       *Offline
+	offlinePath   string
+	offlineLoaded *Offline
+)
+
+// SetOffline 为所有调用启用离线模式，传入 nil 恢复正常调用。Config.Offline 优先于此设置
+func SetOffline(o *Offline) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	offline = o
+}
+
+// offlineFor 返回 cfg 使用的离线模式，依次检查 Config.Offline、SetOffline 和 LLM_OFFLINE 环境变量，未启用时返回 nil
+func offlineFor(cfg Config) (*Offline, error) {
+	if cfg.Offline != nil {
+		return cfg.Offline, nil
+	}
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	if offline != nil {
+		return offline, nil
+	}
+	path := os.Getenv(OfflineEnv)
+	if path == "" {
+		return nil, nil
+	}
+	// 文件只在环境变量变化时重新加载
+	if path != offlinePath {
+		o, err := LoadOffline(path)
+		if err != nil {
+			return nil, err
+		}
+		offlinePath, offlineLoaded = path, o
+	}
+	return offlineLoaded, nil
+}
+
+// offlineClient 以 spec.Client 的形式提供离线回答
+type offlineClient struct {
+	offline *Offline
+}
+
+func (c offlineClient) Model(name string) spec.Model {
+	return offlineModel{offline: c.offline, name: name}
+}
+
+func (c offlineClient) Close() error { return nil }
+
+// offlineModel 返回预置回答，流式调用时按固定长度切分后逐块回调
+type offlineModel struct {
+	offline *Offline
+	name    string
+}
+
+// offlineChunkSize 是离线流式输出每块的字符数
+const offlineChunkSize = 4
+
+func (m offlineModel) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
+	config := spec.NewRequestConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	ctx = config.BindContext(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	canned, err := m.offline.Lookup(messages)
+	if err != nil {
+		return nil, err
+	}
+	if config.Streaming {
+		runes := []rune(canned.Content)
+		for i := 0; i < len(runes); i += offlineChunkSize {
+			if err := config.EmitStreamChunk(ctx, string(runes[i:min(i+offlineChunkSize, len(runes))])); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	usage := canned.Usage
+	if usage == nil {
+		u := spec.Usage{
+			PromptTokens:     tokens.CountMessages(m.name, messages),
+			CompletionTokens: tokens.Count(m.name, canned.Reasoning+canned.Content),
+		}
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+		usage = &u
+	}
+	return &spec.Response{
+		Message: spec.Message{
+			Role:             spec.RoleAssistant,
+			Content:          canned.Content,
+			ReasoningContent: canned.Reasoning,
+			ToolCalls:        canned.ToolCalls,
+		},
+		Usage:     *usage,
+		Timing:    config.Timing(),
+		RequestID: config.RequestID,
+	}, nil
+}
+
+func (m offlineModel) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
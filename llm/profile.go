@@ -0,0 +1,374 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/yamlite"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// ProfileEnv 是选择配置的环境变量，FromProfile("") 优先使用它指定的配置
+const ProfileEnv = "LLM_PROFILE"
+
+// ErrProfileNotFound 表示没有指定名称的配置
+var ErrProfileNotFound = errors.New("llm: profile not found")
+
+// Profile 是配置文件中的一个命名配置。字符串中的 ${VAR} 和 ${VAR:-默认值} 会在使用时替换为环境变量，
+// 适合填写 API Key 等密钥；没有默认值的变量未设置时返回错误
+type Profile struct {
+	Provider     string         `json:"provider"`
+	Model        string         `json:"model"`
+	APIKey       string         `json:"api_key,omitempty"`
+	APIURL       string         `json:"api_url,omitempty"`
+	SystemPrompt string         `json:"system_prompt,omitempty"`
+	Thinking     *bool          `json:"thinking,omitempty"`
+	Parameters   map[string]any `json:"parameters,omitempty"`
+	ProviderOpts map[string]any `json:"provider_opts,omitempty"`
+	// APIKeys 多个 API Key，设置后使用 APIKeyPool 轮询
+	APIKeys []string `json:"api_keys,omitempty"`
+	Proxy   string   `json:"proxy,omitempty"`
+	// Timeout 非流式请求的整体超时，Go 的时长格式，如 "60s"
+	Timeout string `json:"timeout,omitempty"`
+	// MaxRetries 失败后的最大重试次数，其余重试参数使用 spec.DefaultRetryPolicy
+	MaxRetries       int      `json:"max_retries,omitempty"`
+	CompressRequests bool     `json:"compress_requests,omitempty"`
+	Singleflight     bool     `json:"singleflight,omitempty"`
+	NativeProtocol   bool     `json:"native_protocol,omitempty"`
+	ResponsesModels  []string `json:"responses_models,omitempty"`
+	// Fallbacks 故障转移时依次尝试的其他配置名称
+	Fallbacks []string `json:"fallbacks,omitempty"`
+}
+
+// Profiles 是从配置文件加载的一组命名配置
+//
+//	default: prod-qwen
+//	profiles:
+//	  prod-qwen:
+//	    provider: dashscope
+//	    model: qwen-plus
+//	    api_key: ${DASHSCOPE_API_KEY}
+//	    parameters:
+//	      temperature: 0.7
+//	    fallbacks: [backup-deepseek]
+//	  backup-deepseek:
+//	    provider: deepseek
+//	    model: deepseek-chat
+//	    api_key: ${DEEPSEEK_API_KEY}
+type Profiles struct {
+	// Default 未指定名称且未设置 LLM_PROFILE 时使用的配置
+	Default string
+	// raw 未替换环境变量的原始配置，使用时再替换，使未用到的配置不需要设置对应的环境变量
+	raw map[string]any
+}
+
+// profileFile 是配置文件的结构
+type profileFile struct {
+	Default  string             `json:"default"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// ParseProfiles 解析配置文件内容，以 { 开头时按 JSON 解析，否则按 YAML 解析（支持常用子集，不支持锚点和别名，
+// 缩进只能使用空格）。YAML 中字符串字段的值未加引号时按原始文本解析，api_key: 0123456 得到 "0123456"
+func ParseProfiles(data []byte) (*Profiles, error) {
+	var doc any
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("llm: invalid profiles: %w", err)
+		}
+	} else {
+		var err error
+		if doc, err = yamlite.Parse(data); err != nil {
+			return nil, fmt.Errorf("llm: invalid profiles: %w", err)
+		}
+		doc = scalarStrings(doc, reflect.TypeOf(profileFile{}))
+	}
+
+	// 先完整解码一次，尽早发现字段名和类型错误
+	var file profileFile
+	if err := decodeStrict(doc, &file); err != nil {
+		return nil, fmt.Errorf("llm: invalid profiles: %w", err)
+	}
+	// 解码成功时 doc 一定是对象，profiles 字段缺失时 raw 为空
+	raw, _ := doc.(map[string]any)["profiles"].(map[string]any)
+	p := &Profiles{Default: file.Default, raw: make(map[string]any, len(raw))}
+	for name, profile := range file.Profiles {
+		for _, fb := range profile.Fallbacks {
+			if _, ok := file.Profiles[fb]; !ok {
+				return nil, fmt.Errorf("llm: profile %q: unknown fallback profile %q", name, fb)
+			}
+		}
+		p.raw[name] = raw[name]
+	}
+	if p.Default != "" {
+		if _, ok := p.raw[p.Default]; !ok {
+			return nil, fmt.Errorf("llm: default profile %q is not defined", p.Default)
+		}
+	}
+	return p, nil
+}
+
+// scalarStrings 把 doc 中对应 t 的字符串字段的 yamlite.Scalar 替换为原始文本，
+// 使 api_key: 0123456、model: 1.5 这类未加引号的值按写法原样解码为字符串
+func scalarStrings(doc any, t reflect.Type) any {
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := doc.(yamlite.Scalar); ok {
+			return s.Text
+		}
+	case reflect.Slice:
+		if items, ok := doc.([]any); ok {
+			for i, item := range items {
+				items[i] = scalarStrings(item, t.Elem())
+			}
+		}
+	case reflect.Map:
+		if m, ok := doc.(map[string]any); ok {
+			for k, v := range m {
+				m[k] = scalarStrings(v, t.Elem())
+			}
+		}
+	case reflect.Struct:
+		if m, ok := doc.(map[string]any); ok {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+				if v, ok := m[name]; ok && name != "" {
+					m[name] = scalarStrings(v, f.Type)
+				}
+			}
+		}
+	case reflect.Pointer:
+		return scalarStrings(doc, t.Elem())
+	}
+	return doc
+}
+
+// decodeStrict 把 JSON 结构解码到 v，不允许未知字段
+func decodeStrict(doc any, v any) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// Names 返回所有配置名称，按字母顺序排列
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.raw))
+	for name := range p.raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile 返回替换了环境变量的配置，name 为空时依次使用 LLM_PROFILE 环境变量和 Default
+func (p *Profiles) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = p.Default
+	}
+	if name == "" {
+		return nil, fmt.Errorf("llm: no profile specified, set %s or a default profile", ProfileEnv)
+	}
+	raw, ok := p.raw[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	expanded, err := expandEnv(raw)
+	if err != nil {
+		return nil, fmt.Errorf("llm: profile %q: %w", name, err)
+	}
+	var profile Profile
+	if err := decodeStrict(expanded, &profile); err != nil {
+		return nil, fmt.Errorf("llm: profile %q: %w", name, err)
+	}
+	return &profile, nil
+}
+
+// Config 返回配置名称对应的 Config，Fallbacks 转换为故障转移配置
+func (p *Profiles) Config(name string) (Config, error) {
+	profile, err := p.Profile(name)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := profile.Config()
+	if err != nil {
+		return Config{}, err
+	}
+	if len(profile.Fallbacks) > 0 {
+		cfg.Failover = &FailoverConfig{}
+		for _, fb := range profile.Fallbacks {
+			fp, err := p.Profile(fb)
+			if err != nil {
+				return Config{}, err
+			}
+			fallback, err := fp.Config()
+			if err != nil {
+				return Config{}, err
+			}
+			cfg.Failover.Fallbacks = append(cfg.Failover.Fallbacks, fallback)
+		}
+	}
+	return cfg, nil
+}
+
+// Config 把配置转换为 Config，不处理 Fallbacks（见 Profiles.Config）
+func (p *Profile) Config() (Config, error) {
+	if p.Provider == "" || p.Model == "" {
+		return Config{}, errors.New("llm: profile requires provider and model")
+	}
+	cfg := Config{
+		Provider:         p.Provider,
+		Model:            p.Model,
+		APIKey:           p.APIKey,
+		APIURL:           p.APIURL,
+		SystemPrompt:     p.SystemPrompt,
+		Thinking:         p.Thinking,
+		Parameters:       integralParams(p.Parameters),
+		ProviderOpts:     p.ProviderOpts,
+		Proxy:            p.Proxy,
+		CompressRequests: p.CompressRequests,
+		Singleflight:     p.Singleflight,
+		NativeProtocol:   p.NativeProtocol,
+		ResponsesModels:  p.ResponsesModels,
+	}
+	if len(p.APIKeys) > 0 {
+		cfg.APIKeyPool = &spec.APIKeyPool{Keys: p.APIKeys}
+	}
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil {
+			return Config{}, fmt.Errorf("llm: invalid timeout %q: %w", p.Timeout, err)
+		}
+		t := spec.DefaultTimeouts()
+		t.Total, t.ResponseHeader = d, d
+		cfg.Timeouts = &t
+	}
+	if p.MaxRetries > 0 {
+		policy := spec.DefaultRetryPolicy()
+		policy.MaxAttempts = p.MaxRetries + 1
+		cfg.RetryPolicy = &policy
+	}
+	return cfg, nil
+}
+
+// integralParams 把参数中的整数值（解码后为 float64）转换为 int，与代码中设置的 max_tokens 等参数类型一致
+func integralParams(params map[string]any) map[string]any {
+	for k, v := range params {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			params[k] = int(f)
+		}
+	}
+	return params
+}
+
+// envRef 匹配 ${VAR} 和 ${VAR:-默认值}
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv 递归替换 v 中所有字符串里的环境变量引用
+func expandEnv(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		var missing []string
+		s := envRef.ReplaceAllStringFunc(v, func(ref string) string {
+			m := envRef.FindStringSubmatch(ref)
+			if value, ok := os.LookupEnv(m[1]); ok && value != "" {
+				return value
+			}
+			if m[2] == "" {
+				missing = append(missing, m[1])
+			}
+			return m[3]
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("environment variable %s is not set", missing[0])
+		}
+		return s, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// profiles 是 LoadProfiles 加载的全局配置
+var (
+	profilesMu sync.RWMutex
+	profiles   *Profiles
+)
+
+// LoadProfiles 从 JSON 或 YAML 文件加载命名配置，替换之前加载的配置，之后可通过 FromProfile 使用
+func LoadProfiles(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("llm: failed to read profiles: %w", err)
+	}
+	p, err := ParseProfiles(data)
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	profilesMu.Lock()
+	profiles = p
+	profilesMu.Unlock()
+	return nil
+}
+
+// FromProfile 返回 LoadProfiles 加载的命名配置，name 为空时依次使用 LLM_PROFILE 环境变量和文件中的 default，
+// 从而不修改代码即可切换环境：
+//
+//	if err := llm.LoadProfiles("llm.yaml"); err != nil { ... }
+//	cfg, err := llm.FromProfile("") // LLM_PROFILE=prod-qwen
+//	c, err := client.New(cfg)
+func FromProfile(name string) (Config, error) {
+	profilesMu.RLock()
+	p := profiles
+	profilesMu.RUnlock()
+	if p == nil {
+		return Config{}, errors.New("llm: no profiles loaded, call LoadProfiles first")
+	}
+	return p.Config(name)
+}
+
+// ProfileNames 返回 LoadProfiles 加载的配置名称
+func ProfileNames() []string {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	if profiles == nil {
+		return nil
+	}
+	return profiles.Names()
+}
@@ -0,0 +1,183 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/textsplit"
+)
+
+// 摘要的默认参数
+const (
+	defaultSummaryChunkSize   = 3000
+	defaultSummaryConcurrency = 4
+	// maxSummaryRounds 合并阶段最多折叠的轮数，防止摘要无法缩短时无限循环
+	maxSummaryRounds = 5
+)
+
+// DefaultMapPrompt 是对单个文本块生成摘要的默认提示词，{text} 会被替换为文本块
+const DefaultMapPrompt = "请为以下文本写一段简洁的摘要，保留关键事实、数据和结论，不要添加原文没有的内容：\n\n{text}"
+
+// DefaultReducePrompt 是把多段摘要合并为最终摘要的默认提示词，{text} 会被替换为各段摘要
+const DefaultReducePrompt = "以下是同一篇长文各部分的摘要，请将它们合并为一份连贯、不重复的完整摘要：\n\n{text}"
+
+// SummarizeOptions 配置 Summarize，零值即可使用
+type SummarizeOptions struct {
+	// Splitter 切分长文本的方式，nil 时按 cfg.Model 的 token 数切分，块大小为 ChunkSize
+	Splitter textsplit.Splitter
+	// ChunkSize 每块的 token 数，也是合并阶段一次提交的摘要总长度上限，为 0 时使用 3000
+	ChunkSize int
+	// ChunkOverlap 相邻块重叠的 token 数
+	ChunkOverlap int
+	// MapPrompt 对每个文本块生成摘要的提示词，{text} 会被替换为文本块，不含 {text} 时文本追加在末尾
+	MapPrompt string
+	// ReducePrompt 合并摘要的提示词，{text} 会被替换为各段摘要
+	ReducePrompt string
+	// Concurrency 并发调用模型的数量，为 0 时使用 4
+	Concurrency int
+}
+
+// Summarize 使用 map-reduce 方式总结长文本：先切分文本并发地为每块生成摘要，
+// 再把各段摘要合并为最终摘要；摘要总长度仍超过 ChunkSize 时会先分组合并，直到可以一次提交。
+// 文本只有一块时直接调用一次 MapPrompt。cfg.StreamCallback 只接收最终摘要的流式输出，
+// cfg.SystemPrompt 会作为每次调用的系统提示词
+func Summarize(ctx context.Context, text string, cfg Config, opts SummarizeOptions) (string, error) {
+	size := opts.ChunkSize
+	if size <= 0 {
+		size = defaultSummaryChunkSize
+	}
+	length := textsplit.Tokens(cfg.Model)
+	splitter := opts.Splitter
+	if splitter == nil {
+		splitter = &textsplit.Recursive{ChunkSize: size, ChunkOverlap: opts.ChunkOverlap, Length: length}
+	}
+	mapPrompt, reducePrompt := opts.MapPrompt, opts.ReducePrompt
+	if mapPrompt == "" {
+		mapPrompt = DefaultMapPrompt
+	}
+	if reducePrompt == "" {
+		reducePrompt = DefaultReducePrompt
+	}
+
+	chunks := splitter.Split(text)
+	switch len(chunks) {
+	case 0:
+		return "", fmt.Errorf("summarize: text is empty")
+	case 1:
+		summary, err := summarizeOnce(ctx, mapPrompt, chunks[0], cfg)
+		if err != nil {
+			return "", fmt.Errorf("summarize: %w", err)
+		}
+		return summary, nil
+	}
+
+	// 中间步骤的输出不交给流式回调，避免多个并发调用的输出交错
+	inner := cfg
+	inner.StreamCallback = nil
+	summaries, err := summarizeAll(ctx, mapPrompt, chunks, inner, opts.Concurrency)
+	if err != nil {
+		return "", err
+	}
+
+	// 摘要总长度超过上限时分组合并，每轮都应减少摘要的数量
+	for round := 0; round < maxSummaryRounds; round++ {
+		groups := groupSummaries(summaries, size, length)
+		if len(groups) <= 1 || len(groups) >= len(summaries) {
+			break
+		}
+		if summaries, err = summarizeAll(ctx, reducePrompt, groups, inner, opts.Concurrency); err != nil {
+			return "", err
+		}
+	}
+	summary, err := summarizeOnce(ctx, reducePrompt, joinSummaries(summaries), cfg)
+	if err != nil {
+		return "", fmt.Errorf("summarize: reduce: %w", err)
+	}
+	return summary, nil
+}
+
+// summarizeAll 并发地为每段文本生成摘要，结果顺序与输入一致，任一调用失败时取消其余调用
+func summarizeAll(ctx context.Context, prompt string, texts []string, cfg Config, concurrency int) ([]string, error) {
+	if concurrency <= 0 {
+		concurrency = defaultSummaryConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]string, len(texts))
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, text := range texts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			summary, err := summarizeOnce(ctx, prompt, text, cfg)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("summarize: chunk %d: %w", i, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = summary
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("summarize: %w", err)
+	}
+	return results, nil
+}
+
+// summarizeOnce 用 prompt 总结一段文本
+func summarizeOnce(ctx context.Context, prompt, text string, cfg Config) (string, error) {
+	if strings.Contains(prompt, "{text}") {
+		prompt = strings.ReplaceAll(prompt, "{text}", text)
+	} else {
+		prompt += "\n\n" + text
+	}
+	summary, err := ChatText(ctx, prompt, cfg)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(summary), nil
+}
+
+// groupSummaries 把相邻的摘要合并为总长度不超过 size 的若干组
+func groupSummaries(summaries []string, size int, length textsplit.LengthFunc) []string {
+	var groups, current []string
+	total := 0
+	for _, s := range summaries {
+		n := length(s)
+		if total+n > size && len(current) > 0 {
+			groups = append(groups, joinSummaries(current))
+			current, total = nil, 0
+		}
+		current = append(current, s)
+		total += n
+	}
+	if len(current) > 0 {
+		groups = append(groups, joinSummaries(current))
+	}
+	return groups
+}
+
+// joinSummaries 用空行连接各段摘要
+func joinSummaries(summaries []string) string {
+	return strings.Join(summaries, "\n\n")
+}
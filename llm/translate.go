@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Translate 把 text 从 src 翻译为 dst，src 为空或 "auto" 表示自动识别源语言。
+// DashScope 的 qwen-mt 系列模型使用原生的 translation_options，其他模型使用翻译提示词；
+// cfg.Translation 中的术语表和领域提示会被保留，cfg.SystemPrompt 会被忽略
+func Translate(ctx context.Context, text, src, dst string, cfg Config) (string, error) {
+	if dst == "" {
+		return "", fmt.Errorf("translate: target language is required")
+	}
+	opts := spec.TranslationOptions{}
+	if cfg.Translation != nil {
+		opts = *cfg.Translation
+	}
+	opts.SourceLang, opts.TargetLang = src, dst
+	if opts.SourceLang == "" {
+		opts.SourceLang = "auto"
+	}
+	cfg.Translation = &opts
+
+	resp, err := ChatMessages(ctx, []spec.Message{spec.NewUserMessage(text)}, cfg)
+	if err != nil {
+		return "", fmt.Errorf("translate: %w", err)
+	}
+	return strings.TrimSpace(resp.Message.Content), nil
+}
@@ -0,0 +1,117 @@
+// Package llmtest 提供用于离线测试 Provider 和下游应用的辅助工具。
+package llmtest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ReplayTransport 是一个 http.RoundTripper，对每个请求都返回预先录制好的响应体。
+// 配合 spec.WithHTTPClient 使用，可以让录制的 SSE 流完整地经过 Provider 的流式解析逻辑，
+// 无需真实的 API 端点。
+type ReplayTransport struct {
+	// Body 录制好的响应体，例如一段完整的 SSE 文本
+	Body []byte
+	// StatusCode 响应状态码，默认 200
+	StatusCode int
+	// ContentType 响应的 Content-Type，默认 text/event-stream
+	ContentType string
+	// ChunkSize 每次 Read 最多返回的字节数，0 表示不限制。
+	// 设置较小的值可以模拟网络分片，检验解析逻辑对半行数据的处理。
+	ChunkSize int
+
+	mu       sync.Mutex
+	requests [][]byte
+}
+
+// NewReplayClient 创建一个总是返回 transcript 的 http.Client，
+// 同时返回底层的 ReplayTransport 以便检查捕获的请求。
+func NewReplayClient(transcript []byte) (*http.Client, *ReplayTransport) {
+	t := &ReplayTransport{Body: transcript}
+	return &http.Client{Transport: t}, t
+}
+
+// NewReplayClientFromFile 从文件读取录制内容并创建回放客户端。
+func NewReplayClientFromFile(path string) (*http.Client, *ReplayTransport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, t := NewReplayClient(data)
+	return c, t, nil
+}
+
+// RoundTrip 实现了 http.RoundTripper 接口
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+	}
+	t.mu.Lock()
+	t.requests = append(t.requests, reqBody)
+	t.mu.Unlock()
+
+	status := t.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := t.ContentType
+	if contentType == "" {
+		contentType = "text/event-stream"
+	}
+
+	var body io.Reader = bytes.NewReader(t.Body)
+	if t.ChunkSize > 0 {
+		body = &chunkedReader{r: body, size: t.ChunkSize}
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	return &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(body),
+		Request:    req,
+	}, nil
+}
+
+// Requests 返回目前为止捕获到的所有请求体
+func (t *ReplayTransport) Requests() [][]byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([][]byte, len(t.requests))
+	copy(out, t.requests)
+	return out
+}
+
+// LastRequest 返回最近一次捕获的请求体，没有请求时返回 nil
+func (t *ReplayTransport) LastRequest() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.requests) == 0 {
+		return nil
+	}
+	return t.requests[len(t.requests)-1]
+}
+
+// chunkedReader 限制每次 Read 返回的字节数
+type chunkedReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,428 @@
+package llmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// TestAPIKey 是 Server.Config 使用的 API Key，Server 会校验 Authorization 头是否携带了它
+const TestAPIKey = "sk-llmtest"
+
+// defaultEmbeddingDim 是默认的向量维度
+const defaultEmbeddingDim = 8
+
+// Reply 是 Server 对一次对话请求的回复
+type Reply struct {
+	// Content 回复文本，流式响应时按 ChunkSize 拆分为多个数据块
+	Content string
+	// Reasoning 思考过程，以 reasoning_content 字段下发
+	Reasoning string
+	// ToolCalls 函数调用，ID 为空时自动生成
+	ToolCalls []spec.ToolCall
+	// Usage 用量，nil 时按 tokens 包估算
+	Usage *spec.Usage
+	// Status 非 200 时返回 OpenAI 格式的错误响应，Error 为错误信息
+	Status int
+	Error  string
+	// Header 附加的响应头，如 Retry-After
+	Header http.Header
+	// Delay 发送响应前的等待时间，流式响应时为每个数据块之间的间隔
+	Delay time.Duration
+	// ChunkSize 流式响应每个数据块的字符数，默认为 4
+	ChunkSize int
+}
+
+// Text 返回文本回复
+func Text(content string) Reply {
+	return Reply{Content: content}
+}
+
+// ToolCall 返回调用一个函数的回复，arguments 为 JSON 参数
+func ToolCall(name, arguments string) Reply {
+	return Reply{ToolCalls: []spec.ToolCall{{Type: "function", Function: spec.FunctionCall{Name: name, Arguments: arguments}}}}
+}
+
+// Error 返回错误回复，如 Error(429, "rate limit exceeded")
+func Error(status int, message string) Reply {
+	return Reply{Status: status, Error: message}
+}
+
+// Request 是 Server 收到的一次请求
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	// Body 原始请求体
+	Body []byte
+	// Model、Messages、Stream 从对话请求体中解析，其他请求为零值
+	Model    string
+	Messages []spec.Message
+	Stream   bool
+	// Params 解析后的请求体，可用于检查 temperature、tools 等参数
+	Params map[string]any
+}
+
+// LastUserText 返回最后一条用户消息的文本
+func (r Request) LastUserText() string {
+	for i := len(r.Messages) - 1; i >= 0; i-- {
+		if r.Messages[i].Role == spec.RoleUser {
+			return r.Messages[i].PlainText()
+		}
+	}
+	return ""
+}
+
+// Server 是模拟 OpenAI 兼容接口的 httptest 服务器，支持 /chat/completions（JSON 与 SSE）、
+// /embeddings 和 /models，可用于离线测试 Provider 和下游应用：
+//
+//	srv := llmtest.NewServer()
+//	defer srv.Close()
+//	srv.Enqueue(llmtest.ToolCall("get_weather", `{"city":"杭州"}`), llmtest.Text("杭州今天晴。"))
+//	c, _ := client.New(srv.Config("openai", "gpt-4o"))
+//
+// 回复按 Enqueue 的顺序使用，队列为空时交给 Handle 注册的函数，默认回显最后一条用户消息
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	replies  []Reply
+	handler  func(req Request) Reply
+	requests []Request
+	// EmbeddingDim 向量维度，默认为 8；向量由文本哈希生成，相同文本得到相同向量
+	EmbeddingDim int
+}
+
+// NewServer 启动 Server，使用完毕后调用 Close
+func NewServer() *Server {
+	s := &Server{EmbeddingDim: defaultEmbeddingDim}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Enqueue 依次追加对话请求的回复
+func (s *Server) Enqueue(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = append(s.replies, replies...)
+}
+
+// Handle 设置回复队列为空时生成回复的函数
+func (s *Server) Handle(fn func(req Request) Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = fn
+}
+
+// Requests 返回目前为止收到的所有请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest 返回最近一次请求，没有请求时返回零值
+func (s *Server) LastRequest() Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// ChatURL 返回对话接口地址，可作为 llm.Config.APIURL
+func (s *Server) ChatURL() string {
+	return s.URL + "/v1/chat/completions"
+}
+
+// Config 返回指向 Server 的配置，provider 可以是任意使用 OpenAI 兼容协议的 Provider
+func (s *Server) Config(provider, model string) llm.Config {
+	return llm.Config{Provider: provider, Model: model, APIKey: TestAPIKey, APIURL: s.ChatURL()}
+}
+
+// serveHTTP 记录请求并按路径分发
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body}
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &req.Params)
+	}
+	if auth := r.Header.Get("Authorization"); auth != "Bearer "+TestAPIKey {
+		s.record(req)
+		writeError(w, http.StatusUnauthorized, "invalid api key")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/chat/completions"):
+		var chat struct {
+			Model    string         `json:"model"`
+			Messages []spec.Message `json:"messages"`
+			Stream   bool           `json:"stream"`
+		}
+		if err := json.Unmarshal(body, &chat); err != nil {
+			s.record(req)
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.Model, req.Messages, req.Stream = chat.Model, chat.Messages, chat.Stream
+		s.record(req)
+		s.chat(w, req, s.next(req))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/embeddings"):
+		s.record(req)
+		s.embeddings(w, req)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/models"):
+		s.record(req)
+		writeJSON(w, map[string]any{
+			"object": "list",
+			"data":   []map[string]any{{"id": "llmtest", "object": "model", "owned_by": "llmtest"}},
+		})
+	default:
+		s.record(req)
+		writeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+// record 记录请求
+func (s *Server) record(req Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+}
+
+// next 取出下一个回复
+func (s *Server) next(req Request) Reply {
+	s.mu.Lock()
+	if len(s.replies) > 0 {
+		reply := s.replies[0]
+		s.replies = s.replies[1:]
+		s.mu.Unlock()
+		return reply
+	}
+	handler := s.handler
+	s.mu.Unlock()
+	if handler != nil {
+		return handler(req)
+	}
+	return Text("echo: " + req.LastUserText())
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写入 OpenAI 格式的错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"message": message, "type": errorType(status), "code": status},
+	})
+}
+
+// errorType 返回状态码对应的 OpenAI 错误类型
+func errorType(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "authentication_error"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case status >= 500:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
+
+// chat 写入对话响应
+func (s *Server) chat(w http.ResponseWriter, req Request, reply Reply) {
+	for k, v := range reply.Header {
+		w.Header()[k] = v
+	}
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		time.Sleep(reply.Delay)
+		writeError(w, reply.Status, reply.Error)
+		return
+	}
+
+	calls := make([]spec.ToolCall, len(reply.ToolCalls))
+	for i, call := range reply.ToolCalls {
+		if call.ID == "" {
+			call.ID = fmt.Sprintf("call_%d_%d", len(s.Requests()), i)
+		}
+		if call.Type == "" {
+			call.Type = "function"
+		}
+		calls[i] = call
+	}
+	usage := reply.Usage
+	if usage == nil {
+		u := spec.Usage{
+			PromptTokens:     tokens.CountMessages(req.Model, req.Messages),
+			CompletionTokens: tokens.Count(req.Model, reply.Reasoning+reply.Content),
+		}
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+		usage = &u
+	}
+	finish := "stop"
+	if len(calls) > 0 {
+		finish = "tool_calls"
+	}
+	id := fmt.Sprintf("chatcmpl-llmtest-%d", len(s.Requests()))
+
+	if !req.Stream {
+		time.Sleep(reply.Delay)
+		message := map[string]any{"role": "assistant", "content": reply.Content}
+		if reply.Reasoning != "" {
+			message["reasoning_content"] = reply.Reasoning
+		}
+		if len(calls) > 0 {
+			message["tool_calls"] = calls
+		}
+		writeJSON(w, map[string]any{
+			"id":      id,
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]any{{"index": 0, "message": message, "finish_reason": finish}},
+			"usage":   usage,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	send := func(delta map[string]any, finishReason any, u *spec.Usage) {
+		chunk := map[string]any{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]any{},
+		}
+		if delta != nil {
+			chunk["choices"] = []map[string]any{{"index": 0, "delta": delta, "finish_reason": finishReason}}
+		}
+		if u != nil {
+			chunk["usage"] = u
+		}
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		time.Sleep(reply.Delay)
+	}
+
+	size := reply.ChunkSize
+	if size <= 0 {
+		size = 4
+	}
+	send(map[string]any{"role": "assistant", "content": ""}, nil, nil)
+	for _, part := range chunks(reply.Reasoning, size) {
+		send(map[string]any{"reasoning_content": part}, nil, nil)
+	}
+	for _, part := range chunks(reply.Content, size) {
+		send(map[string]any{"content": part}, nil, nil)
+	}
+	for i, call := range calls {
+		// 第一个片段带 ID 和函数名，参数分两段下发以检验拼接逻辑
+		args := []rune(call.Function.Arguments)
+		half := len(args) / 2
+		send(map[string]any{"tool_calls": []map[string]any{{
+			"index": i, "id": call.ID, "type": call.Type,
+			"function": map[string]any{"name": call.Function.Name, "arguments": string(args[:half])},
+		}}}, nil, nil)
+		send(map[string]any{"tool_calls": []map[string]any{{
+			"index": i, "function": map[string]any{"arguments": string(args[half:])},
+		}}}, nil, nil)
+	}
+	send(map[string]any{}, finish, nil)
+	if opts, ok := req.Params["stream_options"].(map[string]any); ok && opts["include_usage"] == true {
+		send(nil, nil, usage)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// chunks 把文本按 size 个字符拆分
+func chunks(text string, size int) []string {
+	var out []string
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := min(size, len(runes))
+		out = append(out, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return out
+}
+
+// embeddings 写入向量响应，向量由文本哈希确定性地生成并归一化
+func (s *Server) embeddings(w http.ResponseWriter, req Request) {
+	var inputs []string
+	switch v := req.Params["input"].(type) {
+	case string:
+		inputs = []string{v}
+	case []any:
+		for _, item := range v {
+			text, _ := item.(string)
+			inputs = append(inputs, text)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "input is required")
+		return
+	}
+	dim := s.EmbeddingDim
+	if dim <= 0 {
+		dim = defaultEmbeddingDim
+	}
+	data := make([]map[string]any, len(inputs))
+	total := 0
+	for i, text := range inputs {
+		data[i] = map[string]any{"object": "embedding", "index": i, "embedding": vector(text, dim)}
+		total += tokens.Count("", text)
+	}
+	model, _ := req.Params["model"].(string)
+	writeJSON(w, map[string]any{
+		"object": "list",
+		"data":   data,
+		"model":  model,
+		"usage":  map[string]int{"prompt_tokens": total, "total_tokens": total},
+	})
+}
+
+// vector 根据文本哈希生成归一化的向量
+func vector(text string, dim int) []float32 {
+	v := make([]float32, dim)
+	var norm float64
+	for i := range v {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d:%s", i, text)
+		x := float64(h.Sum64()%2000)/1000 - 1
+		v[i] = float32(x)
+		norm += x * x
+	}
+	if norm > 0 {
+		for i := range v {
+			v[i] = float32(float64(v[i]) / math.Sqrt(norm))
+		}
+	}
+	return v
+}
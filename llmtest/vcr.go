@@ -0,0 +1,369 @@
+package llmtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// VCRModeEnv 是 ModeFromEnv 读取的环境变量
+const VCRModeEnv = "LLM_VCR_MODE"
+
+// redacted 是遮盖后的值
+const redacted = "REDACTED"
+
+// ErrNoInteraction 表示回放模式下磁带中没有与请求匹配的记录
+var ErrNoInteraction = errors.New("llmtest: no recorded interaction matches the request")
+
+// Mode 是 Recorder 的工作模式
+type Mode int
+
+const (
+	// ModeAuto 磁带中有匹配的记录时回放，否则发出真实请求并追加录制
+	ModeAuto Mode = iota
+	// ModeRecord 总是发出真实请求，重新录制整盘磁带
+	ModeRecord
+	// ModeReplay 只回放，没有匹配的记录时返回 ErrNoInteraction，适合在 CI 中使用
+	ModeReplay
+)
+
+// ModeFromEnv 根据环境变量 LLM_VCR_MODE（record、replay、auto）返回模式，未设置时返回 def
+func ModeFromEnv(def Mode) Mode {
+	switch strings.ToLower(os.Getenv(VCRModeEnv)) {
+	case "record":
+		return ModeRecord
+	case "replay":
+		return ModeReplay
+	case "auto":
+		return ModeAuto
+	}
+	return def
+}
+
+// Cassette 是录制的一组 HTTP 交互，以 JSON 保存
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction 是一次请求及其响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest 是录制的请求，密钥已被遮盖
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse 是录制的响应
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body 是请求或响应体：UTF-8 文本（JSON、SSE）原样保存为字符串，其他内容以 base64 保存
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = raw
+	return err
+}
+
+// defaultRedactHeaders 是默认遮盖的请求头和响应头
+var defaultRedactHeaders = []string{"Authorization", "X-Api-Key", "Api-Key", "X-Goog-Api-Key", "Cookie", "Set-Cookie", "Openai-Organization"}
+
+// redactQuery 是默认遮盖的 URL 查询参数
+var redactQuery = []string{"key", "api_key", "apikey", "access_token", "token"}
+
+// Recorder 录制真实的 Provider 交互并保存到磁带文件，之后可以离线回放，使解析逻辑的测试结果确定。
+// 录制前会遮盖鉴权头、URL 中的密钥参数以及通过 Redact 指定的字符串：
+//
+//	rec, err := llmtest.NewRecorder("testdata/qwen-stream.json", llmtest.ModeFromEnv(llmtest.ModeReplay))
+//	rec.Redact(os.Getenv("DASHSCOPE_API_KEY"))
+//	cfg := llm.Config{Provider: "dashscope", Model: "qwen-plus", APIKey: os.Getenv("DASHSCOPE_API_KEY"),
+//		Interceptors: []spec.Interceptor{rec.Interceptor()}}
+//
+// 请求按方法、URL 和请求体（JSON 会规范化后比较）匹配，每条记录按录制顺序只使用一次，
+// 因此同一请求多次发出时依次回放各次的响应
+type Recorder struct {
+	// Transport 录制时发出真实请求使用的 Transport，仅用于 RoundTrip 和 HTTPClient，默认为 http.DefaultTransport
+	Transport http.RoundTripper
+
+	path          string
+	mode          Mode
+	mu            sync.Mutex
+	cassette      Cassette
+	used          []bool
+	secrets       []string
+	redactHeaders []string
+}
+
+// NewRecorder 创建 Recorder，ModeRecord 以外的模式会读取已有的磁带；
+// ModeReplay 下磁带不存在时返回错误，ModeAuto 下视为空磁带
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, redactHeaders: defaultRedactHeaders}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && mode == ModeAuto:
+		return r, nil
+	case err != nil:
+		return nil, fmt.Errorf("llmtest: failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("llmtest: invalid cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Redact 在录制内容（URL、请求头、请求体和响应体）中把 secrets 替换为 REDACTED，空字符串会被忽略
+func (r *Recorder) Redact(secrets ...string) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	return r
+}
+
+// RedactHeaders 追加需要遮盖的请求头和响应头
+func (r *Recorder) RedactHeaders(names ...string) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactHeaders = append(append([]string(nil), r.redactHeaders...), names...)
+	return r
+}
+
+// Interceptor 返回录制和回放的拦截器，可放入 llm.Config.Interceptors；回放时不会发出真实请求
+func (r *Recorder) Interceptor() spec.Interceptor {
+	return func(next spec.RoundTripFunc) spec.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return r.roundTrip(req, next)
+		}
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper，录制时通过 Transport 发出真实请求
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return r.roundTrip(req, transport.RoundTrip)
+}
+
+// HTTPClient 返回使用 Recorder 的 http.Client，可配合 spec.WithHTTPClient 使用
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette 返回目前录制或加载的交互
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// roundTrip 回放匹配的记录，或者通过 next 发出请求并录制
+func (r *Recorder) roundTrip(req *http.Request, next spec.RoundTripFunc) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := r.recordRequest(req, body)
+
+	if r.mode != ModeRecord {
+		if resp, ok := r.replay(req, recorded); ok {
+			return resp, nil
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+		}
+	}
+
+	resp, err := next(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+			Body:       Body(r.redactString(string(respBody))),
+		},
+	})
+	r.used = append(r.used, true)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordRequest 返回遮盖了密钥的请求记录
+func (r *Recorder) recordRequest(req *http.Request, body []byte) RecordedRequest {
+	u := *req.URL
+	q := u.Query()
+	for _, name := range redactQuery {
+		if q.Has(name) {
+			q.Set(name, redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    r.redactString(u.String()),
+		Header: r.redactHeader(req.Header),
+		Body:   Body(r.redactString(string(body))),
+	}
+}
+
+// replay 查找第一条未使用的匹配记录并构造响应
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !matches(in.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+		status := in.Response.StatusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, true
+	}
+	return nil, false
+}
+
+// matches 按方法、URL 和请求体判断请求是否与记录一致
+func matches(a, b RecordedRequest) bool {
+	return a.Method == b.Method && a.URL == b.URL && bytes.Equal(normalizeJSON(a.Body), normalizeJSON(b.Body))
+}
+
+// normalizeJSON 把 JSON 重新编码以消除字段顺序和空白的差异，非 JSON 原样返回
+func normalizeJSON(data []byte) []byte {
+	var v any
+	if len(data) == 0 || json.Unmarshal(data, &v) != nil {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// redactHeader 返回遮盖了敏感头和密钥的请求头副本，调用方需持有锁
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		for _, v := range values {
+			out.Add(k, r.redactString(v))
+		}
+	}
+	for _, name := range r.redactHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
+
+// redactString 把 s 中的密钥替换为 REDACTED，调用方需持有锁
+func (r *Recorder) redactString(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+		// 密钥出现在 URL 中时可能被转义
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			s = strings.ReplaceAll(s, escaped, redacted)
+		}
+	}
+	return s
+}
+
+// save 把磁带写入文件，先写临时文件再重命名，调用方需持有锁
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("llmtest: failed to save cassette: %w", err)
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("llmtest: failed to save cassette: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("llmtest: failed to save cassette: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iEvan-lhr/go-llm-client/agent"
+)
+
+// Client 是一个 MCP 客户端会话，可并发使用
+type Client struct {
+	transport Transport
+	info      Implementation
+	onNotify  func(method string, params json.RawMessage)
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[string]chan *message
+
+	done chan struct{}
+	err  error
+
+	server       Implementation
+	instructions string
+}
+
+// ClientOption 用于配置 Client
+type ClientOption func(c *Client)
+
+// WithClientInfo 设置初始化时上报给服务端的客户端名称和版本
+func WithClientInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		c.info = Implementation{Name: name, Version: version}
+	}
+}
+
+// WithNotificationHandler 接收服务端的通知，如 "notifications/tools/list_changed"。
+// handler 在接收消息的 goroutine 中调用，不应长时间阻塞
+func WithNotificationHandler(handler func(method string, params json.RawMessage)) ClientOption {
+	return func(c *Client) {
+		c.onNotify = handler
+	}
+}
+
+// Connect 在 transport 上完成初始化握手并返回客户端，失败时关闭 transport。
+// 会话由 Close 结束
+func Connect(ctx context.Context, transport Transport, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		transport: transport,
+		info:      Implementation{Name: "go-llm-client", Version: "1.0.0"},
+		pending:   make(map[string]chan *message),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go c.readLoop()
+
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions"`
+	}
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      c.info,
+	}
+	if err := c.call(ctx, "initialize", params, &result); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("mcp: initialize failed: %w", err)
+	}
+	c.server = result.ServerInfo
+	c.instructions = result.Instructions
+
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// ServerInfo 返回服务端的名称和版本
+func (c *Client) ServerInfo() Implementation {
+	return c.server
+}
+
+// Instructions 返回服务端在初始化时提供的使用说明，可以加入系统提示词
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// Done 返回一个在连接断开后关闭的 channel
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close 结束会话并关闭 transport，stdio 传输会等待服务端进程退出
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// readLoop 持续接收消息：响应交给等待中的调用，服务端请求和通知分别处理
+func (c *Client) readLoop() {
+	defer close(c.done)
+	for {
+		data, err := c.transport.Receive()
+		if err != nil {
+			c.err = err
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.isRequest():
+			go c.handleRequest(&msg)
+		case msg.Method != "":
+			if c.onNotify != nil {
+				c.onNotify(msg.Method, msg.Params)
+			}
+		default:
+			key := string(bytes.TrimSpace(msg.ID))
+			c.mu.Lock()
+			ch, ok := c.pending[key]
+			delete(c.pending, key)
+			c.mu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+		}
+	}
+}
+
+// handleRequest 响应服务端发来的请求，只支持 ping，其他方法返回 method not found
+func (c *Client) handleRequest(req *message) {
+	resp := message{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_ = c.transport.Send(context.Background(), data)
+}
+
+// call 发送请求并等待响应，结果解析到 result（可以为 nil）。
+// ctx 取消时向服务端发送 notifications/cancelled
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	id := c.nextID.Add(1)
+	key := strconv.FormatInt(id, 10)
+	req := message{JSONRPC: "2.0", ID: json.RawMessage(key), Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("mcp: failed to marshal params: %w", err)
+		}
+		req.Params = raw
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("mcp: failed to marshal request: %w", err)
+	}
+
+	ch := make(chan *message, 1)
+	c.mu.Lock()
+	c.pending[key] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	if err := c.transport.Send(ctx, data); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("mcp: failed to parse %s result: %w", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		_ = c.notify(context.WithoutCancel(ctx), "notifications/cancelled", map[string]any{
+			"requestId": id,
+			"reason":    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	case <-c.done:
+		return fmt.Errorf("mcp: connection closed: %w", c.err)
+	}
+}
+
+// notify 发送一条通知
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	msg := message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("mcp: failed to marshal params: %w", err)
+		}
+		msg.Params = raw
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("mcp: failed to marshal notification: %w", err)
+	}
+	return c.transport.Send(ctx, data)
+}
+
+// Ping 检查服务端是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// ListTools 返回服务端提供的全部工具，自动处理分页
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", cursorParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool 调用工具，args 为参数对象（map、结构体或 json.RawMessage），nil 表示无参数。
+// 工具自身的执行失败通过 CallToolResult.IsError 表示，不作为错误返回
+func (c *Client) CallTool(ctx context.Context, name string, args any) (*CallToolResult, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	if raw, ok := args.(json.RawMessage); ok && len(bytes.TrimSpace(raw)) == 0 {
+		args = map[string]any{}
+	}
+	var result CallToolResult
+	params := map[string]any{"name": name, "arguments": args}
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources 返回服务端提供的全部资源，自动处理分页
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	cursor := ""
+	for {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "resources/list", cursorParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Resources...)
+		if page.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// ReadResource 读取资源内容，一个资源可能包含多段内容
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	if err := c.call(ctx, "resources/read", map[string]any{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// cursorParams 返回分页请求的参数，首页不带 cursor
+func cursorParams(cursor string) any {
+	if cursor == "" {
+		return nil
+	}
+	return map[string]any{"cursor": cursor}
+}
+
+// Tools 列出服务端的工具并转换为 agent.Tool，调用时转发给服务端。
+// 工具执行失败（IsError）时结果文本作为错误返回，由智能体提交给模型
+func (c *Client) Tools(ctx context.Context) ([]agent.Tool, error) {
+	list, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]agent.Tool, 0, len(list))
+	for _, t := range list {
+		name := t.Name
+		tools = append(tools, agent.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  t.Spec().Function.Parameters,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				result, err := c.CallTool(ctx, name, args)
+				if err != nil {
+					return "", err
+				}
+				if result.IsError {
+					return "", errors.New(result.Text())
+				}
+				return result.Text(), nil
+			},
+		})
+	}
+	return tools, nil
+}
@@ -0,0 +1,384 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/agent"
+)
+
+// Server 把 agent.Tool 以 MCP 服务端的形式提供给 Claude Desktop 等宿主，
+// 同一组工具既可以在本地交给智能体，也可以通过 MCP 对外提供。可并发使用
+//
+//	s := mcp.NewServer("weather", "1.0.0")
+//	s.AddTools(weatherTool)
+//	log.Fatal(s.ServeStdio(ctx))
+type Server struct {
+	info         Implementation
+	instructions string
+
+	mu    sync.RWMutex
+	tools map[string]agent.Tool
+	order []string
+
+	sessionsMu sync.Mutex
+	sessions   map[string]*sseSession
+}
+
+// ServerOption 用于配置 Server
+type ServerOption func(s *Server)
+
+// WithInstructions 设置初始化时返回给客户端的使用说明
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// NewServer 创建服务端，name 和 version 在初始化时上报给客户端
+func NewServer(name, version string, opts ...ServerOption) *Server {
+	s := &Server{
+		info:     Implementation{Name: name, Version: version},
+		tools:    make(map[string]agent.Tool),
+		sessions: make(map[string]*sseSession),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AddTools 注册工具，同名工具以后注册的为准
+func (s *Server) AddTools(tools ...agent.Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range tools {
+		if _, ok := s.tools[t.Name]; !ok {
+			s.order = append(s.order, t.Name)
+		}
+		s.tools[t.Name] = t
+	}
+}
+
+// ServeStdio 通过当前进程的 stdin/stdout 提供服务，直到 stdin 关闭或 ctx 取消。
+// stdout 专用于协议消息，日志应写入 stderr
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, NewStreamTransport(os.Stdin, os.Stdout, nil))
+}
+
+// Serve 在 transport 上处理一个客户端会话，连接正常结束时返回 nil。
+// 请求并发处理，ctx 取消时结束会话
+func (s *Server) Serve(ctx context.Context, transport Transport) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type received struct {
+		data []byte
+		err  error
+	}
+	incoming := make(chan received)
+	go func() {
+		for {
+			data, err := transport.Receive()
+			select {
+			case incoming <- received{data, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	var inflightMu sync.Mutex
+	inflight := make(map[string]context.CancelFunc)
+
+	for {
+		var r received
+		select {
+		case r = <-incoming:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil {
+			if errors.Is(r.err, io.EOF) {
+				return nil
+			}
+			return r.err
+		}
+
+		var msg message
+		if err := json.Unmarshal(r.data, &msg); err != nil {
+			s.reply(ctx, transport, &message{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &RPCError{Code: CodeParseError, Message: err.Error()}})
+			continue
+		}
+		switch {
+		case msg.isRequest():
+			key := string(bytes.TrimSpace(msg.ID))
+			reqCtx, reqCancel := context.WithCancel(ctx)
+			inflightMu.Lock()
+			inflight[key] = reqCancel
+			inflightMu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					inflightMu.Lock()
+					delete(inflight, key)
+					inflightMu.Unlock()
+					reqCancel()
+				}()
+				resp := s.handle(reqCtx, &msg)
+				// 已取消的请求不再响应
+				if reqCtx.Err() == nil {
+					s.reply(ctx, transport, resp)
+				}
+			}()
+		case msg.Method == "notifications/cancelled":
+			var params struct {
+				RequestID json.RawMessage `json:"requestId"`
+			}
+			if json.Unmarshal(msg.Params, &params) == nil {
+				inflightMu.Lock()
+				if c, ok := inflight[string(bytes.TrimSpace(params.RequestID))]; ok {
+					c()
+				}
+				inflightMu.Unlock()
+			}
+		}
+	}
+}
+
+// reply 发送一条响应
+func (s *Server) reply(ctx context.Context, transport Transport, resp *message) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_ = transport.Send(ctx, data)
+}
+
+// handle 处理一个请求并返回响应
+func (s *Server) handle(ctx context.Context, req *message) *message {
+	resp := &message{JSONRPC: "2.0", ID: req.ID}
+	result, err := s.dispatch(ctx, req)
+	if err != nil {
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		return resp
+	}
+	resp.Result = raw
+	return resp
+}
+
+// dispatch 按方法名处理请求
+func (s *Server) dispatch(ctx context.Context, req *message) (any, error) {
+	switch req.Method {
+	case "initialize":
+		result := map[string]any{
+			// 客户端请求的版本与实现的版本不同时返回实现的版本，由客户端决定是否继续
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      s.info,
+		}
+		if s.instructions != "" {
+			result["instructions"] = s.instructions
+		}
+		return result, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		tools := make([]Tool, 0, len(s.order))
+		for _, name := range s.order {
+			tools = append(tools, toMCPTool(s.tools[name]))
+		}
+		return map[string]any{"tools": tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		s.mu.RLock()
+		tool, ok := s.tools[params.Name]
+		s.mu.RUnlock()
+		if !ok {
+			return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+		}
+		return callTool(ctx, tool, params.Arguments), nil
+	}
+	return nil, &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// toMCPTool 将 agent.Tool 转换为 MCP 的工具定义
+func toMCPTool(t agent.Tool) Tool {
+	schema := json.RawMessage(`{"type":"object","properties":{}}`)
+	if t.Parameters != nil {
+		if raw, err := json.Marshal(t.Parameters); err == nil {
+			schema = raw
+		}
+	}
+	return Tool{Name: t.Name, Description: t.Description, InputSchema: schema}
+}
+
+// callTool 执行工具，错误和 panic 都转换为 IsError 结果，由模型决定如何处理
+func callTool(ctx context.Context, tool agent.Tool, args json.RawMessage) (result *CallToolResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = &CallToolResult{Content: []Content{TextContent(fmt.Sprintf("tool %s panicked: %v", tool.Name, r))}, IsError: true}
+		}
+	}()
+	output, err := tool.Handler(ctx, args)
+	if err != nil {
+		return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}
+	}
+	return &CallToolResult{Content: []Content{TextContent(output)}}
+}
+
+// Handler 返回 HTTP+SSE 传输的 http.Handler：GET 建立事件流，
+// 客户端按 endpoint 事件中的地址（同一路径加 sessionId 参数）POST 消息
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.serveSSE(w, r)
+		case http.MethodPost:
+			s.handlePost(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// sseSession 是一个 SSE 会话的服务端 Transport：消息来自 POST，响应写入事件流
+type sseSession struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	wmu     sync.Mutex
+
+	incoming chan []byte
+	done     chan struct{}
+}
+
+func (t *sseSession) Send(ctx context.Context, msg []byte) error {
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	select {
+	case <-t.done:
+		return io.ErrClosedPipe
+	default:
+	}
+	if _, err := fmt.Fprintf(t.w, "event: message\ndata: %s\n\n", bytes.TrimSpace(msg)); err != nil {
+		return err
+	}
+	t.flusher.Flush()
+	return nil
+}
+
+func (t *sseSession) Receive() ([]byte, error) {
+	select {
+	case msg := <-t.incoming:
+		return msg, nil
+	case <-t.done:
+		return nil, io.EOF
+	}
+}
+
+func (t *sseSession) Close() error {
+	return nil
+}
+
+// serveSSE 建立事件流并在其上运行一个会话，客户端断开时结束
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	id := rand.Text()
+	session := &sseSession{
+		w:        w,
+		flusher:  flusher,
+		incoming: make(chan []byte, 16),
+		done:     make(chan struct{}),
+	}
+	s.sessionsMu.Lock()
+	s.sessions[id] = session
+	s.sessionsMu.Unlock()
+	defer func() {
+		s.sessionsMu.Lock()
+		delete(s.sessions, id)
+		s.sessionsMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	session.wmu.Lock()
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", r.URL.Path, id)
+	flusher.Flush()
+	session.wmu.Unlock()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		session.wmu.Lock()
+		close(session.done)
+		session.wmu.Unlock()
+	}()
+	_ = s.Serve(ctx, session)
+}
+
+// handlePost 将客户端消息交给对应的会话
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	s.sessionsMu.Lock()
+	session, ok := s.sessions[r.URL.Query().Get("sessionId")]
+	s.sessionsMu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	select {
+	case session.incoming <- body:
+		w.WriteHeader(http.StatusAccepted)
+	case <-session.done:
+		http.Error(w, "session closed", http.StatusNotFound)
+	case <-r.Context().Done():
+	}
+}
@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Transport 负责收发 JSON-RPC 消息，每条消息为一个完整的 JSON 对象。
+// Receive 只在一个 goroutine 中调用，Send 可以并发调用
+type Transport interface {
+	Send(ctx context.Context, msg []byte) error
+	// Receive 阻塞直到收到下一条消息，连接结束时返回 io.EOF 或其他错误
+	Receive() ([]byte, error)
+	Close() error
+}
+
+// maxMessageSize 单条消息的最大字节数
+const maxMessageSize = 16 << 20
+
+// streamTransport 以换行分隔的 JSON 在一对读写流上收发消息，是 stdio 传输的基础
+type streamTransport struct {
+	scanner *bufio.Scanner
+	w       io.Writer
+	wmu     sync.Mutex
+	close   func() error
+}
+
+// NewStreamTransport 在 r 和 w 上以换行分隔的 JSON 收发消息，
+// 可用于服务端的 stdin/stdout 或测试中的管道；closer 可以为 nil
+func NewStreamTransport(r io.Reader, w io.Writer, closer io.Closer) Transport {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	t := &streamTransport{scanner: scanner, w: w, close: func() error { return nil }}
+	if closer != nil {
+		t.close = closer.Close
+	}
+	return t
+}
+
+func (t *streamTransport) Send(ctx context.Context, msg []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	if _, err := t.w.Write(append(bytes.TrimSpace(msg), '\n')); err != nil {
+		return fmt.Errorf("mcp: failed to write message: %w", err)
+	}
+	return nil
+}
+
+func (t *streamTransport) Receive() ([]byte, error) {
+	for t.scanner.Scan() {
+		line := bytes.TrimSpace(t.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return append([]byte(nil), line...), nil
+	}
+	if err := t.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("mcp: failed to read message: %w", err)
+	}
+	return nil, io.EOF
+}
+
+func (t *streamTransport) Close() error {
+	return t.close()
+}
+
+// stdioCloseTimeout 是关闭 stdin 后等待服务端进程退出的时间，超时后强制结束
+const stdioCloseTimeout = 5 * time.Second
+
+// NewStdioTransport 启动 MCP 服务端进程，通过其 stdin/stdout 通信。服务端的 stderr 输出被丢弃，
+// 需要查看日志或设置环境变量、工作目录时使用 NewCommandTransport
+func NewStdioTransport(command string, args ...string) (Transport, error) {
+	return NewCommandTransport(exec.Command(command, args...))
+}
+
+// NewCommandTransport 启动尚未运行的 cmd 并通过其 stdin/stdout 通信。
+// Close 会关闭 stdin 并等待进程退出，超时后强制结束进程
+func NewCommandTransport(cmd *exec.Cmd) (Transport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: failed to start server %s: %w", cmd.Path, err)
+	}
+
+	var once sync.Once
+	var closeErr error
+	closer := closerFunc(func() error {
+		once.Do(func() {
+			stdin.Close()
+			exited := make(chan error, 1)
+			go func() { exited <- cmd.Wait() }()
+			select {
+			case <-exited:
+			case <-time.After(stdioCloseTimeout):
+				closeErr = cmd.Process.Kill()
+				<-exited
+			}
+		})
+		return closeErr
+	})
+	return NewStreamTransport(stdout, stdin, closer), nil
+}
+
+// closerFunc 将函数适配为 io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// sseTransport 是 HTTP+SSE 传输：GET 建立事件流，服务端先通过 "endpoint" 事件告知 POST 地址，
+// 之后的响应和通知都以 "message" 事件下发
+type sseTransport struct {
+	req      *requester.Requester
+	headers  http.Header
+	endpoint string
+
+	body     io.ReadCloser
+	messages chan []byte
+	stop     chan struct{}
+	done     chan struct{}
+	err      error
+
+	closeOnce sync.Once
+}
+
+// NewSSETransport 连接 HTTP+SSE 传输的 MCP 服务端，sseURL 为事件流地址（通常以 /sse 结尾）。
+// 代理、TLS 等网络选项与 Provider 客户端一致，设置了 API Key 时以 Bearer 方式发送。
+// ctx 只约束建立连接的过程，连接由 Close 关闭
+func NewSSETransport(ctx context.Context, sseURL string, opts ...spec.ClientOption) (Transport, error) {
+	config := spec.NewClientConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+
+	headers := http.Header{}
+	if config.APIKey != "" {
+		headers.Set("Authorization", "Bearer "+config.APIKey)
+	}
+
+	// 事件流的生命周期由 Close 控制，不受 ctx 约束
+	streamCtx, cancel := context.WithCancel(context.Background())
+	httpReq, err := http.NewRequestWithContext(streamCtx, http.MethodGet, sseURL, nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("mcp: %w", err)
+	}
+	for k, v := range headers {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	connected := make(chan result, 1)
+	go func() {
+		resp, err := req.HTTPClient.Do(httpReq)
+		connected <- result{resp, err}
+	}()
+	var resp *http.Response
+	select {
+	case r := <-connected:
+		if r.err != nil {
+			cancel()
+			return nil, fmt.Errorf("mcp: failed to connect to %s: %w", sseURL, r.err)
+		}
+		resp = r.resp
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		defer cancel()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("mcp: failed to connect to %s: status %d: %s", sseURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	t := &sseTransport{
+		req:      req,
+		headers:  headers,
+		body:     &cancelBody{ReadCloser: resp.Body, cancel: cancel},
+		messages: make(chan []byte, 16),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	endpoint := make(chan string, 1)
+	go t.readLoop(sseURL, endpoint)
+
+	select {
+	case e, ok := <-endpoint:
+		if !ok {
+			t.Close()
+			return nil, fmt.Errorf("mcp: event stream closed before endpoint event: %w", t.err)
+		}
+		t.endpoint = e
+	case <-ctx.Done():
+		t.Close()
+		return nil, ctx.Err()
+	}
+	return t, nil
+}
+
+// cancelBody 在关闭响应体时取消事件流的请求
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// readLoop 解析事件流，endpoint 事件的地址写入 endpoint，message 事件写入 t.messages
+func (t *sseTransport) readLoop(base string, endpoint chan<- string) {
+	defer close(t.done)
+	gotEndpoint := false
+	defer func() {
+		if !gotEndpoint {
+			close(endpoint)
+		}
+	}()
+
+	scanner := requester.NewSSEScanner(t.body, maxMessageSize)
+	event := ""
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// 空行表示一个事件结束
+			if len(data) > 0 {
+				payload := strings.Join(data, "\n")
+				switch event {
+				case "endpoint":
+					if !gotEndpoint {
+						u, err := resolveURL(base, payload)
+						if err != nil {
+							t.err = err
+							return
+						}
+						gotEndpoint = true
+						endpoint <- u
+					}
+				case "", "message":
+					select {
+					case t.messages <- []byte(payload):
+					case <-t.stop:
+						return
+					}
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// 注释行，用于保活
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.err = fmt.Errorf("mcp: event stream error: %w", err)
+	} else {
+		t.err = io.EOF
+	}
+}
+
+// resolveURL 将 endpoint 事件中的相对地址解析为绝对地址
+func resolveURL(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("mcp: invalid url %s: %w", base, err)
+	}
+	r, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "", fmt.Errorf("mcp: invalid endpoint %s: %w", ref, err)
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+func (t *sseTransport) Send(ctx context.Context, msg []byte) error {
+	headers := t.headers.Clone()
+	headers.Set("Content-Type", "application/json")
+	if _, err := t.req.Post(ctx, t.endpoint, headers, json.RawMessage(msg)); err != nil {
+		return fmt.Errorf("mcp: failed to send message: %w", err)
+	}
+	return nil
+}
+
+func (t *sseTransport) Receive() ([]byte, error) {
+	select {
+	case msg := <-t.messages:
+		return msg, nil
+	case <-t.done:
+		// 读循环结束前已入队的消息仍然返回
+		select {
+		case msg := <-t.messages:
+			return msg, nil
+		default:
+		}
+		if t.err == nil {
+			return nil, io.EOF
+		}
+		return nil, t.err
+	}
+}
+
+func (t *sseTransport) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.stop)
+		err = t.body.Close()
+		<-t.done
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+	})
+	return err
+}
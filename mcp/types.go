@@ -0,0 +1,135 @@
+// Package mcp 是 Model Context Protocol 的客户端，通过 stdio 或 SSE 连接 MCP 服务端，
+// 列出其工具和资源，并把工具转换为 agent.Tool 交给智能体循环使用；
+// Server 则反过来把 agent.Tool 作为 MCP 服务端提供给其他宿主。
+//
+//	t, _ := mcp.NewStdioTransport("npx", "-y", "@modelcontextprotocol/server-filesystem", "/tmp")
+//	c, _ := mcp.Connect(ctx, t)
+//	defer c.Close()
+//	tools, _ := c.Tools(ctx)
+//	a, _ := agent.New(cfg, agent.WithTools(tools...))
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// ProtocolVersion 是实现的 MCP 协议版本
+const ProtocolVersion = "2024-11-05"
+
+// Implementation 是客户端或服务端的名称和版本
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool 是 MCP 服务端提供的工具
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema 参数的 JSON Schema
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// Spec 返回发送给模型的函数定义
+func (t Tool) Spec() spec.Tool {
+	var params any = t.InputSchema
+	if len(t.InputSchema) == 0 {
+		params = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	return spec.NewFunctionTool(t.Name, t.Description, params)
+}
+
+// Content 是工具结果中的一段内容，Type 为 "text"、"image" 或 "resource"
+type Content struct {
+	Type string `json:"type"`
+	// Text 文本内容，Type 为 "text" 时有效
+	Text string `json:"text,omitempty"`
+	// Data 和 MimeType 为 base64 编码的图片，Type 为 "image" 时有效
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	// Resource 内嵌的资源，Type 为 "resource" 时有效
+	Resource *ResourceContents `json:"resource,omitempty"`
+}
+
+// TextContent 创建一段文本内容
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
+
+// CallToolResult 是工具调用的结果
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	// IsError 为 true 表示工具执行失败，Content 中为错误信息
+	IsError bool `json:"isError,omitempty"`
+}
+
+// Text 返回结果中全部文本内容（包括内嵌的文本资源），以换行连接；图片等二进制内容以占位说明代替
+func (r *CallToolResult) Text() string {
+	parts := make([]string, 0, len(r.Content))
+	for _, c := range r.Content {
+		switch {
+		case c.Type == "text":
+			parts = append(parts, c.Text)
+		case c.Resource != nil && c.Resource.Text != "":
+			parts = append(parts, c.Resource.Text)
+		default:
+			parts = append(parts, fmt.Sprintf("[%s content omitted]", c.Type))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Resource 是 MCP 服务端提供的资源
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents 是资源的内容，文本资源使用 Text，二进制资源使用 base64 编码的 Blob
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// JSON-RPC 标准错误码
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// RPCError 是 JSON-RPC 错误响应
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp: rpc error %d: %s", e.Code, e.Message)
+}
+
+// message 是一条 JSON-RPC 2.0 消息：请求、响应或通知
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// isRequest 判断消息是否为请求（有 ID 的调用），否则为通知或响应
+func (m *message) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
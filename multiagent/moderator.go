@@ -0,0 +1,84 @@
+package multiagent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/outputs"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Moderator 决定下一位发言者，返回 Stop 表示结束对话。
+// names 为全部参与者名称，t 为截至目前的对话记录
+type Moderator interface {
+	Next(ctx context.Context, t *Transcript, names []string) (string, error)
+}
+
+// ModeratorFunc 把函数转换为 Moderator
+type ModeratorFunc func(ctx context.Context, t *Transcript, names []string) (string, error)
+
+func (f ModeratorFunc) Next(ctx context.Context, t *Transcript, names []string) (string, error) {
+	return f(ctx, t, names)
+}
+
+// RoundRobin 按参与者的顺序轮流发言
+func RoundRobin() Moderator {
+	return ModeratorFunc(func(ctx context.Context, t *Transcript, names []string) (string, error) {
+		return names[len(t.Turns)%len(names)], nil
+	})
+}
+
+// Sequence 按给定的顺序循环发言，同一参与者可以出现多次，如 Sequence("作者", "评审", "评审2")
+func Sequence(order ...string) Moderator {
+	return ModeratorFunc(func(ctx context.Context, t *Transcript, names []string) (string, error) {
+		if len(order) == 0 {
+			return Stop, nil
+		}
+		return order[len(t.Turns)%len(order)], nil
+	})
+}
+
+// moderatorDecision 是模型主持人的决定
+type moderatorDecision struct {
+	Next   string `json:"next" description:"下一位发言者的名称，对话应当结束时为 END"`
+	Reason string `json:"reason" description:"简要说明理由"`
+}
+
+// moderatorPrompt 是模型主持人使用的提示词
+const moderatorPrompt = `你是一场多人对话的主持人。参与者：%s。
+%s
+以下是目前的对话记录：
+
+%s
+
+请决定下一位发言者。对话已经达成目标、陷入重复或无法继续推进时，next 填写 END 结束对话。`
+
+// LLM 返回由 cfg 指定的模型担任主持人的 Moderator，instructions 描述对话目标和主持规则，可以为空。
+// 模型选择了不存在的参与者时返回错误
+func LLM(cfg llm.Config, instructions string) Moderator {
+	return ModeratorFunc(func(ctx context.Context, t *Transcript, names []string) (string, error) {
+		if len(t.Turns) == 0 {
+			return names[0], nil
+		}
+		p := outputs.JSON[moderatorDecision]()
+		prompt := outputs.WithInstructions(fmt.Sprintf(moderatorPrompt, strings.Join(names, "、"), instructions, t.Text()), p)
+		chat := func(ctx context.Context, messages []spec.Message) (*spec.Response, error) {
+			return llm.ChatMessages(ctx, messages, cfg)
+		}
+		v, _, err := outputs.Run(ctx, chat, []spec.Message{spec.NewUserMessage(prompt)}, p, outputs.DefaultRetries)
+		if err != nil {
+			return "", err
+		}
+		next := strings.TrimSpace(v.Next)
+		if strings.EqualFold(next, "END") {
+			return Stop, nil
+		}
+		if !slices.Contains(names, next) {
+			return "", fmt.Errorf("unknown participant %q", next)
+		}
+		return next, nil
+	})
+}
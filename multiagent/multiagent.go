@@ -0,0 +1,262 @@
+// Package multiagent 让多个客户端（不同人设或模型）在主持策略下互相对话，
+// 适用于辩论、作者与评审等模式。每个参与者使用自己的 client.Client 和对话历史，
+// 轮到发言时收到上次发言之后其他参与者的内容：
+//
+//	writer, _ := client.NewWithPersona("writer")
+//	critic, _ := client.NewWithPersona("critic")
+//	conv := multiagent.New([]multiagent.Participant{
+//		{Name: "作者", Client: writer},
+//		{Name: "评审", Client: critic},
+//	}, multiagent.WithMaxTurns(6), multiagent.WithStopPhrase("[通过]"))
+//	t, err := conv.Run(ctx, "写一首关于秋天的七言绝句")
+//	fmt.Println(t.Text())
+package multiagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultMaxTurns 是默认的最大发言次数
+const defaultMaxTurns = 10
+
+// Stop 是 Moderator 表示结束对话时返回的发言者名称
+const Stop = ""
+
+// 对话结束的原因
+const (
+	StopReasonModerator = "moderator"
+	StopReasonMaxTurns  = "max_turns"
+	StopReasonPhrase    = "stop_phrase"
+	StopReasonCondition = "condition"
+)
+
+// Sender 是参与对话的客户端，*client.Client 满足该接口，对话历史由客户端自己维护
+type Sender interface {
+	Send(ctx context.Context, prompt string) (*spec.Response, error)
+}
+
+// Participant 是对话的参与者
+type Participant struct {
+	// Name 参与者名称，在对话中唯一，会出现在其他参与者收到的消息中
+	Name string
+	// Client 用于发言的客户端，通常通过 client.NewWithPersona 创建
+	Client Sender
+}
+
+// Turn 是一次发言
+type Turn struct {
+	Speaker  string         `json:"speaker"`
+	Content  string         `json:"content"`
+	Response *spec.Response `json:"-"`
+	Latency  time.Duration  `json:"latency"`
+}
+
+// Transcript 是完整的对话记录
+type Transcript struct {
+	Topic string `json:"topic"`
+	Turns []Turn `json:"turns"`
+	// StopReason 对话结束的原因，见 StopReason* 常量，出错时为空
+	StopReason string `json:"stop_reason"`
+}
+
+// Last 返回最后一次发言，还没有发言时返回 nil
+func (t *Transcript) Last() *Turn {
+	if len(t.Turns) == 0 {
+		return nil
+	}
+	return &t.Turns[len(t.Turns)-1]
+}
+
+// Text 把对话记录格式化为纯文本
+func (t *Transcript) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[主题] %s\n", t.Topic)
+	for _, turn := range t.Turns {
+		fmt.Fprintf(&b, "\n[%s] %s\n", turn.Speaker, turn.Content)
+	}
+	return b.String()
+}
+
+// Messages 把对话记录转换为消息列表：主题为用户消息，每次发言为以 "[名称]" 开头的助手消息，
+// 可交给 transcript 包导出
+func (t *Transcript) Messages() []spec.Message {
+	messages := []spec.Message{spec.NewUserMessage(t.Topic)}
+	for _, turn := range t.Turns {
+		messages = append(messages, spec.NewAssistantMessage(fmt.Sprintf("[%s] %s", turn.Speaker, turn.Content)))
+	}
+	return messages
+}
+
+// config 是 Conversation 的配置
+type config struct {
+	moderator  Moderator
+	maxTurns   int
+	stopPhrase string
+	stopWhen   []func(t *Transcript) bool
+	hooks      []func(turn Turn)
+}
+
+// Option 用于配置 Conversation
+type Option func(c *config)
+
+// WithModerator 设置决定发言顺序的主持策略，默认为 RoundRobin
+func WithModerator(m Moderator) Option {
+	return func(c *config) {
+		c.moderator = m
+	}
+}
+
+// WithMaxTurns 设置最大发言次数，默认为 10
+func WithMaxTurns(n int) Option {
+	return func(c *config) {
+		c.maxTurns = n
+	}
+}
+
+// WithStopPhrase 在发言包含 phrase 时结束对话，如评审回复 "[通过]"
+func WithStopPhrase(phrase string) Option {
+	return func(c *config) {
+		c.stopPhrase = phrase
+	}
+}
+
+// WithStopCondition 在每次发言后检查对话记录，cond 返回 true 时结束对话，可以注册多个
+func WithStopCondition(cond func(t *Transcript) bool) Option {
+	return func(c *config) {
+		c.stopWhen = append(c.stopWhen, cond)
+	}
+}
+
+// WithTurnHook 注册每次发言后调用的回调，可用于实时展示对话
+func WithTurnHook(hook func(turn Turn)) Option {
+	return func(c *config) {
+		c.hooks = append(c.hooks, hook)
+	}
+}
+
+// Conversation 是多个参与者之间的对话
+type Conversation struct {
+	participants []Participant
+	config
+}
+
+// New 创建对话，参与者的顺序即 RoundRobin 的发言顺序
+func New(participants []Participant, opts ...Option) *Conversation {
+	c := &Conversation{
+		participants: participants,
+		config:       config{moderator: RoundRobin(), maxTurns: defaultMaxTurns},
+	}
+	for _, opt := range opts {
+		opt(&c.config)
+	}
+	return c
+}
+
+// Names 返回参与者名称
+func (c *Conversation) Names() []string {
+	names := make([]string, len(c.participants))
+	for i, p := range c.participants {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// validate 检查参与者配置
+func (c *Conversation) validate() error {
+	if len(c.participants) == 0 {
+		return errors.New("multiagent: no participants")
+	}
+	seen := make(map[string]bool, len(c.participants))
+	for _, p := range c.participants {
+		if p.Name == "" || p.Client == nil {
+			return errors.New("multiagent: participant requires a name and a client")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("multiagent: duplicate participant %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// Run 围绕 topic 进行对话，直到主持策略结束对话或满足停止条件。
+// 出错时返回截至出错前的对话记录和错误
+func (c *Conversation) Run(ctx context.Context, topic string) (*Transcript, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	t := &Transcript{Topic: topic}
+	names := c.Names()
+	// heard 记录每个参与者已经收到的发言数
+	heard := make(map[string]int, len(names))
+	for {
+		if len(t.Turns) >= c.maxTurns {
+			t.StopReason = StopReasonMaxTurns
+			return t, nil
+		}
+		speaker, err := c.moderator.Next(ctx, t, names)
+		if err != nil {
+			return t, fmt.Errorf("multiagent: moderator failed: %w", err)
+		}
+		if speaker == Stop {
+			t.StopReason = StopReasonModerator
+			return t, nil
+		}
+		i := slices.Index(names, speaker)
+		if i < 0 {
+			return t, fmt.Errorf("multiagent: moderator chose unknown participant %q", speaker)
+		}
+
+		_, started := heard[speaker]
+		prompt := c.prompt(t, speaker, heard[speaker], !started)
+		start := time.Now()
+		resp, err := c.participants[i].Client.Send(ctx, prompt)
+		if err != nil {
+			return t, fmt.Errorf("multiagent: %s: %w", speaker, err)
+		}
+		turn := Turn{Speaker: speaker, Content: strings.TrimSpace(resp.Message.PlainText()), Response: resp, Latency: time.Since(start)}
+		t.Turns = append(t.Turns, turn)
+		heard[speaker] = len(t.Turns)
+		for _, hook := range c.hooks {
+			hook(turn)
+		}
+
+		if c.stopPhrase != "" && strings.Contains(turn.Content, c.stopPhrase) {
+			t.StopReason = StopReasonPhrase
+			return t, nil
+		}
+		for _, cond := range c.stopWhen {
+			if cond(t) {
+				t.StopReason = StopReasonCondition
+				return t, nil
+			}
+		}
+	}
+}
+
+// prompt 构建发给 speaker 的消息：第一次发言时包含主题，之后只包含上次发言之后其他参与者的发言
+func (c *Conversation) prompt(t *Transcript, speaker string, from int, first bool) string {
+	var b strings.Builder
+	if first {
+		others := slices.DeleteFunc(c.Names(), func(name string) bool { return name == speaker })
+		fmt.Fprintf(&b, "你正在以「%s」的身份参与一场多人对话", speaker)
+		if len(others) > 0 {
+			fmt.Fprintf(&b, "，其他参与者：%s", strings.Join(others, "、"))
+		}
+		fmt.Fprintf(&b, "。\n\n[主题] %s\n", t.Topic)
+	}
+	for _, turn := range t.Turns[from:] {
+		if turn.Speaker != speaker {
+			fmt.Fprintf(&b, "\n[%s] %s\n", turn.Speaker, turn.Content)
+		}
+	}
+	b.WriteString("\n轮到你发言，请直接给出你的发言内容。")
+	return strings.TrimSpace(b.String())
+}
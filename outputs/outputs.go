@@ -0,0 +1,116 @@
+// Package outputs 把模型的文本回复解析为结构化数据：JSON、列表、键值对和正则解析器。
+// Run 在解析失败时自动把解析错误反馈给模型并重试，修复格式上的小错误。
+//
+//	p := outputs.JSON[Review]()
+//	review, resp, err := outputs.Run(ctx, chat, messages, p, 1)
+package outputs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// DefaultRetries 是解析失败后默认的重试次数
+const DefaultRetries = 1
+
+// repairPrompt 是解析失败后发送给模型的修复提示
+const repairPrompt = "你的回复无法按要求的格式解析：%v\n请修正后重新输出完整的回复，不要包含任何解释。"
+
+// Parser 把模型的文本回复解析为 T
+type Parser[T any] interface {
+	// Parse 解析回复文本，格式不符时返回错误，错误信息会反馈给模型
+	Parse(text string) (T, error)
+	// Instructions 返回追加在提示词后的格式说明，为空表示不追加
+	Instructions() string
+}
+
+// Func 用解析函数和格式说明创建 Parser
+func Func[T any](parse func(text string) (T, error), instructions string) Parser[T] {
+	return funcParser[T]{parse: parse, instructions: instructions}
+}
+
+type funcParser[T any] struct {
+	parse        func(text string) (T, error)
+	instructions string
+}
+
+func (p funcParser[T]) Parse(text string) (T, error) { return p.parse(text) }
+func (p funcParser[T]) Instructions() string         { return p.instructions }
+
+// ParseError 表示重试后回复仍然无法解析
+type ParseError struct {
+	// Text 最后一次回复的文本
+	Text string
+	// Attempts 调用模型的总次数
+	Attempts int
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("outputs: failed to parse response after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// WithInstructions 在 prompt 后追加解析器的格式说明
+func WithInstructions[T any](prompt string, p Parser[T]) string {
+	if ins := p.Instructions(); ins != "" {
+		return prompt + "\n\n" + ins
+	}
+	return prompt
+}
+
+// ChatFunc 发送消息列表并返回模型回复
+type ChatFunc func(ctx context.Context, messages []spec.Message) (*spec.Response, error)
+
+// Run 调用 chat 并解析回复。解析失败时把上一次回复和解析错误追加到对话中重试，
+// 最多重试 retries 次，重试后仍失败时返回 *ParseError 和最后一次回复。
+// messages 应已包含格式说明（见 WithInstructions），不会被修改
+func Run[T any](ctx context.Context, chat ChatFunc, messages []spec.Message, p Parser[T], retries int) (T, *spec.Response, error) {
+	var zero T
+	messages = append([]spec.Message(nil), messages...)
+	for attempt := 0; ; attempt++ {
+		resp, err := chat(ctx, messages)
+		if err != nil {
+			return zero, nil, err
+		}
+		if resp.DryRun != nil {
+			return zero, resp, nil
+		}
+		text := resp.Message.PlainText()
+		value, perr := p.Parse(text)
+		if perr == nil {
+			return value, resp, nil
+		}
+		if attempt >= retries {
+			return zero, resp, &ParseError{Text: text, Attempts: attempt + 1, Err: perr}
+		}
+		messages = append(messages, resp.Message, spec.NewUserMessage(fmt.Sprintf(repairPrompt, perr)))
+	}
+}
+
+// errEmpty 表示回复中没有找到可解析的内容
+var errEmpty = errors.New("no content found in response")
+
+// trimFence 去掉回复外层的 Markdown 代码块标记
+func trimFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	if i := strings.Index(text, "\n"); i >= 0 {
+		text = text[i+1:]
+	} else {
+		return ""
+	}
+	if i := strings.LastIndex(text, "```"); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,150 @@
+package outputs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/tools"
+)
+
+// JSON 返回把回复解析为 T 的解析器。回复外层的代码块标记和 JSON 前后的说明文字会被忽略，
+// T 为结构体时格式说明中包含由 tools.SchemaOf 生成的 JSON Schema
+func JSON[T any]() Parser[T] {
+	instructions := "请只输出一个合法的 JSON 值，不要输出任何其他内容。"
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		if schema, err := tools.SchemaOf(t); err == nil {
+			if raw, err := json.Marshal(schema); err == nil {
+				instructions = "请只输出一个符合以下 JSON Schema 的 JSON 对象，不要输出任何其他内容：\n" + string(raw)
+			}
+		}
+	}
+	return Func(parseJSON[T], instructions)
+}
+
+// parseJSON 从回复中提取并解析 JSON
+func parseJSON[T any](text string) (T, error) {
+	var v T
+	raw := extractJSON(trimFence(text))
+	if raw == "" {
+		return v, fmt.Errorf("no JSON found in response")
+	}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return v, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return v, nil
+}
+
+// extractJSON 返回文本中从第一个 { 或 [ 到与之匹配的最后一个 } 或 ] 的部分
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return ""
+	}
+	closer := "}"
+	if text[start] == '[' {
+		closer = "]"
+	}
+	end := strings.LastIndex(text, closer)
+	if end < start {
+		return text[start:]
+	}
+	return text[start : end+1]
+}
+
+// listItem 匹配列表项的标记：- * • 或 1. 1) 1、
+var listItem = regexp.MustCompile(`^\s*(?:[-*•+]|\d+[.)、])\s+`)
+
+// List 返回把回复中的列表项（- * • 开头或 1. 1) 1、 编号）解析为字符串切片的解析器，非列表行会被忽略
+func List() Parser[[]string] {
+	return Func(func(text string) ([]string, error) {
+		var items []string
+		for _, line := range strings.Split(trimFence(text), "\n") {
+			if loc := listItem.FindStringIndex(line); loc != nil {
+				if item := strings.TrimSpace(line[loc[1]:]); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("no list items found, each item must start with \"- \"")
+		}
+		return items, nil
+	}, "请以列表形式输出，每行一项，以 \"- \" 开头，不要输出其他内容。")
+}
+
+// KeyValue 返回把 "键: 值" 形式的行（支持中文冒号）解析为 map 的解析器。
+// keys 为必须出现的键，缺少时解析失败；格式说明会列出这些键
+func KeyValue(keys ...string) Parser[map[string]string] {
+	instructions := "请按 \"键: 值\" 的形式每行输出一项，不要输出其他内容。"
+	if len(keys) > 0 {
+		instructions = fmt.Sprintf("请按 \"键: 值\" 的形式每行输出一项，必须包含以下键：%s。不要输出其他内容。", strings.Join(keys, "、"))
+	}
+	return Func(func(text string) (map[string]string, error) {
+		result := make(map[string]string)
+		for _, line := range strings.Split(trimFence(text), "\n") {
+			line = listItem.ReplaceAllString(line, "")
+			k, v, ok := cutAny(line, ":", "：")
+			if !ok {
+				continue
+			}
+			k = strings.Trim(strings.TrimSpace(k), "*`\"")
+			if k != "" {
+				result[k] = strings.TrimSpace(v)
+			}
+		}
+		var missing []string
+		for _, k := range keys {
+			if _, ok := result[k]; !ok {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("missing keys: %s", strings.Join(missing, ", "))
+		}
+		if len(result) == 0 {
+			return nil, errEmpty
+		}
+		return result, nil
+	}, instructions)
+}
+
+// cutAny 在第一个出现的分隔符处切分
+func cutAny(s string, seps ...string) (before, after string, found bool) {
+	best := -1
+	sepLen := 0
+	for _, sep := range seps {
+		if i := strings.Index(s, sep); i >= 0 && (best < 0 || i < best) {
+			best, sepLen = i, len(sep)
+		}
+	}
+	if best < 0 {
+		return s, "", false
+	}
+	return s[:best], s[best+sepLen:], true
+}
+
+// Regex 返回用正则匹配回复的解析器，结果为命名分组到匹配内容的映射（未命名的分组以序号为键，
+// "0" 为整个匹配）。instructions 为格式说明，可以为空
+func Regex(re *regexp.Regexp, instructions string) Parser[map[string]string] {
+	return Func(func(text string) (map[string]string, error) {
+		m := re.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("response does not match the expected format %s", re.String())
+		}
+		result := make(map[string]string, len(m))
+		for i, name := range re.SubexpNames() {
+			if name == "" {
+				name = fmt.Sprint(i)
+			}
+			result[name] = m[i]
+		}
+		return result, nil
+	}, instructions)
+}
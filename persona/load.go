@@ -0,0 +1,83 @@
+package persona
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Parse 解析人设包：JSON 内容可以是单个人设对象，也可以是人设数组
+func Parse(data []byte) ([]*Persona, error) {
+	data = bytes.TrimSpace(data)
+	var personas []*Persona
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &personas); err != nil {
+			return nil, fmt.Errorf("persona: invalid pack: %w", err)
+		}
+	} else {
+		var p Persona
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("persona: invalid pack: %w", err)
+		}
+		personas = []*Persona{&p}
+	}
+	for _, p := range personas {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return personas, nil
+}
+
+// LoadFile 从 JSON 文件加载人设包并注册。单个人设的文件未设置 name 时使用文件名（不含扩展名）
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("persona: %w", err)
+	}
+	// 单个对象未指定名称时以文件名命名
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var p Persona
+		if err := json.Unmarshal(trimmed, &p); err != nil {
+			return fmt.Errorf("persona: invalid pack %s: %w", path, err)
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		return r.Register(&p)
+	}
+	personas, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	return r.Register(personas...)
+}
+
+// LoadDir 加载目录下所有 .json 文件中的人设，按文件名顺序注册，后加载的同名人设覆盖先加载的
+func (r *Registry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("persona: %w", err)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := r.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFile 从 JSON 文件加载人设包到默认注册表
+func LoadFile(path string) error {
+	return Default.LoadFile(path)
+}
+
+// LoadDir 加载目录下的人设包到默认注册表
+func LoadDir(dir string) error {
+	return Default.LoadDir(dir)
+}
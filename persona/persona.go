@@ -0,0 +1,180 @@
+// Package persona 管理人设包：每个人设把名称映射到系统提示词、默认参数和首选模型，
+// 可以在代码中注册，也可以从 JSON 文件加载，再通过 client.NewWithPersona 创建配置好的客户端：
+//
+//	// personas/support-agent.json
+//	// {"name": "support-agent", "system_prompt": "你是 ACME 的客服……",
+//	//  "provider": "dashscope", "models": ["qwen-plus", "qwen-turbo"],
+//	//  "parameters": {"temperature": 0.3}}
+//	if err := persona.LoadDir("personas"); err != nil { ... }
+//	c, err := client.NewWithPersona("support-agent")
+package persona
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+)
+
+// ErrNotFound 表示没有指定名称的人设
+var ErrNotFound = errors.New("persona: not found")
+
+// Persona 是一个人设
+type Persona struct {
+	// Name 人设名称，在注册表中唯一
+	Name string `json:"name"`
+	// Description 人设的说明，仅用于展示
+	Description string `json:"description,omitempty"`
+	// SystemPrompt 系统提示词
+	SystemPrompt string `json:"system_prompt"`
+	// Parameters 默认的模型参数，如 temperature、max_tokens，与配置中已有的参数合并，人设优先
+	Parameters map[string]any `json:"parameters,omitempty"`
+	// Thinking 是否开启思考模式，nil 表示不指定
+	Thinking *bool `json:"thinking,omitempty"`
+	// Provider 首选的提供商，为空表示沿用配置
+	Provider string `json:"provider,omitempty"`
+	// Models 按优先级排列的首选模型：第一个作为主模型，其余作为同一提供商的故障转移备选
+	Models []string `json:"models,omitempty"`
+	// APIURL 自定义接口地址，为空表示沿用配置
+	APIURL string `json:"api_url,omitempty"`
+	// APIKeyEnv 读取 API Key 的环境变量名，为空时按提供商使用默认变量名（见 client.NewWithPersona）
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// Validate 检查人设是否完整
+func (p *Persona) Validate() error {
+	if p.Name == "" {
+		return errors.New("persona: name is required")
+	}
+	if p.SystemPrompt == "" {
+		return fmt.Errorf("persona %s: system prompt is required", p.Name)
+	}
+	return nil
+}
+
+// Apply 返回应用了人设的配置：覆盖系统提示词，合并参数，设置了提供商和模型时一并替换。
+// 有多个首选模型且 cfg 未配置故障转移时，其余模型作为 cfg.Failover 的备用后端
+func (p *Persona) Apply(cfg llm.Config) llm.Config {
+	cfg.SystemPrompt = p.SystemPrompt
+	if len(p.Parameters) > 0 {
+		params := maps.Clone(cfg.Parameters)
+		if params == nil {
+			params = make(map[string]any, len(p.Parameters))
+		}
+		maps.Copy(params, p.Parameters)
+		cfg.Parameters = params
+	}
+	if p.Thinking != nil {
+		thinking := *p.Thinking
+		cfg.Thinking = &thinking
+	}
+	if p.Provider != "" {
+		cfg.Provider = p.Provider
+	}
+	if p.APIURL != "" {
+		cfg.APIURL = p.APIURL
+	}
+	if len(p.Models) > 0 {
+		cfg.Model = p.Models[0]
+		if len(p.Models) > 1 && cfg.Failover == nil {
+			failover := &llm.FailoverConfig{}
+			for _, model := range p.Models[1:] {
+				fallback := cfg
+				fallback.Model = model
+				failover.Fallbacks = append(failover.Fallbacks, fallback)
+			}
+			cfg.Failover = failover
+		}
+	}
+	return cfg
+}
+
+// clone 返回人设的副本，避免调用方修改注册表中的数据
+func (p *Persona) clone() *Persona {
+	c := *p
+	c.Parameters = maps.Clone(p.Parameters)
+	c.Models = slices.Clone(p.Models)
+	if p.Thinking != nil {
+		thinking := *p.Thinking
+		c.Thinking = &thinking
+	}
+	return &c
+}
+
+// Registry 是人设注册表，可并发使用
+type Registry struct {
+	mu       sync.RWMutex
+	personas map[string]*Persona
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{personas: make(map[string]*Persona)}
+}
+
+// Register 注册人设，名称相同时覆盖原有人设
+func (r *Registry) Register(personas ...*Persona) error {
+	for _, p := range personas {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range personas {
+		r.personas[p.Name] = p.clone()
+	}
+	return nil
+}
+
+// Get 返回指定名称人设的副本，不存在时返回 ErrNotFound
+func (r *Registry) Get(name string) (*Persona, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.personas[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return p.clone(), nil
+}
+
+// Remove 删除人设
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.personas, name)
+}
+
+// Names 返回所有人设名称，按字母顺序排列
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.personas))
+	for name := range r.personas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default 是包级函数使用的默认注册表
+var Default = NewRegistry()
+
+// Register 向默认注册表注册人设
+func Register(personas ...*Persona) error {
+	return Default.Register(personas...)
+}
+
+// Get 从默认注册表获取人设
+func Get(name string) (*Persona, error) {
+	return Default.Get(name)
+}
+
+// Names 返回默认注册表中的人设名称
+func Names() []string {
+	return Default.Names()
+}
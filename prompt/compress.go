@@ -0,0 +1,214 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// Compressor 把文本压缩到 budget 个 token 以内，count 计算文本的 token 数。
+// 无法达到预算时返回尽量压缩后的文本，由 Compress 决定是否继续使用下一个 Compressor
+type Compressor interface {
+	Compress(ctx context.Context, text string, budget int, count func(string) int) (string, error)
+}
+
+// Compression 是 Compress 的结果
+type Compression struct {
+	Text string
+	// OriginalTokens 和 Tokens 是压缩前后的 token 数
+	OriginalTokens int
+	Tokens         int
+	// Ratio 压缩后与压缩前 token 数之比，1 表示未压缩
+	Ratio float64
+	// Fits 压缩后是否在预算以内
+	Fits bool
+}
+
+// Compress 依次使用 compressors 压缩 text，直到按 model 计算的 token 数不超过 budget。
+// 未指定 compressors 时只去除停用词；文本本来就在预算内时原样返回
+func Compress(ctx context.Context, text, model string, budget int, compressors ...Compressor) (*Compression, error) {
+	count := func(s string) int { return tokens.Count(model, s) }
+	if len(compressors) == 0 {
+		compressors = []Compressor{StopWords()}
+	}
+	original := count(text)
+	n := original
+	for _, c := range compressors {
+		if n <= budget {
+			break
+		}
+		out, err := c.Compress(ctx, text, budget, count)
+		if err != nil {
+			return nil, fmt.Errorf("prompt: compression failed: %w", err)
+		}
+		// 压缩器没有缩短文本时保留原文
+		if m := count(out); m < n {
+			text, n = out, m
+		}
+	}
+	c := &Compression{Text: text, OriginalTokens: original, Tokens: n, Ratio: 1, Fits: n <= budget}
+	if original > 0 {
+		c.Ratio = float64(n) / float64(original)
+	}
+	return c, nil
+}
+
+// CompressorFunc 把函数转换为 Compressor
+type CompressorFunc func(ctx context.Context, text string, budget int, count func(string) int) (string, error)
+
+func (f CompressorFunc) Compress(ctx context.Context, text string, budget int, count func(string) int) (string, error) {
+	return f(ctx, text, budget, count)
+}
+
+// englishStopWords 是常见的英文停用词
+var englishStopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a an the and or but so of to in on at by for from with about as into onto
+		is are was were be been being am do does did have has had will would shall should can could may might must
+		this that these those it its i me my we our you your he him his she her they them their there here
+		very really just quite rather also too then than such some any each every all both either neither
+		which who whom whose what when where how why if because while although though whether
+		not no nor only own same other more most less least much many few again further once
+		up down out off over under above below between through during before after until`) {
+		englishStopWords[w] = true
+	}
+}
+
+// chineseFillers 是可以安全删除的中文口语填充词，按长度从长到短排列以优先匹配长词。
+// 单字虚词（如 "的"、"了"）常是实词的一部分，只在句末删除，见 chineseParticles
+var chineseFillers = []string{"也就是说", "实际上", "就是说", "其实", "然后", "非常", "一下"}
+
+var (
+	englishWord = regexp.MustCompile(`[A-Za-z']+`)
+	extraSpaces = regexp.MustCompile(`[ \t]{2,}`)
+	spaceBefore = regexp.MustCompile(` +([,.;:!?])`)
+	// chineseParticles 匹配句末的语气词
+	chineseParticles = regexp.MustCompile(`[吗呢吧啊呀嘛了]+([。！？，!?,]|\n|$)`)
+)
+
+// StopWords 返回去除英文停用词、中文填充词和句末语气词并合并多余空白的 Compressor。
+// 不依赖模型，速度快，通常能减少 10%~30% 的 token，但可能影响语义的细微差别
+func StopWords() Compressor {
+	return CompressorFunc(func(ctx context.Context, text string, budget int, count func(string) int) (string, error) {
+		out := englishWord.ReplaceAllStringFunc(text, func(w string) string {
+			if englishStopWords[strings.ToLower(w)] {
+				return ""
+			}
+			return w
+		})
+		for _, w := range chineseFillers {
+			out = strings.ReplaceAll(out, w, "")
+		}
+		out = chineseParticles.ReplaceAllString(out, "$1")
+		out = extraSpaces.ReplaceAllString(out, " ")
+		out = spaceBefore.ReplaceAllString(out, "$1")
+		lines := strings.Split(out, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimSpace(l)
+		}
+		return strings.Join(slices.DeleteFunc(lines, func(l string) bool { return l == "" }), "\n"), nil
+	})
+}
+
+// llmCompressPrompt 是模型压缩使用的提示词
+const llmCompressPrompt = "请压缩以下文本，使其长度不超过约 %d 个 token。保留所有关键信息：事实、数字、名称、日期、指令和约束条件；删除重复、寒暄和修饰性内容，可以使用简洁的短句。只输出压缩后的文本，不要解释。\n\n%s"
+
+// LLM 返回由 cfg 指定的模型改写压缩文本的 Compressor，效果最好但需要一次额外的模型调用
+func LLM(cfg llm.Config) Compressor {
+	return CompressorFunc(func(ctx context.Context, text string, budget int, count func(string) int) (string, error) {
+		out, err := llm.ChatText(ctx, fmt.Sprintf(llmCompressPrompt, budget, text), cfg)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(out), nil
+	})
+}
+
+// sentenceEnd 匹配句子的结束位置：中英文句末标点（可带右引号、右括号）或换行
+var sentenceEnd = regexp.MustCompile(`[。！？!?；;]+["”’)）]*|\.["”’)]*\s|\n+`)
+
+// splitSentences 把文本切分为保留结尾标点的句子
+func splitSentences(text string) []string {
+	var out []string
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		if s := text[start:loc[1]]; strings.TrimSpace(s) != "" {
+			out = append(out, s)
+		}
+		start = loc[1]
+	}
+	if s := text[start:]; strings.TrimSpace(s) != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// Sentences 返回按向量相似度删减句子的 Compressor：与 query 最相关的句子优先保留，
+// query 为空时保留与全文主旨（全部句子向量的平均）最接近的句子。保留的句子维持原文顺序
+func Sentences(embedder spec.Embedder, query string) Compressor {
+	return CompressorFunc(func(ctx context.Context, text string, budget int, count func(string) int) (string, error) {
+		sentences := splitSentences(text)
+		if len(sentences) <= 1 {
+			return text, nil
+		}
+		inputs := slices.Clone(sentences)
+		if query != "" {
+			inputs = append(inputs, query)
+		}
+		vectors, err := embedder.Embed(ctx, inputs)
+		if err != nil {
+			return "", err
+		}
+		if len(vectors) != len(inputs) {
+			return "", fmt.Errorf("got %d vectors for %d texts", len(vectors), len(inputs))
+		}
+		for i := range vectors {
+			vectors[i] = normalize(vectors[i])
+		}
+
+		var target []float32
+		if query != "" {
+			target = vectors[len(sentences)]
+		} else {
+			target = make([]float32, len(vectors[0]))
+			for _, v := range vectors {
+				for j := range min(len(v), len(target)) {
+					target[j] += v[j]
+				}
+			}
+			target = normalize(target)
+		}
+
+		order := make([]int, len(sentences))
+		scores := make([]float64, len(sentences))
+		for i := range sentences {
+			order[i] = i
+			scores[i] = dot(vectors[i], target)
+		}
+		sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+		keep := make([]bool, len(sentences))
+		total := 0
+		for _, i := range order {
+			if n := count(sentences[i]); total+n <= budget {
+				keep[i] = true
+				total += n
+			}
+		}
+		var b strings.Builder
+		for i, s := range sentences {
+			if keep[i] {
+				b.WriteString(s)
+			}
+		}
+		return strings.TrimSpace(b.String()), nil
+	})
+}
@@ -0,0 +1,105 @@
+// Package prompt 提供提示词构建的辅助工具：few-shot 示例的管理与渲染，以及把长上下文压缩到 token 预算以内（Compress）。
+//
+//	fs := prompt.NewFewShot(
+//		prompt.Example{Input: "这家店太棒了", Output: "正面"},
+//		prompt.Example{Input: "等了一小时还没上菜", Output: "负面"},
+//	)
+//	examples, _ := fs.Messages(ctx, input)
+//	messages := append(append([]spec.Message{system}, examples...), spec.NewUserMessage(input))
+package prompt
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Example 是一组示例输入和期望输出
+type Example struct {
+	Input  string
+	Output string
+}
+
+// Selector 从全部示例中挑选与 input 相关的示例
+type Selector interface {
+	Select(ctx context.Context, input string, examples []Example) ([]Example, error)
+}
+
+// FewShot 管理 few-shot 示例，可渲染为交替的 user/assistant 消息或格式化的文本块，可并发使用
+type FewShot struct {
+	// Selector 挑选示例的策略，nil 表示使用全部示例
+	Selector Selector
+	// InputPrefix 和 OutputPrefix 是 Format 中输入、输出的前缀，为空时使用 "输入：" 和 "输出："
+	InputPrefix  string
+	OutputPrefix string
+
+	mu       sync.RWMutex
+	examples []Example
+}
+
+// NewFewShot 使用示例创建 FewShot
+func NewFewShot(examples ...Example) *FewShot {
+	return &FewShot{examples: append([]Example(nil), examples...)}
+}
+
+// Add 追加示例
+func (f *FewShot) Add(examples ...Example) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.examples = append(f.examples, examples...)
+}
+
+// Examples 返回全部示例的副本
+func (f *FewShot) Examples() []Example {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]Example(nil), f.examples...)
+}
+
+// Select 返回用于 input 的示例
+func (f *FewShot) Select(ctx context.Context, input string) ([]Example, error) {
+	examples := f.Examples()
+	if f.Selector == nil || len(examples) == 0 {
+		return examples, nil
+	}
+	return f.Selector.Select(ctx, input, examples)
+}
+
+// Messages 把为 input 挑选的示例渲染为交替的 user/assistant 消息，
+// 放在系统消息之后、真正的用户消息之前
+func (f *FewShot) Messages(ctx context.Context, input string) ([]spec.Message, error) {
+	examples, err := f.Select(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]spec.Message, 0, len(examples)*2)
+	for _, e := range examples {
+		messages = append(messages, spec.NewUserMessage(e.Input), spec.NewAssistantMessage(e.Output))
+	}
+	return messages, nil
+}
+
+// Format 把为 input 挑选的示例渲染为文本块，可以放进系统提示词或补全模型的提示中：
+//
+//	输入：这家店太棒了
+//	输出：正面
+func (f *FewShot) Format(ctx context.Context, input string) (string, error) {
+	examples, err := f.Select(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	in, out := f.InputPrefix, f.OutputPrefix
+	if in == "" {
+		in = "输入："
+	}
+	if out == "" {
+		out = "输出："
+	}
+	blocks := make([]string, 0, len(examples))
+	for _, e := range examples {
+		blocks = append(blocks, in+e.Input+"\n"+out+e.Output)
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
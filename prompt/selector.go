@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// FirstK 返回只使用前 k 个示例的 Selector
+func FirstK(k int) Selector {
+	return firstK(k)
+}
+
+type firstK int
+
+func (k firstK) Select(ctx context.Context, input string, examples []Example) ([]Example, error) {
+	return examples[:min(int(k), len(examples))], nil
+}
+
+// SemanticSelector 按向量相似度挑选与输入最相关的 K 个示例。
+// 示例的向量按输入文本缓存，只在首次遇到时计算
+type SemanticSelector struct {
+	Embedder spec.Embedder
+	// K 挑选的示例数，为 0 时使用 3
+	K int
+
+	mu      sync.Mutex
+	vectors map[string][]float32 // 已归一化
+}
+
+// NewSemanticSelector 创建按相似度挑选 k 个示例的 Selector
+func NewSemanticSelector(embedder spec.Embedder, k int) *SemanticSelector {
+	return &SemanticSelector{Embedder: embedder, K: k}
+}
+
+// Select 返回与 input 最相似的 K 个示例，按相似度从低到高排列，使最相关的示例最靠近真正的问题
+func (s *SemanticSelector) Select(ctx context.Context, input string, examples []Example) ([]Example, error) {
+	k := s.K
+	if k <= 0 {
+		k = 3
+	}
+	if len(examples) <= k {
+		return examples, nil
+	}
+
+	s.mu.Lock()
+	if s.vectors == nil {
+		s.vectors = make(map[string][]float32)
+	}
+	texts := []string{input}
+	for _, e := range examples {
+		if _, ok := s.vectors[e.Input]; !ok && !slices.Contains(texts[1:], e.Input) {
+			texts = append(texts, e.Input)
+		}
+	}
+	s.mu.Unlock()
+
+	vectors, err := s.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("prompt: failed to embed examples: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("prompt: got %d vectors for %d texts", len(vectors), len(texts))
+	}
+	query := normalize(vectors[0])
+
+	type scored struct {
+		example Example
+		score   float64
+	}
+	ranked := make([]scored, 0, len(examples))
+	s.mu.Lock()
+	for i, text := range texts[1:] {
+		s.vectors[text] = normalize(vectors[i+1])
+	}
+	for _, e := range examples {
+		ranked = append(ranked, scored{example: e, score: dot(query, s.vectors[e.Input])})
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	selected := make([]Example, k)
+	for i := range k {
+		selected[k-1-i] = ranked[i].example
+	}
+	return selected, nil
+}
+
+// normalize 返回单位长度的向量副本，使余弦相似度可以直接用点积计算
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// dot 计算两个向量的点积，长度不同时返回 0
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
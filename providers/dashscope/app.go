@@ -0,0 +1,180 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// appModelPrefix 是百炼应用的模型名前缀，Model("app:<APP_ID>") 会调用对应的应用
+const appModelPrefix = "app:"
+
+// appID 返回模型名中的百炼应用 ID，不是应用时返回 false
+func (m *modelImpl) appID() (string, bool) {
+	id, ok := strings.CutPrefix(m.name, appModelPrefix)
+	return id, ok && id != ""
+}
+
+// appOutput 是百炼应用接口的响应结构，流式和非流式相同
+type appOutput struct {
+	Output struct {
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+		SessionID    string `json:"session_id"`
+		Thoughts     []struct {
+			Thought string `json:"thought"`
+		} `json:"thoughts"`
+	} `json:"output"`
+	Usage struct {
+		Models []spec.Usage `json:"models"`
+	} `json:"usage"`
+}
+
+// usage 汇总应用内各模型的用量
+func (o *appOutput) usage() spec.Usage {
+	var total spec.Usage
+	for _, u := range o.Usage.Models {
+		total = total.Add(u)
+	}
+	return total
+}
+
+// thoughts 拼接开启 has_thoughts 时返回的思考过程
+func (o *appOutput) thoughts() string {
+	var parts []string
+	for _, t := range o.Output.Thoughts {
+		if t.Thought != "" {
+			parts = append(parts, t.Thought)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// handleApp 调用百炼应用（智能体、工作流、RAG 应用）。
+// 设置了 WithSessionID 时只发送最后一条消息作为 prompt，历史由服务端维护；否则发送完整的 messages。
+// Parameters 中的 biz_params 放入 input，其余（如 has_thoughts、rag_options）放入 parameters。
+func (m *modelImpl) handleApp(ctx context.Context, appID string, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages provided for bailian app %s", appID)
+	}
+
+	input := make(map[string]any)
+	if config.SessionID != "" {
+		input["session_id"] = config.SessionID
+		input["prompt"] = messages[len(messages)-1].PlainText()
+	} else {
+		history := make([]map[string]any, 0, len(messages))
+		for _, msg := range messages {
+			history = append(history, map[string]any{"role": msg.Role, "content": msg.PlainText()})
+		}
+		input["messages"] = history
+	}
+	parameters := make(map[string]any)
+	for k, v := range config.Parameters {
+		if k == "biz_params" {
+			input[k] = v
+			continue
+		}
+		parameters[k] = v
+	}
+	requestBody := map[string]any{
+		"input":      input,
+		"parameters": parameters,
+		"debug":      map[string]any{},
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+	endpoint := m.client.apiBaseURL() + "/apps/" + url.PathEscape(appID) + "/completion"
+
+	// ==================== 流式处理分支 ====================
+	if config.Streaming {
+		parameters["incremental_output"] = true
+		headers.Set("X-DashScope-SSE", "enable")
+
+		resp, err := m.client.requester.PostStream(ctx, endpoint, headers, requestBody)
+		if err != nil {
+			return config.DryRunResult(fmt.Errorf("dashscope app %s request failed: %w", appID, err))
+		}
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
+		var fullContent strings.Builder
+		var last appOutput
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
+		for scanner.Scan() {
+			dataStr, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			dataStr = strings.TrimSpace(dataStr)
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
+
+			var chunk appOutput
+			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
+				continue
+			}
+			last = chunk
+			if chunk.Output.Text != "" {
+				fullContent.WriteString(chunk.Output.Text)
+				if err := config.EmitStreamChunk(ctx, chunk.Output.Text); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("dashscope: stream scan error: %w", err)
+		}
+
+		return &spec.Response{
+			Message: spec.Message{
+				Role:             spec.RoleAssistant,
+				Content:          fullContent.String(),
+				ReasoningContent: last.thoughts(),
+			},
+			Usage:     last.usage(),
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
+			SessionID: last.Output.SessionID,
+		}, nil
+	}
+
+	// ==================== 非流式处理分支 ====================
+	rawBody, err := m.client.requester.Post(ctx, endpoint, headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(fmt.Errorf("dashscope app %s request failed: %w", appID, err))
+	}
+
+	var apiResp appOutput
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("dashscope: failed to unmarshal app response: %w", err)
+	}
+	return &spec.Response{
+		Message: spec.Message{
+			Role:             spec.RoleAssistant,
+			Content:          apiResp.Output.Text,
+			ReasoningContent: apiResp.thoughts(),
+		},
+		RawResponse: rawBody,
+		Usage:       apiResp.usage(),
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+		SessionID:   apiResp.Output.SessionID,
+	}, nil
+}
@@ -1,12 +1,11 @@
 package dashscope
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"iter"
 	"net/http"
 	"strconv"
 	"strings"
@@ -45,20 +44,63 @@ func NewClient(opts ...spec.ClientOption) (spec.Client, error) {
 		return nil, fmt.Errorf("dashscope: API key is required, use llm.WithAPIKey() option")
 	}
 
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope: %w", err)
+	}
+
 	// 4. 创建并返回客户端实例
 	return &clientImpl{
-		requester: &requester.Requester{
-			HTTPClient: config.HTTPClient, // 使用配置好的HTTPClient
-		},
-		config: *config,
+		requester: req.WithErrorParser(parseError),
+		config:    *config,
 	}, nil
 }
 
+// parseError 解析 DashScope 的错误响应。
+// 兼容模式返回 OpenAI 格式 {"error": {...}, "request_id": "..."}，
+// 原生接口（如图像生成）返回 {"code": "DataInspectionFailed", "message": "...", "request_id": "..."}。
+func parseError(e *spec.APIError) {
+	requester.ParseOpenAIError(e)
+
+	var body struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return
+	}
+	if body.Code != "" {
+		e.Code = body.Code
+	}
+	if body.Message != "" {
+		e.Message = body.Message
+	}
+	if body.RequestID != "" {
+		e.RequestID = body.RequestID
+	}
+}
+
 // Model 实现了 llm.Client 接口的方法
 func (c *clientImpl) Model(name string) spec.Model {
 	return &modelImpl{client: c, name: name}
 }
 
+// Close 实现了 llm.Client 接口的方法，取消进行中的请求并释放空闲连接
+func (c *clientImpl) Close() error {
+	return c.requester.Close()
+}
+
+// CloseIdleConnections 释放空闲的 HTTP 连接，不影响进行中的请求
+func (c *clientImpl) CloseIdleConnections() {
+	c.requester.CloseIdleConnections()
+}
+
+// ChatSeq 实现了 llm.Model 接口的方法，以迭代器形式返回流式事件
+func (m *modelImpl) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
+
 // dashscopeChunk 定义了流式响应的数据结构
 type dashscopeChunk struct {
 	Choices []struct {
@@ -67,6 +109,8 @@ type dashscopeChunk struct {
 			Role    string `json:"role"`
 			// 支持获取思考过程内容（OpenAI 兼容格式下的 reasoning_content）
 			ReasoningContent string `json:"reasoning_content,omitempty"`
+			// 函数调用的增量片段，参数分多个数据块下发
+			ToolCalls []spec.ToolCallDelta `json:"tool_calls,omitempty"`
 		} `json:"delta"`
 		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
@@ -81,48 +125,85 @@ type dashscopeChunk struct {
 	Delta string `json:"delta,omitempty"`
 
 	// Chat Completions API 用法统计
-	Usage *struct {
-		TotalTokens  int `json:"total_tokens"`
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-		XTools       map[string]struct {
-			Count int `json:"count"`
-		} `json:"x_tools,omitempty"`
-	} `json:"usage"`
+	Usage *dashscopeUsage `json:"usage"`
 
 	// Responses API 完成时的用法统计
 	Response *struct {
-		Usage *struct {
-			TotalTokens  int `json:"total_tokens"`
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-			XTools       map[string]struct {
-				Count int `json:"count"`
-			} `json:"x_tools,omitempty"`
-		} `json:"usage"`
+		Usage *dashscopeUsage `json:"usage"`
 	} `json:"response,omitempty"`
 }
 
+// dashscopeUsage 在通用用量之外保留 DashScope 的工具调用次数统计
+type dashscopeUsage struct {
+	spec.Usage
+	XTools map[string]struct {
+		Count int `json:"count"`
+	}
+}
+
+func (u *dashscopeUsage) UnmarshalJSON(data []byte) error {
+	if err := u.Usage.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var tools struct {
+		XTools map[string]struct {
+			Count int `json:"count"`
+		} `json:"x_tools"`
+	}
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return err
+	}
+	u.XTools = tools.XTools
+	return nil
+}
+
 // Chat 实现了 llm.Model 接口的方法
 func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
 	config := spec.NewRequestConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
+	ctx = config.BindContext(ctx)
+
+	if appID, ok := m.appID(); ok {
+		return m.handleApp(ctx, appID, messages, config)
+	}
+
+	messages, err := prepareImages(messages)
+	if err != nil {
+		return nil, err
+	}
+	if config.Translation != nil {
+		if isTranslationModel(m.name) {
+			messages = spec.TranslationInput(messages)
+		} else {
+			messages = config.TranslationMessages(messages)
+		}
+	}
 
 	switch {
 	case config.IsText2Image():
 		return m.handleText2Image(ctx, messages, config)
 
 	case config.IsImageEdit():
-		// 预留 ImageEdit 实现位置（根据 DashScope API 文档后续扩展）
-		return nil, fmt.Errorf("image edit is not supported yet for model %s", m.name)
+		return m.handleImageEdit(ctx, messages, config)
+
+	case config.IsImageVariation():
+		return nil, fmt.Errorf("image variation is not supported for model %s, use image edit instead", m.name)
+
+	case m.client.config.NativeProtocol:
+		return m.handleNativeChat(ctx, messages, config)
 
 	default:
 		return m.handleChat(ctx, messages, config)
 	}
 }
 
+// isTranslationModel 判断模型是否为原生支持 translation_options 的 qwen-mt 翻译模型
+func isTranslationModel(model string) bool {
+	return strings.HasPrefix(model, "qwen-mt")
+}
+
 // handleText2Image 处理文生图同步调用流程（qwen-image-2.0-pro）
 func (m *modelImpl) handleText2Image(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
 	// 1. 提取 Prompt（取最后一条用户消息）
@@ -133,6 +214,9 @@ func (m *modelImpl) handleText2Image(ctx context.Context, messages []spec.Messag
 	if prompt == "" {
 		return nil, fmt.Errorf("empty prompt for text2image")
 	}
+	if isAsyncImageModel(m.name) {
+		return m.handleImageSynthesis(ctx, prompt, config)
+	}
 
 	// 2. 构建请求体（qwen-image-2.0-pro 要求 input.messages 格式）
 	requestBody := map[string]any{
@@ -175,24 +259,26 @@ func (m *modelImpl) handleText2Image(ctx context.Context, messages []spec.Messag
 		}
 	}
 
-	// 3. 构建请求头（同步调用，无需异步头）
+	return m.generate(ctx, requestBody, config)
+}
+
+// generate 调用 multimodal-generation 端点（同步返回，无需轮询任务），用于文生图和图像编辑
+func (m *modelImpl) generate(ctx context.Context, requestBody map[string]any, config *spec.RequestConfig) (*spec.Response, error) {
+	// 1. 构建请求头（同步调用，无需异步头）
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
 	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
 
-	// 4. 发起请求（使用 multimodal-generation 端点）
-	generationURL := "https://dashscope.aliyuncs.com/api/v1/services/aigc/multimodal-generation/generation"
-	if strings.Contains(m.client.config.APIURL, "dashscope-intl") {
-		generationURL = "https://dashscope-intl.aliyuncs.com/api/v1/services/aigc/multimodal-generation/generation"
-	}
+	// 2. 发起请求（使用 multimodal-generation 端点）
+	generationURL := m.client.apiBaseURL() + "/services/aigc/multimodal-generation/generation"
 
 	rawBody, err := m.client.requester.Post(ctx, generationURL, headers, requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("dashscope qwen-image generation failed: %w", err)
+		return config.DryRunResult(fmt.Errorf("dashscope %s generation failed: %w", m.name, err))
 	}
 
-	// 5. 解析响应（同步返回，无需轮询任务）
-	// 响应格式：output.choices[0].message.content[0].image
+	// 3. 解析响应
+	// 响应格式：output.choices[0].message.content[].image
 	var genResp struct {
 		Output struct {
 			Choices []struct {
@@ -230,32 +316,75 @@ func (m *modelImpl) handleText2Image(ctx context.Context, messages []spec.Messag
 		return nil, fmt.Errorf("no choices in generation response: %s", string(rawBody))
 	}
 
-	content := genResp.Output.Choices[0].Message.Content
-	if len(content) == 0 {
-		return nil, fmt.Errorf("empty content in generation response")
-	}
-
-	var imageURL string
-	for _, c := range content {
-		if c.Image != "" {
-			imageURL = c.Image
-			break
+	var images []string
+	for _, choice := range genResp.Output.Choices {
+		for _, c := range choice.Message.Content {
+			if c.Image != "" {
+				images = append(images, c.Image)
+			}
 		}
 	}
 
-	if imageURL == "" {
+	if len(images) == 0 {
 		return nil, fmt.Errorf("no image URL in generation response: %s", string(rawBody))
 	}
 
 	return &spec.Response{
 		Message: spec.Message{
 			Role:    spec.RoleAssistant,
-			Content: imageURL,
+			Content: images[0],
 		},
+		Images:      images,
 		RawResponse: rawBody,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
 	}, nil
 }
 
+// handleImageEdit 处理图像编辑同步调用流程（qwen-image-edit）
+// 最后一条用户消息中的图片为待编辑图片（最多 3 张），文本为编辑指令
+func (m *modelImpl) handleImageEdit(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages provided for image edit")
+	}
+	last := messages[len(messages)-1]
+	images := last.ImageInputs()
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no input image for image edit")
+	}
+	prompt := last.PlainText()
+	if prompt == "" {
+		return nil, fmt.Errorf("empty prompt for image edit")
+	}
+	if _, ok := config.Parameters["mask"]; ok {
+		return nil, fmt.Errorf("dashscope %s does not support mask, describe the region to edit in the prompt instead", m.name)
+	}
+
+	content := make([]map[string]any, 0, len(images)+1)
+	for _, img := range images {
+		content = append(content, map[string]any{"image": img})
+	}
+	content = append(content, map[string]any{"text": prompt})
+
+	parameters := map[string]any{"watermark": false}
+	for _, key := range []string{"n", "negative_prompt", "watermark", "size"} {
+		if val, ok := config.Parameters[key]; ok {
+			parameters[key] = val
+		}
+	}
+
+	requestBody := map[string]any{
+		"model": m.name,
+		"input": map[string]any{
+			"messages": []map[string]any{
+				{"role": "user", "content": content},
+			},
+		},
+		"parameters": parameters,
+	}
+	return m.generate(ctx, requestBody, config)
+}
+
 // handleChat 处理标准聊天请求（流式/非流式）
 func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
 	requestBody := make(map[string]any)
@@ -274,6 +403,10 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 	if config.Temperature != nil {
 		requestBody["temperature"] = *config.Temperature
 	}
+	if config.Translation != nil && isTranslationModel(m.name) {
+		requestBody["translation_options"] = config.Translation
+	}
+	config.ApplyTools(requestBody)
 
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
@@ -284,16 +417,25 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 		requestBody["stream"] = true
 		requestBody["stream_options"] = map[string]bool{"include_usage": true}
 
-		resp, err := m.client.requester.PostStream(ctx, m.client.config.APIURL, headers, requestBody)
+		resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 		if err != nil {
-			return nil, err
+			return config.DryRunResult(err)
 		}
 		defer resp.Body.Close()
 
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
 		var fullContent strings.Builder
+		var usage spec.Usage
+		var toolCalls spec.ToolCallAccumulator
 		role := "assistant"
 
-		scanner := bufio.NewScanner(resp.Body)
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
 		for scanner.Scan() {
 			line := scanner.Text()
 			if !strings.HasPrefix(line, "data:") {
@@ -304,6 +446,9 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 			if dataStr == "[DONE]" {
 				break
 			}
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
 
 			var chunk dashscopeChunk
 			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
@@ -312,7 +457,7 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 
 			// 拦截输出：Responses API 的中间工具抓取过程
 			if chunk.Type == "response.output_item.done" && chunk.Item != nil && chunk.Item.Type == "web_extractor_call" {
-				log.Printf("\n[Web Extractor Action] Goal: %s\nOutput: %s\n", chunk.Item.Goal, chunk.Item.Output)
+				m.client.requester.Logger.Info("dashscope web extractor action", "goal", chunk.Item.Goal, "output", chunk.Item.Output)
 			}
 
 			var contentToAppend string
@@ -330,6 +475,7 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 				if delta.Content != "" {
 					contentToAppend += delta.Content
 				}
+				toolCalls.Add(delta.ToolCalls)
 			} else if chunk.Type == "response.output_text.delta" || chunk.Type == "response.reasoning_summary_text.delta" {
 				// 解析 Responses API 格式
 				contentToAppend = chunk.Delta
@@ -338,20 +484,22 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 			// 如果存在内容，写入 Builder 并触发 Callback
 			if contentToAppend != "" {
 				fullContent.WriteString(contentToAppend)
-				if config.StreamCallback != nil {
-					if err := config.StreamCallback(ctx, contentToAppend); err != nil {
-						return nil, err
-					}
+				if err := config.EmitStreamChunk(ctx, contentToAppend); err != nil {
+					return nil, err
 				}
 			}
 
-			// 拦截输出：打印工具调用次数
+			// 记录用量，并拦截输出：打印工具调用次数
 			if chunk.Type == "response.completed" && chunk.Response != nil && chunk.Response.Usage != nil {
+				usage = chunk.Response.Usage.Usage
 				if len(chunk.Response.Usage.XTools) > 0 {
-					log.Printf("\n[Usage Stats] Tools: %+v", chunk.Response.Usage.XTools)
+					m.client.requester.Logger.Info("dashscope tool usage", "tools", chunk.Response.Usage.XTools)
+				}
+			} else if chunk.Usage != nil {
+				usage = chunk.Usage.Usage
+				if len(chunk.Usage.XTools) > 0 {
+					m.client.requester.Logger.Info("dashscope tool usage", "tools", chunk.Usage.XTools)
 				}
-			} else if chunk.Usage != nil && len(chunk.Usage.XTools) > 0 {
-				log.Printf("\n[Usage Stats] Tools: %+v", chunk.Usage.XTools)
 			}
 		}
 
@@ -361,22 +509,27 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 
 		return &spec.Response{
 			Message: spec.Message{
-				Role:    spec.Role(role),
-				Content: fullContent.String(),
+				Role:      spec.Role(role),
+				Content:   fullContent.String(),
+				ToolCalls: toolCalls.ToolCalls(),
 			},
+			Usage:     usage,
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
 		}, nil
 	}
 
 	// ==================== 非流式处理分支 ====================
-	rawBody, err := m.client.requester.Post(ctx, m.client.config.APIURL, headers, requestBody)
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 	if err != nil {
-		return nil, err
+		return config.DryRunResult(err)
 	}
 
 	var apiResp struct {
 		Choices []struct {
 			Message spec.Message `json:"message"`
 		} `json:"choices"`
+		Usage spec.Usage `json:"usage"`
 	}
 	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
 		return nil, fmt.Errorf("dashscope: failed to unmarshal response: %w", err)
@@ -390,6 +543,9 @@ func (m *modelImpl) handleChat(ctx context.Context, messages []spec.Message, con
 	return &spec.Response{
 		Message:     responseMessage,
 		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
 	}, nil
 }
 
@@ -514,7 +670,7 @@ func parseRetryAfter(header http.Header) time.Duration {
 	return 0
 }
 
-// Embed 实现了 spec.Embedder 接口
+// Embed 实现了 spec.Embedded 接口
 // input 可以是 string (单条文本) 或 []string (多条文本批量向量化)
 func (m *modelImpl) Embed(ctx context.Context, input any) (*spec.EmbeddingResponse, error) {
 	// 1. 构建请求体
@@ -528,15 +684,8 @@ func (m *modelImpl) Embed(ctx context.Context, input any) (*spec.EmbeddingRespon
 	headers.Set("Content-Type", "application/json")
 	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
 
-	// 3. 动态解析 Embed 端点 URL
-	// 如果用户配置的是默认的 Chat 完成端点，自动替换为 Embedding 端点
-	embedURL := "https://dashscope.aliyuncs.com/compatible-mode/v1/embeddings"
-	if strings.HasSuffix(m.client.config.APIURL, "/chat/completions") {
-		embedURL = strings.Replace(m.client.config.APIURL, "/chat/completions", "/embeddings", 1)
-	} else if m.client.config.APIURL != "" && !strings.Contains(m.client.config.APIURL, "chat/completions") {
-		// 如果用户配置了自定义的代理/网关域名，且不是以 chat/completions 结尾，尝试直接使用该自定义域名的基础路径
-		// 这里的逻辑可以根据你的具体架构需求微调，通常直接替换后缀是最稳妥的
-	}
+	// 3. 解析 Embed 端点 URL
+	embedURL := m.client.embeddingURL()
 
 	// 4. 发起 HTTP POST 请求
 	rawBody, err := m.client.requester.Post(ctx, embedURL, headers, requestBody)
@@ -0,0 +1,75 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// maxEmbedBatch 是 text-embedding-v3 单次请求允许的最大文本条数
+const maxEmbedBatch = 10
+
+// embedderImpl 实现了 spec.Embedder
+type embedderImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// Embedder 实现了 spec.EmbedderProvider 接口，返回指定模型（如 text-embedding-v3）的 Embedder
+func (c *clientImpl) Embedder(model string) spec.Embedder {
+	return &embedderImpl{client: c, name: model}
+}
+
+// embeddingURL 返回向量化端点地址。
+// 如果用户配置的是 Chat 完成端点，自动替换为 Embedding 端点，否则使用默认的兼容模式端点。
+func (c *clientImpl) embeddingURL() string {
+	if strings.HasSuffix(c.config.APIURL, "/chat/completions") {
+		return strings.Replace(c.config.APIURL, "/chat/completions", "/embeddings", 1)
+	}
+	return "https://dashscope.aliyuncs.com/compatible-mode/v1/embeddings"
+}
+
+// Embed 实现了 spec.Embedder 接口，超过 10 条的输入会自动分批
+func (e *embedderImpl) Embed(ctx context.Context, texts []string, opts ...spec.EmbedOption) ([][]float32, error) {
+	config := spec.NewEmbedConfig(opts...)
+	batchSize := maxEmbedBatch
+	if config.BatchSize > 0 && config.BatchSize < batchSize {
+		batchSize = config.BatchSize
+	}
+	return spec.EmbedBatches(ctx, texts, batchSize, func(ctx context.Context, batch []string) ([][]float32, error) {
+		return e.embed(ctx, batch, config)
+	})
+}
+
+// embed 发送一批文本的向量化请求
+func (e *embedderImpl) embed(ctx context.Context, texts []string, config *spec.EmbedConfig) ([][]float32, error) {
+	requestBody := make(map[string]any)
+	for k, v := range config.Parameters {
+		requestBody[k] = v
+	}
+	requestBody["model"] = e.name
+	requestBody["input"] = texts
+	requestBody["encoding_format"] = "float"
+	if config.Dimensions > 0 {
+		requestBody["dimensions"] = config.Dimensions
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+e.client.config.APIKey)
+
+	rawBody, err := e.client.requester.Post(ctx, e.client.embeddingURL(), headers, requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope embedding request failed: %w", err)
+	}
+
+	var embedResp spec.EmbeddingResponse
+	if err := json.Unmarshal(rawBody, &embedResp); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse embedding response: %w", err)
+	}
+	return embedResp.Vectors(), nil
+}
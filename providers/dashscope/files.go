@@ -0,0 +1,22 @@
+package dashscope
+
+import (
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// filesURL 返回兼容模式的 /files 端点，自定义的 Chat 端点会被替换为同一前缀下的 /files
+func (c *clientImpl) filesURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/files"
+	}
+	return "https://dashscope.aliyuncs.com/compatible-mode/v1/files"
+}
+
+// Files 实现了 spec.FileStoreProvider 接口。
+// 用途为 file-extract 的文件可在 qwen-long 的对话中通过 "fileid://<ID>" 系统消息引用，batch 用于批量推理。
+func (c *clientImpl) Files() spec.FileStore {
+	return requester.NewOpenAIFiles(c.requester, c.filesURL(), c.config.APIKey)
+}
@@ -0,0 +1,190 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// fineTunerImpl 实现了 spec.FineTuner
+type fineTunerImpl struct {
+	client *clientImpl
+}
+
+// FineTuner 实现了 spec.FineTunerProvider 接口
+func (c *clientImpl) FineTuner() spec.FineTuner {
+	return &fineTunerImpl{client: c}
+}
+
+// dashscopeFineTuneStatus 将 DashScope 的任务状态映射为统一状态
+var dashscopeFineTuneStatus = map[string]spec.FineTuneStatus{
+	"PENDING":   spec.FineTuneQueued,
+	"QUEUING":   spec.FineTuneQueued,
+	"RUNNING":   spec.FineTuneRunning,
+	"SUCCEEDED": spec.FineTuneSucceeded,
+	"FAILED":    spec.FineTuneFailed,
+	"CANCELED":  spec.FineTuneCancelled,
+}
+
+// dashscopeTimeZone 是 DashScope 返回的 "2006-01-02 15:04:05" 格式时间所在的时区（北京时间）
+var dashscopeTimeZone = time.FixedZone("CST", 8*60*60)
+
+// dashscopeFineTuneJob 是 /fine-tunes 接口返回的任务对象，只列出用到的字段
+type dashscopeFineTuneJob struct {
+	JobID           string   `json:"job_id"`
+	Model           string   `json:"model"`
+	BaseModel       string   `json:"base_model"`
+	FinetunedOutput string   `json:"finetuned_output"`
+	Status          string   `json:"status"`
+	TrainingFileIDs []string `json:"training_file_ids"`
+	Usage           int      `json:"usage"`
+	Message         string   `json:"message"`
+	CreateTime      string   `json:"create_time"`
+	EndTime         string   `json:"end_time"`
+}
+
+// fineTunesURL 返回微调任务接口地址
+func (f *fineTunerImpl) fineTunesURL() string {
+	return f.client.apiBaseURL() + "/fine-tunes"
+}
+
+// headers 返回微调接口的请求头
+func (f *fineTunerImpl) headers() http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+f.client.config.APIKey)
+	return headers
+}
+
+// CreateJob 实现了 spec.FineTuner 接口
+func (f *fineTunerImpl) CreateJob(ctx context.Context, req spec.FineTuneRequest) (*spec.FineTuneJob, error) {
+	hyperParameters := make(map[string]any)
+	if req.Epochs > 0 {
+		hyperParameters["n_epochs"] = req.Epochs
+	}
+	if req.BatchSize > 0 {
+		hyperParameters["batch_size"] = req.BatchSize
+	}
+	if req.LearningRate > 0 {
+		hyperParameters["learning_rate"] = strconv.FormatFloat(req.LearningRate, 'g', -1, 64)
+	}
+
+	requestBody := map[string]any{
+		"model":             req.Model,
+		"training_file_ids": []string{req.TrainingFile},
+	}
+	if req.ValidationFile != "" {
+		requestBody["validation_file_ids"] = []string{req.ValidationFile}
+	}
+	if len(hyperParameters) > 0 {
+		requestBody["hyper_parameters"] = hyperParameters
+	}
+	for k, v := range req.Parameters {
+		requestBody[k] = v
+	}
+
+	rawBody, err := f.client.requester.Post(ctx, f.fineTunesURL(), f.headers(), requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope fine-tune request failed: %w", err)
+	}
+	return parseFineTuneJob(rawBody)
+}
+
+// GetJob 实现了 spec.FineTuner 接口
+func (f *fineTunerImpl) GetJob(ctx context.Context, id string) (*spec.FineTuneJob, error) {
+	rawBody, err := f.client.requester.Get(ctx, f.fineTunesURL()+"/"+url.PathEscape(id), f.headers())
+	if err != nil {
+		return nil, fmt.Errorf("dashscope failed to query fine-tune job %s: %w", id, err)
+	}
+	return parseFineTuneJob(rawBody)
+}
+
+// ListJobs 实现了 spec.FineTuner 接口，自动翻页直到取回全部任务
+func (f *fineTunerImpl) ListJobs(ctx context.Context) ([]spec.FineTuneJob, error) {
+	var jobs []spec.FineTuneJob
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page_no", strconv.Itoa(page))
+		query.Set("page_size", "100")
+		rawBody, err := f.client.requester.Get(ctx, f.fineTunesURL()+"?"+query.Encode(), f.headers())
+		if err != nil {
+			return nil, fmt.Errorf("dashscope failed to list fine-tune jobs: %w", err)
+		}
+
+		var listResp struct {
+			Output struct {
+				Jobs  []json.RawMessage `json:"jobs"`
+				Total int               `json:"total"`
+			} `json:"output"`
+		}
+		if err := json.Unmarshal(rawBody, &listResp); err != nil {
+			return nil, fmt.Errorf("dashscope failed to parse fine-tune job list: %w", err)
+		}
+		for _, item := range listResp.Output.Jobs {
+			job, err := convertFineTuneJob(item)
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, *job)
+		}
+		if len(listResp.Output.Jobs) == 0 || len(jobs) >= listResp.Output.Total {
+			return jobs, nil
+		}
+	}
+}
+
+// CancelJob 实现了 spec.FineTuner 接口，取消后返回任务的最新状态
+func (f *fineTunerImpl) CancelJob(ctx context.Context, id string) (*spec.FineTuneJob, error) {
+	_, err := f.client.requester.Post(ctx, f.fineTunesURL()+"/"+url.PathEscape(id)+"/cancel", f.headers(), map[string]any{})
+	if err != nil {
+		return nil, fmt.Errorf("dashscope failed to cancel fine-tune job %s: %w", id, err)
+	}
+	return f.GetJob(ctx, id)
+}
+
+// parseFineTuneJob 解析 {"request_id": "...", "output": {...}} 格式的响应
+func parseFineTuneJob(raw []byte) (*spec.FineTuneJob, error) {
+	var resp struct {
+		Output json.RawMessage `json:"output"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil || len(resp.Output) == 0 {
+		return nil, fmt.Errorf("dashscope failed to parse fine-tune job: %s", string(raw))
+	}
+	return convertFineTuneJob(resp.Output)
+}
+
+// convertFineTuneJob 将任务对象转换为 spec.FineTuneJob
+func convertFineTuneJob(raw json.RawMessage) (*spec.FineTuneJob, error) {
+	var j dashscopeFineTuneJob
+	if err := json.Unmarshal(raw, &j); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse fine-tune job: %w", err)
+	}
+
+	job := &spec.FineTuneJob{
+		ID:             j.JobID,
+		Model:          j.BaseModel,
+		FineTunedModel: j.FinetunedOutput,
+		Status:         dashscopeFineTuneStatus[j.Status],
+		RawStatus:      j.Status,
+		TrainedTokens:  j.Usage,
+		Raw:            raw,
+	}
+	if job.Model == "" {
+		job.Model = j.Model
+	}
+	if len(j.TrainingFileIDs) > 0 {
+		job.TrainingFile = j.TrainingFileIDs[0]
+	}
+	if job.Status == spec.FineTuneFailed {
+		job.Error = j.Message
+	}
+	job.CreatedAt, _ = time.ParseInLocation(time.DateTime, j.CreateTime, dashscopeTimeZone)
+	job.FinishedAt, _ = time.ParseInLocation(time.DateTime, j.EndTime, dashscopeTimeZone)
+	return job, nil
+}
@@ -0,0 +1,35 @@
+package dashscope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// modelsURL 返回兼容模式的 /models 端点，自定义的 Chat 端点会被替换为同一前缀下的 /models
+func (c *clientImpl) modelsURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/models"
+	}
+	return "https://dashscope.aliyuncs.com/compatible-mode/v1/models"
+}
+
+// ListModels 实现了 spec.ModelLister 接口。
+// /models 只返回模型 ID 和所属组织，能力根据模型名称推断。
+func (c *clientImpl) ListModels(ctx context.Context) ([]spec.ModelInfo, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	rawBody, err := c.requester.Get(ctx, c.modelsURL(), headers)
+	if err != nil {
+		return nil, err
+	}
+	models, err := spec.ParseModelList(rawBody)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope: %w", err)
+	}
+	return models, nil
+}
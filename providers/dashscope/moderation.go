@@ -0,0 +1,68 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// moderationPrompt 要求模型以 JSON 输出审核结论
+const moderationPrompt = `你是内容安全审核员。判断用户提供的文本是否包含以下类别的违规内容：
+sexual（色情）、violence（暴力）、hate（仇恨歧视）、self-harm（自残）、illicit（违法犯罪）、politics（政治敏感）、harassment（骚扰）。
+只输出 JSON，不要输出其他内容，格式为：{"flagged": true/false, "categories": ["命中的类别"]}`
+
+// moderatorImpl 实现了 spec.Moderator。
+// DashScope 没有独立的审核接口：文本先经过平台自带的绿网检测（命中时接口返回 DataInspectionFailed），
+// 通过后再由指定的通义千问模型按类别判断。
+type moderatorImpl struct {
+	model *modelImpl
+}
+
+// Moderator 实现了 spec.ModeratorProvider 接口，model 为用于审核的对话模型（如 qwen-plus）
+func (c *clientImpl) Moderator(model string) spec.Moderator {
+	return &moderatorImpl{model: &modelImpl{client: c, name: model}}
+}
+
+// Moderate 实现了 spec.Moderator 接口，逐条审核
+func (m *moderatorImpl) Moderate(ctx context.Context, inputs []string) ([]spec.ModerationResult, error) {
+	results := make([]spec.ModerationResult, len(inputs))
+	for i, input := range inputs {
+		r, err := m.moderate(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// moderate 审核单条文本
+func (m *moderatorImpl) moderate(ctx context.Context, input string) (spec.ModerationResult, error) {
+	resp, err := m.model.Chat(ctx, []spec.Message{
+		spec.NewSystemMessage(moderationPrompt),
+		spec.NewUserMessage(input),
+	}, spec.WithThinking(false), spec.WithTemperature(0))
+	if err != nil {
+		// 绿网拦截说明文本本身违规
+		if errors.Is(err, spec.ErrContentFiltered) {
+			return spec.ModerationResult{Flagged: true, Categories: []string{"data_inspection"}}, nil
+		}
+		return spec.ModerationResult{}, fmt.Errorf("dashscope moderation failed: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.Trim(content, "` \n")
+	var verdict struct {
+		Flagged    bool     `json:"flagged"`
+		Categories []string `json:"categories"`
+	}
+	if err := json.Unmarshal([]byte(content), &verdict); err != nil {
+		return spec.ModerationResult{}, fmt.Errorf("dashscope moderation returned invalid verdict %q: %w", resp.Message.Content, err)
+	}
+	return spec.ModerationResult{Flagged: verdict.Flagged, Categories: verdict.Categories}, nil
+}
@@ -0,0 +1,269 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// nativeOutput 是原生协议 output 字段的结构，流式和非流式相同
+type nativeOutput struct {
+	Output struct {
+		Choices []struct {
+			FinishReason string `json:"finish_reason"`
+			Message      struct {
+				Role             string               `json:"role"`
+				Content          json.RawMessage      `json:"content"`
+				ReasoningContent string               `json:"reasoning_content"`
+				ToolCalls        []spec.ToolCallDelta `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+	} `json:"output"`
+	Usage *spec.Usage `json:"usage"`
+}
+
+// message 返回第一个 choice 的角色、正文和思考过程
+func (o *nativeOutput) message() (role, content, reasoning string) {
+	if len(o.Output.Choices) == 0 {
+		return "", "", ""
+	}
+	msg := o.Output.Choices[0].Message
+	return msg.Role, nativeText(msg.Content), msg.ReasoningContent
+}
+
+// toolCalls 返回第一个 choice 中的函数调用，流式时为增量片段
+func (o *nativeOutput) toolCalls() []spec.ToolCallDelta {
+	if len(o.Output.Choices) == 0 {
+		return nil
+	}
+	return o.Output.Choices[0].Message.ToolCalls
+}
+
+// nativeText 解析消息内容：text-generation 返回字符串，multimodal-generation 返回 [{"text": "..."}]
+func nativeText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range parts {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+// hasImages 判断消息中是否包含图片，包含时需要使用 multimodal-generation 接口
+func hasImages(messages []spec.Message) bool {
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if part.ImageURL != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nativeMessages 将消息转换为原生协议的格式。
+// 多模态接口的 content 为 [{"image": "..."}, {"text": "..."}]，文本接口的 content 为字符串。
+func nativeMessages(messages []spec.Message, multimodal bool) []map[string]any {
+	result := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		if !multimodal || msg.Role == spec.RoleTool || len(msg.ToolCalls) > 0 {
+			m := map[string]any{"role": msg.Role, "content": msg.PlainText()}
+			if len(msg.ToolCalls) > 0 {
+				m["tool_calls"] = msg.ToolCalls
+			}
+			if msg.ToolCallID != "" {
+				m["tool_call_id"] = msg.ToolCallID
+			}
+			result = append(result, m)
+			continue
+		}
+		var content []map[string]any
+		if len(msg.Parts) == 0 {
+			content = append(content, map[string]any{"text": msg.Content})
+		}
+		for _, part := range msg.Parts {
+			if part.ImageURL != nil {
+				content = append(content, map[string]any{"image": part.ImageURL.URL})
+			} else {
+				content = append(content, map[string]any{"text": part.Text})
+			}
+		}
+		result = append(result, map[string]any{"role": msg.Role, "content": content})
+	}
+	return result
+}
+
+// handleNativeChat 通过原生的 text-generation / multimodal-generation 接口完成对话。
+// 除 plugins 外的 Parameters 都放入 parameters 字段；plugins 会以 X-DashScope-Plugin 请求头发送，
+// 例如 {"code_interpreter": {}}。
+func (m *modelImpl) handleNativeChat(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	multimodal := hasImages(messages)
+	endpoint := m.client.apiBaseURL() + "/services/aigc/text-generation/generation"
+	if multimodal {
+		endpoint = m.client.apiBaseURL() + "/services/aigc/multimodal-generation/generation"
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+
+	// 1. 构建 parameters
+	parameters := map[string]any{"result_format": "message"}
+	for k, v := range config.Parameters {
+		if k == "plugins" {
+			plugins, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("dashscope: invalid plugins parameter: %w", err)
+			}
+			headers.Set("X-DashScope-Plugin", string(plugins))
+			continue
+		}
+		parameters[k] = v
+	}
+	if config.Thinking != nil {
+		parameters["enable_thinking"] = *config.Thinking
+	}
+	if config.Temperature != nil {
+		parameters["temperature"] = *config.Temperature
+	}
+	if config.TopP != nil {
+		parameters["top_p"] = *config.TopP
+	}
+	if config.MaxTokens != nil {
+		parameters["max_tokens"] = *config.MaxTokens
+	}
+	if config.Translation != nil && isTranslationModel(m.name) {
+		parameters["translation_options"] = config.Translation
+	}
+	config.ApplyTools(parameters)
+
+	requestBody := map[string]any{
+		"model":      m.name,
+		"input":      map[string]any{"messages": nativeMessages(messages, multimodal)},
+		"parameters": parameters,
+	}
+
+	// ==================== 流式处理分支 ====================
+	if config.Streaming {
+		parameters["incremental_output"] = true
+		headers.Set("X-DashScope-SSE", "enable")
+
+		resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), endpoint, headers, requestBody)
+		if err != nil {
+			return config.DryRunResult(err)
+		}
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
+		var fullContent, reasoningContent strings.Builder
+		var usage spec.Usage
+		var toolCalls spec.ToolCallAccumulator
+		role := "assistant"
+
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
+		for scanner.Scan() {
+			dataStr, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			dataStr = strings.TrimSpace(dataStr)
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
+
+			var chunk nativeOutput
+			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
+				continue
+			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
+			r, content, reasoning := chunk.message()
+			if r != "" {
+				role = r
+			}
+			reasoningContent.WriteString(reasoning)
+			toolCalls.Add(chunk.toolCalls())
+			if content != "" {
+				fullContent.WriteString(content)
+				if err := config.EmitStreamChunk(ctx, content); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("dashscope: stream scan error: %w", err)
+		}
+
+		return &spec.Response{
+			Message: spec.Message{
+				Role:             spec.Role(role),
+				Content:          fullContent.String(),
+				ReasoningContent: reasoningContent.String(),
+				ToolCalls:        toolCalls.ToolCalls(),
+			},
+			Usage:     usage,
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
+		}, nil
+	}
+
+	// ==================== 非流式处理分支 ====================
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), endpoint, headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+
+	var apiResp nativeOutput
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("dashscope: failed to unmarshal response: %w", err)
+	}
+	role, content, reasoning := apiResp.message()
+	var toolCalls []spec.ToolCall
+	for _, d := range apiResp.toolCalls() {
+		toolCalls = append(toolCalls, spec.ToolCall{
+			ID:       d.ID,
+			Type:     d.Type,
+			Function: spec.FunctionCall{Name: d.Function.Name, Arguments: d.Function.Arguments},
+		})
+	}
+	response := &spec.Response{
+		Message: spec.Message{
+			Role:             spec.Role(role),
+			Content:          content,
+			ReasoningContent: reasoning,
+			ToolCalls:        toolCalls,
+		},
+		RawResponse: rawBody,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}
+	if apiResp.Usage != nil {
+		response.Usage = *apiResp.Usage
+	}
+	return response, nil
+}
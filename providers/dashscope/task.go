@@ -0,0 +1,262 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultTaskPollInterval 是异步任务的默认轮询间隔
+const defaultTaskPollInterval = time.Second
+
+// taskCancelTimeout 是轮询被取消后撤销远端任务的超时时间
+const taskCancelTimeout = 5 * time.Second
+
+// AsyncTasks 是 DashScope 异步任务（万相图像/视频生成、录音文件识别等）的通用提交与轮询接口，
+// 由 dashscope.NewClient 返回的客户端实现，可通过类型断言获取：
+//
+//	tasks := c.(dashscope.AsyncTasks)
+//	task, err := tasks.RunTask(ctx, "/services/aigc/video-generation/video-synthesis", body, 5*time.Second)
+type AsyncTasks interface {
+	// SubmitTask 以 X-DashScope-Async 方式提交任务，path 为 /api/v1 之后的路径，返回任务 ID
+	SubmitTask(ctx context.Context, path string, body any) (string, error)
+	// WaitTask 按 interval 轮询直到任务结束，interval <= 0 时每秒查询一次。
+	// ctx 被取消时会尝试撤销仍在排队的任务；任务失败时同时返回任务状态和 *TaskError
+	WaitTask(ctx context.Context, taskID string, interval time.Duration) (*Task, error)
+	// RunTask 提交任务并等待结束
+	RunTask(ctx context.Context, path string, body any, interval time.Duration) (*Task, error)
+	// CancelTask 撤销任务，只有排队中（PENDING）的任务可以撤销
+	CancelTask(ctx context.Context, taskID string) error
+}
+
+// Task 是异步任务的状态
+type Task struct {
+	// ID 任务 ID
+	ID string
+	// Status 任务状态：PENDING、RUNNING、SUCCEEDED、FAILED、CANCELED、UNKNOWN
+	Status string
+	// Code 和 Message 为任务失败的原因
+	Code    string
+	Message string
+	// Output 完整的 output 字段，各服务的结果格式不同，由调用方解析
+	Output json.RawMessage
+	// Usage 任务的用量，格式因服务而异
+	Usage json.RawMessage
+	// Raw 最后一次查询的原始响应
+	Raw []byte
+}
+
+// Done 判断任务是否已经结束
+func (t *Task) Done() bool {
+	switch t.Status {
+	case "SUCCEEDED", "FAILED", "CANCELED", "UNKNOWN":
+		return true
+	}
+	return false
+}
+
+// TaskError 表示异步任务没有成功完成
+type TaskError struct {
+	TaskID  string
+	Status  string
+	Code    string
+	Message string
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("dashscope task %s %s (code: %s): %s", e.TaskID, strings.ToLower(e.Status), e.Code, e.Message)
+}
+
+// taskHeaders 返回任务接口的请求头
+func (c *clientImpl) taskHeaders() http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+	return headers
+}
+
+// SubmitTask 实现了 AsyncTasks 接口
+func (c *clientImpl) SubmitTask(ctx context.Context, path string, body any) (string, error) {
+	headers := c.taskHeaders()
+	headers.Set("X-DashScope-Async", "enable")
+
+	rawBody, err := c.requester.Post(ctx, c.apiBaseURL()+path, headers, body)
+	if err != nil {
+		return "", fmt.Errorf("dashscope task request failed: %w", err)
+	}
+	var submitResp struct {
+		Output struct {
+			TaskID string `json:"task_id"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(rawBody, &submitResp); err != nil || submitResp.Output.TaskID == "" {
+		return "", fmt.Errorf("dashscope failed to submit task: %s", string(rawBody))
+	}
+	return submitResp.Output.TaskID, nil
+}
+
+// GetTask 查询一次任务状态
+func (c *clientImpl) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	rawBody, err := c.requester.Get(ctx, c.apiBaseURL()+"/tasks/"+url.PathEscape(taskID), c.taskHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("dashscope failed to query task %s: %w", taskID, err)
+	}
+	var taskResp struct {
+		Output json.RawMessage `json:"output"`
+		Usage  json.RawMessage `json:"usage"`
+	}
+	if err := json.Unmarshal(rawBody, &taskResp); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse task %s: %w", taskID, err)
+	}
+	var status struct {
+		TaskStatus string `json:"task_status"`
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+	}
+	if err := json.Unmarshal(taskResp.Output, &status); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse task %s: %w", taskID, err)
+	}
+	return &Task{
+		ID:      taskID,
+		Status:  status.TaskStatus,
+		Code:    status.Code,
+		Message: status.Message,
+		Output:  taskResp.Output,
+		Usage:   taskResp.Usage,
+		Raw:     rawBody,
+	}, nil
+}
+
+// WaitTask 实现了 AsyncTasks 接口
+func (c *clientImpl) WaitTask(ctx context.Context, taskID string, interval time.Duration) (*Task, error) {
+	return c.waitTask(ctx, taskID, interval, nil)
+}
+
+// waitTask 轮询任务直到结束，每次查询到状态后调用 onPoll（可以为 nil）
+func (c *clientImpl) waitTask(ctx context.Context, taskID string, interval time.Duration, onPoll func(*Task)) (*Task, error) {
+	if interval <= 0 {
+		interval = defaultTaskPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		task, err := c.GetTask(ctx, taskID)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.abandonTask(ctx, taskID)
+			}
+			return nil, err
+		}
+		if onPoll != nil {
+			onPoll(task)
+		}
+		if task.Done() {
+			if task.Status != "SUCCEEDED" {
+				return task, &TaskError{TaskID: taskID, Status: task.Status, Code: task.Code, Message: task.Message}
+			}
+			return task, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			c.abandonTask(ctx, taskID)
+			return task, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// abandonTask 在调用方放弃等待后尽力撤销远端任务，避免排队中的任务继续产生费用
+func (c *clientImpl) abandonTask(ctx context.Context, taskID string) {
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), taskCancelTimeout)
+	defer cancel()
+	if err := c.CancelTask(cancelCtx, taskID); err != nil {
+		c.requester.Logger.Debug("dashscope task not cancelled", "task_id", taskID, "error", err)
+	}
+}
+
+// RunTask 实现了 AsyncTasks 接口
+func (c *clientImpl) RunTask(ctx context.Context, path string, body any, interval time.Duration) (*Task, error) {
+	taskID, err := c.SubmitTask(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitTask(ctx, taskID, interval)
+}
+
+// CancelTask 实现了 AsyncTasks 接口
+func (c *clientImpl) CancelTask(ctx context.Context, taskID string) error {
+	_, err := c.requester.Post(ctx, c.apiBaseURL()+"/tasks/"+url.PathEscape(taskID)+"/cancel", c.taskHeaders(), map[string]any{})
+	if err != nil {
+		return fmt.Errorf("dashscope failed to cancel task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// isAsyncImageModel 判断模型是否只支持异步调用的万相文生图（wanx-*、wan2.x-t2i-*）
+func isAsyncImageModel(model string) bool {
+	return strings.HasPrefix(model, "wanx") || strings.HasPrefix(model, "wan2")
+}
+
+// handleImageSynthesis 通过异步任务调用万相文生图，提交任务后轮询直到图片生成完成
+func (m *modelImpl) handleImageSynthesis(ctx context.Context, prompt string, config *spec.RequestConfig) (*spec.Response, error) {
+	input := map[string]any{"prompt": prompt}
+	parameters := map[string]any{"size": "1024*1024", "n": 1}
+	for k, v := range config.Parameters {
+		if k == "negative_prompt" {
+			input[k] = v
+			continue
+		}
+		parameters[k] = v
+	}
+	requestBody := map[string]any{
+		"model":      m.name,
+		"input":      input,
+		"parameters": parameters,
+	}
+
+	task, err := m.client.RunTask(ctx, "/services/aigc/text2image/image-synthesis", requestBody, 0)
+	if err != nil {
+		return config.DryRunResult(fmt.Errorf("dashscope %s generation failed: %w", m.name, err))
+	}
+
+	var out struct {
+		Results []struct {
+			URL     string `json:"url"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(task.Output, &out); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse task %s: %w", task.ID, err)
+	}
+	var images []string
+	for _, r := range out.Results {
+		if r.URL != "" {
+			images = append(images, r.URL)
+		}
+	}
+	if len(images) == 0 {
+		if len(out.Results) > 0 {
+			return nil, fmt.Errorf("dashscope generation failed (code: %s): %s", out.Results[0].Code, out.Results[0].Message)
+		}
+		return nil, fmt.Errorf("no image URL in task %s: %s", task.ID, string(task.Raw))
+	}
+
+	return &spec.Response{
+		Message: spec.Message{
+			Role:    spec.RoleAssistant,
+			Content: images[0],
+		},
+		Images:      images,
+		RawResponse: task.Raw,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}, nil
+}
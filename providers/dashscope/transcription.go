@@ -0,0 +1,141 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// transcriberImpl 实现了 spec.Transcriber
+type transcriberImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// Transcriber 实现了 spec.TranscriberProvider 接口，返回指定模型（如 paraformer-v2）的 Transcriber
+func (c *clientImpl) Transcriber(model string) spec.Transcriber {
+	return &transcriberImpl{client: c, name: model}
+}
+
+// apiBaseURL 返回 DashScope 原生 API 的基础地址
+func (c *clientImpl) apiBaseURL() string {
+	if strings.Contains(c.config.APIURL, "dashscope-intl") {
+		return "https://dashscope-intl.aliyuncs.com/api/v1"
+	}
+	return "https://dashscope.aliyuncs.com/api/v1"
+}
+
+// Transcribe 实现了 spec.Transcriber 接口。
+// paraformer 录音文件识别是异步任务：提交任务后轮询任务状态，成功后下载识别结果。
+// 音频必须是公网可访问的 URL。
+func (t *transcriberImpl) Transcribe(ctx context.Context, audio spec.AudioInput, opts ...spec.TranscribeOption) (*spec.Transcription, error) {
+	config := spec.NewTranscribeConfig(opts...)
+	if audio.URL == "" || strings.HasPrefix(audio.URL, "data:") {
+		return nil, fmt.Errorf("dashscope %s requires a publicly accessible audio URL", t.name)
+	}
+
+	// 1. 提交异步任务
+	parameters := make(map[string]any)
+	for k, v := range config.Parameters {
+		parameters[k] = v
+	}
+	if config.Language != "" {
+		parameters["language_hints"] = []string{config.Language}
+	}
+	requestBody := map[string]any{
+		"model":      t.name,
+		"input":      map[string]any{"file_urls": []string{audio.URL}},
+		"parameters": parameters,
+	}
+
+	taskID, err := t.client.SubmitTask(ctx, "/services/audio/asr/transcription", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope transcription: %w", err)
+	}
+
+	// 2. 轮询任务状态
+	resultURL, err := t.wait(ctx, taskID, config.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 下载识别结果
+	model := &modelImpl{client: t.client, name: t.name}
+	rawResult, err := model.Get(ctx, resultURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope failed to download transcription result: %w", err)
+	}
+	return parseTranscription(rawResult, config.Segments)
+}
+
+// wait 轮询任务直到结束，返回识别结果的下载地址
+func (t *transcriberImpl) wait(ctx context.Context, taskID string, interval time.Duration) (string, error) {
+	task, err := t.client.WaitTask(ctx, taskID, interval)
+	if err != nil {
+		return "", fmt.Errorf("dashscope transcription: %w", err)
+	}
+	var out struct {
+		Results []struct {
+			TranscriptionURL string `json:"transcription_url"`
+			SubtaskStatus    string `json:"subtask_status"`
+			Code             string `json:"code"`
+			Message          string `json:"message"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(task.Output, &out); err != nil {
+		return "", fmt.Errorf("dashscope failed to parse transcription task: %w", err)
+	}
+	if len(out.Results) == 0 {
+		return "", fmt.Errorf("dashscope transcription task %s returned no result", taskID)
+	}
+	r := out.Results[0]
+	if r.SubtaskStatus != "" && r.SubtaskStatus != "SUCCEEDED" {
+		return "", fmt.Errorf("dashscope transcription failed (code: %s): %s", r.Code, r.Message)
+	}
+	return r.TranscriptionURL, nil
+}
+
+// parseTranscription 解析 paraformer 的识别结果文件，segments 为 true 时保留句级时间戳
+func parseTranscription(rawBody []byte, segments bool) (*spec.Transcription, error) {
+	var result struct {
+		Properties struct {
+			OriginalDurationMs int64 `json:"original_duration_in_milliseconds"`
+		} `json:"properties"`
+		Transcripts []struct {
+			Text      string `json:"text"`
+			Sentences []struct {
+				BeginTime int64  `json:"begin_time"`
+				EndTime   int64  `json:"end_time"`
+				Text      string `json:"text"`
+			} `json:"sentences"`
+		} `json:"transcripts"`
+	}
+	if err := json.Unmarshal(rawBody, &result); err != nil {
+		return nil, fmt.Errorf("dashscope failed to parse transcription result: %w", err)
+	}
+
+	t := &spec.Transcription{
+		Duration:    time.Duration(result.Properties.OriginalDurationMs) * time.Millisecond,
+		RawResponse: rawBody,
+	}
+	var texts []string
+	for _, tr := range result.Transcripts {
+		texts = append(texts, tr.Text)
+		if !segments {
+			continue
+		}
+		for _, s := range tr.Sentences {
+			t.Segments = append(t.Segments, spec.TranscriptionSegment{
+				Start: time.Duration(s.BeginTime) * time.Millisecond,
+				End:   time.Duration(s.EndTime) * time.Millisecond,
+				Text:  s.Text,
+			})
+		}
+	}
+	t.Text = strings.Join(texts, "\n")
+	return t, nil
+}
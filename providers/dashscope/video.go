@@ -0,0 +1,110 @@
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// videoImpl 实现了 spec.VideoModel
+type videoImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// VideoModel 实现了 spec.VideoModelProvider 接口，返回指定模型（如 wanx2.1-t2v-turbo、wanx2.1-i2v-plus）的 VideoModel
+func (c *clientImpl) VideoModel(model string) spec.VideoModel {
+	return &videoImpl{client: c, name: model}
+}
+
+// GenerateVideo 实现了 spec.VideoModel 接口。
+// 万相视频生成是异步任务，通常需要数分钟；成功后 Video.URL 为有效期 24 小时的下载地址。
+func (v *videoImpl) GenerateVideo(ctx context.Context, req spec.VideoRequest, opts ...spec.VideoOption) (*spec.Video, error) {
+	config := spec.NewVideoConfig(opts...)
+	if req.Prompt == "" && req.ImageURL == "" {
+		return nil, fmt.Errorf("dashscope %s: video prompt or image is required", v.name)
+	}
+
+	input := map[string]any{}
+	if req.Prompt != "" {
+		input["prompt"] = req.Prompt
+	}
+	if req.ImageURL != "" {
+		resolved, err := resolveImage(req.ImageURL)
+		if err != nil {
+			return nil, fmt.Errorf("dashscope %s: %w", v.name, err)
+		}
+		input["img_url"] = resolved
+	}
+	parameters := map[string]any{}
+	if req.Size != "" {
+		parameters["size"] = strings.ReplaceAll(req.Size, "x", "*")
+	}
+	if req.Duration > 0 {
+		parameters["duration"] = int(req.Duration.Seconds())
+	}
+	for k, val := range config.Parameters {
+		parameters[k] = val
+	}
+	requestBody := map[string]any{
+		"model":      v.name,
+		"input":      input,
+		"parameters": parameters,
+	}
+
+	taskID, err := v.client.SubmitTask(ctx, "/services/aigc/video-generation/video-synthesis", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope %s video generation failed: %w", v.name, err)
+	}
+
+	var video *spec.Video
+	_, err = v.client.waitTask(ctx, taskID, config.PollInterval, func(t *Task) {
+		video = convertVideoTask(t)
+		if config.OnProgress != nil {
+			config.OnProgress(video)
+		}
+	})
+	var taskErr *TaskError
+	if errors.As(err, &taskErr) {
+		return video, fmt.Errorf("dashscope %s video generation failed: %w", v.name, err)
+	}
+	if err != nil {
+		return video, err
+	}
+	if video.URL == "" {
+		return video, fmt.Errorf("dashscope %s: no video URL in task %s", v.name, taskID)
+	}
+	return video, nil
+}
+
+// convertVideoTask 将异步任务状态转换为 spec.Video
+func convertVideoTask(t *Task) *spec.Video {
+	video := &spec.Video{
+		ID:          t.ID,
+		RawStatus:   t.Status,
+		RawResponse: t.Raw,
+	}
+	switch t.Status {
+	case "PENDING":
+		video.Status = spec.VideoQueued
+	case "RUNNING":
+		video.Status = spec.VideoRunning
+	case "SUCCEEDED":
+		video.Status = spec.VideoSucceeded
+		video.Progress = 100
+		var out struct {
+			VideoURL string `json:"video_url"`
+		}
+		if err := json.Unmarshal(t.Output, &out); err == nil {
+			video.URL = out.VideoURL
+		}
+	default:
+		video.Status = spec.VideoFailed
+		video.Error = t.Message
+	}
+	return video
+}
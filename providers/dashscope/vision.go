@@ -0,0 +1,112 @@
+package dashscope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Qwen-VL 对输入图片的限制
+const (
+	// maxImageBytes 单张图片（base64 编码后）的最大字节数
+	maxImageBytes = 10 << 20
+	// minImageSide 图片宽高的最小像素数
+	minImageSide = 10
+	// maxImageAspect 图片长边与短边的最大比例
+	maxImageAspect = 200
+)
+
+// prepareImages 在发送前处理消息中的图片，返回新的消息列表，不修改调用方的消息：
+//   - data URL 校验大小和宽高；
+//   - 本地文件（file:// 或不带协议的路径）返回错误，不会读取；
+//   - http(s)、oss:// 地址原样发送，由服务端下载。
+//
+// 同时设置了 Content 和图片 Parts 的消息会把 Content 作为文本部分发送，避免提问文字被丢弃。
+func prepareImages(messages []spec.Message) ([]spec.Message, error) {
+	if !hasImages(messages) {
+		return messages, nil
+	}
+	result := spec.CloneMessages(messages)
+	for i := range result {
+		msg := &result[i]
+		if len(msg.Parts) == 0 {
+			continue
+		}
+		hasText := false
+		for j := range msg.Parts {
+			part := &msg.Parts[j]
+			if part.ImageURL == nil {
+				hasText = hasText || part.Type == "text"
+				continue
+			}
+			resolved, err := resolveImage(part.ImageURL.URL)
+			if err != nil {
+				return nil, fmt.Errorf("dashscope: message %d: %w", i, err)
+			}
+			part.ImageURL.URL = resolved
+		}
+		if !hasText && msg.Content != "" {
+			msg.Parts = append(msg.Parts, spec.NewTextPart(msg.Content))
+		}
+	}
+	return result, nil
+}
+
+// resolveImage 返回可直接发送给服务端的图片地址
+func resolveImage(ref string) (string, error) {
+	switch {
+	case ref == "":
+		return "", fmt.Errorf("empty image url")
+
+	case strings.HasPrefix(ref, "data:"):
+		meta, payload, ok := strings.Cut(strings.TrimPrefix(ref, "data:"), ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return "", fmt.Errorf("invalid image data url, expected data:<mime>;base64,<data>")
+		}
+		if len(payload) > maxImageBytes {
+			return "", fmt.Errorf("image is %d bytes after base64 encoding, exceeds the %d bytes limit", len(payload), maxImageBytes)
+		}
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return "", fmt.Errorf("invalid base64 image data: %w", err)
+		}
+		if err := validateImage(data); err != nil {
+			return "", err
+		}
+		return ref, nil
+
+	case strings.HasPrefix(ref, "file://") || !strings.Contains(ref, "://"):
+		// 不读取本地文件，避免消息内容（可能来自外部输入）读取到任意文件，本地图片请使用 client.SendImageFile
+		return "", fmt.Errorf("local image %q is not supported, read the file with client.SendImageFile or spec.NewImageFilePart", ref)
+
+	default:
+		return ref, nil
+	}
+}
+
+// validateImage 校验图片宽高，标准库无法解码的格式（如 webp、bmp）交给服务端校验
+func validateImage(data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid image: %w", err)
+	}
+	w, h := cfg.Width, cfg.Height
+	if w < minImageSide || h < minImageSide {
+		return fmt.Errorf("image is %dx%d, width and height must be at least %d pixels", w, h, minImageSide)
+	}
+	if max(w, h) > maxImageAspect*min(w, h) {
+		return fmt.Errorf("image is %dx%d, aspect ratio must not exceed %d:1", w, h, maxImageAspect)
+	}
+	return nil
+}
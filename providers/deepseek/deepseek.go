@@ -1,10 +1,11 @@
 package deepseek
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
 	"strings"
 
@@ -39,11 +40,14 @@ func NewClient(opts ...spec.ClientOption) (spec.Client, error) {
 		return nil, fmt.Errorf("deepseek provider: API key is required")
 	}
 
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("deepseek provider: %w", err)
+	}
+
 	return &clientImpl{
-		requester: &requester.Requester{
-			HTTPClient: config.HTTPClient,
-		},
-		config: *config,
+		requester: req.WithErrorParser(requester.ParseOpenAIError),
+		config:    *config,
 	}, nil
 }
 
@@ -52,12 +56,29 @@ func (c *clientImpl) Model(name string) spec.Model {
 	return &modelImpl{client: c, name: name}
 }
 
+// Close 实现了 spec.Client 接口的方法，取消进行中的请求并释放空闲连接
+func (c *clientImpl) Close() error {
+	return c.requester.Close()
+}
+
+// CloseIdleConnections 释放空闲的 HTTP 连接，不影响进行中的请求
+func (c *clientImpl) CloseIdleConnections() {
+	c.requester.CloseIdleConnections()
+}
+
+// ChatSeq 实现了 spec.Model 接口的方法，以迭代器形式返回流式事件
+func (m *modelImpl) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
+
 // Chat 执行一次对话调用，完全适配 DeepSeek V4 API 规范。
 func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
 	config := spec.NewRequestConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
+	ctx = config.BindContext(ctx)
+	messages = config.TranslationMessages(messages)
 
 	// 1. 构建请求体，从 Parameters 初始化以支持透传
 	requestBody := make(map[string]any)
@@ -81,8 +102,10 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	if config.TopP != nil {
 		requestBody["top_p"] = *config.TopP
 	}
+	config.ApplyTools(requestBody)
 	if config.Streaming {
 		requestBody["stream"] = true
+		requestBody["stream_options"] = map[string]bool{"include_usage": true}
 	}
 
 	// 4. 【关键适配】根据 Thinking 选项构造 reasoning_effort 参数
@@ -113,17 +136,26 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 
 	// ==================== 流式处理分支 ====================
 	if config.Streaming {
-		resp, err := m.client.requester.PostStream(ctx, m.client.config.APIURL, headers, requestBody)
+		resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 		if err != nil {
-			return nil, err
+			return config.DryRunResult(err)
 		}
 		defer resp.Body.Close()
 
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
 		var fullContent strings.Builder
+		var usage spec.Usage
 		var reasoningContent strings.Builder
+		var toolCalls spec.ToolCallAccumulator
 		role := "assistant"
 
-		scanner := bufio.NewScanner(resp.Body)
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
 		for scanner.Scan() {
 			line := scanner.Text()
 			if line == "" {
@@ -136,20 +168,28 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 			if dataStr == "[DONE]" {
 				break
 			}
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
 
 			var chunk struct {
 				Choices []struct {
 					Delta struct {
-						Content          string `json:"content"`
-						Role             string `json:"role"`
-						ReasoningContent string `json:"reasoning_content"`
+						Content          string               `json:"content"`
+						Role             string               `json:"role"`
+						ReasoningContent string               `json:"reasoning_content"`
+						ToolCalls        []spec.ToolCallDelta `json:"tool_calls"`
 					} `json:"delta"`
 				} `json:"choices"`
+				Usage *spec.Usage `json:"usage"`
 			}
 
 			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
 				continue
 			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
 
 			if len(chunk.Choices) > 0 {
 				delta := chunk.Choices[0].Delta
@@ -159,12 +199,11 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 				if delta.ReasoningContent != "" {
 					reasoningContent.WriteString(delta.ReasoningContent)
 				}
+				toolCalls.Add(delta.ToolCalls)
 				if delta.Content != "" {
 					fullContent.WriteString(delta.Content)
-					if config.StreamCallback != nil {
-						if err := config.StreamCallback(ctx, delta.Content); err != nil {
-							return nil, err
-						}
+					if err := config.EmitStreamChunk(ctx, delta.Content); err != nil {
+						return nil, err
 					}
 				}
 			}
@@ -179,24 +218,30 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 				Role:             spec.Role(role),
 				Content:          fullContent.String(),
 				ReasoningContent: reasoningContent.String(),
+				ToolCalls:        toolCalls.ToolCalls(),
 			},
+			Usage:     usage,
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
 		}, nil
 	}
 
 	// ==================== 非流式处理分支 ====================
-	rawBody, err := m.client.requester.Post(ctx, m.client.config.APIURL, headers, requestBody)
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 	if err != nil {
-		return nil, err
+		return config.DryRunResult(err)
 	}
 
 	var apiResp struct {
 		Choices []struct {
 			Message struct {
-				Role             string `json:"role"`
-				Content          string `json:"content"`
-				ReasoningContent string `json:"reasoning_content"`
+				Role             string          `json:"role"`
+				Content          string          `json:"content"`
+				ReasoningContent string          `json:"reasoning_content"`
+				ToolCalls        []spec.ToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage spec.Usage `json:"usage"`
 	}
 
 	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
@@ -210,11 +255,15 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 			Role:             spec.Role(msg.Role),
 			Content:          msg.Content,
 			ReasoningContent: msg.ReasoningContent,
+			ToolCalls:        msg.ToolCalls,
 		}
 	}
 
 	return &spec.Response{
 		Message:     responseMessage,
 		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
 	}, nil
 }
@@ -0,0 +1,161 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// completerImpl 实现了 spec.Completer
+type completerImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// Completer 实现了 spec.CompleterProvider 接口
+func (c *clientImpl) Completer(model string) spec.Completer {
+	return &completerImpl{client: c, name: model}
+}
+
+// completionsURL 返回 /completions 端点；APIURL 本身就是补全端点时直接使用
+func (c *clientImpl) completionsURL() string {
+	if strings.HasSuffix(c.config.APIURL, "/completions") && !strings.HasSuffix(c.config.APIURL, "/chat/completions") {
+		return c.config.APIURL
+	}
+	return c.baseURL() + "/completions"
+}
+
+// completionChunk 是补全接口的响应结构，流式和非流式相同
+type completionChunk struct {
+	Choices []struct {
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *spec.Usage `json:"usage"`
+}
+
+// text 返回第一个 choice 的文本
+func (c *completionChunk) text() string {
+	if len(c.Choices) == 0 {
+		return ""
+	}
+	return c.Choices[0].Text
+}
+
+// Complete 实现了 spec.Completer 接口
+func (m *completerImpl) Complete(ctx context.Context, prompt string, opts ...spec.Option) (*spec.Response, error) {
+	config := spec.NewRequestConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	ctx = config.BindContext(ctx)
+
+	requestBody := make(map[string]any)
+	for k, v := range config.Parameters {
+		requestBody[k] = v
+	}
+	requestBody["model"] = m.name
+	requestBody["prompt"] = prompt
+	if config.Temperature != nil {
+		requestBody["temperature"] = *config.Temperature
+	}
+	if config.MaxTokens != nil {
+		requestBody["max_tokens"] = *config.MaxTokens
+	}
+	if config.TopP != nil {
+		requestBody["top_p"] = *config.TopP
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+	endpoint := m.client.completionsURL()
+
+	// ==================== 流式处理分支 ====================
+	if config.Streaming {
+		requestBody["stream"] = true
+		resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), endpoint, headers, requestBody)
+		if err != nil {
+			return config.DryRunResult(err)
+		}
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
+		var fullContent strings.Builder
+		var usage spec.Usage
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
+		for scanner.Scan() {
+			dataStr, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			dataStr = strings.TrimSpace(dataStr)
+			if dataStr == "[DONE]" {
+				break
+			}
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
+
+			var chunk completionChunk
+			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
+				continue
+			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
+			if text := chunk.text(); text != "" {
+				fullContent.WriteString(text)
+				if err := config.EmitStreamChunk(ctx, text); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("generic provider: stream scan error: %w", err)
+		}
+
+		return &spec.Response{
+			Message:   spec.Message{Role: spec.RoleAssistant, Content: fullContent.String()},
+			Usage:     usage,
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
+		}, nil
+	}
+
+	// ==================== 非流式处理分支 ====================
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), endpoint, headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+	var apiResp completionChunk
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("generic provider: failed to unmarshal completion response: %w", err)
+	}
+	if len(apiResp.Choices) == 0 {
+		return nil, fmt.Errorf("generic provider: invalid completion response, no choices found")
+	}
+
+	response := &spec.Response{
+		Message:     spec.Message{Role: spec.RoleAssistant, Content: apiResp.text()},
+		RawResponse: rawBody,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}
+	if apiResp.Usage != nil {
+		response.Usage = *apiResp.Usage
+	}
+	return response, nil
+}
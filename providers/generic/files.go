@@ -0,0 +1,11 @@
+package generic
+
+import (
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Files 实现了 spec.FileStoreProvider 接口，适用于提供 OpenAI 兼容 /files 接口的部署，如 Moonshot
+func (c *clientImpl) Files() spec.FileStore {
+	return requester.NewOpenAIFiles(c.requester, c.baseURL()+"/files", c.config.APIKey)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/iEvan-lhr/go-llm-client/spec"
+	"iter"
 	"net/http"
 	"regexp"
 
@@ -42,11 +43,14 @@ func NewClient(opts ...spec.ClientOption) (spec.Client, error) {
 		return nil, fmt.Errorf("generic provider: API URL is required for private deployment, use llm.WithAPIURL()")
 	}
 
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("generic provider: %w", err)
+	}
+
 	return &clientImpl{
-		requester: &requester.Requester{
-			HTTPClient: config.HTTPClient,
-		},
-		config: *config,
+		requester: req.WithErrorParser(requester.ParseOpenAIError),
+		config:    *config,
 	}, nil
 }
 
@@ -55,12 +59,29 @@ func (c *clientImpl) Model(name string) spec.Model {
 	return &modelImpl{client: c, name: name}
 }
 
+// Close 实现了 llm.Client 接口的方法，取消进行中的请求并释放空闲连接
+func (c *clientImpl) Close() error {
+	return c.requester.Close()
+}
+
+// CloseIdleConnections 释放空闲的 HTTP 连接，不影响进行中的请求
+func (c *clientImpl) CloseIdleConnections() {
+	c.requester.CloseIdleConnections()
+}
+
+// ChatSeq 实现了 llm.Model 接口的方法，以迭代器形式返回流式事件
+func (m *modelImpl) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
+
 // Chat 实现了 llm.Model 接口的方法
 func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
 	config := spec.NewRequestConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
+	ctx = config.BindContext(ctx)
+	messages = config.TranslationMessages(messages)
 	requestBody := config.Parameters
 	// 为了不修改用户传入的原始messages切片，我们创建一个副本
 	processedMessages := make([]spec.Message, len(messages))
@@ -99,15 +120,17 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 		requestBody["top_p"] = 1
 	}
 
+	config.ApplyTools(requestBody)
+
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
 	// 这里的APIKey就是完整的 "Bearer aieif=..." 字符串
 	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
 
 	// 调用通用 Requester
-	rawBody, err := m.client.requester.Post(ctx, m.client.config.APIURL, headers, requestBody)
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 	if err != nil {
-		return nil, err
+		return config.DryRunResult(err)
 	}
 
 	// 解析响应
@@ -115,6 +138,7 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 		Choices []struct {
 			Message spec.Message `json:"message"`
 		} `json:"choices"`
+		Usage spec.Usage `json:"usage"`
 	}
 	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
 		return nil, fmt.Errorf("generic provider: failed to unmarshal response: %w", err)
@@ -132,5 +156,8 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	return &spec.Response{
 		Message:     responseMessage,
 		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
 	}, nil
 }
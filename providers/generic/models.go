@@ -0,0 +1,41 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// baseURL 根据 Chat 端点推导出 API 的基础地址，用于 /models、/files 等接口；
+// 端点不以 /chat/completions 结尾时使用同一主机下的 /v1（vLLM、Ollama 等部署的默认路径）
+func (c *clientImpl) baseURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base
+	}
+	u, err := url.Parse(c.config.APIURL)
+	if err != nil {
+		return c.config.APIURL
+	}
+	return u.Scheme + "://" + u.Host + "/v1"
+}
+
+// ListModels 实现了 spec.ModelLister 接口。
+// /models 只返回模型 ID 和所属组织，能力根据模型名称推断，部署返回 max_model_len 时作为上下文窗口。
+func (c *clientImpl) ListModels(ctx context.Context) ([]spec.ModelInfo, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	rawBody, err := c.requester.Get(ctx, c.baseURL()+"/models", headers)
+	if err != nil {
+		return nil, err
+	}
+	models, err := spec.ParseModelList(rawBody)
+	if err != nil {
+		return nil, fmt.Errorf("generic provider: %w", err)
+	}
+	return models, nil
+}
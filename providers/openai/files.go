@@ -0,0 +1,21 @@
+package openai
+
+import (
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// filesURL 根据 Chat 端点推导出 /files 端点
+func (c *clientImpl) filesURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/files"
+	}
+	return "https://api.openai.com/v1/files"
+}
+
+// Files 实现了 spec.FileStoreProvider 接口
+func (c *clientImpl) Files() spec.FileStore {
+	return requester.NewOpenAIFiles(c.requester, c.filesURL(), c.config.APIKey)
+}
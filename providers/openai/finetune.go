@@ -0,0 +1,181 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// fineTunerImpl 实现了 spec.FineTuner
+type fineTunerImpl struct {
+	client *clientImpl
+}
+
+// FineTuner 实现了 spec.FineTunerProvider 接口
+func (c *clientImpl) FineTuner() spec.FineTuner {
+	return &fineTunerImpl{client: c}
+}
+
+// fineTuningURL 根据 Chat 端点推导出 /fine_tuning/jobs 端点
+func (c *clientImpl) fineTuningURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/fine_tuning/jobs"
+	}
+	return "https://api.openai.com/v1/fine_tuning/jobs"
+}
+
+// openAIFineTuneStatus 将 OpenAI 的任务状态映射为统一状态
+var openAIFineTuneStatus = map[string]spec.FineTuneStatus{
+	"validating_files": spec.FineTuneQueued,
+	"queued":           spec.FineTuneQueued,
+	"running":          spec.FineTuneRunning,
+	"succeeded":        spec.FineTuneSucceeded,
+	"failed":           spec.FineTuneFailed,
+	"cancelled":        spec.FineTuneCancelled,
+}
+
+// headers 返回微调接口的请求头
+func (f *fineTunerImpl) headers() http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+f.client.config.APIKey)
+	return headers
+}
+
+// CreateJob 实现了 spec.FineTuner 接口
+func (f *fineTunerImpl) CreateJob(ctx context.Context, req spec.FineTuneRequest) (*spec.FineTuneJob, error) {
+	hyperparameters := make(map[string]any)
+	if req.Epochs > 0 {
+		hyperparameters["n_epochs"] = req.Epochs
+	}
+	if req.BatchSize > 0 {
+		hyperparameters["batch_size"] = req.BatchSize
+	}
+	if req.LearningRate > 0 {
+		hyperparameters["learning_rate_multiplier"] = req.LearningRate
+	}
+
+	requestBody := map[string]any{
+		"model":         req.Model,
+		"training_file": req.TrainingFile,
+	}
+	if req.ValidationFile != "" {
+		requestBody["validation_file"] = req.ValidationFile
+	}
+	if req.Suffix != "" {
+		requestBody["suffix"] = req.Suffix
+	}
+	if len(hyperparameters) > 0 {
+		requestBody["hyperparameters"] = hyperparameters
+	}
+	for k, v := range req.Parameters {
+		requestBody[k] = v
+	}
+
+	rawBody, err := f.client.requester.Post(ctx, f.client.fineTuningURL(), f.headers(), requestBody)
+	if err != nil {
+		return nil, err
+	}
+	return parseFineTuneJob(rawBody)
+}
+
+// GetJob 实现了 spec.FineTuner 接口
+func (f *fineTunerImpl) GetJob(ctx context.Context, id string) (*spec.FineTuneJob, error) {
+	rawBody, err := f.client.requester.Get(ctx, f.client.fineTuningURL()+"/"+url.PathEscape(id), f.headers())
+	if err != nil {
+		return nil, err
+	}
+	return parseFineTuneJob(rawBody)
+}
+
+// ListJobs 实现了 spec.FineTuner 接口，自动翻页直到取回全部任务
+func (f *fineTunerImpl) ListJobs(ctx context.Context) ([]spec.FineTuneJob, error) {
+	var jobs []spec.FineTuneJob
+	after := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", "100")
+		if after != "" {
+			query.Set("after", after)
+		}
+		rawBody, err := f.client.requester.Get(ctx, f.client.fineTuningURL()+"?"+query.Encode(), f.headers())
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Data    []json.RawMessage `json:"data"`
+			HasMore bool              `json:"has_more"`
+		}
+		if err := json.Unmarshal(rawBody, &page); err != nil {
+			return nil, fmt.Errorf("openai provider: failed to unmarshal fine-tuning job list: %w", err)
+		}
+		for _, item := range page.Data {
+			job, err := parseFineTuneJob(item)
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, *job)
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return jobs, nil
+		}
+		after = jobs[len(jobs)-1].ID
+	}
+}
+
+// CancelJob 实现了 spec.FineTuner 接口
+func (f *fineTunerImpl) CancelJob(ctx context.Context, id string) (*spec.FineTuneJob, error) {
+	rawBody, err := f.client.requester.Post(ctx, f.client.fineTuningURL()+"/"+url.PathEscape(id)+"/cancel", f.headers(), map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	return parseFineTuneJob(rawBody)
+}
+
+// parseFineTuneJob 将 fine_tuning.job 对象转换为 spec.FineTuneJob
+func parseFineTuneJob(raw []byte) (*spec.FineTuneJob, error) {
+	var j struct {
+		ID             string `json:"id"`
+		Model          string `json:"model"`
+		FineTunedModel string `json:"fine_tuned_model"`
+		Status         string `json:"status"`
+		TrainingFile   string `json:"training_file"`
+		TrainedTokens  int    `json:"trained_tokens"`
+		CreatedAt      int64  `json:"created_at"`
+		FinishedAt     int64  `json:"finished_at"`
+		Error          *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &j); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal fine-tuning job: %w", err)
+	}
+
+	job := &spec.FineTuneJob{
+		ID:             j.ID,
+		Model:          j.Model,
+		FineTunedModel: j.FineTunedModel,
+		Status:         openAIFineTuneStatus[j.Status],
+		RawStatus:      j.Status,
+		TrainingFile:   j.TrainingFile,
+		TrainedTokens:  j.TrainedTokens,
+		Raw:            raw,
+	}
+	if j.Error != nil {
+		job.Error = j.Error.Message
+	}
+	if j.CreatedAt > 0 {
+		job.CreatedAt = time.Unix(j.CreatedAt, 0)
+	}
+	if j.FinishedAt > 0 {
+		job.FinishedAt = time.Unix(j.FinishedAt, 0)
+	}
+	return job, nil
+}
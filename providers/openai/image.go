@@ -0,0 +1,140 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// maxImageSize 是下载输入图片时允许的最大字节数
+const maxImageSize = 50 << 20
+
+// imagesURL 根据 Chat 端点推导出 Images 端点，如 /images/edits、/images/variations
+func (c *clientImpl) imagesURL(path string) string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/images/" + path
+	}
+	return "https://api.openai.com/v1/images/" + path
+}
+
+// handleImageEdit 调用 /images/edits：最后一条用户消息中的图片为待编辑图片，文本为编辑指令，
+// 配置了 mask 时只重绘蒙版中透明的区域
+func (m *modelImpl) handleImageEdit(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("openai provider: no messages provided for image edit")
+	}
+	last := messages[len(messages)-1]
+	images := last.ImageInputs()
+	if len(images) == 0 {
+		return nil, fmt.Errorf("openai provider: no input image for image edit")
+	}
+	prompt := last.PlainText()
+	if prompt == "" {
+		return nil, fmt.Errorf("openai provider: empty prompt for image edit")
+	}
+
+	form := requester.NewForm()
+	// gpt-image-1 支持多张输入图片，使用 image[] 字段
+	field := "image"
+	if len(images) > 1 {
+		field = "image[]"
+	}
+	for i, img := range images {
+		if err := m.client.addImage(ctx, form, field, fmt.Sprintf("image%d", i+1), img); err != nil {
+			return nil, err
+		}
+	}
+	if mask, ok := config.Parameters["mask"].(string); ok && mask != "" {
+		if err := m.client.addImage(ctx, form, "mask", "mask", mask); err != nil {
+			return nil, err
+		}
+	}
+	form.AddField("prompt", prompt)
+	return m.sendImageForm(ctx, "edits", form, config)
+}
+
+// handleImageVariation 调用 /images/variations，基于最后一条用户消息中的第一张图片生成变体（dall-e-2）
+func (m *modelImpl) handleImageVariation(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("openai provider: no messages provided for image variation")
+	}
+	images := messages[len(messages)-1].ImageInputs()
+	if len(images) == 0 {
+		return nil, fmt.Errorf("openai provider: no input image for image variation")
+	}
+
+	form := requester.NewForm()
+	if err := m.client.addImage(ctx, form, "image", "image", images[0]); err != nil {
+		return nil, err
+	}
+	return m.sendImageForm(ctx, "variations", form, config)
+}
+
+// sendImageForm 附加通用参数后发送表单，并将响应转换为 spec.Response
+func (m *modelImpl) sendImageForm(ctx context.Context, path string, form *requester.Form, config *spec.RequestConfig) (*spec.Response, error) {
+	form.AddField("model", m.name)
+	for _, key := range []string{"n", "size", "response_format"} {
+		if val, ok := config.Parameters[key]; ok {
+			form.AddField(key, fmt.Sprint(val))
+		}
+	}
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+
+	rawBody, err := m.client.requester.PostForm(ctx, m.client.imagesURL(path), headers, form)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+
+	var apiResp struct {
+		Data []struct {
+			URL     string `json:"url"`
+			B64JSON string `json:"b64_json"`
+		} `json:"data"`
+		Usage spec.Usage `json:"usage"`
+	}
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal image response: %w", err)
+	}
+
+	var images []string
+	for _, d := range apiResp.Data {
+		switch {
+		case d.URL != "":
+			images = append(images, d.URL)
+		case d.B64JSON != "":
+			images = append(images, "data:image/png;base64,"+d.B64JSON)
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("openai provider: no image in response: %s", string(rawBody))
+	}
+
+	return &spec.Response{
+		Message: spec.Message{
+			Role:    spec.RoleAssistant,
+			Content: images[0],
+		},
+		Images:      images,
+		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}, nil
+}
+
+// addImage 读取图片（URL 或 data URL）并作为文件字段写入表单
+func (c *clientImpl) addImage(ctx context.Context, form *requester.Form, field, name, image string) error {
+	data, mimeType, err := c.requester.Fetch(ctx, image, maxImageSize)
+	if err != nil {
+		return fmt.Errorf("openai provider: failed to load image: %w", err)
+	}
+	form.AddFile(field, requester.FileName(name, mimeType), mimeType, data)
+	return nil
+}
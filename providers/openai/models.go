@@ -0,0 +1,35 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// modelsURL 根据 Chat 端点推导出 /models 端点
+func (c *clientImpl) modelsURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/models"
+	}
+	return "https://api.openai.com/v1/models"
+}
+
+// ListModels 实现了 spec.ModelLister 接口。
+// /models 只返回模型 ID 和所属组织，能力根据模型名称推断。
+func (c *clientImpl) ListModels(ctx context.Context) ([]spec.ModelInfo, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	rawBody, err := c.requester.Get(ctx, c.modelsURL(), headers)
+	if err != nil {
+		return nil, err
+	}
+	models, err := spec.ParseModelList(rawBody)
+	if err != nil {
+		return nil, fmt.Errorf("openai provider: %w", err)
+	}
+	return models, nil
+}
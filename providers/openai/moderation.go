@@ -0,0 +1,71 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// moderatorImpl 实现了 spec.Moderator
+type moderatorImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// Moderator 实现了 spec.ModeratorProvider 接口，返回指定模型（如 omni-moderation-latest）的 Moderator
+func (c *clientImpl) Moderator(model string) spec.Moderator {
+	return &moderatorImpl{client: c, name: model}
+}
+
+// moderationURL 根据 Chat 端点推导出 /moderations 端点
+func (c *clientImpl) moderationURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/moderations"
+	}
+	return "https://api.openai.com/v1/moderations"
+}
+
+// Moderate 实现了 spec.Moderator 接口
+func (m *moderatorImpl) Moderate(ctx context.Context, inputs []string) ([]spec.ModerationResult, error) {
+	requestBody := map[string]any{"input": inputs}
+	if m.name != "" {
+		requestBody["model"] = m.name
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+
+	rawBody, err := m.client.requester.Post(ctx, m.client.moderationURL(), headers, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp struct {
+		Results []struct {
+			Flagged        bool               `json:"flagged"`
+			Categories     map[string]bool    `json:"categories"`
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal moderation response: %w", err)
+	}
+	if len(apiResp.Results) != len(inputs) {
+		return nil, fmt.Errorf("openai provider: got %d moderation results for %d inputs", len(apiResp.Results), len(inputs))
+	}
+
+	results := make([]spec.ModerationResult, len(apiResp.Results))
+	for i, r := range apiResp.Results {
+		results[i] = spec.ModerationResult{
+			Flagged:    r.Flagged,
+			Categories: spec.FlaggedCategories(r.Categories),
+			Scores:     r.CategoryScores,
+		}
+	}
+	return results, nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
+	"strings"
 
 	"github.com/iEvan-lhr/go-llm-client/internal/requester"
 	"github.com/iEvan-lhr/go-llm-client/spec"
@@ -38,12 +41,15 @@ func NewClient(opts ...spec.ClientOption) (spec.Client, error) {
 		return nil, fmt.Errorf("openai provider: API key is required, use spec.WithAPIKey()")
 	}
 
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("openai provider: %w", err)
+	}
+
 	// 4. 创建并返回客户端实例
 	return &clientImpl{
-		requester: &requester.Requester{
-			HTTPClient: config.HTTPClient,
-		},
-		config: *config,
+		requester: req.WithErrorParser(requester.ParseOpenAIError),
+		config:    *config,
 	}, nil
 }
 
@@ -52,12 +58,38 @@ func (c *clientImpl) Model(name string) spec.Model {
 	return &modelImpl{client: c, name: name}
 }
 
+// Close 实现了 spec.Client 接口的方法，取消进行中的请求并释放空闲连接
+func (c *clientImpl) Close() error {
+	return c.requester.Close()
+}
+
+// CloseIdleConnections 释放空闲的 HTTP 连接，不影响进行中的请求
+func (c *clientImpl) CloseIdleConnections() {
+	c.requester.CloseIdleConnections()
+}
+
+// ChatSeq 实现了 spec.Model 接口的方法，以迭代器形式返回流式事件
+func (m *modelImpl) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
+
 // Chat 实现了 spec.Model 接口的方法
 func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
 	config := spec.NewRequestConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
+	ctx = config.BindContext(ctx)
+	messages = config.TranslationMessages(messages)
+
+	switch {
+	case config.IsImageEdit():
+		return m.handleImageEdit(ctx, messages, config)
+	case config.IsImageVariation():
+		return m.handleImageVariation(ctx, messages, config)
+	case m.usesResponses(config):
+		return m.handleResponses(ctx, messages, config)
+	}
 
 	// 1. 基础请求体来自用户传入的任意参数
 	requestBody := config.Parameters
@@ -78,8 +110,10 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	if config.TopP != nil {
 		requestBody["top_p"] = *config.TopP
 	}
+	config.ApplyTools(requestBody)
 	if config.Streaming {
 		requestBody["stream"] = true
+		requestBody["stream_options"] = map[string]bool{"include_usage": true}
 	}
 
 	// 3. 准备请求头
@@ -87,10 +121,14 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	headers.Set("Content-Type", "application/json")
 	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
 
+	if config.Streaming {
+		return m.streamChat(ctx, headers, requestBody, config)
+	}
+
 	// 4. 调用通用 Requester
-	rawBody, err := m.client.requester.Post(ctx, m.client.config.APIURL, headers, requestBody)
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 	if err != nil {
-		return nil, err
+		return config.DryRunResult(err)
 	}
 
 	// 5. 解析响应
@@ -98,6 +136,7 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 		Choices []struct {
 			Message spec.Message `json:"message"`
 		} `json:"choices"`
+		Usage spec.Usage `json:"usage"`
 	}
 	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
 		return nil, fmt.Errorf("openai provider: failed to unmarshal response: %w", err)
@@ -112,5 +151,92 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	return &spec.Response{
 		Message:     responseMessage,
 		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}, nil
+}
+
+// streamChat 处理 Chat Completions 的 SSE 流式响应，逐块回调增量内容并拼接完整消息
+func (m *modelImpl) streamChat(ctx context.Context, headers http.Header, requestBody map[string]any, config *spec.RequestConfig) (*spec.Response, error) {
+	resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if config.StreamIdleTimeout > 0 {
+		idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+		defer idle.Close()
+		body = idle
+	}
+
+	var content, reasoning strings.Builder
+	var usage spec.Usage
+	var toolCalls spec.ToolCallAccumulator
+	role := spec.RoleAssistant
+
+	scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+		if err := config.EmitRawChunk(data); err != nil {
+			return nil, err
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Role             spec.Role            `json:"role"`
+					Content          string               `json:"content"`
+					ReasoningContent string               `json:"reasoning_content"`
+					ToolCalls        []spec.ToolCallDelta `json:"tool_calls"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *spec.Usage `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta
+		if delta.Role != "" {
+			role = delta.Role
+		}
+		reasoning.WriteString(delta.ReasoningContent)
+		toolCalls.Add(delta.ToolCalls)
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			if err := config.EmitStreamChunk(ctx, delta.Content); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("openai provider: stream scan error: %w", err)
+	}
+
+	return &spec.Response{
+		Message: spec.Message{
+			Role:             role,
+			Content:          content.String(),
+			ReasoningContent: reasoning.String(),
+			ToolCalls:        toolCalls.ToolCalls(),
+		},
+		Usage:     usage,
+		Timing:    config.Timing(),
+		RequestID: config.RequestID,
 	}, nil
 }
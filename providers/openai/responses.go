@@ -0,0 +1,365 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// responsesOnlyModels 是只能通过 Responses API 调用的模型，Chat 会自动切换
+var responsesOnlyModels = []string{
+	"o1-pro*",
+	"o3-pro*",
+	"o3-deep-research*",
+	"o4-mini-deep-research*",
+	"codex-mini*",
+	"computer-use-preview*",
+	"gpt-5-pro*",
+	"gpt-5-codex*",
+}
+
+// responsesURL 根据 Chat 端点推导出 /responses 端点
+func (c *clientImpl) responsesURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/responses"
+	}
+	return "https://api.openai.com/v1/responses"
+}
+
+// usesResponses 判断本次调用是否走 Responses API
+func (m *modelImpl) usesResponses(config *spec.RequestConfig) bool {
+	return config.UsesResponsesAPI() ||
+		spec.MatchModel(m.name, m.client.config.ResponsesModels) ||
+		spec.MatchModel(m.name, responsesOnlyModels)
+}
+
+// responsesResult 是 Responses API 返回的 response 对象，只列出用到的字段
+type responsesResult struct {
+	Status string `json:"status"`
+	Output []struct {
+		Type    string `json:"type"`
+		Role    string `json:"role"`
+		Content []struct {
+			Type    string `json:"type"`
+			Text    string `json:"text"`
+			Refusal string `json:"refusal"`
+		} `json:"content"`
+		Summary []struct {
+			Text string `json:"text"`
+		} `json:"summary"`
+		CallID    string `json:"call_id"`
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"output"`
+	Usage spec.Usage `json:"usage"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// message 将输出项合并为一条助手消息：output_text 拼接为正文，reasoning 摘要拼接为思考过程，
+// function_call 转换为 ToolCalls
+func (r *responsesResult) message() spec.Message {
+	var content strings.Builder
+	var summaries []string
+	var toolCalls []spec.ToolCall
+	for _, item := range r.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				switch part.Type {
+				case "output_text":
+					content.WriteString(part.Text)
+				case "refusal":
+					content.WriteString(part.Refusal)
+				}
+			}
+		case "reasoning":
+			for _, s := range item.Summary {
+				summaries = append(summaries, s.Text)
+			}
+		case "function_call":
+			toolCalls = append(toolCalls, spec.ToolCall{
+				ID:       item.CallID,
+				Type:     "function",
+				Function: spec.FunctionCall{Name: item.Name, Arguments: item.Arguments},
+			})
+		}
+	}
+	return spec.Message{
+		Role:             spec.RoleAssistant,
+		Content:          content.String(),
+		ReasoningContent: strings.Join(summaries, "\n\n"),
+		ToolCalls:        toolCalls,
+	}
+}
+
+// err 返回失败的 response 对应的错误
+func (r *responsesResult) err() error {
+	if r.Status != "failed" {
+		return nil
+	}
+	if r.Error != nil {
+		return fmt.Errorf("openai provider: response failed: %s: %s", r.Error.Code, r.Error.Message)
+	}
+	return errors.New("openai provider: response failed")
+}
+
+// responsesInput 将消息转换为 Responses API 的 input 项，多模态内容转换为 input_text / input_image，
+// 助手的函数调用和工具结果分别转换为 function_call / function_call_output
+func responsesInput(messages []spec.Message) []map[string]any {
+	input := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == spec.RoleTool {
+			input = append(input, map[string]any{
+				"type":    "function_call_output",
+				"call_id": msg.ToolCallID,
+				"output":  msg.PlainText(),
+			})
+			continue
+		}
+		for _, call := range msg.ToolCalls {
+			input = append(input, map[string]any{
+				"type":      "function_call",
+				"call_id":   call.ID,
+				"name":      call.Function.Name,
+				"arguments": call.Function.Arguments,
+			})
+		}
+		if len(msg.ToolCalls) > 0 && msg.PlainText() == "" {
+			continue
+		}
+
+		item := map[string]any{"role": msg.Role}
+		if len(msg.Parts) == 0 {
+			item["content"] = msg.Content
+			input = append(input, item)
+			continue
+		}
+
+		textType := "input_text"
+		if msg.Role == spec.RoleAssistant {
+			textType = "output_text"
+		}
+		content := make([]map[string]any, 0, len(msg.Parts))
+		for _, part := range msg.Parts {
+			switch {
+			case part.ImageURL != nil:
+				image := map[string]any{"type": "input_image", "image_url": part.ImageURL.URL}
+				if part.ImageURL.Detail != "" {
+					image["detail"] = part.ImageURL.Detail
+				}
+				content = append(content, image)
+			default:
+				content = append(content, map[string]any{"type": textType, "text": part.Text})
+			}
+		}
+		item["content"] = content
+		input = append(input, item)
+	}
+	return input
+}
+
+// responsesTool 将函数工具转换为 Responses API 的扁平格式
+func responsesTool(tool spec.Tool) map[string]any {
+	t := map[string]any{
+		"type": tool.Type,
+		"name": tool.Function.Name,
+	}
+	if tool.Function.Description != "" {
+		t["description"] = tool.Function.Description
+	}
+	if tool.Function.Parameters != nil {
+		t["parameters"] = tool.Function.Parameters
+	}
+	if tool.Function.Strict != nil {
+		t["strict"] = *tool.Function.Strict
+	}
+	return t
+}
+
+// responsesToolChoice 将 Chat Completions 格式的 tool_choice 转换为 Responses API 的格式，
+// 字符串（auto / none / required）原样返回
+func responsesToolChoice(choice any) any {
+	m, ok := choice.(map[string]any)
+	if !ok {
+		return choice
+	}
+	if fn, ok := m["function"].(map[string]any); ok {
+		return map[string]any{"type": "function", "name": fn["name"]}
+	}
+	return choice
+}
+
+// handleResponses 通过 /v1/responses 完成一次对话
+func (m *modelImpl) handleResponses(ctx context.Context, messages []spec.Message, config *spec.RequestConfig) (*spec.Response, error) {
+	// 1. 构建请求体，从 Parameters 初始化以支持透传
+	requestBody := make(map[string]any)
+	for k, v := range config.Parameters {
+		requestBody[k] = v
+	}
+	requestBody["model"] = m.name
+	requestBody["input"] = responsesInput(messages)
+
+	if config.Temperature != nil {
+		requestBody["temperature"] = *config.Temperature
+	}
+	if config.MaxTokens != nil {
+		requestBody["max_output_tokens"] = *config.MaxTokens
+	}
+	if config.TopP != nil {
+		requestBody["top_p"] = *config.TopP
+	}
+	if len(config.BuiltinTools) > 0 || len(config.Tools) > 0 {
+		tools, _ := requestBody["tools"].([]any)
+		for _, tool := range config.BuiltinTools {
+			tools = append(tools, tool)
+		}
+		for _, tool := range config.Tools {
+			tools = append(tools, responsesTool(tool))
+		}
+		requestBody["tools"] = tools
+	}
+	if config.ToolChoice != nil {
+		requestBody["tool_choice"] = responsesToolChoice(config.ToolChoice)
+	}
+	if config.ParallelToolCalls != nil {
+		requestBody["parallel_tool_calls"] = *config.ParallelToolCalls
+	}
+	// 开启思考模式时默认返回思考过程摘要
+	if config.ReasoningSummary != "" || (config.Thinking != nil && *config.Thinking) {
+		reasoning, _ := requestBody["reasoning"].(map[string]any)
+		if reasoning == nil {
+			reasoning = make(map[string]any)
+		}
+		summary := config.ReasoningSummary
+		if summary == "" {
+			summary = "auto"
+		}
+		reasoning["summary"] = summary
+		requestBody["reasoning"] = reasoning
+	}
+	if config.Streaming {
+		requestBody["stream"] = true
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Authorization", "Bearer "+m.client.config.APIKey)
+
+	if config.Streaming {
+		return m.streamResponses(ctx, headers, requestBody, config)
+	}
+
+	// 2. 非流式调用
+	rawBody, err := m.client.requester.Post(ctx, m.client.responsesURL(), headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+	var result responsesResult
+	if err := json.Unmarshal(rawBody, &result); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal response: %w", err)
+	}
+	if err := result.err(); err != nil {
+		return nil, err
+	}
+
+	return &spec.Response{
+		Message:     result.message(),
+		RawResponse: rawBody,
+		Usage:       result.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}, nil
+}
+
+// streamResponses 处理 Responses API 的流式事件。
+// 正文增量通过 StreamCallback 输出，最终结果以 response.completed 事件中的完整 response 为准。
+func (m *modelImpl) streamResponses(ctx context.Context, headers http.Header, requestBody map[string]any, config *spec.RequestConfig) (*spec.Response, error) {
+	resp, err := m.client.requester.PostStream(ctx, m.client.responsesURL(), headers, requestBody)
+	if err != nil {
+		return config.DryRunResult(err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if config.StreamIdleTimeout > 0 {
+		idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+		defer idle.Close()
+		body = idle
+	}
+
+	var fullContent, reasoningContent strings.Builder
+	var final json.RawMessage
+
+	scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
+	for scanner.Scan() {
+		dataStr, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		dataStr = strings.TrimSpace(dataStr)
+		if err := config.EmitRawChunk(dataStr); err != nil {
+			return nil, err
+		}
+
+		var event struct {
+			Type     string          `json:"type"`
+			Delta    string          `json:"delta"`
+			Response json.RawMessage `json:"response"`
+			Code     string          `json:"code"`
+			Message  string          `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(dataStr), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "response.output_text.delta":
+			fullContent.WriteString(event.Delta)
+			if err := config.EmitStreamChunk(ctx, event.Delta); err != nil {
+				return nil, err
+			}
+		case "response.reasoning_summary_text.delta":
+			reasoningContent.WriteString(event.Delta)
+		case "response.completed", "response.incomplete", "response.failed":
+			final = event.Response
+		case "error":
+			return nil, fmt.Errorf("openai provider: stream error: %s: %s", event.Code, event.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("openai provider: stream scan error: %w", err)
+	}
+
+	response := &spec.Response{
+		Message: spec.Message{
+			Role:             spec.RoleAssistant,
+			Content:          fullContent.String(),
+			ReasoningContent: reasoningContent.String(),
+		},
+		RawResponse: final,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
+	}
+	if len(final) > 0 {
+		var result responsesResult
+		if err := json.Unmarshal(final, &result); err != nil {
+			return nil, fmt.Errorf("openai provider: failed to unmarshal response: %w", err)
+		}
+		if err := result.err(); err != nil {
+			return nil, err
+		}
+		response.Message = result.message()
+		response.Usage = result.Usage
+	}
+	return response, nil
+}
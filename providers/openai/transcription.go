@@ -0,0 +1,121 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// maxAudioSize 是 Whisper 接口允许上传的最大音频大小
+const maxAudioSize = 25 << 20
+
+// transcriberImpl 实现了 spec.Transcriber
+type transcriberImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// Transcriber 实现了 spec.TranscriberProvider 接口，返回指定模型（如 whisper-1）的 Transcriber
+func (c *clientImpl) Transcriber(model string) spec.Transcriber {
+	return &transcriberImpl{client: c, name: model}
+}
+
+// transcriptionURL 根据 Chat 端点推导出 /audio/transcriptions 端点
+func (c *clientImpl) transcriptionURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/audio/transcriptions"
+	}
+	return "https://api.openai.com/v1/audio/transcriptions"
+}
+
+// Transcribe 实现了 spec.Transcriber 接口，以 multipart 表单上传音频
+func (t *transcriberImpl) Transcribe(ctx context.Context, audio spec.AudioInput, opts ...spec.TranscribeOption) (*spec.Transcription, error) {
+	config := spec.NewTranscribeConfig(opts...)
+
+	data, filename := audio.Data, audio.Filename
+	if data == nil {
+		if audio.URL == "" {
+			return nil, fmt.Errorf("openai provider: audio input is empty")
+		}
+		var mimeType string
+		var err error
+		data, mimeType, err = t.client.requester.Fetch(ctx, audio.URL, maxAudioSize)
+		if err != nil {
+			return nil, fmt.Errorf("openai provider: failed to load audio: %w", err)
+		}
+		if filename == "" {
+			filename = requester.FileName("audio", mimeType)
+		}
+	}
+	if filename == "" {
+		filename = requester.FileName("audio", http.DetectContentType(data))
+	}
+
+	form := requester.NewForm()
+	form.AddFile("file", filename, "", data)
+	form.AddField("model", t.name)
+	if config.Language != "" {
+		form.AddField("language", config.Language)
+	}
+	if config.Prompt != "" {
+		form.AddField("prompt", config.Prompt)
+	}
+	// 只有 verbose_json 会返回语言、时长和分段
+	if config.Segments {
+		form.AddField("response_format", "verbose_json")
+		form.AddField("timestamp_granularities[]", "segment")
+	} else {
+		form.AddField("response_format", "json")
+	}
+	for k, v := range config.Parameters {
+		form.AddField(k, fmt.Sprint(v))
+	}
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+t.client.config.APIKey)
+
+	rawBody, err := t.client.requester.PostForm(ctx, t.client.transcriptionURL(), headers, form)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp struct {
+		Text     string  `json:"text"`
+		Language string  `json:"language"`
+		Duration float64 `json:"duration"`
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Text  string  `json:"text"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal transcription response: %w", err)
+	}
+
+	result := &spec.Transcription{
+		Text:        apiResp.Text,
+		Language:    apiResp.Language,
+		Duration:    seconds(apiResp.Duration),
+		RawResponse: rawBody,
+	}
+	for _, s := range apiResp.Segments {
+		result.Segments = append(result.Segments, spec.TranscriptionSegment{
+			Start: seconds(s.Start),
+			End:   seconds(s.End),
+			Text:  strings.TrimSpace(s.Text),
+		})
+	}
+	return result, nil
+}
+
+// seconds 将秒数转换为 time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,154 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultVideoPollInterval 是视频任务的默认轮询间隔
+const defaultVideoPollInterval = 5 * time.Second
+
+// videoImpl 实现了 spec.VideoModel
+type videoImpl struct {
+	client *clientImpl
+	name   string
+}
+
+// VideoModel 实现了 spec.VideoModelProvider 接口，返回指定模型（如 sora-2、sora-2-pro）的 VideoModel
+func (c *clientImpl) VideoModel(model string) spec.VideoModel {
+	return &videoImpl{client: c, name: model}
+}
+
+// videosURL 根据 Chat 端点推导出 /videos 端点
+func (c *clientImpl) videosURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/videos"
+	}
+	return "https://api.openai.com/v1/videos"
+}
+
+// videoJob 是 /videos 接口返回的任务对象
+type videoJob struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Error    *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GenerateVideo 实现了 spec.VideoModel 接口。
+// 任务完成后自动下载视频内容到 Video.Data（mp4）；首帧图片作为 input_reference 上传，分辨率需与 Size 一致。
+func (v *videoImpl) GenerateVideo(ctx context.Context, req spec.VideoRequest, opts ...spec.VideoOption) (*spec.Video, error) {
+	config := spec.NewVideoConfig(opts...)
+	if req.Prompt == "" {
+		return nil, fmt.Errorf("openai provider: video prompt is required")
+	}
+
+	// 1. 提交任务
+	form := requester.NewForm()
+	form.AddField("model", v.name)
+	form.AddField("prompt", req.Prompt)
+	if req.Size != "" {
+		form.AddField("size", strings.ReplaceAll(req.Size, "*", "x"))
+	}
+	if req.Duration > 0 {
+		form.AddField("seconds", strconv.Itoa(int(req.Duration.Seconds())))
+	}
+	for k, val := range config.Parameters {
+		form.AddField(k, fmt.Sprint(val))
+	}
+	if req.ImageURL != "" {
+		if err := v.client.addImage(ctx, form, "input_reference", "reference", req.ImageURL); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+v.client.config.APIKey)
+	rawBody, err := v.client.requester.PostForm(ctx, v.client.videosURL(), headers, form)
+	if err != nil {
+		return nil, err
+	}
+	video, err := parseVideoJob(rawBody)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 轮询任务状态
+	interval := config.PollInterval
+	if interval <= 0 {
+		interval = defaultVideoPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if config.OnProgress != nil {
+			config.OnProgress(video)
+		}
+		switch video.Status {
+		case spec.VideoFailed:
+			return video, fmt.Errorf("openai provider: video %s failed: %s", video.ID, video.Error)
+		case spec.VideoSucceeded:
+			// 3. 下载视频内容
+			data, err := v.client.requester.Get(ctx, v.client.videosURL()+"/"+url.PathEscape(video.ID)+"/content", headers)
+			if err != nil {
+				return video, fmt.Errorf("openai provider: failed to download video %s: %w", video.ID, err)
+			}
+			video.Data = data
+			return video, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return video, ctx.Err()
+		case <-ticker.C:
+		}
+		rawBody, err := v.client.requester.Get(ctx, v.client.videosURL()+"/"+url.PathEscape(video.ID), headers)
+		if err != nil {
+			return video, fmt.Errorf("openai provider: failed to query video %s: %w", video.ID, err)
+		}
+		if video, err = parseVideoJob(rawBody); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseVideoJob 将任务对象转换为 spec.Video
+func parseVideoJob(rawBody []byte) (*spec.Video, error) {
+	var job videoJob
+	if err := json.Unmarshal(rawBody, &job); err != nil {
+		return nil, fmt.Errorf("openai provider: failed to unmarshal video job: %w", err)
+	}
+	video := &spec.Video{
+		ID:          job.ID,
+		RawStatus:   job.Status,
+		Progress:    job.Progress,
+		RawResponse: rawBody,
+	}
+	switch job.Status {
+	case "queued":
+		video.Status = spec.VideoQueued
+	case "in_progress":
+		video.Status = spec.VideoRunning
+	case "completed":
+		video.Status = spec.VideoSucceeded
+		video.Progress = 100
+	default:
+		video.Status = spec.VideoFailed
+		if job.Error != nil {
+			video.Error = job.Error.Code + ": " + job.Error.Message
+		}
+	}
+	return video, nil
+}
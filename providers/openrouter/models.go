@@ -0,0 +1,101 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// modelsURL 根据 Chat 端点推导出 /models 端点
+func (c *clientImpl) modelsURL() string {
+	if base, ok := strings.CutSuffix(c.config.APIURL, "/chat/completions"); ok {
+		return base + "/models"
+	}
+	return "https://openrouter.ai/api/v1/models"
+}
+
+// openRouterModel 是 OpenRouter /models 返回的单个模型，只列出用到的字段
+type openRouterModel struct {
+	ID            string `json:"id"`
+	Created       int64  `json:"created"`
+	ContextLength int    `json:"context_length"`
+	Architecture  struct {
+		InputModalities  []string `json:"input_modalities"`
+		OutputModalities []string `json:"output_modalities"`
+	} `json:"architecture"`
+	TopProvider struct {
+		MaxCompletionTokens int `json:"max_completion_tokens"`
+	} `json:"top_provider"`
+	SupportedParameters []string `json:"supported_parameters"`
+}
+
+// ListModels 实现了 spec.ModelLister 接口。
+// OpenRouter 会返回上下文长度、输入输出模态和支持的参数，能力据此得出而不是根据名称推断。
+func (c *clientImpl) ListModels(ctx context.Context) ([]spec.ModelInfo, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	rawBody, err := c.requester.Get(ctx, c.modelsURL(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("openrouter provider: failed to unmarshal model list: %w", err)
+	}
+
+	models := make([]spec.ModelInfo, 0, len(apiResp.Data))
+	for _, item := range apiResp.Data {
+		var m openRouterModel
+		if err := json.Unmarshal(item, &m); err != nil {
+			return nil, fmt.Errorf("openrouter provider: failed to unmarshal model entry: %w", err)
+		}
+		info := spec.ModelInfo{
+			ID:              m.ID,
+			ContextWindow:   m.ContextLength,
+			MaxOutputTokens: m.TopProvider.MaxCompletionTokens,
+			Capabilities:    m.capabilities(),
+			Raw:             item,
+		}
+		// OpenRouter 的模型 ID 形如 "openai/gpt-4o"，前缀即为所属组织
+		if owner, _, ok := strings.Cut(m.ID, "/"); ok {
+			info.OwnedBy = owner
+		}
+		if m.Created > 0 {
+			info.Created = time.Unix(m.Created, 0)
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// capabilities 根据模态和支持的参数得出模型能力
+func (m *openRouterModel) capabilities() []string {
+	in, out := m.Architecture.InputModalities, m.Architecture.OutputModalities
+	var caps []string
+	if slices.Contains(out, "text") {
+		caps = append(caps, spec.CapabilityChat)
+	}
+	if slices.Contains(in, "image") {
+		caps = append(caps, spec.CapabilityVision)
+	}
+	if slices.Contains(m.SupportedParameters, "tools") {
+		caps = append(caps, spec.CapabilityTools)
+	}
+	if slices.Contains(m.SupportedParameters, "reasoning") || slices.Contains(m.SupportedParameters, "include_reasoning") {
+		caps = append(caps, spec.CapabilityReasoning)
+	}
+	if slices.Contains(out, "image") {
+		caps = append(caps, spec.CapabilityImage)
+	}
+	return caps
+}
@@ -1,10 +1,11 @@
 package openrouter
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
 	"strings"
 
@@ -34,23 +35,80 @@ func NewClient(opts ...spec.ClientOption) (spec.Client, error) {
 		return nil, fmt.Errorf("openrouter provider: API key is required")
 	}
 
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("openrouter provider: %w", err)
+	}
+
 	return &clientImpl{
-		requester: &requester.Requester{
-			HTTPClient: config.HTTPClient,
-		},
-		config: *config,
+		requester: req.WithErrorParser(parseError),
+		config:    *config,
 	}, nil
 }
 
+// parseError 解析 OpenRouter 的错误响应：
+//
+//	{"error": {"code": 429, "message": "...", "metadata": {"provider_name": "...", "raw": "...", "reasons": [...]}}}
+//
+// metadata.raw 为上游提供商的原始错误，会追加到 Message 中；带 reasons 的 403 表示被内容审核拦截。
+func parseError(e *spec.APIError) {
+	requester.ParseOpenAIError(e)
+
+	var body struct {
+		Error struct {
+			Metadata struct {
+				ProviderName string   `json:"provider_name"`
+				Raw          any      `json:"raw"`
+				Reasons      []string `json:"reasons"`
+			} `json:"metadata"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return
+	}
+	meta := body.Error.Metadata
+	if len(meta.Reasons) > 0 {
+		e.Type = "moderation"
+	}
+	if meta.Raw != nil {
+		raw, ok := meta.Raw.(string)
+		if !ok {
+			b, _ := json.Marshal(meta.Raw)
+			raw = string(b)
+		}
+		if meta.ProviderName != "" {
+			raw = meta.ProviderName + ": " + raw
+		}
+		e.Message += " (" + raw + ")"
+	}
+}
+
 func (c *clientImpl) Model(name string) spec.Model {
 	return &modelImpl{client: c, name: name}
 }
 
+// Close 实现了 spec.Client 接口的方法，取消进行中的请求并释放空闲连接
+func (c *clientImpl) Close() error {
+	return c.requester.Close()
+}
+
+// CloseIdleConnections 释放空闲的 HTTP 连接，不影响进行中的请求
+func (c *clientImpl) CloseIdleConnections() {
+	c.requester.CloseIdleConnections()
+}
+
+// ChatSeq 实现了 spec.Model 接口的方法，以迭代器形式返回流式事件
+func (m *modelImpl) ChatSeq(ctx context.Context, messages []spec.Message, opts ...spec.Option) iter.Seq2[spec.StreamEvent, error] {
+	return spec.ChatSeq(ctx, m.Chat, messages, opts...)
+}
+
 func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, error) {
 	config := spec.NewRequestConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
+	ctx = config.BindContext(ctx)
+	messages = config.TranslationMessages(messages)
 
 	requestBody := make(map[string]any)
 	if config.Parameters != nil {
@@ -71,6 +129,7 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	if config.TopP != nil {
 		requestBody["top_p"] = *config.TopP
 	}
+	config.ApplyTools(requestBody)
 
 	if config.Provider != nil {
 		requestBody["provider"] = config.Provider
@@ -102,18 +161,28 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 	// ==================== 流式处理分支 ====================
 	if config.Streaming {
 		requestBody["stream"] = true
+		requestBody["stream_options"] = map[string]bool{"include_usage": true}
 
-		resp, err := m.client.requester.PostStream(ctx, m.client.config.APIURL, headers, requestBody)
+		resp, err := m.client.requester.PostStream(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 		if err != nil {
-			return nil, err
+			return config.DryRunResult(err)
 		}
 		defer resp.Body.Close()
 
+		var body io.Reader = resp.Body
+		if config.StreamIdleTimeout > 0 {
+			idle := requester.NewIdleTimeoutReader(resp.Body, config.StreamIdleTimeout)
+			defer idle.Close()
+			body = idle
+		}
+
 		var fullContent strings.Builder
+		var usage spec.Usage
 		var reasoningContent strings.Builder // 收集思考过程
+		var toolCalls spec.ToolCallAccumulator
 		role := "assistant"
 
-		scanner := bufio.NewScanner(resp.Body)
+		scanner := requester.NewSSEScanner(body, config.StreamBufferSize)
 		for scanner.Scan() {
 			line := scanner.Text()
 
@@ -129,21 +198,29 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 			if dataStr == "[DONE]" {
 				break
 			}
+			if err := config.EmitRawChunk(dataStr); err != nil {
+				return nil, err
+			}
 
 			// 解析包含 OpenRouter 专属 reasoning 字段的 Delta
 			var chunk struct {
 				Choices []struct {
 					Delta struct {
-						Content   string `json:"content"`
-						Role      string `json:"role"`
-						Reasoning string `json:"reasoning"` // 思考过程字段
+						Content   string               `json:"content"`
+						Role      string               `json:"role"`
+						Reasoning string               `json:"reasoning"` // 思考过程字段
+						ToolCalls []spec.ToolCallDelta `json:"tool_calls"`
 					} `json:"delta"`
 				} `json:"choices"`
+				Usage *spec.Usage `json:"usage"`
 			}
 
 			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
 				continue
 			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
 
 			if len(chunk.Choices) > 0 {
 				delta := chunk.Choices[0].Delta
@@ -155,14 +232,13 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 				if delta.Reasoning != "" {
 					reasoningContent.WriteString(delta.Reasoning)
 				}
+				toolCalls.Add(delta.ToolCalls)
 
 				// 收集正文并触发回调
 				if delta.Content != "" {
 					fullContent.WriteString(delta.Content)
-					if config.StreamCallback != nil {
-						if err := config.StreamCallback(ctx, delta.Content); err != nil {
-							return nil, err
-						}
+					if err := config.EmitStreamChunk(ctx, delta.Content); err != nil {
+						return nil, err
 					}
 				}
 			}
@@ -177,24 +253,30 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 				Role:             spec.Role(role),
 				Content:          fullContent.String(),
 				ReasoningContent: reasoningContent.String(),
+				ToolCalls:        toolCalls.ToolCalls(),
 			},
+			Usage:     usage,
+			Timing:    config.Timing(),
+			RequestID: config.RequestID,
 		}, nil
 	}
 
 	// ==================== 非流式处理分支 ====================
-	rawBody, err := m.client.requester.Post(ctx, m.client.config.APIURL, headers, requestBody)
+	rawBody, err := m.client.requester.Post(requester.Hedgeable(ctx), m.client.config.APIURL, headers, requestBody)
 	if err != nil {
-		return nil, err
+		return config.DryRunResult(err)
 	}
 
 	var apiResp struct {
 		Choices []struct {
 			Message struct {
-				Role      string `json:"role"`
-				Content   string `json:"content"`
-				Reasoning string `json:"reasoning"`
+				Role      string          `json:"role"`
+				Content   string          `json:"content"`
+				Reasoning string          `json:"reasoning"`
+				ToolCalls []spec.ToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage spec.Usage `json:"usage"`
 	}
 
 	if err := json.Unmarshal(rawBody, &apiResp); err != nil {
@@ -208,11 +290,15 @@ func (m *modelImpl) Chat(ctx context.Context, messages []spec.Message, opts ...s
 			Role:             spec.Role(msg.Role),
 			Content:          msg.Content,
 			ReasoningContent: msg.Reasoning,
+			ToolCalls:        msg.ToolCalls,
 		}
 	}
 
 	return &spec.Response{
 		Message:     responseMessage,
 		RawResponse: rawBody,
+		Usage:       apiResp.Usage,
+		Timing:      config.Timing(),
+		RequestID:   config.RequestID,
 	}, nil
 }
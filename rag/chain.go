@@ -0,0 +1,242 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultTopK 是默认检索的文档数
+const defaultTopK = 4
+
+// defaultContextHeader 是参考资料前的默认说明
+const defaultContextHeader = "请根据以下参考资料回答用户的问题。如果参考资料中没有相关信息，请直接说明不知道，不要编造。"
+
+// Chain 先检索与问题相关的文档，把它们放进系统提示词，再调用模型回答，可并发使用
+type Chain struct {
+	config   llm.Config
+	client   spec.Client
+	store    VectorStore
+	embedder spec.Embedder
+
+	topK     int
+	minScore float64
+	format   func(matches []Match) string
+	chatOpts []spec.Option
+
+	// release 释放 client，见 llm.AcquireClient
+	release func()
+}
+
+// ChainOption 用于配置 Chain
+type ChainOption func(c *Chain)
+
+// WithTopK 设置每次检索的文档数，默认为 4
+func WithTopK(k int) ChainOption {
+	return func(c *Chain) {
+		c.topK = k
+	}
+}
+
+// WithMinScore 过滤相似度低于 score 的文档，默认不过滤
+func WithMinScore(score float64) ChainOption {
+	return func(c *Chain) {
+		c.minScore = score
+	}
+}
+
+// WithContextFormatter 自定义检索结果在系统提示词中的格式，默认为带编号的资料列表
+func WithContextFormatter(format func(matches []Match) string) ChainOption {
+	return func(c *Chain) {
+		c.format = format
+	}
+}
+
+// WithChatOptions 为每次模型调用附加选项，如 spec.WithTemperature
+func WithChatOptions(opts ...spec.Option) ChainOption {
+	return func(c *Chain) {
+		c.chatOpts = append(c.chatOpts, opts...)
+	}
+}
+
+// NewChain 创建 Chain。cfg 为回答问题使用的模型配置，其 SystemPrompt 位于参考资料之前；
+// embedder 用于计算文档和问题的向量，可由 EmbedderFor 获取
+func NewChain(cfg llm.Config, store VectorStore, embedder spec.Embedder, opts ...ChainOption) (*Chain, error) {
+	if store == nil || embedder == nil {
+		return nil, errors.New("rag: store and embedder are required")
+	}
+	pc, release, err := llm.AcquireClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c := &Chain{
+		config:   cfg,
+		client:   pc,
+		release:  release,
+		store:    store,
+		embedder: embedder,
+		topK:     defaultTopK,
+		format:   FormatContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// EmbedderFor 返回 cfg 指定的提供商和模型（如 text-embedding-v3）的 Embedder。
+// 返回值实现了 io.Closer，不再使用时调用 Close 释放独占的 provider 客户端（见 llm.AcquireClient）
+func EmbedderFor(cfg llm.Config) (spec.Embedder, error) {
+	pc, release, err := llm.AcquireClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := pc.(spec.EmbedderProvider)
+	if !ok {
+		release()
+		return nil, fmt.Errorf("provider '%s' does not support batch embeddings (EmbedderProvider interface not implemented)", cfg.Provider)
+	}
+	return &closingEmbedder{Embedder: p.Embedder(cfg.Model), release: release}, nil
+}
+
+// closingEmbedder 在 Close 时释放 EmbedderFor 获取的 provider 客户端
+type closingEmbedder struct {
+	spec.Embedder
+	release func()
+}
+
+func (e *closingEmbedder) Close() error {
+	e.release()
+	return nil
+}
+
+// Close 释放 Chain 独占的 provider 客户端，不会关闭 store 和 embedder
+func (c *Chain) Close() error {
+	c.release()
+	return nil
+}
+
+// Index 为没有向量的文档计算向量后写入存储
+func (c *Chain) Index(ctx context.Context, docs []Document) error {
+	var texts []string
+	var pending []int
+	for i, d := range docs {
+		if len(d.Vector) == 0 {
+			texts = append(texts, d.Text)
+			pending = append(pending, i)
+		}
+	}
+	if len(texts) > 0 {
+		vectors, err := c.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("rag: failed to embed documents: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return fmt.Errorf("rag: got %d vectors for %d documents", len(vectors), len(texts))
+		}
+		docs = append([]Document(nil), docs...)
+		for j, i := range pending {
+			docs[i].Vector = vectors[j]
+		}
+	}
+	return c.store.Upsert(ctx, docs)
+}
+
+// Retrieve 返回与 question 最相关的文档
+func (c *Chain) Retrieve(ctx context.Context, question string) ([]Match, error) {
+	vectors, err := c.embedder.Embed(ctx, []string{question})
+	if err != nil {
+		return nil, fmt.Errorf("rag: failed to embed question: %w", err)
+	}
+	if len(vectors) == 0 {
+		return nil, errors.New("rag: empty embedding response")
+	}
+	matches, err := c.store.Query(ctx, vectors[0], c.topK)
+	if err != nil {
+		return nil, err
+	}
+	if c.minScore > 0 {
+		filtered := matches[:0]
+		for _, m := range matches {
+			if m.Score >= c.minScore {
+				filtered = append(filtered, m)
+			}
+		}
+		matches = filtered
+	}
+	return matches, nil
+}
+
+// Answer 是 Ask 的结果
+type Answer struct {
+	// Text 模型的回答
+	Text string
+	// Sources 提供给模型的参考资料
+	Sources []Match
+	// Response 模型的完整回复
+	Response *spec.Response
+}
+
+// Ask 检索相关文档并让模型据此回答 question，opts 覆盖本次调用的参数
+func (c *Chain) Ask(ctx context.Context, question string, opts ...spec.Option) (*Answer, error) {
+	matches, err := c.Retrieve(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	system := c.format(matches)
+	if c.config.SystemPrompt != "" {
+		system = c.config.SystemPrompt + "\n\n" + system
+	}
+	messages := []spec.Message{spec.NewSystemMessage(system), spec.NewUserMessage(question)}
+
+	callOpts := append(c.options(), opts...)
+	resp, err := llm.RunFailover(ctx, c.config, c.client, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+		return pc.Model(cfg.Model).Chat(ctx, messages, callOpts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Answer{Text: resp.Message.Content, Sources: matches, Response: resp}, nil
+}
+
+// options 构建每次模型调用的选项
+func (c *Chain) options() []spec.Option {
+	var opts []spec.Option
+	if c.config.Parameters != nil {
+		opts = append(opts, spec.WithParameters(c.config.Parameters))
+	}
+	if c.config.Thinking != nil {
+		opts = append(opts, spec.WithThinking(*c.config.Thinking))
+	}
+	if c.config.StreamCallback != nil {
+		opts = append(opts, spec.WithStreamCallback(c.config.StreamCallback))
+	}
+	return append(opts, c.chatOpts...)
+}
+
+// FormatContext 是默认的参考资料格式：说明文字加带编号的资料列表，资料有 title 或 source 元数据时一并列出
+func FormatContext(matches []Match) string {
+	var b strings.Builder
+	b.WriteString(defaultContextHeader)
+	b.WriteString("\n\n参考资料：\n")
+	if len(matches) == 0 {
+		b.WriteString("（无）\n")
+	}
+	for i, m := range matches {
+		fmt.Fprintf(&b, "\n[%d]", i+1)
+		for _, key := range []string{"title", "source"} {
+			if v, ok := m.Metadata[key]; ok {
+				fmt.Fprintf(&b, " %v", v)
+			}
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.TrimSpace(m.Text))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package rag
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validTable 是允许的表名格式，表名会直接拼接到 SQL 中
+var validTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// PGVectorStore 是基于 PostgreSQL pgvector 扩展的向量存储。
+// db 由调用方使用任意 PostgreSQL 驱动（如 pgx 的 stdlib、lib/pq）打开，本包不引入驱动依赖。
+//
+// 表结构为 (id text primary key, content text, metadata jsonb, embedding vector(n))，
+// 可用 EnsureTable 创建。
+type PGVectorStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewPGVectorStore 创建使用 table 表的向量存储，table 可以带 schema 前缀
+func NewPGVectorStore(db *sql.DB, table string) (*PGVectorStore, error) {
+	if !validTable.MatchString(table) {
+		return nil, fmt.Errorf("rag: invalid table name %q", table)
+	}
+	return &PGVectorStore{db: db, table: table}, nil
+}
+
+// EnsureTable 创建 vector 扩展、表和 HNSW 余弦索引（均为 IF NOT EXISTS），dimensions 为向量维度
+func (s *PGVectorStore) EnsureTable(ctx context.Context, dimensions int) error {
+	index := strings.ReplaceAll(s.table, ".", "_") + "_embedding_idx"
+	stmts := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id text PRIMARY KEY,
+	content text NOT NULL,
+	metadata jsonb,
+	embedding vector(%d) NOT NULL
+)`, s.table, dimensions),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (embedding vector_cosine_ops)`, index, s.table),
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("rag: pgvector: %w", err)
+		}
+	}
+	return nil
+}
+
+// Upsert 在一个事务中写入文档
+func (s *PGVectorStore) Upsert(ctx context.Context, docs []Document) error {
+	if err := validateDocs(docs); err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("rag: pgvector: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, content, metadata, embedding) VALUES ($1, $2, $3, $4::vector)
+ON CONFLICT (id) DO UPDATE SET content = EXCLUDED.content, metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding`, s.table)
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("rag: pgvector: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range docs {
+		var metadata any
+		if d.Metadata != nil {
+			raw, err := json.Marshal(d.Metadata)
+			if err != nil {
+				return fmt.Errorf("rag: pgvector: failed to marshal metadata of %s: %w", d.ID, err)
+			}
+			metadata = string(raw)
+		}
+		if _, err := stmt.ExecContext(ctx, d.ID, d.Text, metadata, vectorLiteral(d.Vector)); err != nil {
+			return fmt.Errorf("rag: pgvector: failed to upsert %s: %w", d.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("rag: pgvector: %w", err)
+	}
+	return nil
+}
+
+// Query 按余弦距离返回最相似的 k 个文档
+func (s *PGVectorStore) Query(ctx context.Context, vector []float32, k int) ([]Match, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf(`SELECT id, content, metadata, 1 - (embedding <=> $1::vector) AS score
+FROM %s ORDER BY embedding <=> $1::vector LIMIT $2`, s.table)
+	rows, err := s.db.QueryContext(ctx, query, vectorLiteral(vector), k)
+	if err != nil {
+		return nil, fmt.Errorf("rag: pgvector: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var m Match
+		var metadata []byte
+		if err := rows.Scan(&m.ID, &m.Text, &metadata, &m.Score); err != nil {
+			return nil, fmt.Errorf("rag: pgvector: %w", err)
+		}
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &m.Metadata); err != nil {
+				return nil, fmt.Errorf("rag: pgvector: invalid metadata of %s: %w", m.ID, err)
+			}
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rag: pgvector: %w", err)
+	}
+	return matches, nil
+}
+
+// Delete 删除指定 ID 的文档
+func (s *PGVectorStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id IN (%s)`, s.table, strings.Join(placeholders, ", "))
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("rag: pgvector: %w", err)
+	}
+	return nil
+}
+
+// vectorLiteral 返回 pgvector 的文本表示，如 "[0.1,0.2,0.3]"
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.Grow(len(v) * 10)
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
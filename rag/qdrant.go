@@ -0,0 +1,156 @@
+package rag
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// QdrantStore 是基于 Qdrant REST API 的向量存储。
+// Qdrant 的点 ID 只能是整数或 UUID，文档 ID 会映射为确定性的 UUID，原始 ID 保存在 payload 中
+type QdrantStore struct {
+	req        *requester.Requester
+	baseURL    string
+	collection string
+	apiKey     string
+}
+
+// NewQdrantStore 创建使用 collection 集合的向量存储，baseURL 如 "http://localhost:6333"。
+// 代理、TLS、重试等网络选项与 Provider 客户端一致，WithAPIKey 设置的 Key 以 api-key 请求头发送
+func NewQdrantStore(baseURL, collection string, opts ...spec.ClientOption) (*QdrantStore, error) {
+	config := spec.NewClientConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("rag: qdrant: %w", err)
+	}
+	return &QdrantStore{
+		req:        req,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		collection: collection,
+		apiKey:     config.APIKey,
+	}, nil
+}
+
+// headers 返回 Qdrant 请求头
+func (s *QdrantStore) headers() http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		headers.Set("api-key", s.apiKey)
+	}
+	return headers
+}
+
+// collectionURL 返回集合下的接口地址
+func (s *QdrantStore) collectionURL(path string) string {
+	return s.baseURL + "/collections/" + url.PathEscape(s.collection) + path
+}
+
+// EnsureCollection 在集合不存在时以余弦距离创建，dimensions 为向量维度
+func (s *QdrantStore) EnsureCollection(ctx context.Context, dimensions int) error {
+	_, err := s.req.Get(ctx, s.collectionURL(""), s.headers())
+	if err == nil {
+		return nil
+	}
+	var apiErr *spec.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("rag: qdrant: %w", err)
+	}
+	body := map[string]any{
+		"vectors": map[string]any{"size": dimensions, "distance": "Cosine"},
+	}
+	if _, err := s.req.Put(ctx, s.collectionURL(""), s.headers(), body); err != nil {
+		return fmt.Errorf("rag: qdrant: failed to create collection %s: %w", s.collection, err)
+	}
+	return nil
+}
+
+// Upsert 写入文档并等待写入完成
+func (s *QdrantStore) Upsert(ctx context.Context, docs []Document) error {
+	if err := validateDocs(docs); err != nil {
+		return err
+	}
+	points := make([]map[string]any, 0, len(docs))
+	for _, d := range docs {
+		points = append(points, map[string]any{
+			"id":     pointID(d.ID),
+			"vector": d.Vector,
+			"payload": map[string]any{
+				"doc_id":   d.ID,
+				"text":     d.Text,
+				"metadata": d.Metadata,
+			},
+		})
+	}
+	if _, err := s.req.Put(ctx, s.collectionURL("/points?wait=true"), s.headers(), map[string]any{"points": points}); err != nil {
+		return fmt.Errorf("rag: qdrant: failed to upsert: %w", err)
+	}
+	return nil
+}
+
+// Query 返回与 vector 最相似的 k 个文档
+func (s *QdrantStore) Query(ctx context.Context, vector []float32, k int) ([]Match, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	body := map[string]any{"vector": vector, "limit": k, "with_payload": true}
+	raw, err := s.req.Post(ctx, s.collectionURL("/points/search"), s.headers(), body)
+	if err != nil {
+		return nil, fmt.Errorf("rag: qdrant: failed to search: %w", err)
+	}
+	var resp struct {
+		Result []struct {
+			Score   float64 `json:"score"`
+			Payload struct {
+				DocID    string         `json:"doc_id"`
+				Text     string         `json:"text"`
+				Metadata map[string]any `json:"metadata"`
+			} `json:"payload"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("rag: qdrant: failed to parse search response: %w", err)
+	}
+	matches := make([]Match, 0, len(resp.Result))
+	for _, r := range resp.Result {
+		matches = append(matches, Match{
+			Document: Document{ID: r.Payload.DocID, Text: r.Payload.Text, Metadata: r.Payload.Metadata},
+			Score:    r.Score,
+		})
+	}
+	return matches, nil
+}
+
+// Delete 删除指定 ID 的文档
+func (s *QdrantStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	points := make([]string, len(ids))
+	for i, id := range ids {
+		points[i] = pointID(id)
+	}
+	if _, err := s.req.Post(ctx, s.collectionURL("/points/delete?wait=true"), s.headers(), map[string]any{"points": points}); err != nil {
+		return fmt.Errorf("rag: qdrant: failed to delete: %w", err)
+	}
+	return nil
+}
+
+// pointID 将文档 ID 映射为确定性的 UUID（按 UUID v5 的格式设置版本和变体位）
+func pointID(id string) string {
+	h := sha1.Sum([]byte(id))
+	h[6] = (h[6] & 0x0f) | 0x50
+	h[8] = (h[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
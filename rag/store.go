@@ -0,0 +1,168 @@
+// Package rag 提供检索增强生成（RAG）的基础设施：向量存储接口及内存、pgvector、Qdrant 实现，
+// 以及先检索、再把相关资料放进系统提示词、最后调用模型回答的 Chain。
+//
+//	store := rag.NewMemoryStore()
+//	chain, _ := rag.NewChain(cfg, store, embedder)
+//	chain.Index(ctx, docs)
+//	answer, _ := chain.Ask(ctx, "退货政策是什么？")
+package rag
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Document 是一段可检索的文本
+type Document struct {
+	// ID 文档的唯一标识，Upsert 时相同 ID 的文档会被覆盖
+	ID   string
+	Text string
+	// Metadata 附加信息，如来源、标题，随检索结果返回
+	Metadata map[string]any
+	// Vector 文本的向量，为空时由 Chain.Index 计算
+	Vector []float32
+}
+
+// Match 是一条检索结果
+type Match struct {
+	Document
+	// Score 与查询的相似度，越大越相似；内置实现均为余弦相似度
+	Score float64
+}
+
+// VectorStore 是向量存储，实现需要可以并发使用
+type VectorStore interface {
+	// Upsert 写入文档，已存在的 ID 会被覆盖，文档必须带有 Vector
+	Upsert(ctx context.Context, docs []Document) error
+	// Query 返回与 vector 最相似的 k 个文档，按相似度从高到低排列
+	Query(ctx context.Context, vector []float32, k int) ([]Match, error)
+}
+
+// MemoryStore 是内存中的向量存储，使用暴力搜索，适合文档量不大的场景和测试
+type MemoryStore struct {
+	mu   sync.RWMutex
+	docs map[string]memoryDoc
+}
+
+type memoryDoc struct {
+	doc    Document
+	vector []float32 // 已归一化
+}
+
+// NewMemoryStore 创建内存向量存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{docs: make(map[string]memoryDoc)}
+}
+
+// Upsert 写入文档
+func (s *MemoryStore) Upsert(ctx context.Context, docs []Document) error {
+	if err := validateDocs(docs); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range docs {
+		s.docs[d.ID] = memoryDoc{doc: d, vector: normalize(d.Vector)}
+	}
+	return nil
+}
+
+// Query 返回与 vector 最相似的 k 个文档
+func (s *MemoryStore) Query(ctx context.Context, vector []float32, k int) ([]Match, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	q := normalize(vector)
+	s.mu.RLock()
+	matches := make([]Match, 0, len(s.docs))
+	for _, d := range s.docs {
+		if len(d.vector) != len(q) {
+			continue
+		}
+		matches = append(matches, Match{Document: d.doc, Score: dot(q, d.vector)})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// Delete 删除指定 ID 的文档，不存在的 ID 会被忽略
+func (s *MemoryStore) Delete(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+// Len 返回文档数
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.docs)
+}
+
+// validateDocs 检查文档都带有 ID 和向量
+func validateDocs(docs []Document) error {
+	for i, d := range docs {
+		if d.ID == "" {
+			return &DocumentError{Index: i, Reason: "empty ID"}
+		}
+		if len(d.Vector) == 0 {
+			return &DocumentError{Index: i, ID: d.ID, Reason: "missing vector"}
+		}
+	}
+	return nil
+}
+
+// DocumentError 表示写入的文档无效
+type DocumentError struct {
+	Index  int
+	ID     string
+	Reason string
+}
+
+func (e *DocumentError) Error() string {
+	if e.ID != "" {
+		return "rag: document " + e.ID + ": " + e.Reason
+	}
+	return "rag: invalid document: " + e.Reason
+}
+
+// normalize 返回单位长度的向量副本，使余弦相似度可以直接用点积计算
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// dot 计算两个等长向量的点积
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
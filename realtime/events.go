@@ -0,0 +1,95 @@
+package realtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// 常用的服务端事件类型
+const (
+	EventError                 = "error"
+	EventSessionCreated        = "session.created"
+	EventSessionUpdated        = "session.updated"
+	EventSpeechStarted         = "input_audio_buffer.speech_started"
+	EventSpeechStopped         = "input_audio_buffer.speech_stopped"
+	EventInputTranscription    = "conversation.item.input_audio_transcription.completed"
+	EventTextDelta             = "response.text.delta"
+	EventAudioDelta            = "response.audio.delta"
+	EventAudioTranscriptDelta  = "response.audio_transcript.delta"
+	EventFunctionCallArgsDelta = "response.function_call_arguments.delta"
+	EventFunctionCallDone      = "response.function_call_arguments.done"
+	EventResponseDone          = "response.done"
+)
+
+// Event 是一个服务端事件，常用字段已解析，其余内容通过 Decode 读取
+type Event struct {
+	Type    string `json:"type"`
+	EventID string `json:"event_id"`
+
+	// 增量事件的内容：文本 / 转写为原文，音频为 base64
+	RawDelta string `json:"delta"`
+	// Transcript 输入音频转写完成事件中的文本
+	Transcript string `json:"transcript"`
+
+	// 函数调用事件的字段
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+
+	// Error 错误事件的详情
+	Error *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+
+	// Raw 事件的原始 JSON
+	Raw json.RawMessage `json:"-"`
+}
+
+// Decode 将事件的完整内容解析到 v
+func (e Event) Decode(v any) error {
+	if err := json.Unmarshal(e.Raw, v); err != nil {
+		return fmt.Errorf("realtime: failed to decode %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Delta 返回文本增量，适用于 response.text.delta 和 response.audio_transcript.delta
+func (e Event) Delta() string {
+	return e.RawDelta
+}
+
+// Audio 解码 response.audio.delta 事件中的音频数据
+func (e Event) Audio() ([]byte, error) {
+	audio, err := base64.StdEncoding.DecodeString(e.RawDelta)
+	if err != nil {
+		return nil, fmt.Errorf("realtime: invalid audio delta: %w", err)
+	}
+	return audio, nil
+}
+
+// ToolCall 将 response.function_call_arguments.done 事件转换为函数调用
+func (e Event) ToolCall() spec.ToolCall {
+	return spec.ToolCall{
+		ID:       e.CallID,
+		Type:     "function",
+		Function: spec.FunctionCall{Name: e.Name, Arguments: e.Arguments},
+	}
+}
+
+// Err 返回错误事件对应的错误，其他事件返回 nil
+func (e Event) Err() error {
+	if e.Type != EventError || e.Error == nil {
+		return nil
+	}
+	return fmt.Errorf("realtime: %s (%s): %s", e.Error.Type, e.Error.Code, e.Error.Message)
+}
+
+// encodeAudio 将音频编码为事件中使用的 base64
+func encodeAudio(audio []byte) string {
+	return base64.StdEncoding.EncodeToString(audio)
+}
@@ -0,0 +1,286 @@
+// Package realtime 是 OpenAI Realtime API 的 WebSocket 客户端：文本和音频的输入输出、
+// 服务端事件订阅以及函数调用，用于构建语音助手等低延迟的双向会话。
+//
+//	s, err := realtime.Connect(ctx, "gpt-4o-realtime-preview", spec.WithAPIKey(key))
+//	s.On(realtime.EventTextDelta, func(e realtime.Event) { fmt.Print(e.Delta()) })
+//	s.SendText(ctx, "你好")
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/internal/requester"
+	"github.com/iEvan-lhr/go-llm-client/internal/websocket"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultURL 是 Realtime API 的默认地址
+const defaultURL = "wss://api.openai.com/v1/realtime"
+
+// Handler 处理一个服务端事件，在接收事件的 goroutine 中按顺序调用，不应长时间阻塞
+type Handler func(Event)
+
+// Session 是一个实时会话，可并发使用
+type Session struct {
+	conn *websocket.Conn
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	// ctx 在会话结束时取消，用于函数调用等后台任务
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Connect 建立实时会话。代理、TLS 等网络选项与 Provider 客户端一致；
+// WithAPIURL 可以传入 Realtime 地址（wss://.../v1/realtime）或 Chat 端点，后者会自动转换。
+// ctx 只约束建立连接的过程，会话由 Close 结束。
+// 事件在返回后立即开始分发，早于订阅到达的事件（如 session.created）不会重放。
+func Connect(ctx context.Context, model string, opts ...spec.ClientOption) (*Session, error) {
+	config := spec.NewClientConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("realtime: API key is required, use spec.WithAPIKey()")
+	}
+
+	req, err := requester.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("realtime: %w", err)
+	}
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+config.APIKey)
+	headers.Set("OpenAI-Beta", "realtime=v1")
+	conn, err := websocket.Dial(ctx, req.HTTPClient, realtimeURL(config.APIURL, model), headers)
+	if err != nil {
+		return nil, fmt.Errorf("realtime: %w", err)
+	}
+
+	s := &Session{
+		conn:     conn,
+		handlers: make(map[string][]Handler),
+		done:     make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.readLoop()
+	return s, nil
+}
+
+// realtimeURL 根据配置的 API 地址推导出带 model 参数的 Realtime 地址
+func realtimeURL(apiURL, model string) string {
+	endpoint := defaultURL
+	if base, ok := strings.CutSuffix(apiURL, "/chat/completions"); ok {
+		endpoint = base + "/realtime"
+	} else if apiURL != "" {
+		endpoint = apiURL
+	}
+	endpoint = strings.Replace(endpoint, "https://", "wss://", 1)
+	endpoint = strings.Replace(endpoint, "http://", "ws://", 1)
+
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return endpoint + sep + "model=" + url.QueryEscape(model)
+}
+
+// On 订阅指定类型的服务端事件，eventType 为 "*" 时接收全部事件。
+// 服务端的 "error" 事件同样通过 On 订阅，不会中断会话
+func (s *Session) On(eventType string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[eventType] = append(s.handlers[eventType], h)
+}
+
+// readLoop 持续接收服务端事件并分发给订阅者，连接断开时结束
+func (s *Session) readLoop() {
+	defer close(s.done)
+	defer s.cancel()
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.err = err
+			return
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+		event.Raw = data
+
+		s.mu.RLock()
+		handlers := append(append([]Handler(nil), s.handlers[event.Type]...), s.handlers["*"]...)
+		s.mu.RUnlock()
+		for _, h := range handlers {
+			h(event)
+		}
+	}
+}
+
+// Done 返回一个在会话结束（连接断开或调用 Close）后关闭的 channel
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait 阻塞直到会话结束，返回断开的原因；调用 Close 主动结束时可能返回连接已关闭的错误
+func (s *Session) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Close 结束会话
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
+
+// Send 发送一个客户端事件，event 必须包含 "type" 字段
+func (s *Session) Send(ctx context.Context, event any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("realtime: failed to marshal event: %w", err)
+	}
+	select {
+	case <-s.done:
+		return fmt.Errorf("realtime: session closed: %w", s.err)
+	default:
+	}
+	if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("realtime: failed to send event: %w", err)
+	}
+	return nil
+}
+
+// SessionConfig 是会话配置，零值字段使用服务端默认值
+type SessionConfig struct {
+	// Modalities 输出模态，如 []string{"text"} 或 []string{"text", "audio"}
+	Modalities   []string `json:"modalities,omitempty"`
+	Instructions string   `json:"instructions,omitempty"`
+	// Voice 语音回复的音色，如 "alloy"
+	Voice string `json:"voice,omitempty"`
+	// InputAudioFormat 和 OutputAudioFormat 音频格式："pcm16"（24kHz 单声道）、"g711_ulaw"、"g711_alaw"
+	InputAudioFormat  string `json:"input_audio_format,omitempty"`
+	OutputAudioFormat string `json:"output_audio_format,omitempty"`
+	// InputAudioTranscription 输入音频的转写配置，如 {"model": "whisper-1"}
+	InputAudioTranscription map[string]any `json:"input_audio_transcription,omitempty"`
+	// TurnDetection 轮次检测，如 {"type": "server_vad"}；需要手动提交音频时使用 DisableTurnDetection
+	TurnDetection map[string]any `json:"turn_detection,omitempty"`
+	// DisableTurnDetection 关闭服务端轮次检测，由 CommitAudio 和 CreateResponse 控制轮次
+	DisableTurnDetection bool `json:"-"`
+	// Tools 会话可用的函数工具
+	Tools       []spec.Tool `json:"-"`
+	ToolChoice  any         `json:"tool_choice,omitempty"`
+	Temperature *float32    `json:"temperature,omitempty"`
+}
+
+// UpdateSession 更新会话配置
+func (s *Session) UpdateSession(ctx context.Context, cfg SessionConfig) error {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("realtime: failed to marshal session config: %w", err)
+	}
+	var session map[string]any
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return fmt.Errorf("realtime: failed to marshal session config: %w", err)
+	}
+	if cfg.DisableTurnDetection {
+		session["turn_detection"] = nil
+	}
+	if len(cfg.Tools) > 0 {
+		tools := make([]map[string]any, 0, len(cfg.Tools))
+		for _, t := range cfg.Tools {
+			tool := map[string]any{"type": t.Type, "name": t.Function.Name}
+			if t.Function.Description != "" {
+				tool["description"] = t.Function.Description
+			}
+			if t.Function.Parameters != nil {
+				tool["parameters"] = t.Function.Parameters
+			}
+			tools = append(tools, tool)
+		}
+		session["tools"] = tools
+	}
+	return s.Send(ctx, map[string]any{"type": "session.update", "session": session})
+}
+
+// SendText 发送一条用户文本消息并请求回复
+func (s *Session) SendText(ctx context.Context, text string) error {
+	item := map[string]any{
+		"type":    "message",
+		"role":    "user",
+		"content": []map[string]any{{"type": "input_text", "text": text}},
+	}
+	if err := s.Send(ctx, map[string]any{"type": "conversation.item.create", "item": item}); err != nil {
+		return err
+	}
+	return s.CreateResponse(ctx)
+}
+
+// AppendAudio 向输入音频缓冲区追加一段音频，格式由 InputAudioFormat 决定（默认 24kHz 单声道 pcm16）
+func (s *Session) AppendAudio(ctx context.Context, audio []byte) error {
+	return s.Send(ctx, map[string]any{
+		"type":  "input_audio_buffer.append",
+		"audio": encodeAudio(audio),
+	})
+}
+
+// CommitAudio 提交输入音频缓冲区作为一条用户消息，关闭轮次检测时需要手动调用
+func (s *Session) CommitAudio(ctx context.Context) error {
+	return s.Send(ctx, map[string]any{"type": "input_audio_buffer.commit"})
+}
+
+// ClearAudio 清空尚未提交的输入音频
+func (s *Session) ClearAudio(ctx context.Context) error {
+	return s.Send(ctx, map[string]any{"type": "input_audio_buffer.clear"})
+}
+
+// CreateResponse 请求模型根据当前会话内容生成回复
+func (s *Session) CreateResponse(ctx context.Context) error {
+	return s.Send(ctx, map[string]any{"type": "response.create"})
+}
+
+// CancelResponse 打断正在生成的回复，例如用户开始说话时
+func (s *Session) CancelResponse(ctx context.Context) error {
+	return s.Send(ctx, map[string]any{"type": "response.cancel"})
+}
+
+// SubmitFunctionOutput 提交函数调用的执行结果并请求模型继续回复
+func (s *Session) SubmitFunctionOutput(ctx context.Context, callID, output string) error {
+	item := map[string]any{
+		"type":    "function_call_output",
+		"call_id": callID,
+		"output":  output,
+	}
+	if err := s.Send(ctx, map[string]any{"type": "conversation.item.create", "item": item}); err != nil {
+		return err
+	}
+	return s.CreateResponse(ctx)
+}
+
+// HandleFunctionCalls 订阅函数调用事件：模型请求调用函数时执行 handler 并自动提交结果。
+// handler 在独立的 goroutine 中执行，不阻塞事件接收，ctx 在会话结束时取消；
+// 返回错误时错误信息作为结果提交给模型
+func (s *Session) HandleFunctionCalls(handler func(ctx context.Context, call spec.ToolCall) (string, error)) {
+	s.On(EventFunctionCallDone, func(e Event) {
+		call := e.ToolCall()
+		go func() {
+			output, err := handler(s.ctx, call)
+			if err != nil {
+				output = fmt.Sprintf("error: %v", err)
+			}
+			_ = s.SubmitFunctionOutput(s.ctx, call.ID, output)
+		}()
+	})
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// Task 是请求的任务类型
+type Task string
+
+const (
+	TaskChat          Task = "chat"
+	TaskCode          Task = "code"
+	TaskMath          Task = "math"
+	TaskReasoning     Task = "reasoning"
+	TaskTranslation   Task = "translation"
+	TaskSummarization Task = "summarization"
+	TaskExtraction    Task = "extraction"
+)
+
+// Features 是路由所依据的请求特征
+type Features struct {
+	// Tokens 输入消息的 token 数（按 tokens.CountMessages 计算）
+	Tokens int
+	// Task 识别出的任务类型
+	Task Task
+	// Capabilities 请求需要的模型能力，如 spec.CapabilityVision、spec.CapabilityTools
+	Capabilities []string
+}
+
+// Classifier 从消息和请求配置中提取路由特征，config 由本次调用的选项构建
+type Classifier func(messages []spec.Message, config *spec.RequestConfig) Features
+
+// taskKeywords 按优先级列出各任务类型的关键词，匹配最后一条用户消息（小写后）
+var taskKeywords = []struct {
+	task     Task
+	keywords []string
+}{
+	{TaskTranslation, []string{"翻译", "译成", "译为", "translate", "translation"}},
+	{TaskSummarization, []string{"总结", "摘要", "概括", "summarize", "summary", "tl;dr"}},
+	{TaskExtraction, []string{"提取", "抽取", "extract", "parse the following"}},
+	{TaskCode, []string{"```", "代码", "函数", "编程", "报错", "bug", "code", "function", "implement", "compile", "stack trace", "sql", "regex"}},
+	{TaskMath, []string{"计算", "求解", "证明", "方程", "积分", "概率", "solve", "prove", "equation", "integral", "probability"}},
+	{TaskReasoning, []string{"分析", "推理", "为什么", "比较", "权衡", "step by step", "analyze", "reason about", "why", "compare", "trade-off"}},
+}
+
+// arithmetic 匹配算式，如 "12 * 34"
+var arithmetic = regexp.MustCompile(`\d+(\.\d+)?\s*[-+*/^×÷]\s*\d+`)
+
+// Classify 是默认的 Classifier：按 token 数衡量长度，用关键词识别任务类型，
+// 消息含图片时需要 vision，设置了工具时需要 tools，开启思考模式时需要 reasoning
+func Classify(messages []spec.Message, config *spec.RequestConfig) Features {
+	f := Features{Task: TaskChat}
+	model := ""
+	if config != nil {
+		model = config.Model
+	}
+	f.Tokens = tokens.CountMessages(model, messages)
+
+	var last string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == spec.RoleUser {
+			last = strings.ToLower(messages[i].PlainText())
+			break
+		}
+	}
+	f.Task = detectTask(last)
+
+	for _, m := range messages {
+		if hasImage(m) {
+			f.Capabilities = append(f.Capabilities, spec.CapabilityVision)
+			break
+		}
+	}
+	if config != nil {
+		if len(config.Tools) > 0 {
+			f.Capabilities = append(f.Capabilities, spec.CapabilityTools)
+		}
+		if config.Thinking != nil && *config.Thinking {
+			f.Capabilities = append(f.Capabilities, spec.CapabilityReasoning)
+		}
+		if config.Translation != nil {
+			f.Task = TaskTranslation
+		}
+	}
+	return f
+}
+
+// detectTask 根据关键词识别任务类型，都不匹配时为 TaskChat
+func detectTask(text string) Task {
+	for _, k := range taskKeywords {
+		for _, kw := range k.keywords {
+			if strings.Contains(text, kw) {
+				return k.task
+			}
+		}
+	}
+	if arithmetic.MatchString(text) {
+		return TaskMath
+	}
+	return TaskChat
+}
+
+// hasImage 判断消息是否包含图片
+func hasImage(m spec.Message) bool {
+	for _, p := range m.Parts {
+		if p.ImageURL != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+// Package router 按请求的复杂度和所需能力把调用路由到不同档位的模型：
+// 简短的闲聊交给便宜的模型，长文本、代码、推理或需要图片、工具的请求交给更强的模型。
+//
+//	r, _ := router.New([]router.Tier{
+//		{Name: "cheap", Config: cheapCfg, MaxTokens: 2000, Tasks: []router.Task{router.TaskChat, router.TaskTranslation}},
+//		{Name: "premium", Config: premiumCfg},
+//	})
+//	resp, decision, err := r.Chat(ctx, messages)
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/iEvan-lhr/go-llm-client/llm"
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// ErrNoTier 表示没有任何档位具备请求所需的能力
+var ErrNoTier = errors.New("router: no tier satisfies the request")
+
+// Tier 是一个模型档位
+type Tier struct {
+	// Name 档位名称，在路由器中唯一
+	Name string
+	// Config 该档位使用的模型配置
+	Config llm.Config
+	// Capabilities 模型具备的能力，nil 时按 spec.InferCapabilities 推断，并认为支持 tools
+	Capabilities []string
+	// MaxTokens 该档位处理的最大输入 token 数，0 表示不限制
+	MaxTokens int
+	// Tasks 该档位处理的任务类型，nil 表示全部
+	Tasks []Task
+}
+
+// supports 判断档位是否能处理具有特征 f 的请求
+func (t *Tier) supports(f Features) bool {
+	if t.MaxTokens > 0 && f.Tokens > t.MaxTokens {
+		return false
+	}
+	if t.Tasks != nil && !slices.Contains(t.Tasks, f.Task) {
+		return false
+	}
+	return t.capable(f)
+}
+
+// capable 判断档位是否具备请求所需的全部能力
+func (t *Tier) capable(f Features) bool {
+	caps := t.Capabilities
+	if caps == nil {
+		caps = append(spec.InferCapabilities(t.Config.Model), spec.CapabilityTools)
+	}
+	for _, c := range f.Capabilities {
+		if !slices.Contains(caps, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decision 记录一次路由决策
+type Decision struct {
+	// Tier 选中的档位名称
+	Tier string
+	// Config 选中档位的模型配置
+	Config llm.Config
+	// Features 请求的特征
+	Features Features
+	// Reason 选择该档位的原因
+	Reason string
+	// Overridden 是否由覆盖规则或 WithTier 指定
+	Overridden bool
+}
+
+// Stats 是一个档位的路由统计
+type Stats struct {
+	// Requests 路由到该档位的请求数
+	Requests int
+	// Overrides 其中由覆盖规则指定的请求数
+	Overrides int
+	// Errors 调用失败的次数，仅统计通过 Router.Chat 发出的调用
+	Errors int
+	// Usage 累计 token 用量，仅统计通过 Router.Chat 发出的调用
+	Usage spec.Usage
+	// Tasks 按任务类型统计的请求数
+	Tasks map[Task]int
+}
+
+// Router 根据请求特征选择档位，可并发使用
+type Router struct {
+	tiers     []Tier
+	classify  Classifier
+	overrides map[Task]string
+	hooks     []func(Decision)
+	chatOpts  []spec.Option
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// Option 用于配置 Router
+type Option func(r *Router)
+
+// WithClassifier 替换默认的 Classify
+func WithClassifier(c Classifier) Option {
+	return func(r *Router) {
+		r.classify = c
+	}
+}
+
+// WithOverride 把 task 类型的请求固定路由到名为 tier 的档位（能力不足时仍会升级）
+func WithOverride(task Task, tier string) Option {
+	return func(r *Router) {
+		r.overrides[task] = tier
+	}
+}
+
+// WithDecisionHook 注册在每次路由决策后调用的回调，可用于记录日志或上报监控
+func WithDecisionHook(hook func(Decision)) Option {
+	return func(r *Router) {
+		r.hooks = append(r.hooks, hook)
+	}
+}
+
+// WithChatOptions 为 Router.Chat 的每次调用附加选项，如 spec.WithTemperature
+func WithChatOptions(opts ...spec.Option) Option {
+	return func(r *Router) {
+		r.chatOpts = append(r.chatOpts, opts...)
+	}
+}
+
+// New 创建 Router，tiers 按从便宜到昂贵的顺序排列
+func New(tiers []Tier, opts ...Option) (*Router, error) {
+	if len(tiers) == 0 {
+		return nil, errors.New("router: at least one tier is required")
+	}
+	r := &Router{
+		tiers:     slices.Clone(tiers),
+		classify:  Classify,
+		overrides: make(map[Task]string),
+		stats:     make(map[string]*Stats),
+	}
+	for i, t := range r.tiers {
+		if t.Name == "" {
+			return nil, fmt.Errorf("router: tier %d has no name", i)
+		}
+		if r.tier(t.Name) != i {
+			return nil, fmt.Errorf("router: duplicate tier name %q", t.Name)
+		}
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for task, name := range r.overrides {
+		if r.tier(name) < 0 {
+			return nil, fmt.Errorf("router: override for task %s refers to unknown tier %q", task, name)
+		}
+	}
+	return r, nil
+}
+
+// tier 返回名为 name 的档位下标，不存在时返回 -1
+func (r *Router) tier(name string) int {
+	return slices.IndexFunc(r.tiers, func(t Tier) bool { return t.Name == name })
+}
+
+type tierKey struct{}
+
+// WithTier 返回强制使用名为 name 的档位的 ctx，优先级高于路由规则和 WithOverride
+func WithTier(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, tierKey{}, name)
+}
+
+// Route 为请求选择档位：WithTier 指定的档位优先，其次是任务类型的覆盖规则，
+// 否则选择第一个（最便宜的）满足长度、任务类型和能力要求的档位；都不满足时选择具备所需能力的最贵档位。
+// opts 为本次调用的选项，用于识别工具等能力需求
+func (r *Router) Route(ctx context.Context, messages []spec.Message, opts ...spec.Option) (Decision, error) {
+	config := spec.NewRequestConfig()
+	config.Model = r.tiers[0].Config.Model
+	for _, opt := range append(slices.Clone(r.chatOpts), opts...) {
+		opt(config)
+	}
+	f := r.classify(messages, config)
+
+	d, err := r.decide(ctx, f)
+	if err != nil {
+		return d, err
+	}
+	r.record(d)
+	for _, hook := range r.hooks {
+		hook(d)
+	}
+	return d, nil
+}
+
+// decide 根据特征选择档位
+func (r *Router) decide(ctx context.Context, f Features) (Decision, error) {
+	d := Decision{Features: f}
+	choose := func(i int, reason string, overridden bool) Decision {
+		d.Tier, d.Config, d.Reason, d.Overridden = r.tiers[i].Name, r.tiers[i].Config, reason, overridden
+		return d
+	}
+
+	if name, ok := ctx.Value(tierKey{}).(string); ok {
+		i := r.tier(name)
+		if i < 0 {
+			return d, fmt.Errorf("router: unknown tier %q", name)
+		}
+		return choose(i, "forced by context", true), nil
+	}
+	if name, ok := r.overrides[f.Task]; ok {
+		// 覆盖的档位能力不足时向上寻找具备能力的档位
+		for i := r.tier(name); i < len(r.tiers); i++ {
+			if r.tiers[i].capable(f) {
+				return choose(i, fmt.Sprintf("override for task %s", f.Task), true), nil
+			}
+		}
+	}
+	for i := range r.tiers {
+		if r.tiers[i].supports(f) {
+			return choose(i, fmt.Sprintf("cheapest tier for %s task with %d tokens", f.Task, f.Tokens), false), nil
+		}
+	}
+	for i := len(r.tiers) - 1; i >= 0; i-- {
+		if r.tiers[i].capable(f) {
+			return choose(i, "no tier matched, using the most capable one", false), nil
+		}
+	}
+	return d, fmt.Errorf("%w: requires %v", ErrNoTier, f.Capabilities)
+}
+
+// Chat 路由请求并调用选中档位的模型，失败时按该档位配置的 Failover 切换后端
+func (r *Router) Chat(ctx context.Context, messages []spec.Message, opts ...spec.Option) (*spec.Response, Decision, error) {
+	d, err := r.Route(ctx, messages, opts...)
+	if err != nil {
+		return nil, d, err
+	}
+	pc, release, err := llm.AcquireClient(d.Config)
+	if err != nil {
+		r.recordResult(d.Tier, nil, err)
+		return nil, d, err
+	}
+	defer release()
+	callOpts := append(options(d.Config), r.chatOpts...)
+	callOpts = append(callOpts, opts...)
+	resp, err := llm.RunFailover(ctx, d.Config, pc, func(ctx context.Context, pc spec.Client, cfg llm.Config) (*spec.Response, error) {
+		return pc.Model(cfg.Model).Chat(ctx, messages, callOpts...)
+	})
+	r.recordResult(d.Tier, resp, err)
+	if err != nil {
+		return nil, d, fmt.Errorf("router: tier %s: %w", d.Tier, err)
+	}
+	return resp, d, nil
+}
+
+// options 构建调用 cfg 对应模型的选项
+func options(cfg llm.Config) []spec.Option {
+	var opts []spec.Option
+	if cfg.Parameters != nil {
+		opts = append(opts, spec.WithParameters(cfg.Parameters))
+	}
+	if cfg.Thinking != nil {
+		opts = append(opts, spec.WithThinking(*cfg.Thinking))
+	}
+	if cfg.StreamCallback != nil {
+		opts = append(opts, spec.WithStreamCallback(cfg.StreamCallback))
+	}
+	return opts
+}
+
+// record 记录一次路由决策
+func (r *Router) record(d Decision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.statsFor(d.Tier)
+	s.Requests++
+	s.Tasks[d.Features.Task]++
+	if d.Overridden {
+		s.Overrides++
+	}
+}
+
+// recordResult 记录一次调用的结果
+func (r *Router) recordResult(tier string, resp *spec.Response, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.statsFor(tier)
+	if err != nil {
+		s.Errors++
+		return
+	}
+	s.Usage = s.Usage.Add(resp.Usage)
+}
+
+// statsFor 返回档位的统计，调用方需持有锁
+func (r *Router) statsFor(tier string) *Stats {
+	s, ok := r.stats[tier]
+	if !ok {
+		s = &Stats{Tasks: make(map[Task]int)}
+		r.stats[tier] = s
+	}
+	return s
+}
+
+// Stats 返回各档位的路由统计，键为档位名称
+func (r *Router) Stats() map[string]Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]Stats, len(r.stats))
+	for name, s := range r.stats {
+		c := *s
+		c.Tasks = maps.Clone(s.Tasks)
+		out[name] = c
+	}
+	return out
+}
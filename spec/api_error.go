@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 常见错误类别，可通过 errors.Is 判断，例如 errors.Is(err, spec.ErrRateLimited)。
+var (
+	// ErrRateLimited 请求被限流或配额不足
+	ErrRateLimited = errors.New("rate limited")
+	// ErrAuth API Key 无效、过期或没有权限
+	ErrAuth = errors.New("authentication failed")
+	// ErrContextLengthExceeded 输入超出模型的上下文长度
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	// ErrContentFiltered 输入或输出被内容安全策略拦截
+	ErrContentFiltered = errors.New("content filtered")
+	// ErrModelNotFound 模型不存在或当前账号无权使用
+	ErrModelNotFound = errors.New("model not found")
+)
+
+// APIError 表示服务端返回了非 2xx 状态码。
+// 可通过 errors.As 获取状态码、提供商错误码等详细信息，也可通过 errors.Is 判断错误类别。
+type APIError struct {
+	// StatusCode HTTP 状态码
+	StatusCode int
+	// Code 提供商返回的错误码，例如 "context_length_exceeded"、"Throttling.RateQuota"
+	Code string
+	// Type 提供商返回的错误类型，例如 OpenAI 的 "invalid_request_error"
+	Type string
+	// Message 提供商返回的错误描述，无法解析时为原始响应体
+	Message string
+	// RequestID 提供商返回的请求 ID，便于向提供商提交工单
+	RequestID string
+	// ClientRequestID 客户端生成并通过 X-Request-ID 发送的请求 ID
+	ClientRequestID string
+	// Body 原始响应体
+	Body []byte
+	// Header 响应头
+	Header http.Header
+	// Kind 错误类别，为上面的 Err* 之一，无法归类时为 nil
+	Kind error
+}
+
+// NewAPIError 根据状态码和响应体创建 APIError，并按状态码和内容进行初步归类。
+func NewAPIError(statusCode int, body []byte, header http.Header) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Message:    strings.TrimSpace(string(body)),
+		Body:       body,
+		Header:     header,
+	}
+	e.Classify()
+	return e
+}
+
+func (e *APIError) Error() string {
+	if e.ClientRequestID != "" {
+		return fmt.Sprintf("API error (status %d, request %s): %s", e.StatusCode, e.ClientRequestID, string(e.Body))
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, string(e.Body))
+}
+
+// Is 使 errors.Is(err, ErrRateLimited) 等判断可以匹配到对应类别的 APIError
+func (e *APIError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// Classify 根据状态码、错误码和错误描述重新计算 Kind，
+// 在补充了 Code / Message 等字段后调用可以得到更准确的结果。
+func (e *APIError) Classify() {
+	text := strings.ToLower(e.Code + " " + e.Type + " " + e.Message)
+	contains := func(keywords ...string) bool {
+		for _, k := range keywords {
+			if strings.Contains(text, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case contains("context_length_exceeded", "maximum context length", "context length", "range of input length", "too many tokens", "prompt is too long"):
+		e.Kind = ErrContextLengthExceeded
+	case contains("content_filter", "content_policy", "data_inspection_failed", "datainspectionfailed", "inappropriate content", "moderation", "content management policy"):
+		e.Kind = ErrContentFiltered
+	case e.StatusCode == http.StatusTooManyRequests || contains("rate_limit", "rate limit", "throttling", "insufficient_quota"):
+		e.Kind = ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || contains("invalid_api_key", "invalidapikey", "incorrect api key"):
+		e.Kind = ErrAuth
+	case contains("model_not_found", "model not found", "model not exist", "model does not exist", "modelnotfound") ||
+		(e.StatusCode == http.StatusNotFound && contains("model")):
+		e.Kind = ErrModelNotFound
+	default:
+		e.Kind = nil
+	}
+}
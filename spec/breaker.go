@@ -0,0 +1,42 @@
+package spec
+
+import "time"
+
+// CircuitBreakerConfig 配置按主机（协议和主机名）划分的熔断器。
+//
+// 在统计窗口内请求数达到 MinRequests 且失败率达到 FailureRate 后熔断器打开，
+// 之后的请求直接返回 ErrCircuitOpen 而不再访问服务端；经过 OpenTimeout 后进入半开状态，
+// 放行最多 HalfOpenProbes 个探测请求，全部成功则恢复，任一失败则重新打开。
+// 只有网络错误、超时和 5xx 会被计为失败，4xx（包括 429）不会触发熔断。
+type CircuitBreakerConfig struct {
+	// FailureRate 触发熔断的失败率 (0~1]
+	FailureRate float64
+	// MinRequests 统计窗口内至少需要的请求数，避免少量请求误触发
+	MinRequests int
+	// Window 统计窗口长度，窗口结束后计数清零
+	Window time.Duration
+	// OpenTimeout 熔断打开后等待多久进入半开状态
+	OpenTimeout time.Duration
+	// HalfOpenProbes 半开状态下允许的探测请求数
+	HalfOpenProbes int
+}
+
+// DefaultCircuitBreakerConfig 返回默认的熔断配置：
+// 1 分钟窗口内至少 10 个请求且失败率达到 50% 时熔断，30 秒后放行 1 个探测请求。
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureRate:    0.5,
+		MinRequests:    10,
+		Window:         time.Minute,
+		OpenTimeout:    30 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
+
+// WithCircuitBreaker 为客户端开启熔断。熔断状态按请求的主机分别维护，
+// 同一个 provider 客户端的所有请求共享同一组熔断器。
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientOption {
+	return func(c *ClientConfig) {
+		c.CircuitBreaker = &cfg
+	}
+}
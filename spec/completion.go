@@ -0,0 +1,15 @@
+package spec
+
+import "context"
+
+// Completer 文本补全（prompt-style，/v1/completions）接口，用于只提供补全端点的自部署后端和基座模型。
+// 返回的 Response.Message.Content 为补全的文本，Temperature、MaxTokens、TopP、流式等选项与 Chat 一致，
+// stop、echo、suffix 等补全专有参数通过 WithParameter 传入。
+type Completer interface {
+	Complete(ctx context.Context, prompt string, opts ...Option) (*Response, error)
+}
+
+// CompleterProvider 由支持文本补全的 Client 实现，用于获取指定模型的 Completer
+type CompleterProvider interface {
+	Completer(model string) Completer
+}
@@ -0,0 +1,17 @@
+package spec
+
+// defaultCompressionMinSize 是默认开始压缩的请求体大小
+const defaultCompressionMinSize = 1024
+
+// WithRequestCompression 开启请求体 gzip 压缩，并声明 Accept-Encoding: gzip。
+// 只有不小于 minSize 字节的请求体会被压缩，minSize <= 0 时使用 1KB；
+// gzip 压缩的响应（包括流式响应）会被透明解压。
+// 需要服务端支持 Content-Encoding: gzip 的请求体，常见于自建的 vLLM 网关等私有部署。
+func WithRequestCompression(minSize int) ClientOption {
+	return func(c *ClientConfig) {
+		if minSize <= 0 {
+			minSize = defaultCompressionMinSize
+		}
+		c.CompressionMinSize = minSize
+	}
+}
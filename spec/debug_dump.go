@@ -0,0 +1,228 @@
+package spec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// debugDumpEnabled 是所有 DebugDump 共用的总开关，默认开启
+var debugDumpEnabled atomic.Bool
+
+func init() {
+	debugDumpEnabled.Store(true)
+}
+
+// SetDebugDump 在运行时开启或关闭所有 DebugDump 的输出，不需要重建客户端
+func SetDebugDump(enabled bool) {
+	debugDumpEnabled.Store(enabled)
+}
+
+// DebugDump 把每次 HTTP 请求和响应写入目录中以时间戳命名的文件，用于排查私有网关改写请求体等问题。
+// 鉴权请求头和 URL 中的 key 参数会被遮盖，流式响应在读取的同时逐块写入，文件内容与调用方收到的一致。
+// 每次交换生成两个文件：
+//
+//	20261018T150405.123456-000001.request.http
+//	20261018T150405.123456-000001.response.http
+//
+// 写文件失败不会影响请求本身。
+type DebugDump struct {
+	// Dir 输出目录，不存在时自动创建
+	Dir string
+
+	disabled atomic.Bool
+	seq      atomic.Uint64
+}
+
+// NewDebugDump 创建输出到 dir 的 DebugDump，默认开启
+func NewDebugDump(dir string) *DebugDump {
+	return &DebugDump{Dir: dir}
+}
+
+// SetEnabled 在运行时开启或关闭输出，SetDebugDump 关闭时始终不输出
+func (d *DebugDump) SetEnabled(enabled bool) {
+	d.disabled.Store(!enabled)
+}
+
+// Enabled 返回当前是否输出
+func (d *DebugDump) Enabled() bool {
+	return !d.disabled.Load() && debugDumpEnabled.Load()
+}
+
+// WithDebugDump 把客户端的每次请求和响应写入 dir，见 DebugDump
+func WithDebugDump(dir string) ClientOption {
+	return WithDebugDumper(NewDebugDump(dir))
+}
+
+// WithDebugDumper 使用指定的 DebugDump，便于通过 SetEnabled 单独控制该客户端的输出
+func WithDebugDumper(d *DebugDump) ClientOption {
+	return WithInterceptors(d.Interceptor())
+}
+
+// Interceptor 返回写入请求和响应的拦截器，也可以直接放入 llm.Config.Interceptors
+func (d *DebugDump) Interceptor() Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if !d.Enabled() {
+				return next(req)
+			}
+			prefix, err := d.prefix()
+			if err != nil {
+				return next(req)
+			}
+			d.write(prefix+".request.http", dumpRequest(req))
+
+			start := time.Now()
+			resp, err := next(req)
+			if err != nil {
+				d.write(prefix+".response.http", []byte(fmt.Sprintf("error after %s: %v\n", time.Since(start), err)))
+				return nil, err
+			}
+			f, ferr := os.OpenFile(filepath.Join(d.Dir, prefix+".response.http"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if ferr != nil {
+				return resp, nil
+			}
+			f.Write(dumpResponseHead(resp, time.Since(start)))
+			resp.Body = &dumpBody{ReadCloser: resp.Body, file: f}
+			return resp, nil
+		}
+	}
+}
+
+// prefix 创建输出目录并返回本次交换的文件名前缀
+func (d *DebugDump) prefix() (string, error) {
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%06d", time.Now().Format("20060102T150405.000000"), d.seq.Add(1)), nil
+}
+
+// write 写入一个完整的文件，忽略错误
+func (d *DebugDump) write(name string, data []byte) {
+	_ = os.WriteFile(filepath.Join(d.Dir, name), data, 0o600)
+}
+
+// dumpRequest 以 HTTP 报文的格式输出请求，gzip 压缩的请求体会被解压
+func dumpRequest(req *http.Request) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", req.Method, redactURL(req.URL))
+	writeHeader(&buf, req.Header)
+	buf.WriteString("\n")
+
+	body, err := requestBody(req)
+	if err != nil {
+		fmt.Fprintf(&buf, "(failed to read body: %v)\n", err)
+		return buf.Bytes()
+	}
+	if strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		if zr, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if plain, err := io.ReadAll(zr); err == nil {
+				body = plain
+			}
+		}
+	}
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// requestBody 读取请求体而不消耗它
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+// dumpResponseHead 输出响应的状态行和响应头，响应体随读取写入
+func dumpResponseHead(resp *http.Response, elapsed time.Duration) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", resp.Proto, resp.Status)
+	fmt.Fprintf(&buf, "# elapsed %s\n", elapsed)
+	writeHeader(&buf, resp.Header)
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// writeHeader 按名称排序输出遮盖后的请求头
+func writeHeader(w io.Writer, h http.Header) {
+	h = RedactHeaders(h)
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range h[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, v)
+		}
+	}
+}
+
+// redactURL 遮盖 URL 查询参数中的密钥（如 Gemini 风格的 ?key=）
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	changed := false
+	for _, name := range []string{"key", "api_key", "apikey", "access_token"} {
+		if v := q.Get(name); v != "" {
+			q.Set(name, RedactSecret(v))
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+// dumpBody 在响应体被读取时同步写入文件，流式响应的每个数据块都会按到达顺序写入
+type dumpBody struct {
+	io.ReadCloser
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (b *dumpBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	if b.file != nil && n > 0 {
+		b.file.Write(p[:n])
+	}
+	if err != nil && err != io.EOF && b.file != nil {
+		fmt.Fprintf(b.file, "\n(read error: %v)\n", err)
+	}
+	b.mu.Unlock()
+	return n, err
+}
+
+func (b *dumpBody) Close() error {
+	b.mu.Lock()
+	if b.file != nil {
+		b.file.Close()
+		b.file = nil
+	}
+	b.mu.Unlock()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// DryRunRequest 是 dry run 模式下将要发送的 HTTP 请求。
+// Header 中的鉴权信息已脱敏，Body 为未压缩的请求体（图像编辑等接口为 multipart 表单，其余为 JSON）。
+type DryRunRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   json.RawMessage
+}
+
+// DryRunError 表示请求因 dry run 模式在发出前中止。
+// Provider 会将其转换为携带 DryRun 的 Response，只有直接使用底层 Requester 时才会遇到它。
+type DryRunError struct {
+	Request *DryRunRequest
+}
+
+func (e *DryRunError) Error() string {
+	return "dry run: request to " + e.Request.URL + " was not sent"
+}
+
+type dryRunKey struct{}
+
+// WithDryRun 开启 dry run 模式：请求在发出 HTTP 调用前中止，
+// 返回的 Response.DryRun 中包含将要发送的 URL、请求头（已脱敏）和 JSON 请求体，
+// 便于调试各 Provider 的请求适配逻辑（例如 /no_think 的注入）。
+func WithDryRun() Option {
+	return func(r *RequestConfig) {
+		r.DryRun = true
+	}
+}
+
+// ContextWithDryRun 返回标记为 dry run 的上下文
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun 判断上下文是否处于 dry run 模式
+func IsDryRun(ctx context.Context) bool {
+	v, _ := ctx.Value(dryRunKey{}).(bool)
+	return v
+}
+
+// DryRunResult 处理请求返回的错误：dry run 中止时返回携带请求内容的 Response，否则原样返回 err
+func (r *RequestConfig) DryRunResult(err error) (*Response, error) {
+	var dr *DryRunError
+	if errors.As(err, &dr) {
+		return &Response{
+			Timing:    r.Timing(),
+			RequestID: r.RequestID,
+			DryRun:    dr.Request,
+		}, nil
+	}
+	return nil, err
+}
@@ -1,6 +1,9 @@
 package spec
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // Embedded 定义了支持向量化能力的方法集
 // 采用可选接口设计，不强制所有的 Model 都必须实现
@@ -28,3 +31,93 @@ type EmbeddingUsage struct {
 	PromptTokens int `json:"prompt_tokens"`
 	TotalTokens  int `json:"total_tokens"`
 }
+
+// Embedder 批量文本向量化接口，返回的向量与 texts 按顺序一一对应。
+// 输入条数超过提供商单次请求的上限时会自动分批发送。
+type Embedder interface {
+	Embed(ctx context.Context, texts []string, opts ...EmbedOption) ([][]float32, error)
+}
+
+// EmbedderProvider 由支持向量化的 Client 实现，用于获取指定模型的 Embedder
+type EmbedderProvider interface {
+	Embedder(model string) Embedder
+}
+
+// EmbedOption 用于配置单次向量化调用
+type EmbedOption func(c *EmbedConfig)
+
+// EmbedConfig 存储了单次向量化调用的配置
+type EmbedConfig struct {
+	// Dimensions 输出向量的维度，0 表示使用模型默认值
+	Dimensions int
+	// BatchSize 单次请求的最大条数，0 表示使用提供商的上限
+	BatchSize int
+	// Parameters 透传到请求体的额外参数
+	Parameters map[string]any
+}
+
+// NewEmbedConfig 应用选项并返回配置
+func NewEmbedConfig(opts ...EmbedOption) *EmbedConfig {
+	c := &EmbedConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithDimensions 设置输出向量的维度，需要模型支持（如 text-embedding-3、text-embedding-v3）
+func WithDimensions(n int) EmbedOption {
+	return func(c *EmbedConfig) {
+		c.Dimensions = n
+	}
+}
+
+// WithEmbedBatchSize 设置单次请求的最大条数，超过提供商上限时以提供商上限为准
+func WithEmbedBatchSize(n int) EmbedOption {
+	return func(c *EmbedConfig) {
+		c.BatchSize = n
+	}
+}
+
+// WithEmbedParameters 设置透传到请求体的额外参数
+func WithEmbedParameters(params map[string]any) EmbedOption {
+	return func(c *EmbedConfig) {
+		c.Parameters = params
+	}
+}
+
+// EmbedBatches 将 texts 按 batchSize 分批交给 embed，并按原顺序拼接结果。
+// 每批返回的向量条数必须与输入一致。
+func EmbedBatches(ctx context.Context, texts []string, batchSize int, embed func(ctx context.Context, batch []string) ([][]float32, error)) ([][]float32, error) {
+	if batchSize <= 0 {
+		batchSize = len(texts)
+	}
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		batch, err := embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("embedding: got %d vectors for %d inputs", len(batch), end-start)
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// Vectors 按 Index 顺序返回全部向量，Index 不完整时保持响应中的顺序
+func (r *EmbeddingResponse) Vectors() [][]float32 {
+	vectors := make([][]float32, len(r.Data))
+	for _, d := range r.Data {
+		if d.Index < 0 || d.Index >= len(vectors) || vectors[d.Index] != nil {
+			for i, d := range r.Data {
+				vectors[i] = d.Embedding
+			}
+			return vectors
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors
+}
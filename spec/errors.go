@@ -0,0 +1,43 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrClientClosed 表示客户端已经被关闭，不能再发起新的请求。
+var ErrClientClosed = errors.New("llm client is closed")
+
+// ErrCircuitOpen 表示目标接口的熔断器处于打开状态，请求未被发出。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// StreamIdleTimeoutError 表示流式响应在指定时间窗口内没有收到任何数据块。
+// 可通过 errors.As 判断并决定是否重试。
+type StreamIdleTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *StreamIdleTimeoutError) Error() string {
+	return fmt.Sprintf("stream idle timeout: no chunk received within %v", e.Timeout)
+}
+
+// RetryExhaustedError 表示请求在重试策略允许的范围内始终没有成功。
+// RetryAfter 为服务端最后一次建议的等待时间（来自 Retry-After 或 x-ratelimit-reset-* 响应头），
+// 没有建议时为 0，调用方可以据此决定何时再次发起请求。
+type RetryExhaustedError struct {
+	Attempts   int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("giving up after %d attempt(s), retry after %v: %v", e.Attempts, e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("giving up after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
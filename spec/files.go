@@ -0,0 +1,58 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// 常用的文件用途，不同提供商支持的取值不同
+const (
+	// FilePurposeAssistants 供 Assistants 检索使用
+	FilePurposeAssistants = "assistants"
+	// FilePurposeBatch 批量推理的输入文件
+	FilePurposeBatch = "batch"
+	// FilePurposeFineTune 微调训练数据
+	FilePurposeFineTune = "fine-tune"
+	// FilePurposeFileExtract 长文档解析，DashScope 的 qwen-long 和 Moonshot 使用
+	FilePurposeFileExtract = "file-extract"
+	// FilePurposeUserData 通用的用户数据，例如对话中引用的 PDF
+	FilePurposeUserData = "user_data"
+)
+
+// FileStore 提供商的文件管理接口，上传的文件可用于批量推理、微调、Assistants 和长文档对话
+type FileStore interface {
+	// Upload 上传文件，purpose 为文件用途，如 FilePurposeBatch
+	Upload(ctx context.Context, filename string, data []byte, purpose string) (*File, error)
+	// List 列出已上传的文件，purpose 为空时返回全部文件
+	List(ctx context.Context, purpose string) ([]File, error)
+	// Get 查询单个文件的信息
+	Get(ctx context.Context, id string) (*File, error)
+	// Delete 删除文件
+	Delete(ctx context.Context, id string) error
+	// Content 下载文件内容，例如批量推理的结果文件
+	Content(ctx context.Context, id string) ([]byte, error)
+}
+
+// FileStoreProvider 由支持文件接口的 Client 实现
+type FileStoreProvider interface {
+	Files() FileStore
+}
+
+// File 描述提供商保存的一个文件
+type File struct {
+	// ID 文件 ID，在对话、批量推理等接口中引用
+	ID string
+	// Filename 上传时的文件名
+	Filename string
+	// Bytes 文件大小
+	Bytes int64
+	// Purpose 文件用途
+	Purpose string
+	// Status 文件状态，如 "uploaded"、"processed"、"error"，提供商未返回时为空
+	Status string
+	// CreatedAt 上传时间
+	CreatedAt time.Time
+	// Raw 提供商返回的原始 JSON
+	Raw json.RawMessage
+}
@@ -0,0 +1,91 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// FineTuneStatus 是统一后的微调任务状态，各提供商的原始状态保存在 FineTuneJob.RawStatus 中
+type FineTuneStatus string
+
+const (
+	// FineTuneQueued 任务已创建，正在校验文件或排队
+	FineTuneQueued FineTuneStatus = "queued"
+	// FineTuneRunning 任务正在训练
+	FineTuneRunning FineTuneStatus = "running"
+	// FineTuneSucceeded 训练完成，FineTunedModel 可用于对话
+	FineTuneSucceeded FineTuneStatus = "succeeded"
+	// FineTuneFailed 训练失败，原因见 FineTuneJob.Error
+	FineTuneFailed FineTuneStatus = "failed"
+	// FineTuneCancelled 任务已取消
+	FineTuneCancelled FineTuneStatus = "cancelled"
+)
+
+// Done 判断任务是否已经结束
+func (s FineTuneStatus) Done() bool {
+	return s == FineTuneSucceeded || s == FineTuneFailed || s == FineTuneCancelled
+}
+
+// FineTuner 微调任务管理接口
+type FineTuner interface {
+	// CreateJob 创建微调任务，训练文件需先通过 FileStore 上传
+	CreateJob(ctx context.Context, req FineTuneRequest) (*FineTuneJob, error)
+	// GetJob 查询任务状态
+	GetJob(ctx context.Context, id string) (*FineTuneJob, error)
+	// ListJobs 列出全部微调任务，按创建时间倒序
+	ListJobs(ctx context.Context) ([]FineTuneJob, error)
+	// CancelJob 取消进行中的任务
+	CancelJob(ctx context.Context, id string) (*FineTuneJob, error)
+}
+
+// FineTunerProvider 由支持微调的 Client 实现
+type FineTunerProvider interface {
+	FineTuner() FineTuner
+}
+
+// FineTuneRequest 创建微调任务的参数，零值字段使用提供商的默认值
+type FineTuneRequest struct {
+	// Model 基础模型，如 "gpt-4o-mini-2024-07-18"、"qwen-turbo"
+	Model string
+	// TrainingFile 训练数据的文件 ID
+	TrainingFile string
+	// ValidationFile 验证数据的文件 ID，可选
+	ValidationFile string
+	// Suffix 微调后模型名称的后缀，仅 OpenAI 支持
+	Suffix string
+	// Epochs 训练轮数
+	Epochs int
+	// BatchSize 批大小
+	BatchSize int
+	// LearningRate 学习率，OpenAI 为 learning_rate_multiplier，DashScope 为 learning_rate
+	LearningRate float64
+	// Parameters 透传给提供商的其他参数，会覆盖同名字段
+	Parameters map[string]any
+}
+
+// FineTuneJob 描述一个微调任务
+type FineTuneJob struct {
+	// ID 任务 ID
+	ID string
+	// Model 基础模型
+	Model string
+	// FineTunedModel 训练完成后得到的模型名称，任务未成功时为空
+	FineTunedModel string
+	// Status 统一后的任务状态
+	Status FineTuneStatus
+	// RawStatus 提供商返回的原始状态，如 "validating_files"、"PENDING"
+	RawStatus string
+	// TrainingFile 训练数据的文件 ID
+	TrainingFile string
+	// TrainedTokens 已训练的 token 数，提供商未返回时为 0
+	TrainedTokens int
+	// Error 任务失败的原因
+	Error string
+	// CreatedAt 任务创建时间
+	CreatedAt time.Time
+	// FinishedAt 任务结束时间，未结束时为零值
+	FinishedAt time.Time
+	// Raw 提供商返回的原始 JSON
+	Raw json.RawMessage
+}
@@ -0,0 +1,28 @@
+package spec
+
+import "time"
+
+// HedgePolicy 对冲请求策略：请求发出后 Delay 内仍未收到响应头时，
+// 再发出一个相同的备份请求，采用最先成功的响应并取消其余请求，用于降低尾延迟。
+//
+// 只有对话补全（Chat Completions 及兼容接口）请求会被对冲；文件上传、任务提交、微调、
+// Responses API 等非幂等或会创建资源的请求重复发送会产生重复的资源和费用，始终只发送一次。
+//
+// 配置了 API Key 池时备份请求会使用另一个 Key；配置了 Endpoints 时备份请求依次发往这些地址。
+type HedgePolicy struct {
+	// Delay 发出下一个备份请求前等待的时间
+	Delay time.Duration
+	// MaxHedges 最多发出的备份请求数，<= 0 时为 1
+	MaxHedges int
+	// Endpoints 备份请求使用的基础地址（如 "https://backup.example.com"），原请求的路径和查询参数会拼接在其后，
+	// 例如原请求为 https://api.example.com/v1/chat/completions 时发往 https://backup.example.com/v1/chat/completions；
+	// 为空时与原请求相同
+	Endpoints []string
+}
+
+// WithHedging 开启对冲请求。对冲会成倍消耗配额，建议只用于延迟敏感的场景。
+func WithHedging(policy HedgePolicy) ClientOption {
+	return func(c *ClientConfig) {
+		c.Hedge = &policy
+	}
+}
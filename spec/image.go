@@ -0,0 +1,103 @@
+package spec
+
+// ============== 图像编辑与变体 ==============
+
+// ImageEditConfig 图像编辑 / 变体专用配置
+type ImageEditConfig struct {
+	Mask           string // 蒙版图片（URL 或 data URL），透明区域为需要重绘的部分
+	Size           string // 输出尺寸，如 "1024x1024"（OpenAI）或 "1024*1024"（DashScope）
+	ImageCount     int    // 生成图像数量，默认 1
+	ResponseFormat string // 返回格式："url" 或 "b64_json"，为空时使用提供商默认值
+	NegativePrompt string // 负面提示词
+	Watermark      *bool  // 是否添加水印，nil 表示使用默认值
+}
+
+// ImageEditOption 图像编辑配置选项函数类型
+type ImageEditOption func(*ImageEditConfig)
+
+// WithImageEditMask 设置蒙版图片，用于局部重绘（inpaint）
+func WithImageEditMask(mask string) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.Mask = mask
+	}
+}
+
+// WithImageEditSize 设置输出尺寸
+func WithImageEditSize(size string) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.Size = size
+	}
+}
+
+// WithImageEditCount 设置生成图像数量
+func WithImageEditCount(count int) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.ImageCount = count
+	}
+}
+
+// WithImageEditFormat 设置返回格式："url" 或 "b64_json"
+func WithImageEditFormat(format string) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.ResponseFormat = format
+	}
+}
+
+// WithImageEditNegativePrompt 设置负面提示词
+func WithImageEditNegativePrompt(negativePrompt string) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.NegativePrompt = negativePrompt
+	}
+}
+
+// WithImageEditWatermark 设置是否添加水印
+func WithImageEditWatermark(enable bool) ImageEditOption {
+	return func(cfg *ImageEditConfig) {
+		cfg.Watermark = &enable
+	}
+}
+
+// Parameters 将配置转换为请求参数，由 Provider 按各自的接口格式读取
+func (cfg *ImageEditConfig) Parameters() map[string]any {
+	params := make(map[string]any)
+	if cfg.Mask != "" {
+		params["mask"] = cfg.Mask
+	}
+	if cfg.Size != "" {
+		params["size"] = cfg.Size
+	}
+	if cfg.ImageCount > 0 {
+		params["n"] = cfg.ImageCount
+	}
+	if cfg.ResponseFormat != "" {
+		params["response_format"] = cfg.ResponseFormat
+	}
+	if cfg.NegativePrompt != "" {
+		params["negative_prompt"] = cfg.NegativePrompt
+	}
+	if cfg.Watermark != nil {
+		params["watermark"] = *cfg.Watermark
+	}
+	return params
+}
+
+// WithImageEdit 将本次调用标记为图像编辑：
+// 最后一条用户消息中的图片（Parts 中的 image_url）为待编辑的图片，文本为编辑指令。
+func WithImageEdit() Option {
+	return func(r *RequestConfig) {
+		r.imageEdit = true
+	}
+}
+
+// WithImageVariation 将本次调用标记为图像变体：
+// 基于最后一条用户消息中的第一张图片生成相似的新图片，不需要文本指令。
+func WithImageVariation() Option {
+	return func(r *RequestConfig) {
+		r.imageVariation = true
+	}
+}
+
+// IsImageVariation 判断本次调用是否为图像变体
+func (r *RequestConfig) IsImageVariation() bool {
+	return r.imageVariation
+}
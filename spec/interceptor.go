@@ -0,0 +1,33 @@
+package spec
+
+import "net/http"
+
+// RoundTripFunc 发送一个 HTTP 请求并返回响应。
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor 是请求/响应中间件，包装下一个 RoundTripFunc。
+// 可用于刷新鉴权、审计日志、注入请求头等，无需修改 provider 代码。
+// 拦截器作用于每一次实际发出的 HTTP 请求（包括重试），拦截器内读取响应体后需要重新设置 resp.Body。
+//
+//	func AddHeader(next spec.RoundTripFunc) spec.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Tenant", "demo")
+//			return next(req)
+//		}
+//	}
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// WithInterceptors 注册一个或多个拦截器，先注册的位于外层，最先看到请求、最后看到响应。
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *ClientConfig) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
+
+// ChainInterceptors 将拦截器依次包装在 base 之外，interceptors[0] 位于最外层。
+func ChainInterceptors(base RoundTripFunc, interceptors ...Interceptor) RoundTripFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		base = interceptors[i](base)
+	}
+	return base
+}
@@ -1,13 +1,21 @@
 package spec
 
-import "context"
+import (
+	"context"
+	"iter"
+)
 
 // Model 是一个具体LLM模型的抽象接口。
 type Model interface {
 	Chat(ctx context.Context, messages []Message, opts ...Option) (*Response, error)
+	// ChatSeq 以迭代器形式返回流式事件，可直接用于 for range 循环。
+	ChatSeq(ctx context.Context, messages []Message, opts ...Option) iter.Seq2[StreamEvent, error]
 }
 
 // Client 是与特定LLM提供商交互的顶层客户端。
 type Client interface {
 	Model(name string) Model
+	// Close 取消所有进行中的请求（包括流式请求）并释放空闲的 HTTP 连接。
+	// 关闭后再发起的请求会返回 ErrClientClosed。
+	Close() error
 }
@@ -0,0 +1,36 @@
+package spec
+
+import "time"
+
+// KeySelection 决定 API Key 池中每次请求使用哪个 Key。
+type KeySelection int
+
+const (
+	// KeyRoundRobin 依次轮询，跳过处于冷却期的 Key
+	KeyRoundRobin KeySelection = iota
+	// KeyLeastRecentlyThrottled 优先使用最久没有被限流的 Key
+	KeyLeastRecentlyThrottled
+)
+
+// APIKeyPool 配置同一个提供商的多个 API Key。
+// 每次请求（包括重试）都会从池中选择一个 Key 写入 "Authorization: Bearer <key>" 请求头；
+// 某个 Key 收到 429 后进入冷却期，冷却期内不会被选中，除非所有 Key 都在冷却。
+type APIKeyPool struct {
+	Keys     []string
+	Strategy KeySelection
+	// Cooldown 收到 429 后的冷却时间，服务端通过 Retry-After 等响应头给出更长时间时以响应头为准；
+	// 为 0 时默认 1 分钟
+	Cooldown time.Duration
+}
+
+// WithAPIKeyPool 使用多个 API Key 进行负载均衡，用于突破单个 Key 的 QPM 限制。
+// 未单独设置 APIKey 时，池中第一个 Key 同时作为 APIKey。
+func WithAPIKeyPool(pool APIKeyPool) ClientOption {
+	return func(c *ClientConfig) {
+		pool.Keys = append([]string(nil), pool.Keys...)
+		c.APIKeyPool = &pool
+		if c.APIKey == "" && len(pool.Keys) > 0 {
+			c.APIKey = pool.Keys[0]
+		}
+	}
+}
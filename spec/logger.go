@@ -0,0 +1,132 @@
+package spec
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Logger 是库内部使用的分级日志接口，参数为 slog 风格的键值对。
+// *slog.Logger 直接实现了该接口，未设置时使用 slog.Default()。
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// WithLogger 设置客户端使用的日志记录器。
+// 记录器会被包装为自动脱敏的版本，Authorization 等请求头和已配置的 API Key 不会出现在日志中。
+func WithLogger(l Logger) ClientOption {
+	return func(c *ClientConfig) {
+		c.Logger = l
+	}
+}
+
+// NopLogger 返回一个丢弃所有日志的记录器
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// sensitiveHeaders 是需要脱敏的请求头
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "X-Api-Key", "Api-Key", "Cookie", "Set-Cookie"}
+
+// RedactSecret 遮盖密钥，只保留首尾少量字符便于辨认，例如 "sk-abc...wxyz" 变为 "sk-a****wxyz"。
+func RedactSecret(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return secret[:4] + "****" + secret[len(secret)-4:]
+}
+
+// RedactHeaders 返回请求头的副本，其中的鉴权信息已被遮盖。
+func RedactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		values := out.Values(name)
+		for i, v := range values {
+			if scheme, token, ok := strings.Cut(v, " "); ok {
+				values[i] = scheme + " " + RedactSecret(token)
+			} else {
+				values[i] = RedactSecret(v)
+			}
+		}
+	}
+	return out
+}
+
+// NewRedactingLogger 包装 l，在输出前遮盖消息和参数中出现的 secrets 以及请求头中的鉴权信息。
+// l 为 nil 时使用 slog.Default()。
+func NewRedactingLogger(l Logger, secrets ...string) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	r := &redactingLogger{next: l}
+	for _, s := range secrets {
+		if s != "" {
+			r.replacements = append(r.replacements, s, RedactSecret(s))
+		}
+	}
+	if len(r.replacements) > 0 {
+		r.replacer = strings.NewReplacer(r.replacements...)
+	}
+	return r
+}
+
+type redactingLogger struct {
+	next         Logger
+	replacements []string
+	replacer     *strings.Replacer
+}
+
+func (r *redactingLogger) Debug(msg string, args ...any) {
+	msg, args = r.redact(msg, args)
+	r.next.Debug(msg, args...)
+}
+
+func (r *redactingLogger) Info(msg string, args ...any) {
+	msg, args = r.redact(msg, args)
+	r.next.Info(msg, args...)
+}
+
+func (r *redactingLogger) Warn(msg string, args ...any) {
+	msg, args = r.redact(msg, args)
+	r.next.Warn(msg, args...)
+}
+
+func (r *redactingLogger) Error(msg string, args ...any) {
+	msg, args = r.redact(msg, args)
+	r.next.Error(msg, args...)
+}
+
+// redact 遮盖消息与参数中的敏感信息
+func (r *redactingLogger) redact(msg string, args []any) (string, []any) {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case http.Header:
+			out[i] = RedactHeaders(v)
+		case string:
+			out[i] = r.replace(v)
+		case error:
+			out[i] = r.replace(v.Error())
+		default:
+			out[i] = arg
+		}
+	}
+	return r.replace(msg), out
+}
+
+func (r *redactingLogger) replace(s string) string {
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
@@ -25,6 +25,10 @@ type Message struct {
 	// 【新增】ReasoningContent 用于存储模型返回的思考过程或工具调用信息。
 	// `omitempty` 表示如果该字段为空，则在序列化为JSON时忽略它。
 	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// ToolCalls 助手消息中模型请求执行的函数调用
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID 工具结果消息（RoleTool）对应的函数调用 ID
+	ToolCallID string `json:"tool_call_id,omitempty"`
 }
 
 // NewSystemMessage 创建一条系统消息
@@ -55,8 +59,10 @@ type ContentPart struct {
 
 func (m *Message) MarshalJSON() ([]byte, error) {
 	type alias struct {
-		Role    Role `json:"role"`
-		Content any  `json:"content"`
+		Role       Role       `json:"role"`
+		Content    any        `json:"content"`
+		ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+		ToolCallID string     `json:"tool_call_id,omitempty"`
 	}
 
 	var content any
@@ -67,15 +73,19 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 	}
 
 	return json.Marshal(alias{
-		Role:    m.Role,
-		Content: content,
+		Role:       m.Role,
+		Content:    content,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
 	})
 }
 
 func (m *Message) UnmarshalJSON(data []byte) error {
 	var raw struct {
-		Role    Role            `json:"role"`
-		Content json.RawMessage `json:"content"`
+		Role       Role            `json:"role"`
+		Content    json.RawMessage `json:"content"`
+		ToolCalls  []ToolCall      `json:"tool_calls"`
+		ToolCallID string          `json:"tool_call_id"`
 	}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -83,6 +93,8 @@ func (m *Message) UnmarshalJSON(data []byte) error {
 	}
 
 	m.Role = raw.Role
+	m.ToolCalls = raw.ToolCalls
+	m.ToolCallID = raw.ToolCallID
 
 	if len(raw.Content) == 0 || string(raw.Content) == "null" {
 		return nil
@@ -152,6 +164,37 @@ func NewImageFilePart(path, mimeType string) (ContentPart, error) {
 	return NewImageBytesPart(mimeType, data), nil
 }
 
+// Clone 返回消息的深拷贝，Parts、ImageURL 和 ToolCalls 不会与原消息共享底层内存。
+func (m Message) Clone() Message {
+	if m.Parts != nil {
+		parts := make([]ContentPart, len(m.Parts))
+		for i, p := range m.Parts {
+			if p.ImageURL != nil {
+				img := *p.ImageURL
+				p.ImageURL = &img
+			}
+			parts[i] = p
+		}
+		m.Parts = parts
+	}
+	if m.ToolCalls != nil {
+		m.ToolCalls = append([]ToolCall(nil), m.ToolCalls...)
+	}
+	return m
+}
+
+// CloneMessages 返回消息列表的深拷贝
+func CloneMessages(msgs []Message) []Message {
+	if msgs == nil {
+		return nil
+	}
+	out := make([]Message, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.Clone()
+	}
+	return out
+}
+
 // PlainText 如果你还想兼容 SendText 这种调用，可以加一个取纯文本的方法
 func (m *Message) PlainText() string {
 	if m.Content != "" {
@@ -165,3 +208,14 @@ func (m *Message) PlainText() string {
 	}
 	return sb.String()
 }
+
+// ImageInputs 返回消息中的所有图片地址（URL 或 data URL）
+func (m Message) ImageInputs() []string {
+	var images []string
+	for _, p := range m.Parts {
+		if p.ImageURL != nil && p.ImageURL.URL != "" {
+			images = append(images, p.ImageURL.URL)
+		}
+	}
+	return images
+}
@@ -0,0 +1,144 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 模型能力标识，用于 ModelInfo.Capabilities
+const (
+	CapabilityChat          = "chat"
+	CapabilityVision        = "vision"
+	CapabilityTools         = "tools"
+	CapabilityReasoning     = "reasoning"
+	CapabilityEmbedding     = "embedding"
+	CapabilityImage         = "image"
+	CapabilityTranscription = "transcription"
+	CapabilityModeration    = "moderation"
+)
+
+// ModelLister 由支持列出可用模型的 Client 实现
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+}
+
+// ModelInfo 描述提供商返回的一个可用模型
+type ModelInfo struct {
+	// ID 模型名称，可直接用于 Config.Model
+	ID string
+	// OwnedBy 模型所属的组织，提供商未返回时为空
+	OwnedBy string
+	// Created 模型的发布时间，提供商未返回时为零值
+	Created time.Time
+	// ContextWindow 上下文窗口大小（token），未知时为 0
+	ContextWindow int
+	// MaxOutputTokens 单次最多输出的 token 数，未知时为 0
+	MaxOutputTokens int
+	// Capabilities 模型支持的能力，如 CapabilityChat、CapabilityVision。
+	// 提供商返回了能力信息时以其为准，否则根据模型名称推断
+	Capabilities []string
+	// Raw 提供商返回的该模型的原始 JSON
+	Raw json.RawMessage
+}
+
+// Has 判断模型是否具备指定能力
+func (m ModelInfo) Has(capability string) bool {
+	return slices.Contains(m.Capabilities, capability)
+}
+
+// FindModel 在模型列表中按 ID 查找模型
+func FindModel(models []ModelInfo, id string) (ModelInfo, bool) {
+	for _, m := range models {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// ParseModelList 解析 OpenAI 兼容的 /models 响应：
+//
+//	{"object": "list", "data": [{"id": "...", "created": 1686935002, "owned_by": "..."}]}
+//
+// 同时识别 vLLM 等部署返回的 max_model_len 和部分网关返回的 context_length 作为上下文窗口。
+func ParseModelList(raw []byte) ([]ModelInfo, error) {
+	var body struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal model list: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(body.Data))
+	for _, item := range body.Data {
+		var m struct {
+			ID            string `json:"id"`
+			Created       int64  `json:"created"`
+			OwnedBy       string `json:"owned_by"`
+			ContextLength int    `json:"context_length"`
+			MaxModelLen   int    `json:"max_model_len"`
+		}
+		if err := json.Unmarshal(item, &m); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal model entry: %w", err)
+		}
+		info := ModelInfo{
+			ID:            m.ID,
+			OwnedBy:       m.OwnedBy,
+			ContextWindow: max(m.ContextLength, m.MaxModelLen),
+			Capabilities:  InferCapabilities(m.ID),
+			Raw:           item,
+		}
+		if m.Created > 0 {
+			info.Created = time.Unix(m.Created, 0)
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// capabilityKeywords 按模型名称中的关键字推断非对话类模型的能力，先匹配的优先
+var capabilityKeywords = []struct {
+	keywords   []string
+	capability string
+}{
+	{[]string{"embedding", "embed"}, CapabilityEmbedding},
+	{[]string{"moderation"}, CapabilityModeration},
+	{[]string{"whisper", "transcribe", "paraformer", "sensevoice", "asr"}, CapabilityTranscription},
+	{[]string{"dall-e", "gpt-image", "wanx", "wan2", "qwen-image", "flux", "stable-diffusion"}, CapabilityImage},
+}
+
+// InferCapabilities 根据模型名称推断模型能力，用于提供商未返回能力信息的场景。
+// 推断结果仅供参考，不能保证准确。
+func InferCapabilities(id string) []string {
+	name := strings.ToLower(id)
+	for _, k := range capabilityKeywords {
+		for _, kw := range k.keywords {
+			if strings.Contains(name, kw) {
+				return []string{k.capability}
+			}
+		}
+	}
+	if strings.Contains(name, "tts") || strings.Contains(name, "audio") || strings.Contains(name, "realtime") {
+		return nil
+	}
+
+	caps := []string{CapabilityChat}
+	if strings.Contains(name, "-vl") || strings.Contains(name, "vision") || strings.Contains(name, "gpt-4o") ||
+		strings.Contains(name, "gpt-4.1") || strings.Contains(name, "gpt-5") || strings.Contains(name, "qvq") {
+		caps = append(caps, CapabilityVision)
+	}
+	if strings.Contains(name, "reasoner") || strings.Contains(name, "qwq") || strings.Contains(name, "qvq") ||
+		strings.Contains(name, "thinking") || strings.Contains(name, "gpt-5") || isOSeries(name) {
+		caps = append(caps, CapabilityReasoning)
+	}
+	return caps
+}
+
+// isOSeries 判断是否为 OpenAI o 系列推理模型，如 o1、o3-mini、o4-mini
+func isOSeries(name string) bool {
+	return len(name) >= 2 && name[0] == 'o' && name[1] >= '1' && name[1] <= '9'
+}
@@ -0,0 +1,56 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Moderator 内容审核接口，返回的结果与 inputs 按顺序一一对应
+type Moderator interface {
+	Moderate(ctx context.Context, inputs []string) ([]ModerationResult, error)
+}
+
+// ModeratorProvider 由支持内容审核的 Client 实现，用于获取指定模型的 Moderator
+type ModeratorProvider interface {
+	Moderator(model string) Moderator
+}
+
+// ModerationResult 单条文本的审核结果
+type ModerationResult struct {
+	// Flagged 是否违规
+	Flagged bool
+	// Categories 命中的类别，例如 "violence"、"sexual"
+	Categories []string
+	// Scores 各类别的置信度，提供商未返回时为空
+	Scores map[string]float64
+}
+
+// ModerationError 表示输入未通过内容审核，可通过 errors.Is(err, ErrContentFiltered) 判断
+type ModerationError struct {
+	Result ModerationResult
+}
+
+func (e *ModerationError) Error() string {
+	if len(e.Result.Categories) == 0 {
+		return "content flagged by moderation"
+	}
+	return fmt.Sprintf("content flagged by moderation: %s", strings.Join(e.Result.Categories, ", "))
+}
+
+func (e *ModerationError) Is(target error) bool {
+	return target == ErrContentFiltered
+}
+
+// FlaggedCategories 返回 categories 中取值为 true 的类别，按名称排序
+func FlaggedCategories(categories map[string]bool) []string {
+	var flagged []string
+	for name, hit := range categories {
+		if hit {
+			flagged = append(flagged, name)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
@@ -2,6 +2,9 @@ package spec
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 )
@@ -19,13 +22,46 @@ type ClientConfig struct {
 	APIURL     string
 	HTTPClient *http.Client
 	Text2Image bool
+
+	// RetryPolicy 失败重试策略，nil 表示不重试
+	RetryPolicy *RetryPolicy
+	// CircuitBreaker 熔断配置，nil 表示不启用
+	CircuitBreaker *CircuitBreakerConfig
+	// Hedge 对冲请求策略，nil 表示不启用
+	Hedge *HedgePolicy
+	// APIKeyPool 多 Key 负载均衡配置，nil 表示只使用 APIKey
+	APIKeyPool *APIKeyPool
+	// RateLimit 客户端侧限流配置，nil 表示不限流
+	RateLimit *RateLimit
+	// Interceptors 请求/响应拦截器，按注册顺序由外到内执行
+	Interceptors []Interceptor
+	// Logger 日志记录器，nil 表示使用 slog.Default()
+	Logger Logger
+	// Singleflight 是否合并相同的并发请求
+	Singleflight bool
+	// ProxyURL 代理地址，为空时沿用 HTTPClient 的设置（默认读取 HTTP_PROXY 等环境变量）
+	ProxyURL string
+	// TLSConfig 自定义 TLS 配置，nil 表示沿用 HTTPClient 的设置
+	TLSConfig *tls.Config
+	// ClientCert 和 ClientKey 为 mTLS 客户端证书，可以是 PEM 文件路径或 PEM 内容
+	ClientCert string
+	ClientKey  string
+	// CompressionMinSize 开启 gzip 请求压缩的最小请求体大小，0 表示不压缩
+	CompressionMinSize int
+	// Timeouts 分阶段的超时配置
+	Timeouts Timeouts
+	// ResponsesModels 默认通过 Responses API 调用的模型，支持 "o3-pro*" 形式的前缀匹配
+	ResponsesModels []string
+	// NativeProtocol 使用提供商的原生协议而不是 OpenAI 兼容模式，目前仅 DashScope 支持
+	NativeProtocol bool
 }
 
 // NewClientConfig 创建一个带有默认值的客户端配置。
 func NewClientConfig() *ClientConfig {
 	return &ClientConfig{
-		// 设置一个合理的默认HTTP客户端
-		HTTPClient: &http.Client{Timeout: 240 * time.Second},
+		// 整体超时会截断长时间的流式响应，由 Timeouts 分阶段控制
+		HTTPClient: &http.Client{},
+		Timeouts:   DefaultTimeouts(),
 	}
 }
 
@@ -53,6 +89,14 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithNativeProtocol 使用提供商的原生协议发送对话请求。
+// DashScope 的插件和部分模型只能通过原生的 text-generation / multimodal-generation 接口调用。
+func WithNativeProtocol() ClientOption {
+	return func(c *ClientConfig) {
+		c.NativeProtocol = true
+	}
+}
+
 // --- 2. Request Options ---
 // 用于在单次调用Chat方法时，微调该次请求的参数。
 
@@ -78,6 +122,17 @@ type RequestConfig struct {
 	// 【新增】StreamCallback 用于处理流式输出的每一个数据块
 	StreamCallback StreamCallback
 
+	// StreamIdleTimeout 流式响应中两个数据块之间允许的最长间隔，0 表示不限制
+	StreamIdleTimeout time.Duration
+
+	// StreamBufferSize 流式响应中单行 SSE 数据的最大字节数，0 表示使用库的默认值 (4MB)
+	StreamBufferSize int
+
+	// StreamTee 流式文本的旁路输出，每个增量文本块都会同时写入该 Writer
+	StreamTee io.Writer
+	// StreamRawTee 原始 SSE 数据的旁路输出，每一行 data 负载都会原样写入该 Writer
+	StreamRawTee io.Writer
+
 	// 【新增】Thinking 用于统一控制思考模式。
 	// 使用指针 *bool 可以区分三种状态:
 	// - nil:   用户未指定，使用Provider的默认行为。
@@ -87,9 +142,40 @@ type RequestConfig struct {
 
 	Parameters map[string]any
 
-	text2Image bool
-	imageEdit  bool
-	Provider   map[string]any
+	text2Image     bool
+	imageEdit      bool
+	imageVariation bool
+	responsesAPI   bool
+	Provider       map[string]any
+
+	// BuiltinTools 由提供商执行的内置工具，如 web_search，目前仅 Responses API 支持
+	BuiltinTools []map[string]any
+	// ReasoningSummary 思考过程摘要的详细程度（"auto"、"concise"、"detailed"），目前仅 Responses API 支持
+	ReasoningSummary string
+
+	// Tools 本次调用可用的函数工具
+	Tools []Tool
+	// ToolChoice 工具选择策略，nil 表示使用提供商的默认行为（auto）
+	ToolChoice any
+	// ParallelToolCalls 是否允许并行函数调用，nil 表示使用提供商的默认行为
+	ParallelToolCalls *bool
+
+	// 耗时统计，由 EmitStreamChunk 自动记录
+	startedAt    time.Time
+	firstChunkAt time.Time
+	chunkCount   int
+
+	// RequestID 客户端生成的请求 ID，通过 X-Request-ID 请求头发送，并记录在 Response、错误和日志中
+	RequestID string
+
+	// DryRun 只构造请求而不发送，Response.DryRun 中返回将要发送的内容
+	DryRun bool
+
+	// SessionID 服务端会话 ID（如百炼应用的 session_id），设置后由服务端维护历史，只需发送最新一条消息
+	SessionID string
+
+	// Translation 翻译参数，nil 表示普通对话
+	Translation *TranslationOptions
 }
 
 func WithProvider(provider map[string]any) Option {
@@ -120,6 +206,7 @@ func NewRequestConfig() *RequestConfig {
 		Parameters: make(map[string]any),
 		Streaming:  false,
 		// Thinking 默认是 nil
+		startedAt: time.Now(),
 	}
 }
 
@@ -178,6 +265,83 @@ func WithStreamCallback(callback StreamCallback) Option {
 	}
 }
 
+// WithStreamIdleTimeout 设置流式响应的空闲超时。
+// 如果在 d 时间内没有收到任何数据块，流会被中断并返回 *StreamIdleTimeoutError，
+// 避免上游连接卡死时请求一直挂起。
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(r *RequestConfig) {
+		r.StreamIdleTimeout = d
+	}
+}
+
+// WithStreamBufferSize 设置流式响应中单行 SSE 数据允许的最大字节数。
+// 当模型返回超长的数据块（如很长的工具调用参数）时，可调大此值。
+func WithStreamBufferSize(size int) Option {
+	return func(r *RequestConfig) {
+		r.StreamBufferSize = size
+	}
+}
+
+// WithStreamTee 将所有流式增量文本额外写入 w（例如日志文件或转录缓冲区），
+// 正常的 StreamCallback 仍然照常执行。
+func WithStreamTee(w io.Writer) Option {
+	return func(r *RequestConfig) {
+		r.StreamTee = w
+	}
+}
+
+// WithStreamRawTee 将流式响应中每个原始 data 数据块（JSON 字符串）写入 w，
+// 每个数据块以换行结尾，便于排查 Provider 的原始输出。
+func WithStreamRawTee(w io.Writer) Option {
+	return func(r *RequestConfig) {
+		r.StreamRawTee = w
+	}
+}
+
+// EmitStreamChunk 分发一个流式增量文本块：先写入 StreamTee，再调用 StreamCallback。
+// Provider 在解析出增量文本后应统一调用此方法。
+func (r *RequestConfig) EmitStreamChunk(ctx context.Context, chunk string) error {
+	if r.chunkCount == 0 {
+		r.firstChunkAt = time.Now()
+	}
+	r.chunkCount++
+	if r.StreamTee != nil {
+		if _, err := io.WriteString(r.StreamTee, chunk); err != nil {
+			return fmt.Errorf("stream tee write failed: %w", err)
+		}
+	}
+	if r.StreamCallback != nil {
+		return r.StreamCallback(ctx, chunk)
+	}
+	return nil
+}
+
+// Timing 返回从创建该配置（即调用开始）到现在的耗时统计。
+// Provider 在构造最终 Response 时调用。
+func (r *RequestConfig) Timing() Timing {
+	now := time.Now()
+	t := Timing{
+		Duration:         now.Sub(r.startedAt),
+		TimeToFirstToken: now.Sub(r.startedAt),
+		ChunkCount:       r.chunkCount,
+	}
+	if r.chunkCount > 0 {
+		t.TimeToFirstToken = r.firstChunkAt.Sub(r.startedAt)
+	}
+	return t
+}
+
+// EmitRawChunk 将一个原始 data 数据块写入 StreamRawTee（如果已设置）。
+func (r *RequestConfig) EmitRawChunk(data string) error {
+	if r.StreamRawTee == nil {
+		return nil
+	}
+	if _, err := io.WriteString(r.StreamRawTee, data+"\n"); err != nil {
+		return fmt.Errorf("stream raw tee write failed: %w", err)
+	}
+	return nil
+}
+
 // WithParameters 附加一个map中所有的任意键值对参数。
 // 如果key已存在，则会被覆盖。
 func WithParameters(params map[string]any) Option {
@@ -215,26 +379,11 @@ func WithParameter(key string, value any) Option {
 	}
 }
 
-// [在 spec/options.go 中添加以下代码]
-
-// TranslationOptions 定义了翻译模型的特定参数
-type TranslationOptions struct {
-	SourceLang string `json:"source_lang"` // 例如 "auto", "English", "Chinese"
-	TargetLang string `json:"target_lang"` // 例如 "English", "Chinese"
-}
-
-// WithTranslation 是一个专用选项，用于设置翻译参数。
-// 它会将结构化的参数注入到通用的 Parameters map 中，从而被 Provider 自动处理。
-func WithTranslation(sourceLang, targetLang string) Option {
+// WithSessionID 在服务端维护的会话中继续对话，id 来自上一次调用的 Response.SessionID。
+// 目前用于 DashScope 百炼应用，此时只发送最后一条消息，历史由服务端保存。
+func WithSessionID(id string) Option {
 	return func(r *RequestConfig) {
-		// 确保 Parameters 已初始化
-		if r.Parameters == nil {
-			r.Parameters = make(map[string]any)
-		}
-		r.Parameters["translation_options"] = TranslationOptions{
-			SourceLang: sourceLang,
-			TargetLang: targetLang,
-		}
+		r.SessionID = id
 	}
 }
 
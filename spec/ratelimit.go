@@ -0,0 +1,34 @@
+package spec
+
+// RateLimit 配置客户端侧的令牌桶限流，在请求发出前等待，避免触发提供商的限流甚至封禁 Key。
+type RateLimit struct {
+	// RPS 每秒允许的请求数，<= 0 表示不限制请求数
+	RPS float64
+	// Burst 允许的突发请求数，<= 0 时按 1 处理
+	Burst int
+	// TokensPerMinute 每分钟的 token 预算，<= 0 表示不限制。
+	// 请求的 token 数按请求体大小粗略估算（约 4 字节一个 token），仅用于平滑流量。
+	TokensPerMinute int
+}
+
+// WithRateLimit 限制每秒请求数，burst 为允许的突发请求数。
+// 限流器由同一个 provider 客户端的所有请求共享，重试也会计入。
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *ClientConfig) {
+		if c.RateLimit == nil {
+			c.RateLimit = &RateLimit{}
+		}
+		c.RateLimit.RPS = rps
+		c.RateLimit.Burst = burst
+	}
+}
+
+// WithTokensPerMinute 设置每分钟的 token 预算，可与 WithRateLimit 同时使用。
+func WithTokensPerMinute(tpm int) ClientOption {
+	return func(c *ClientConfig) {
+		if c.RateLimit == nil {
+			c.RateLimit = &RateLimit{}
+		}
+		c.RateLimit.TokensPerMinute = tpm
+	}
+}
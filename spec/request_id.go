@@ -0,0 +1,57 @@
+package spec
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// RequestIDHeader 是客户端生成的请求 ID 使用的请求头
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// NewRequestID 生成一个随机的 UUID v4 作为请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// ContextWithRequestID 返回携带请求 ID 的上下文。
+// 同一上下文中的所有调用（包括重试和故障转移）都会使用这个 ID。
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 返回上下文中的请求 ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID 为本次调用指定请求 ID，不指定时自动生成
+func WithRequestID(id string) Option {
+	return func(r *RequestConfig) {
+		r.RequestID = id
+	}
+}
+
+// BindContext 将本次调用的请求级设置写入上下文，供底层的 Requester 读取：
+//   - 请求 ID：优先使用 WithRequestID 指定的 ID，其次是上下文中已有的 ID，都没有时生成新的 ID；
+//   - dry run：开启 WithDryRun 时标记上下文，请求在发出前中止。
+func (r *RequestConfig) BindContext(ctx context.Context) context.Context {
+	if r.RequestID == "" {
+		r.RequestID = RequestIDFromContext(ctx)
+	}
+	if r.RequestID == "" {
+		r.RequestID = NewRequestID()
+	}
+	ctx = ContextWithRequestID(ctx, r.RequestID)
+	if r.DryRun {
+		ctx = ContextWithDryRun(ctx)
+	}
+	return ctx
+}
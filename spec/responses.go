@@ -0,0 +1,66 @@
+package spec
+
+import "strings"
+
+// WithResponsesAPI 本次调用改用 OpenAI 的 /v1/responses 接口，
+// 可以使用内置工具（WithWebSearch）和思考过程摘要（WithReasoningSummary）。
+// 不支持 Responses API 的提供商会忽略此选项。
+func WithResponsesAPI() Option {
+	return func(r *RequestConfig) {
+		r.responsesAPI = true
+	}
+}
+
+// UsesResponsesAPI 判断本次调用是否要求使用 Responses API
+func (r *RequestConfig) UsesResponsesAPI() bool {
+	return r.responsesAPI
+}
+
+// WithBuiltinTool 启用一个由提供商执行的内置工具，params 为工具的其他参数，如 file_search 的 vector_store_ids。
+// 内置工具只能通过 Responses API 使用，设置后会自动开启 WithResponsesAPI。
+func WithBuiltinTool(toolType string, params map[string]any) Option {
+	return func(r *RequestConfig) {
+		tool := map[string]any{"type": toolType}
+		for k, v := range params {
+			tool[k] = v
+		}
+		r.BuiltinTools = append(r.BuiltinTools, tool)
+		r.responsesAPI = true
+	}
+}
+
+// WithWebSearch 启用内置的联网搜索工具，回答中会附带引用的网页
+func WithWebSearch() Option {
+	return WithBuiltinTool("web_search", nil)
+}
+
+// WithReasoningSummary 要求返回思考过程的摘要，summary 可选 "auto"、"concise"、"detailed"，
+// 摘要写入 Message.ReasoningContent。设置后会自动开启 WithResponsesAPI。
+func WithReasoningSummary(summary string) Option {
+	return func(r *RequestConfig) {
+		r.ReasoningSummary = summary
+		r.responsesAPI = true
+	}
+}
+
+// WithResponsesModels 指定默认通过 Responses API 调用的模型，
+// 以 "*" 结尾时按前缀匹配，例如 "o3-pro*"、"codex-*"。
+func WithResponsesModels(models ...string) ClientOption {
+	return func(c *ClientConfig) {
+		c.ResponsesModels = append(c.ResponsesModels, models...)
+	}
+}
+
+// MatchModel 判断模型是否匹配 patterns 中的任意一项，以 "*" 结尾的项按前缀匹配
+func MatchModel(model string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(model, prefix) {
+				return true
+			}
+		} else if model == p {
+			return true
+		}
+	}
+	return false
+}
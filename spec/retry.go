@@ -0,0 +1,67 @@
+package spec
+
+import "time"
+
+// RetryCondition 描述哪些错误需要重试，可以按位组合。
+type RetryCondition int
+
+const (
+	// RetryOnRateLimit 在收到 429 时重试
+	RetryOnRateLimit RetryCondition = 1 << iota
+	// RetryOnServerError 在收到 5xx 时重试
+	RetryOnServerError
+	// RetryOnNetworkError 在连接失败、连接被重置等网络错误时重试
+	RetryOnNetworkError
+
+	// RetryOnAll 包含以上全部条件
+	RetryOnAll = RetryOnRateLimit | RetryOnServerError | RetryOnNetworkError
+)
+
+// RetryPolicy 配置请求失败后的指数退避重试。
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（包含首次请求），<= 1 表示不重试
+	MaxAttempts int
+	// BaseDelay 第一次重试前的等待时间，之后每次翻倍
+	BaseDelay time.Duration
+	// MaxDelay 单次等待的上限；服务端建议的等待时间超过该值时不再重试
+	MaxDelay time.Duration
+	// Jitter 随机抖动比例 (0~1)，实际等待时间在 [delay*(1-Jitter), delay] 之间
+	Jitter float64
+	// RetryOn 需要重试的错误类型
+	RetryOn RetryCondition
+}
+
+// DefaultRetryPolicy 返回一个适合大多数场景的重试策略：
+// 最多 3 次尝试，初始等待 500ms，上限 10s，20% 抖动，对限流、服务端错误和网络错误都进行重试。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		RetryOn:     RetryOnAll,
+	}
+}
+
+// Backoff 返回第 attempt 次重试（从 1 开始）前应等待的时间，不含抖动。
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// WithRetryPolicy 为客户端开启失败重试。
+// 只有在尚未收到成功响应之前的失败会被重试，流式响应开始输出后不会重试。
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *ClientConfig) {
+		c.RetryPolicy = &policy
+	}
+}
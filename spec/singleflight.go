@@ -0,0 +1,10 @@
+package spec
+
+// WithSingleflight 开启相同请求合并：多个 goroutine 同时发出完全相同的非流式请求
+// （相同的地址、鉴权信息和请求体）时，只向上游发送一次，并共享同一份响应。
+// 适用于缓存击穿等场景；流式请求不会被合并。
+func WithSingleflight() ClientOption {
+	return func(c *ClientConfig) {
+		c.Singleflight = true
+	}
+}
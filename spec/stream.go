@@ -0,0 +1,136 @@
+package spec
+
+import (
+	"context"
+	"io"
+	"iter"
+)
+
+// StreamEvent 是迭代器式流式接口产出的单个事件。
+// 流进行中 Delta 携带增量文本；流结束时产出最后一个事件，其 Response 为完整响应。
+type StreamEvent struct {
+	// Delta 本次收到的增量文本内容
+	Delta string
+	// Response 仅在最后一个事件中非 nil，包含完整的响应消息
+	Response *Response
+}
+
+// ChatFunc 是 Model.Chat 的函数签名，便于在不同实现之间复用流式适配逻辑。
+type ChatFunc func(ctx context.Context, messages []Message, opts ...Option) (*Response, error)
+
+// ChatSeq 将基于回调的 Chat 调用适配为 Go 1.23 的 range-over-func 迭代器。
+// Provider 实现 Model.ChatSeq 时可直接委托给此函数。
+//
+// 如果底层实现不支持流式（没有触发任何增量回调），完整内容会作为一个 Delta 事件产出，
+// 调用方无需区分 Provider 是否真正支持流式。
+// 提前 break 会取消底层请求。
+func ChatSeq(ctx context.Context, chat ChatFunc, messages []Message, opts ...Option) iter.Seq2[StreamEvent, error] {
+	return func(yield func(StreamEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		events := make(chan StreamEvent)
+		var resp *Response
+		var err error
+
+		// 包装用户已设置的回调，保证原有回调依然会被执行
+		seqOpt := func(r *RequestConfig) {
+			prev := r.StreamCallback
+			r.Streaming = true
+			r.StreamCallback = func(cbCtx context.Context, chunk string) error {
+				if prev != nil {
+					if err := prev(cbCtx, chunk); err != nil {
+						return err
+					}
+				}
+				select {
+				case events <- StreamEvent{Delta: chunk}:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		go func() {
+			defer close(events)
+			resp, err = chat(ctx, messages, append(opts, seqOpt)...)
+		}()
+
+		streamed := false
+		for ev := range events {
+			streamed = true
+			if !yield(ev, nil) {
+				cancel()
+				for range events {
+				}
+				return
+			}
+		}
+
+		if err != nil {
+			yield(StreamEvent{}, err)
+			return
+		}
+		if !streamed && resp != nil && resp.Message.Content != "" {
+			if !yield(StreamEvent{Delta: resp.Message.Content}, nil) {
+				return
+			}
+		}
+		yield(StreamEvent{Response: resp}, nil)
+	}
+}
+
+// MultiStreamCallback 将多个回调合并为一个，每个数据块会按顺序分发给所有回调。
+// 任一回调返回错误都会中断流式接收；nil 回调会被忽略。
+// 适用于同时更新终端界面、推送 websocket 以及写入日志文件等场景。
+func MultiStreamCallback(cbs ...StreamCallback) StreamCallback {
+	return func(ctx context.Context, chunk string) error {
+		for _, cb := range cbs {
+			if cb == nil {
+				continue
+			}
+			if err := cb(ctx, chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WriterStreamCallback 返回一个将数据块写入 w 的回调。
+// 如果 w 实现了 Flush() 或 Flush() error（例如 http.ResponseWriter、bufio.Writer），
+// 每次写入后会立即刷新，保证下游能实时看到输出。
+func WriterStreamCallback(w io.Writer) StreamCallback {
+	return func(ctx context.Context, chunk string) error {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			return f.Flush()
+		case interface{ Flush() }:
+			f.Flush()
+		}
+		return nil
+	}
+}
+
+// StreamWriter 是流式输出目标（SSE、gRPC、websocket 等）的统一约定。
+// WriteDelta 接收每个增量文本块；Flush 将已缓冲的数据推送给下游；
+// Close 在流结束时调用一次，final 为完整响应，出错时为 nil。
+type StreamWriter interface {
+	WriteDelta(delta string) error
+	Flush() error
+	Close(final *Response) error
+}
+
+// StreamWriterCallback 将 StreamWriter 适配为 StreamCallback，每个数据块写入后立即 Flush。
+func StreamWriterCallback(w StreamWriter) StreamCallback {
+	return func(ctx context.Context, chunk string) error {
+		if err := w.WriteDelta(chunk); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+}
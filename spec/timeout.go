@@ -0,0 +1,36 @@
+package spec
+
+import "time"
+
+// Timeouts 分阶段的超时配置，0 表示该阶段不限制。
+//
+// 流式响应的总时长可能远超任何固定的整体超时，因此整体超时 Total 只作用于非流式请求；
+// 流式请求由 ResponseHeader 限制首包等待时间，由 WithStreamIdleTimeout 限制数据块间隔。
+type Timeouts struct {
+	// Dial 建立 TCP 连接的超时
+	Dial time.Duration
+	// TLSHandshake TLS 握手的超时
+	TLSHandshake time.Duration
+	// ResponseHeader 发送完请求后等待响应头的超时，非流式请求需要等待模型生成完毕才返回响应头
+	ResponseHeader time.Duration
+	// Total 非流式请求从发出到读完响应体的整体超时
+	Total time.Duration
+}
+
+// DefaultTimeouts 返回默认的超时配置
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Dial:           10 * time.Second,
+		TLSHandshake:   10 * time.Second,
+		ResponseHeader: 240 * time.Second,
+		Total:          240 * time.Second,
+	}
+}
+
+// WithTimeouts 设置分阶段的超时，替代 http.Client.Timeout 的单一整体超时。
+// 连接相关的超时只作用于 *http.Transport，使用 WithHTTPClient 传入其他类型的 Transport 时需要自行配置。
+func WithTimeouts(t Timeouts) ClientOption {
+	return func(c *ClientConfig) {
+		c.Timeouts = t
+	}
+}
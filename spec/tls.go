@@ -0,0 +1,20 @@
+package spec
+
+import "crypto/tls"
+
+// WithTLSConfig 为客户端设置自定义 TLS 配置，例如信任私有 CA 或指定最低 TLS 版本。
+// 配置会被复制后使用，之后修改传入的 tls.Config 不会生效。
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *ClientConfig) {
+		c.TLSConfig = cfg.Clone()
+	}
+}
+
+// WithClientCertificate 为客户端设置 mTLS 客户端证书，用于访问需要双向认证的私有网关。
+// cert 和 key 可以是 PEM 文件路径，也可以直接是 PEM 内容（以 "-----BEGIN" 开头）。
+// 可与 WithTLSConfig 同时使用，证书会追加到 TLS 配置中。
+func WithClientCertificate(cert, key string) ClientOption {
+	return func(c *ClientConfig) {
+		c.ClientCert, c.ClientKey = cert, key
+	}
+}
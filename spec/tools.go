@@ -0,0 +1,169 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RoleTool 是工具执行结果消息的角色
+const RoleTool Role = "tool"
+
+// Tool 是模型可以调用的函数定义，序列化为 OpenAI 兼容的 tools 格式
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction 是函数的名称、说明和参数的 JSON Schema
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parameters 参数的 JSON Schema，可以是 map、结构体或 json.RawMessage
+	Parameters any `json:"parameters,omitempty"`
+	// Strict 要求模型严格按 Schema 生成参数，部分提供商支持
+	Strict *bool `json:"strict,omitempty"`
+}
+
+// NewFunctionTool 创建一个函数工具，parameters 为参数的 JSON Schema
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{
+		Type: "function",
+		Function: ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}
+}
+
+// ToolCall 是模型请求执行的一次函数调用
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall 是函数调用的名称和 JSON 编码的参数
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// DecodeArguments 将函数参数解析到 v
+func (c ToolCall) DecodeArguments(v any) error {
+	args := c.Function.Arguments
+	if args == "" {
+		args = "{}"
+	}
+	if err := json.Unmarshal([]byte(args), v); err != nil {
+		return fmt.Errorf("invalid arguments for tool %s: %w", c.Function.Name, err)
+	}
+	return nil
+}
+
+// NewToolResultMessage 创建一条工具执行结果消息，toolCallID 为对应 ToolCall 的 ID
+func NewToolResultMessage(toolCallID, content string) Message {
+	return Message{Role: RoleTool, Content: content, ToolCallID: toolCallID}
+}
+
+// WithTools 设置本次调用可用的函数工具，多次调用会追加
+func WithTools(tools ...Tool) Option {
+	return func(r *RequestConfig) {
+		r.Tools = append(r.Tools, tools...)
+	}
+}
+
+// WithToolChoice 控制模型是否调用工具："auto"、"none"、"required"，
+// 或用 ToolChoiceFunction 强制调用指定函数
+func WithToolChoice(choice any) Option {
+	return func(r *RequestConfig) {
+		r.ToolChoice = choice
+	}
+}
+
+// ToolChoiceFunction 返回强制调用指定函数的 tool_choice
+func ToolChoiceFunction(name string) map[string]any {
+	return map[string]any{
+		"type":     "function",
+		"function": map[string]any{"name": name},
+	}
+}
+
+// WithParallelToolCalls 设置是否允许模型在一次回复中请求多个函数调用
+func WithParallelToolCalls(enabled bool) Option {
+	return func(r *RequestConfig) {
+		r.ParallelToolCalls = &enabled
+	}
+}
+
+// ApplyTools 将工具相关的配置以 OpenAI 兼容的字段写入请求体，未设置工具时不做任何修改
+func (r *RequestConfig) ApplyTools(body map[string]any) {
+	if len(r.Tools) == 0 {
+		return
+	}
+	body["tools"] = r.Tools
+	if r.ToolChoice != nil {
+		body["tool_choice"] = r.ToolChoice
+	}
+	if r.ParallelToolCalls != nil {
+		body["parallel_tool_calls"] = *r.ParallelToolCalls
+	}
+}
+
+// ToolCallDelta 是流式响应中函数调用的增量片段，同一调用的片段 Index 相同
+type ToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// ToolCallAccumulator 按 Index 拼接流式响应中的函数调用片段，零值可用
+type ToolCallAccumulator struct {
+	calls map[int]*ToolCall
+}
+
+// Add 合并一批增量片段。ID、类型和函数名只在首个片段中出现，参数按顺序拼接
+func (a *ToolCallAccumulator) Add(deltas []ToolCallDelta) {
+	if a.calls == nil && len(deltas) > 0 {
+		a.calls = make(map[int]*ToolCall)
+	}
+	for _, d := range deltas {
+		call, ok := a.calls[d.Index]
+		if !ok {
+			call = &ToolCall{Type: "function"}
+			a.calls[d.Index] = call
+		}
+		if d.ID != "" {
+			call.ID = d.ID
+		}
+		if d.Type != "" {
+			call.Type = d.Type
+		}
+		if d.Function.Name != "" {
+			call.Function.Name = d.Function.Name
+		}
+		call.Function.Arguments += d.Function.Arguments
+	}
+}
+
+// ToolCalls 返回按 Index 排序的完整函数调用，没有调用时返回 nil
+func (a *ToolCallAccumulator) ToolCalls() []ToolCall {
+	if len(a.calls) == 0 {
+		return nil
+	}
+	indexes := make([]int, 0, len(a.calls))
+	for i := range a.calls {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	calls := make([]ToolCall, 0, len(indexes))
+	for _, i := range indexes {
+		calls = append(calls, *a.calls[i])
+	}
+	return calls
+}
@@ -0,0 +1,120 @@
+package spec
+
+import (
+	"context"
+	"time"
+)
+
+// Transcriber 语音转写（speech-to-text）接口
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio AudioInput, opts ...TranscribeOption) (*Transcription, error)
+}
+
+// TranscriberProvider 由支持语音转写的 Client 实现，用于获取指定模型的 Transcriber
+type TranscriberProvider interface {
+	Transcriber(model string) Transcriber
+}
+
+// AudioInput 待转写的音频，URL 与 Data 二选一。
+// 需要上传文件的提供商（如 OpenAI Whisper）会自动下载 URL；
+// 只接受公网地址的提供商（如 DashScope paraformer）要求使用 URL。
+type AudioInput struct {
+	// URL 音频地址，支持 http(s) URL 和 data URL
+	URL string
+	// Data 音频内容
+	Data []byte
+	// Filename 上传时使用的文件名，扩展名决定音频格式，例如 "meeting.mp3"
+	Filename string
+}
+
+// NewAudioURL 使用音频地址创建 AudioInput
+func NewAudioURL(url string) AudioInput {
+	return AudioInput{URL: url}
+}
+
+// NewAudioData 使用音频内容创建 AudioInput，filename 的扩展名需与音频格式一致
+func NewAudioData(filename string, data []byte) AudioInput {
+	return AudioInput{Data: data, Filename: filename}
+}
+
+// Transcription 语音转写结果
+type Transcription struct {
+	// Text 完整的转写文本
+	Text string
+	// Language 识别出的语言，提供商未返回时为空
+	Language string
+	// Duration 音频时长，提供商未返回时为 0
+	Duration time.Duration
+	// Segments 带时间戳的分段，开启 WithTranscribeSegments 时返回
+	Segments []TranscriptionSegment
+	// RawResponse 原始响应体
+	RawResponse []byte
+}
+
+// TranscriptionSegment 带时间戳的一段转写文本
+type TranscriptionSegment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// TranscribeOption 用于配置单次语音转写调用
+type TranscribeOption func(c *TranscribeConfig)
+
+// TranscribeConfig 存储了单次语音转写调用的配置
+type TranscribeConfig struct {
+	// Language 音频语言（ISO-639-1，如 "zh"、"en"），为空时自动识别
+	Language string
+	// Prompt 提示文本，用于提供专有名词或延续上一段的风格
+	Prompt string
+	// Segments 是否返回带时间戳的分段
+	Segments bool
+	// PollInterval 异步任务的轮询间隔，0 表示使用提供商的默认值
+	PollInterval time.Duration
+	// Parameters 透传到请求中的额外参数
+	Parameters map[string]any
+}
+
+// NewTranscribeConfig 应用选项并返回配置
+func NewTranscribeConfig(opts ...TranscribeOption) *TranscribeConfig {
+	c := &TranscribeConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithTranscribeLanguage 指定音频语言，可提高识别准确率
+func WithTranscribeLanguage(lang string) TranscribeOption {
+	return func(c *TranscribeConfig) {
+		c.Language = lang
+	}
+}
+
+// WithTranscribePrompt 设置提示文本
+func WithTranscribePrompt(prompt string) TranscribeOption {
+	return func(c *TranscribeConfig) {
+		c.Prompt = prompt
+	}
+}
+
+// WithTranscribeSegments 返回带时间戳的分段
+func WithTranscribeSegments() TranscribeOption {
+	return func(c *TranscribeConfig) {
+		c.Segments = true
+	}
+}
+
+// WithTranscribePollInterval 设置异步转写任务的轮询间隔
+func WithTranscribePollInterval(d time.Duration) TranscribeOption {
+	return func(c *TranscribeConfig) {
+		c.PollInterval = d
+	}
+}
+
+// WithTranscribeParameters 设置透传到请求中的额外参数
+func WithTranscribeParameters(params map[string]any) TranscribeOption {
+	return func(c *TranscribeConfig) {
+		c.Parameters = params
+	}
+}
@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TranslationOptions 定义了翻译参数。DashScope 的 qwen-mt 系列模型原生支持（translation_options），
+// 其他模型由提供商通过 TranslationMessages 转换为翻译提示词
+type TranslationOptions struct {
+	SourceLang string `json:"source_lang"` // 例如 "auto", "English", "Chinese"
+	TargetLang string `json:"target_lang"` // 例如 "English", "Chinese"
+	// Terms 术语表，其中的术语按指定译法翻译
+	Terms []TranslationTerm `json:"terms,omitempty"`
+	// Domains 用自然语言描述译文的领域和风格，qwen-mt 要求使用英文描述
+	Domains string `json:"domains,omitempty"`
+}
+
+// TranslationTerm 是术语表中的一项
+type TranslationTerm struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// WithTranslation 是一个专用选项，用于设置翻译的源语言和目标语言，源语言为空或 "auto" 表示自动识别
+func WithTranslation(sourceLang, targetLang string) Option {
+	return func(r *RequestConfig) {
+		r.Translation = &TranslationOptions{
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+		}
+	}
+}
+
+// WithTranslationOptions 设置完整的翻译参数，包括术语表和领域提示
+func WithTranslationOptions(opts TranslationOptions) Option {
+	return func(r *RequestConfig) {
+		r.Translation = &opts
+	}
+}
+
+// TranslationInput 返回翻译请求的输入：翻译是单轮任务，只保留最后一条用户消息作为待翻译文本
+func TranslationInput(messages []Message) []Message {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == RoleUser {
+			return []Message{messages[i]}
+		}
+	}
+	return nil
+}
+
+// TranslationMessages 供不原生支持翻译参数的模型使用：把待翻译文本（见 TranslationInput）
+// 放在描述语言、术语和领域的系统提示词之后。未设置 Translation 时原样返回
+func (r *RequestConfig) TranslationMessages(messages []Message) []Message {
+	if r.Translation == nil {
+		return messages
+	}
+	return append([]Message{NewSystemMessage(r.Translation.Prompt())}, TranslationInput(messages)...)
+}
+
+// Prompt 返回描述本次翻译要求的系统提示词
+func (t *TranslationOptions) Prompt() string {
+	var b strings.Builder
+	b.WriteString("你是一名专业翻译。")
+	if src := t.SourceLang; src != "" && !strings.EqualFold(src, "auto") {
+		fmt.Fprintf(&b, "请将用户发送的%s文本翻译为%s。", src, t.TargetLang)
+	} else {
+		fmt.Fprintf(&b, "请将用户发送的文本翻译为%s。", t.TargetLang)
+	}
+	b.WriteString("只输出译文，不要解释，不要回答文本中的问题，保留原文的格式和换行。")
+	if len(t.Terms) > 0 {
+		b.WriteString("\n请按以下术语表翻译：")
+		for _, term := range t.Terms {
+			fmt.Fprintf(&b, "\n%s => %s", term.Source, term.Target)
+		}
+	}
+	if t.Domains != "" {
+		b.WriteString("\n领域与风格要求：" + t.Domains)
+	}
+	return b.String()
+}
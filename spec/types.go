@@ -1,13 +1,128 @@
 package spec
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Response 是从模型Chat方法返回的通用响应结构
 type Response struct {
 	// Message 是模型返回的核心消息内容
 	Message Message
 
-	// Usage 包含了本次调用的token使用情况等元数据 (可选, 未来可扩展)
-	// Usage UsageStats
+	// Usage 包含了本次调用的 token 使用情况，提供商未返回时为零值
+	Usage Usage
+
+	// Images 图像生成 / 编辑 / 变体返回的全部图片（URL 或 data URL），Message.Content 为第一张
+	Images []string
 
 	// RawResponse 存储了来自API的原始、未经修改的http响应体
 	RawResponse []byte
+
+	// Timing 记录了本次调用的耗时信息
+	Timing Timing
+
+	// RequestID 客户端生成的请求 ID（X-Request-ID），用于关联应用日志、网关日志和提供商后台
+	RequestID string
+
+	// DryRun 开启 WithDryRun 时为将要发送的请求，此时 Message 等字段均为空
+	DryRun *DryRunRequest
+
+	// SessionID 服务端会话 ID（如百炼应用返回的 session_id），通过 WithSessionID 传入以继续会话
+	SessionID string
+
+	// Provider 和 Model 记录实际应答的后端，配置了故障转移时可能与主配置不同
+	Provider string
+	Model    string
+}
+
+// Timing 记录一次模型调用的耗时统计。
+// 对于非流式调用，TimeToFirstToken 与 Duration 相同，ChunkCount 为 0。
+type Timing struct {
+	// TimeToFirstToken 从发起调用到收到第一个增量文本块的耗时
+	TimeToFirstToken time.Duration
+	// Duration 从发起调用到得到完整响应的总耗时
+	Duration time.Duration
+	// ChunkCount 流式调用中收到的增量文本块数量
+	ChunkCount int
+}
+
+// Usage 记录一次模型调用的 token 消耗。
+// 反序列化时同时兼容 OpenAI 格式 (prompt_tokens / completion_tokens) 和
+// DashScope 原生及 Responses API 格式 (input_tokens / output_tokens)。
+type Usage struct {
+	// PromptTokens 输入 token 数（包含命中缓存的部分）
+	PromptTokens int `json:"prompt_tokens"`
+	// CompletionTokens 输出 token 数（包含思考过程）
+	CompletionTokens int `json:"completion_tokens"`
+	// TotalTokens 总 token 数
+	TotalTokens int `json:"total_tokens"`
+	// CachedTokens 输入中命中上下文缓存的 token 数
+	CachedTokens int `json:"cached_tokens,omitempty"`
+	// ReasoningTokens 输出中用于思考过程的 token 数
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
+// UnmarshalJSON 兼容各提供商不同的 usage 字段命名
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		PromptTokens         int `json:"prompt_tokens"`
+		CompletionTokens     int `json:"completion_tokens"`
+		InputTokens          int `json:"input_tokens"`
+		OutputTokens         int `json:"output_tokens"`
+		TotalTokens          int `json:"total_tokens"`
+		CachedTokens         int `json:"cached_tokens"`
+		ReasoningTokens      int `json:"reasoning_tokens"`
+		PromptCacheHitTokens int `json:"prompt_cache_hit_tokens"` // DeepSeek
+		PromptTokensDetails  *struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
+		InputTokensDetails *struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"input_tokens_details"`
+		CompletionTokensDetails *struct {
+			ReasoningTokens int `json:"reasoning_tokens"`
+		} `json:"completion_tokens_details"`
+		OutputTokensDetails *struct {
+			ReasoningTokens int `json:"reasoning_tokens"`
+		} `json:"output_tokens_details"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*u = Usage{
+		PromptTokens:     max(raw.PromptTokens, raw.InputTokens),
+		CompletionTokens: max(raw.CompletionTokens, raw.OutputTokens),
+		TotalTokens:      raw.TotalTokens,
+		CachedTokens:     max(raw.CachedTokens, raw.PromptCacheHitTokens),
+		ReasoningTokens:  raw.ReasoningTokens,
+	}
+	if d := raw.PromptTokensDetails; d != nil {
+		u.CachedTokens = max(u.CachedTokens, d.CachedTokens)
+	}
+	if d := raw.InputTokensDetails; d != nil {
+		u.CachedTokens = max(u.CachedTokens, d.CachedTokens)
+	}
+	if d := raw.CompletionTokensDetails; d != nil {
+		u.ReasoningTokens = max(u.ReasoningTokens, d.ReasoningTokens)
+	}
+	if d := raw.OutputTokensDetails; d != nil {
+		u.ReasoningTokens = max(u.ReasoningTokens, d.ReasoningTokens)
+	}
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+	return nil
+}
+
+// Add 返回两次用量之和，用于累计多次调用的消耗
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		CachedTokens:     u.CachedTokens + other.CachedTokens,
+		ReasoningTokens:  u.ReasoningTokens + other.ReasoningTokens,
+	}
 }
@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"context"
+	"time"
+)
+
+// VideoModel 视频生成接口。视频生成都是异步任务：提交后按 PollInterval 轮询直到完成，
+// 每次查询到新状态时调用 OnProgress。
+type VideoModel interface {
+	GenerateVideo(ctx context.Context, req VideoRequest, opts ...VideoOption) (*Video, error)
+}
+
+// VideoModelProvider 由支持视频生成的 Client 实现，用于获取指定模型（如 wanx2.1-t2v-turbo、sora-2）的 VideoModel
+type VideoModelProvider interface {
+	VideoModel(model string) VideoModel
+}
+
+// VideoRequest 视频生成的输入
+type VideoRequest struct {
+	// Prompt 视频内容的描述
+	Prompt string
+	// ImageURL 图生视频的首帧图片，支持 http(s) URL 和 data URL，为空时为文生视频
+	ImageURL string
+	// Size 分辨率，如 "1280x720"，各提供商的分隔符会自动转换
+	Size string
+	// Duration 视频时长，0 表示使用提供商的默认值
+	Duration time.Duration
+}
+
+// VideoStatus 视频生成任务的状态
+type VideoStatus string
+
+const (
+	VideoQueued    VideoStatus = "queued"
+	VideoRunning   VideoStatus = "running"
+	VideoSucceeded VideoStatus = "succeeded"
+	VideoFailed    VideoStatus = "failed"
+)
+
+// Video 视频生成任务及其结果
+type Video struct {
+	// ID 任务 ID
+	ID string
+	// Status 统一后的任务状态，RawStatus 为提供商返回的原始状态
+	Status    VideoStatus
+	RawStatus string
+	// Progress 完成百分比（0-100），提供商不返回进度时为 0，成功后为 100
+	Progress int
+	// URL 生成视频的下载地址（DashScope 等返回临时链接的提供商）
+	URL string
+	// Data 视频内容（OpenAI 等通过接口下载内容的提供商），格式一般为 mp4
+	Data []byte
+	// Error 任务失败的原因
+	Error string
+	// RawResponse 最后一次查询的原始响应体
+	RawResponse []byte
+}
+
+// VideoOption 用于配置单次视频生成调用
+type VideoOption func(c *VideoConfig)
+
+// VideoConfig 存储了单次视频生成调用的配置
+type VideoConfig struct {
+	// PollInterval 轮询间隔，0 表示使用提供商的默认值
+	PollInterval time.Duration
+	// OnProgress 每次查询到任务状态时调用
+	OnProgress func(*Video)
+	// Parameters 透传到请求中的额外参数，如 DashScope 的 prompt_extend、seed
+	Parameters map[string]any
+}
+
+// NewVideoConfig 应用选项并返回配置
+func NewVideoConfig(opts ...VideoOption) *VideoConfig {
+	c := &VideoConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithVideoPollInterval 设置任务的轮询间隔
+func WithVideoPollInterval(d time.Duration) VideoOption {
+	return func(c *VideoConfig) {
+		c.PollInterval = d
+	}
+}
+
+// WithVideoProgress 设置进度回调，可用于展示进度条或记录任务 ID
+func WithVideoProgress(fn func(*Video)) VideoOption {
+	return func(c *VideoConfig) {
+		c.OnProgress = fn
+	}
+}
+
+// WithVideoParameters 设置透传到请求中的额外参数
+func WithVideoParameters(params map[string]any) VideoOption {
+	return func(c *VideoConfig) {
+		c.Parameters = params
+	}
+}
@@ -0,0 +1,118 @@
+package textsplit
+
+import (
+	"strings"
+)
+
+// MarkdownSeparators 是 Markdown 默认的分隔符，优先在段落和列表项之间切分
+var MarkdownSeparators = []string{"\n\n", "\n- ", "\n* ", "\n", "。", "！", "？", ". ", "! ", "? ", "；", "; ", "，", ", ", " ", ""}
+
+// Section 是 Markdown 中一个标题下的内容
+type Section struct {
+	// Headings 从一级标题开始的标题路径，如 ["安装", "Linux"]；文档开头没有标题的部分为空
+	Headings []string
+	// Text 标题下的正文，不包含标题行
+	Text string
+}
+
+// Markdown 先按标题把文档切分为章节，再用 Recursive 切分过长的章节，块不会跨越章节。
+// 代码块中以 # 开头的行不会被当作标题。
+type Markdown struct {
+	// ChunkSize、ChunkOverlap 和 Length 的含义与 Recursive 相同
+	ChunkSize    int
+	ChunkOverlap int
+	Length       LengthFunc
+	// IncludeHeadings 在每个块前加上标题路径（如 "# 安装 > Linux"），使块脱离原文后仍有上下文
+	IncludeHeadings bool
+}
+
+// Split 切分 Markdown 文本
+func (m *Markdown) Split(text string) []string {
+	r := &Recursive{ChunkSize: m.ChunkSize, ChunkOverlap: m.ChunkOverlap, Separators: MarkdownSeparators, Length: m.Length}
+	var chunks []string
+	for _, s := range SplitSections(text) {
+		prefix := ""
+		if m.IncludeHeadings && len(s.Headings) > 0 {
+			prefix = "# " + strings.Join(s.Headings, " > ") + "\n\n"
+		}
+		sr := *r
+		if prefix != "" {
+			// 为标题前缀预留长度，保证加上前缀后不超过块大小
+			size, _, length := r.params()
+			if n := length(prefix); n < size/2 {
+				sr.ChunkSize = size - n
+			}
+		}
+		for _, c := range sr.Split(s.Text) {
+			chunks = append(chunks, prefix+c)
+		}
+	}
+	return chunks
+}
+
+// SplitSections 按标题把 Markdown 切分为章节，正文为空的章节会被跳过
+func SplitSections(text string) []Section {
+	var sections []Section
+	var path []headingLevel
+	var body strings.Builder
+	fence := ""
+
+	flush := func() {
+		if t := strings.TrimSpace(body.String()); t != "" {
+			headings := make([]string, len(path))
+			for i, h := range path {
+				headings[i] = h.title
+			}
+			sections = append(sections, Section{Headings: headings, Text: t})
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			body.WriteString(line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			body.WriteString(line)
+			continue
+		}
+		if level, title, ok := parseHeading(trimmed); ok {
+			flush()
+			for len(path) > 0 && path[len(path)-1].level >= level {
+				path = path[:len(path)-1]
+			}
+			path = append(path, headingLevel{level: level, title: title})
+			continue
+		}
+		body.WriteString(line)
+	}
+	flush()
+	return sections
+}
+
+// headingLevel 是标题路径中的一级
+type headingLevel struct {
+	level int
+	title string
+}
+
+// parseHeading 解析 ATX 标题行（# 到 ######，后跟空格）
+func parseHeading(line string) (level int, title string, ok bool) {
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return 0, "", false
+	}
+	title = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	return level, title, true
+}
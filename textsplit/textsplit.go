@@ -0,0 +1,177 @@
+// Package textsplit 把长文本切分为适合向量化或分段摘要的块：
+// 按分隔符逐级递归切分（Recursive）、按 Markdown 标题分节（Markdown），
+// 长度可以按字符数或按模型的 token 数（Tokens）计算。
+//
+//	s := textsplit.NewTokenSplitter("gpt-4o", 512, 64)
+//	chunks := s.Split(doc)
+package textsplit
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iEvan-lhr/go-llm-client/tokens"
+)
+
+// defaultChunkSize 是默认的块大小
+const defaultChunkSize = 1000
+
+// Splitter 把文本切分为若干块
+type Splitter interface {
+	Split(text string) []string
+}
+
+// LengthFunc 计算文本长度，块大小和重叠都以它为单位
+type LengthFunc func(text string) int
+
+// Runes 以字符（rune）数计算长度，是默认的长度函数
+func Runes(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// Tokens 返回以模型 token 数计算长度的函数，模型的编码未注册时按 tokens.Estimate 估算
+func Tokens(model string) LengthFunc {
+	return func(text string) int {
+		return tokens.Count(model, text)
+	}
+}
+
+// DefaultSeparators 是 Recursive 默认的分隔符，按优先级从段落、句子到词逐级尝试，兼顾中英文标点
+var DefaultSeparators = []string{"\n\n", "\n", "。", "！", "？", ". ", "! ", "? ", "；", "; ", "，", ", ", " ", ""}
+
+// Recursive 按分隔符逐级递归切分：先用优先级最高的分隔符切分，仍然过长的片段再用下一级分隔符切分，
+// 最后把相邻的小片段合并为不超过 ChunkSize 的块。分隔符保留在前一个片段的末尾。
+type Recursive struct {
+	// ChunkSize 块的最大长度，为 0 时使用 1000
+	ChunkSize int
+	// ChunkOverlap 相邻块重叠的长度，用于保留上下文，必须小于 ChunkSize，否则视为 0
+	ChunkOverlap int
+	// Separators 分隔符，nil 时使用 DefaultSeparators；空字符串表示按字符切分
+	Separators []string
+	// Length 长度函数，nil 时使用 Runes
+	Length LengthFunc
+}
+
+// NewTokenSplitter 创建按模型 token 数计算长度的 Recursive，块在句子等边界处切分而不会截断字符
+func NewTokenSplitter(model string, chunkSize, chunkOverlap int) *Recursive {
+	return &Recursive{ChunkSize: chunkSize, ChunkOverlap: chunkOverlap, Length: Tokens(model)}
+}
+
+// Split 切分文本，返回去除首尾空白后的非空块
+func (r *Recursive) Split(text string) []string {
+	seps := r.Separators
+	if seps == nil {
+		seps = DefaultSeparators
+	}
+	return r.split(text, seps)
+}
+
+// params 返回生效的块大小、重叠和长度函数
+func (r *Recursive) params() (size, overlap int, length LengthFunc) {
+	size, overlap, length = r.ChunkSize, r.ChunkOverlap, r.Length
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+	if length == nil {
+		length = Runes
+	}
+	return size, overlap, length
+}
+
+// split 用 seps 中第一个出现在文本中的分隔符切分，过长的片段用之后的分隔符递归切分
+func (r *Recursive) split(text string, seps []string) []string {
+	size, _, length := r.params()
+
+	sep, rest := "", []string(nil)
+	for i, s := range seps {
+		if s == "" || strings.Contains(text, s) {
+			sep, rest = s, seps[i+1:]
+			break
+		}
+	}
+
+	var pieces []string
+	if sep == "" {
+		pieces = splitRunes(text)
+	} else {
+		pieces = splitKeep(text, sep)
+	}
+
+	var chunks, good []string
+	for _, p := range pieces {
+		if p == "" {
+			continue
+		}
+		if length(p) <= size {
+			good = append(good, p)
+			continue
+		}
+		chunks = append(chunks, r.merge(good)...)
+		good = nil
+		if len(rest) > 0 {
+			chunks = append(chunks, r.split(p, rest)...)
+		} else if c := strings.TrimSpace(p); c != "" {
+			chunks = append(chunks, c)
+		}
+	}
+	return append(chunks, r.merge(good)...)
+}
+
+// merge 把相邻的片段合并为不超过块大小的块，相邻块之间保留不超过 overlap 的重叠
+func (r *Recursive) merge(pieces []string) []string {
+	size, overlap, length := r.params()
+	var chunks []string
+	var current []string
+	total := 0
+	for _, p := range pieces {
+		n := length(p)
+		if total+n > size && len(current) > 0 {
+			if c := strings.TrimSpace(strings.Join(current, "")); c != "" {
+				chunks = append(chunks, c)
+			}
+			// 从头部丢弃片段，直到剩余部分不超过重叠长度且能放下新片段
+			for len(current) > 0 && (total > overlap || total+n > size) {
+				total -= length(current[0])
+				current = current[1:]
+			}
+		}
+		current = append(current, p)
+		total += n
+	}
+	if c := strings.TrimSpace(strings.Join(current, "")); c != "" {
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
+
+// splitKeep 按 sep 切分并保留分隔符：一般的分隔符（如句号）留在前一个片段末尾；
+// 以换行开头的结构标记（如 "\n- "、"\n```"）在换行处切开，标记留在后一个片段开头
+func splitKeep(text, sep string) []string {
+	mark := strings.TrimLeft(sep, "\n")
+	if mark == "" || mark == sep {
+		return strings.SplitAfter(text, sep)
+	}
+	newlines := sep[:len(sep)-len(mark)]
+	parts := strings.Split(text, sep)
+	for i := range parts {
+		if i > 0 {
+			parts[i] = mark + parts[i]
+		}
+		if i < len(parts)-1 {
+			parts[i] += newlines
+		}
+	}
+	return parts
+}
+
+// splitRunes 把文本切分为单个字符
+func splitRunes(text string) []string {
+	out := make([]string, 0, len(text))
+	for _, r := range text {
+		out = append(out, string(r))
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package tokens
+
+import (
+	"math"
+	"strings"
+)
+
+// Encoding 是一个字节级 BPE 编码，与 tiktoken 的算法一致：
+// 先用预分词规则把文本切成片段，再在每个片段的 UTF-8 字节上按合并优先级（rank）合并。
+type Encoding struct {
+	name    string
+	ranks   map[string]int
+	decoder map[int]string
+	split   Splitter
+	special map[string]int
+}
+
+// NewEncoding 使用词表创建编码。ranks 为字节序列到 token ID 的映射，ID 越小合并优先级越高；
+// split 为预分词规则，special 为特殊 token（如 "<|endoftext|>"），可以为 nil。
+func NewEncoding(name string, ranks map[string]int, split Splitter, special map[string]int) *Encoding {
+	e := &Encoding{
+		name:    name,
+		ranks:   ranks,
+		decoder: make(map[int]string, len(ranks)+len(special)),
+		split:   split,
+		special: special,
+	}
+	for k, v := range ranks {
+		e.decoder[v] = k
+	}
+	for k, v := range special {
+		e.decoder[v] = k
+	}
+	return e
+}
+
+// Name 返回编码名称，如 "cl100k_base"
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// Encode 将文本编码为 token ID。文本中的特殊 token 按普通文本处理。
+func (e *Encoding) Encode(text string) []int {
+	var ids []int
+	for _, piece := range e.split(text) {
+		if id, ok := e.ranks[piece]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		ids = append(ids, e.bytePairEncode(piece)...)
+	}
+	return ids
+}
+
+// Count 返回文本的 token 数
+func (e *Encoding) Count(text string) int {
+	n := 0
+	for _, piece := range e.split(text) {
+		if _, ok := e.ranks[piece]; ok {
+			n++
+			continue
+		}
+		n += len(e.bytePairEncode(piece))
+	}
+	return n
+}
+
+// Decode 将 token ID 还原为文本，未知的 ID 会被忽略
+func (e *Encoding) Decode(ids []int) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		sb.WriteString(e.decoder[id])
+	}
+	return sb.String()
+}
+
+// bytePairEncode 在片段的字节上反复合并优先级最高的相邻字节对
+func (e *Encoding) bytePairEncode(piece string) []int {
+	if len(piece) == 1 {
+		if id, ok := e.ranks[piece]; ok {
+			return []int{id}
+		}
+		return []int{-1}
+	}
+	// parts[i] 为第 i 个分段的起始位置，最后一个元素为 len(piece)
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+	rank := func(i int) int {
+		if i+2 >= len(parts) {
+			return math.MaxInt
+		}
+		if r, ok := e.ranks[piece[parts[i]:parts[i+2]]]; ok {
+			return r
+		}
+		return math.MaxInt
+	}
+	ranks := make([]int, len(parts)-1)
+	for i := range ranks {
+		ranks[i] = rank(i)
+	}
+	for len(parts) > 2 {
+		best, idx := math.MaxInt, -1
+		for i, r := range ranks[:len(ranks)-1] {
+			if r < best {
+				best, idx = r, i
+			}
+		}
+		if idx < 0 {
+			break
+		}
+		parts = append(parts[:idx+1], parts[idx+2:]...)
+		ranks = append(ranks[:idx+1], ranks[idx+2:]...)
+		ranks[idx] = rank(idx)
+		if idx > 0 {
+			ranks[idx-1] = rank(idx - 1)
+		}
+	}
+
+	ids := make([]int, 0, len(parts)-1)
+	for i := 0; i+1 < len(parts); i++ {
+		if id, ok := e.ranks[piece[parts[i]:parts[i+1]]]; ok {
+			ids = append(ids, id)
+		} else {
+			// 词表缺少单字节时（不完整的词表）按每字节一个 token 计
+			ids = append(ids, -1)
+		}
+	}
+	return ids
+}
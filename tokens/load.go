@@ -0,0 +1,119 @@
+package tokens
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// LoadTiktoken 读取 tiktoken 格式的词表（如 cl100k_base.tiktoken、qwen.tiktoken），
+// 每行为 "<base64 编码的字节序列> <rank>"。
+func LoadTiktoken(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("tokens: invalid tiktoken line %d", line)
+		}
+		token, err := base64.StdEncoding.DecodeString(string(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("tokens: invalid tiktoken line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(string(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("tokens: invalid tiktoken line %d: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("tokens: failed to read tiktoken file: %w", err)
+	}
+	return ranks, nil
+}
+
+// LoadHuggingFace 读取 Hugging Face 的 tokenizer.json（如 Qwen2 / Qwen3），
+// 返回字节级 BPE 词表和特殊 token。词表中的 ID 即合并优先级，与 tiktoken 的算法兼容。
+func LoadHuggingFace(r io.Reader) (ranks map[string]int, special map[string]int, err error) {
+	var file struct {
+		AddedTokens []struct {
+			ID      int    `json:"id"`
+			Content string `json:"content"`
+		} `json:"added_tokens"`
+		Model struct {
+			Type  string         `json:"type"`
+			Vocab map[string]int `json:"vocab"`
+		} `json:"model"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, nil, fmt.Errorf("tokens: invalid tokenizer.json: %w", err)
+	}
+	if file.Model.Type != "" && file.Model.Type != "BPE" {
+		return nil, nil, fmt.Errorf("tokens: unsupported tokenizer model %q, only byte-level BPE is supported", file.Model.Type)
+	}
+
+	decoder := byteDecoder()
+	ranks = make(map[string]int, len(file.Model.Vocab))
+	for token, id := range file.Model.Vocab {
+		b := make([]byte, 0, len(token))
+		for _, r := range token {
+			c, ok := decoder[r]
+			if !ok {
+				return nil, nil, fmt.Errorf("tokens: token %q is not byte-level encoded", token)
+			}
+			b = append(b, c)
+		}
+		ranks[string(b)] = id
+	}
+	special = make(map[string]int, len(file.AddedTokens))
+	for _, t := range file.AddedTokens {
+		special[t.Content] = t.ID
+	}
+	return ranks, special, nil
+}
+
+// LoadFile 从文件加载词表并注册编码，文件扩展名为 .json 时按 tokenizer.json 解析，否则按 tiktoken 格式解析
+func LoadFile(name, path string, split Splitter) (*Encoding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("tokens: %w", err)
+	}
+	defer f.Close()
+
+	var ranks, special map[string]int
+	if len(path) > 5 && path[len(path)-5:] == ".json" {
+		ranks, special, err = LoadHuggingFace(f)
+	} else {
+		ranks, err = LoadTiktoken(f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	enc := NewEncoding(name, ranks, split, special)
+	Register(enc)
+	return enc, nil
+}
+
+// byteDecoder 返回 GPT-2 字节级 BPE 中可见字符到原始字节的映射
+func byteDecoder() map[rune]byte {
+	decoder := make(map[rune]byte, 256)
+	n := 0
+	for b := 0; b < 256; b++ {
+		if (b >= '!' && b <= '~') || (b >= 0xA1 && b <= 0xAC) || (b >= 0xAE && b <= 0xFF) {
+			decoder[rune(b)] = byte(b)
+		} else {
+			decoder[rune(256+n)] = byte(b)
+			n++
+		}
+	}
+	return decoder
+}
@@ -0,0 +1,179 @@
+package tokens
+
+import "unicode"
+
+// Splitter 是 BPE 之前的预分词规则，将文本切分为互不重叠的片段
+type Splitter func(text string) []string
+
+// 以下预分词规则与 tiktoken / Hugging Face tokenizers 使用的正则表达式等价。
+// Go 的 regexp 不支持 (?!\S) 等环视语法，因此按正则的匹配顺序手工实现。
+var (
+	// SplitCL100K 对应 cl100k_base：
+	//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+	SplitCL100K Splitter = splitter{digits: 3}.split
+	// SplitQwen 对应 Qwen2 / Qwen3 的 tokenizer，与 cl100k_base 的区别是数字逐个切分
+	SplitQwen Splitter = splitter{digits: 1}.split
+	// SplitO200K 对应 o200k_base，单词按大小写切分且缩写附着在单词上
+	SplitO200K Splitter = splitter{digits: 3, caseAware: true}.split
+)
+
+type splitter struct {
+	digits    int
+	caseAware bool
+}
+
+func (s splitter) split(text string) []string {
+	rs := []rune(text)
+	var pieces []string
+	for i := 0; i < len(rs); {
+		n := s.match(rs, i)
+		pieces = append(pieces, string(rs[i:i+n]))
+		i += n
+	}
+	return pieces
+}
+
+// match 返回从 i 开始的片段长度（按 rune 计），至少为 1
+func (s splitter) match(rs []rune, i int) int {
+	if !s.caseAware {
+		if n := contraction(rs, i); n > 0 {
+			return n
+		}
+	}
+	if n := s.word(rs, i); n > 0 {
+		return n
+	}
+	if unicode.IsNumber(rs[i]) {
+		n := 1
+		for n < s.digits && i+n < len(rs) && unicode.IsNumber(rs[i+n]) {
+			n++
+		}
+		return n
+	}
+	if n := s.punct(rs, i); n > 0 {
+		return n
+	}
+	return whitespace(rs, i)
+}
+
+// word 匹配 [^\r\n\p{L}\p{N}]?\p{L}+，caseAware 时匹配 o200k_base 的大小写规则
+func (s splitter) word(rs []rune, i int) int {
+	start := i
+	if !isNewline(rs[i]) && !unicode.IsLetter(rs[i]) && !unicode.IsNumber(rs[i]) && !(s.caseAware && unicode.Is(unicode.M, rs[i])) {
+		start = i + 1
+	}
+	if start >= len(rs) {
+		return 0
+	}
+	if !s.caseAware {
+		j := start
+		for j < len(rs) && unicode.IsLetter(rs[j]) {
+			j++
+		}
+		if j == start {
+			return 0
+		}
+		return j - i
+	}
+
+	// [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+
+	u := start
+	for u < len(rs) && isUpperish(rs[u]) {
+		u++
+	}
+	end := 0
+	for k := u; k >= start; k-- {
+		l := k
+		for l < len(rs) && isLowerish(rs[l]) {
+			l++
+		}
+		if l > k {
+			end = l
+			break
+		}
+	}
+	// [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*
+	if end == 0 && u > start {
+		end = u
+		for end < len(rs) && isLowerish(rs[end]) {
+			end++
+		}
+	}
+	if end == 0 {
+		return 0
+	}
+	end += contraction(rs, end)
+	return end - i
+}
+
+// punct 匹配 ` ?[^\s\p{L}\p{N}]+[\r\n]*`，o200k_base 的结尾还可以包含 '/'
+func (s splitter) punct(rs []rune, i int) int {
+	j := i
+	if rs[j] == ' ' {
+		j++
+	}
+	k := j
+	for k < len(rs) && !unicode.IsSpace(rs[k]) && !unicode.IsLetter(rs[k]) && !unicode.IsNumber(rs[k]) {
+		k++
+	}
+	if k == j {
+		return 0
+	}
+	for k < len(rs) && (isNewline(rs[k]) || (s.caseAware && rs[k] == '/')) {
+		k++
+	}
+	return k - i
+}
+
+// whitespace 依次匹配 \s*[\r\n]+、\s+(?!\S)、\s+，以及无法归类的单个字符
+func whitespace(rs []rune, i int) int {
+	j := i
+	lastNewline := -1
+	for j < len(rs) && unicode.IsSpace(rs[j]) {
+		if isNewline(rs[j]) {
+			lastNewline = j
+		}
+		j++
+	}
+	switch {
+	case j == i:
+		return 1
+	case lastNewline >= 0:
+		return lastNewline + 1 - i
+	case j == len(rs) || j-i == 1:
+		return j - i
+	default:
+		// 留下最后一个空白字符与后面的单词组成片段
+		return j - 1 - i
+	}
+}
+
+// contraction 匹配 (?i:'s|'t|'re|'ve|'m|'ll|'d)
+func contraction(rs []rune, i int) int {
+	if i+1 >= len(rs) || rs[i] != '\'' {
+		return 0
+	}
+	switch unicode.ToLower(rs[i+1]) {
+	case 's', 't', 'm', 'd':
+		return 2
+	}
+	if i+2 < len(rs) {
+		switch string([]rune{unicode.ToLower(rs[i+1]), unicode.ToLower(rs[i+2])}) {
+		case "re", "ve", "ll":
+			return 3
+		}
+	}
+	return 0
+}
+
+func isNewline(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+func isUpperish(r rune) bool {
+	return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+func isLowerish(r rune) bool {
+	return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
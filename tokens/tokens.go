@@ -0,0 +1,195 @@
+// Package tokens 提供 token 计数：支持 tiktoken 兼容的 BPE 编码（cl100k_base、o200k_base）
+// 和 Qwen tokenizer，用于历史截断和发送前的上下文长度检查。
+//
+// 词表文件体积较大，不随库分发，需要通过 LoadFile 或 Register 注册；
+// 模型对应的编码未注册时按字符数估算，保证在没有词表的环境中也能使用。
+package tokens
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// 内置的编码名称，注册词表时使用这些名称即可自动关联到对应的模型
+const (
+	CL100KBase = "cl100k_base"
+	O200KBase  = "o200k_base"
+	Qwen       = "qwen"
+)
+
+// modelEncodings 按模型名前缀映射到编码，最长前缀优先
+var modelEncodings = map[string]string{
+	"gpt-4o":            O200KBase,
+	"gpt-4.1":           O200KBase,
+	"gpt-4.5":           O200KBase,
+	"gpt-5":             O200KBase,
+	"o1":                O200KBase,
+	"o3":                O200KBase,
+	"o4":                O200KBase,
+	"gpt-4":             CL100KBase,
+	"gpt-3.5":           CL100KBase,
+	"text-embedding-3":  CL100KBase,
+	"text-embedding-ad": CL100KBase,
+	"qwen":              Qwen,
+	"qwq":               Qwen,
+	"/mnt/qwen":         Qwen,
+}
+
+// contextWindows 按模型名前缀记录上下文长度（token），最长前缀优先
+var contextWindows = map[string]int{
+	"gpt-4o":        128000,
+	"gpt-4.1":       1047576,
+	"gpt-4-turbo":   128000,
+	"gpt-4":         8192,
+	"gpt-3.5-turbo": 16385,
+	"o1":            200000,
+	"o3":            200000,
+	"o4-mini":       200000,
+	"qwen-max":      32768,
+	"qwen-plus":     131072,
+	"qwen-turbo":    1000000,
+	"qwen-flash":    1000000,
+	"qwen3-max":     262144,
+	"qwen3":         131072,
+	"deepseek-chat": 128000,
+	"deepseek-reas": 128000,
+}
+
+var (
+	mu        sync.RWMutex
+	encodings = make(map[string]*Encoding)
+)
+
+// Register 注册编码，同名编码会被覆盖
+func Register(enc *Encoding) {
+	mu.Lock()
+	defer mu.Unlock()
+	encodings[enc.Name()] = enc
+}
+
+// Get 返回已注册的编码
+func Get(name string) (*Encoding, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	enc, ok := encodings[name]
+	return enc, ok
+}
+
+// SetModelEncoding 将模型名前缀关联到编码，例如私有化部署的模型路径
+func SetModelEncoding(modelPrefix, encoding string) {
+	mu.Lock()
+	defer mu.Unlock()
+	modelEncodings[strings.ToLower(modelPrefix)] = encoding
+}
+
+// SetContextWindow 设置模型名前缀对应的上下文长度
+func SetContextWindow(modelPrefix string, tokens int) {
+	mu.Lock()
+	defer mu.Unlock()
+	contextWindows[strings.ToLower(modelPrefix)] = tokens
+}
+
+// ForModel 返回模型使用的编码，对应的编码未注册时返回 false
+func ForModel(model string) (*Encoding, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	name, ok := lookup(modelEncodings, model)
+	if !ok {
+		return nil, false
+	}
+	enc, ok := encodings[name]
+	return enc, ok
+}
+
+// ContextWindow 返回模型的上下文长度，未知的模型返回 0
+func ContextWindow(model string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	n, _ := lookup(contextWindows, model)
+	return n
+}
+
+// lookup 依次尝试完整名称、去掉 "openai/" 这类路由前缀后的名称，以及最长前缀匹配
+func lookup[V any](table map[string]V, model string) (V, bool) {
+	model = strings.ToLower(model)
+	candidates := []string{model}
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		candidates = append(candidates, model[i+1:])
+	}
+	var best string
+	for _, name := range candidates {
+		if v, ok := table[name]; ok {
+			return v, true
+		}
+		for prefix := range table {
+			if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+				best = prefix
+			}
+		}
+	}
+	v, ok := table[best]
+	return v, ok && best != ""
+}
+
+// Count 返回文本在模型编码下的 token 数，编码未注册时按字符数估算
+func Count(model, text string) int {
+	if enc, ok := ForModel(model); ok {
+		return enc.Count(text)
+	}
+	return Estimate(text)
+}
+
+// 每条消息的格式开销（角色标记、分隔符等），以及回复的起始标记
+const (
+	tokensPerMessage = 4
+	tokensPerReply   = 3
+)
+
+// CountMessages 返回消息列表作为模型输入时的 token 数，包含每条消息的格式开销。
+// 图片等非文本内容不计入。
+func CountMessages(model string, messages []spec.Message) int {
+	count := Estimate
+	if enc, ok := ForModel(model); ok {
+		count = enc.Count
+	}
+	total := tokensPerReply
+	for _, m := range messages {
+		total += tokensPerMessage + count(m.PlainText())
+		if m.ReasoningContent != "" {
+			total += count(m.ReasoningContent)
+		}
+	}
+	return total
+}
+
+// Estimate 在没有词表时粗略估算 token 数：
+// 中日韩字符按 1 个 token 计，其余字符按每 4 个约 1 个 token 计。
+func Estimate(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// CheckContext 在发送前检查输入加上预留的输出 token 是否超出模型的上下文长度，
+// 超出时返回的错误满足 errors.Is(err, spec.ErrContextLengthExceeded)。未知的模型不做检查。
+func CheckContext(model string, messages []spec.Message, reserveOutput int) error {
+	window := ContextWindow(model)
+	if window <= 0 {
+		return nil
+	}
+	if n := CountMessages(model, messages); n+reserveOutput > window {
+		return fmt.Errorf("tokens: %d input tokens + %d reserved output tokens exceed the %d token context window of %s: %w",
+			n, reserveOutput, window, model, spec.ErrContextLengthExceeded)
+	}
+	return nil
+}
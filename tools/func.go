@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+
+	// validName 是模型接受的函数名格式
+	validName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+	// anonymousName 是匿名函数在运行时的名称格式，如 "func1"
+	anonymousName = regexp.MustCompile(`^func\d+$`)
+)
+
+// Function 是由 Go 函数生成的工具：Tool 返回发送给模型的定义，Call 执行函数
+type Function struct {
+	Name        string
+	Description string
+	// Parameters 由参数结构体生成的 JSON Schema
+	Parameters map[string]any
+
+	fn      reflect.Value
+	hasCtx  bool
+	argType reflect.Type
+}
+
+// Option 用于配置 FromFunc
+type Option func(f *Function)
+
+// WithName 设置函数名，默认使用 Go 函数名；匿名函数必须设置
+func WithName(name string) Option {
+	return func(f *Function) {
+		f.Name = name
+	}
+}
+
+// WithDescription 设置函数说明，模型据此决定何时调用
+func WithDescription(description string) Option {
+	return func(f *Function) {
+		f.Description = description
+	}
+}
+
+// FromFunc 从 Go 函数创建工具，参数的 JSON Schema 由参数结构体通过 Schema 生成。
+//
+// fn 的参数可以是 (ctx context.Context, args T)、(args T)、(ctx context.Context) 或为空，
+// T 为结构体或结构体指针；返回值可以是 (R, error)、R 或 error。
+// R 为 string 时原样作为结果，其他类型编码为 JSON。
+func FromFunc(fn any, opts ...Option) (*Function, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("tools: FromFunc expects a function, got %T", fn)
+	}
+	t := v.Type()
+	f := &Function{fn: v}
+
+	in := 0
+	if t.NumIn() > in && t.In(in) == contextType {
+		f.hasCtx = true
+		in++
+	}
+	if t.NumIn() > in {
+		f.argType = t.In(in)
+		in++
+	}
+	if t.NumIn() > in || t.IsVariadic() {
+		return nil, fmt.Errorf("tools: %s must take at most a context.Context and one argument struct", t)
+	}
+
+	switch {
+	case t.NumOut() == 1:
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+	default:
+		return nil, fmt.Errorf("tools: %s must return (R, error), R or error", t)
+	}
+
+	if f.argType != nil {
+		st := f.argType
+		if st.Kind() == reflect.Pointer {
+			st = st.Elem()
+		}
+		if st.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("tools: argument of %s must be a struct or a pointer to struct", t)
+		}
+		schema, err := SchemaOf(st)
+		if err != nil {
+			return nil, err
+		}
+		f.Parameters = schema
+	} else {
+		f.Parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+
+	f.Name = funcName(v)
+	for _, opt := range opts {
+		opt(f)
+	}
+	if !validName.MatchString(f.Name) {
+		return nil, fmt.Errorf("tools: invalid function name %q, use WithName to set one", f.Name)
+	}
+	return f, nil
+}
+
+// funcName 返回 Go 函数名（不含包路径），匿名函数返回空字符串
+func funcName(v reflect.Value) string {
+	rf := runtime.FuncForPC(v.Pointer())
+	if rf == nil {
+		return ""
+	}
+	name := rf.Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	// 方法值的名称带有 "-fm" 后缀
+	name = strings.TrimSuffix(name, "-fm")
+	if anonymousName.MatchString(name) {
+		return ""
+	}
+	return name
+}
+
+// Tool 返回发送给模型的函数定义
+func (f *Function) Tool() spec.Tool {
+	return spec.NewFunctionTool(f.Name, f.Description, f.Parameters)
+}
+
+// Call 将模型生成的 JSON 参数解析到参数结构体并执行函数，返回提交给模型的结果文本
+func (f *Function) Call(ctx context.Context, args json.RawMessage) (string, error) {
+	var in []reflect.Value
+	if f.hasCtx {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+	if f.argType != nil {
+		arg := reflect.New(f.argType)
+		if len(args) > 0 && string(args) != "null" {
+			if err := json.Unmarshal(args, arg.Interface()); err != nil {
+				return "", fmt.Errorf("invalid arguments for %s: %w", f.Name, err)
+			}
+		}
+		if f.argType.Kind() == reflect.Pointer && arg.Elem().IsNil() {
+			arg.Elem().Set(reflect.New(f.argType.Elem()))
+		}
+		in = append(in, arg.Elem())
+	}
+
+	out := f.fn.Call(in)
+	if last := out[len(out)-1]; last.Type() == errorType {
+		if !last.IsNil() {
+			return "", last.Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return "", nil
+	}
+	if s, ok := out[0].Interface().(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(out[0].Interface())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result of %s: %w", f.Name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,204 @@
+// Package tools 通过反射从 Go 类型和函数生成工具定义，
+// 参数的 JSON Schema 由结构体字段和标签推导，工具定义与代码始终保持一致。
+//
+//	type WeatherArgs struct {
+//		City string `json:"city" description:"城市名称"`
+//		Unit string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+//	}
+//
+//	fn, err := tools.FromFunc(GetWeather, tools.WithDescription("查询城市天气"))
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	rawJSONType = reflect.TypeFor[json.RawMessage]()
+)
+
+// Schema 返回 v 的类型对应的 JSON Schema，v 通常是参数结构体的零值。
+//
+// 字段名取自 json 标签，json:"-" 和未导出的字段会被忽略，匿名嵌入的结构体字段会展开。
+// 非指针且没有 omitempty 的字段为必填。支持的标签：
+//   - description:"..." 字段说明；
+//   - enum:"a,b,c" 可选值，按字段类型解析。
+func Schema(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, fmt.Errorf("tools: cannot derive schema from nil")
+	}
+	return SchemaOf(reflect.TypeOf(v))
+}
+
+// SchemaOf 返回类型 t 对应的 JSON Schema，规则与 Schema 相同
+func SchemaOf(t reflect.Type) (map[string]any, error) {
+	return schemaOf(t, make(map[reflect.Type]bool))
+}
+
+// schemaOf 递归生成 Schema，visiting 用于检测递归类型
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) (map[string]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case rawJSONType:
+		return map[string]any{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte 按 encoding/json 的规则编码为 base64 字符串
+			return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("tools: unsupported map key type %s", t.Key())
+		}
+		values, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("tools: recursive type %s is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := make(map[string]any)
+		required := []string{}
+		if err := structFields(t, visiting, properties, &required); err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}, nil
+	}
+	return nil, fmt.Errorf("tools: unsupported type %s", t)
+}
+
+// structFields 收集结构体的字段，匿名嵌入的结构体字段合并到外层
+func structFields(t reflect.Type, visiting map[reflect.Type]bool, properties map[string]any, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := structFields(ft, visiting, properties, required); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := schemaOf(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("%w (field %s.%s)", err, t.Name(), field.Name)
+		}
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values, err := parseEnum(field.Type, enum)
+			if err != nil {
+				return fmt.Errorf("tools: invalid enum tag on field %s.%s: %w", t.Name(), field.Name, err)
+			}
+			prop["enum"] = values
+		}
+		properties[name] = prop
+
+		if field.Type.Kind() != reflect.Pointer && !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// parseEnum 按字段类型解析以逗号分隔的可选值
+func parseEnum(t reflect.Type, tag string) ([]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	parts := strings.Split(tag, ",")
+	values := make([]any, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		switch t.Kind() {
+		case reflect.String:
+			values = append(values, p)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, n)
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, f)
+		default:
+			return nil, fmt.Errorf("enum is not supported for type %s", t)
+		}
+	}
+	return values, nil
+}
+
+// hasOption 判断 json 标签是否包含指定选项
+func hasOption(opts, name string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package transcript
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// htmlTemplate 是 HTML 记录的模板，消息文本保留换行原样显示，不做 Markdown 渲染
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{if .Title}}{{.Title}}{{else}}对话记录{{end}}</title>
+<style>
+body { font-family: -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; max-width: 860px; margin: 2em auto; padding: 0 1em; color: #222; }
+.msg { border: 1px solid #ddd; border-radius: 8px; padding: .8em 1em; margin: 1em 0; }
+.msg.system { background: #f6f6f6; }
+.msg.user { background: #eef5ff; }
+.msg.tool { background: #fbf8ee; }
+.role { font-weight: bold; margin-bottom: .5em; }
+.role code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; }
+.text { white-space: pre-wrap; word-break: break-word; }
+details { margin: .5em 0; color: #666; }
+summary { cursor: pointer; }
+pre { background: #f4f4f4; padding: .6em; border-radius: 4px; overflow-x: auto; white-space: pre-wrap; }
+img { max-width: 100%; }
+</style>
+</head>
+<body>
+{{- if .Title}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{- range .Entries}}
+<div class="msg {{.Role}}">
+<div class="role">{{.Label}}{{if .ToolName}} <code>{{.ToolName}}</code>{{end}}</div>
+{{- if .Thinking}}
+<details{{if $.Expand}} open{{end}}><summary>思考过程</summary><div class="text">{{.Thinking}}</div></details>
+{{- end}}
+{{- if .Text}}
+{{- if eq .Role "tool"}}
+<pre>{{.Text}}</pre>
+{{- else}}
+<div class="text">{{.Text}}</div>
+{{- end}}
+{{- end}}
+{{- range .Images}}
+<img src="{{.}}" alt="图片">
+{{- end}}
+{{- range .ToolCalls}}
+<div>调用工具 <code>{{.Name}}</code></div>
+<pre>{{.Arguments}}</pre>
+{{- end}}
+</div>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlData 是模板的数据
+type htmlData struct {
+	Title   string
+	Expand  bool
+	Entries []htmlEntry
+}
+
+// htmlEntry 在 entry 的基础上把 data:image/ 图片标记为可信的 URL 以便内嵌显示，
+// 其他地址仍由模板过滤，javascript: 等不安全的地址不会被输出
+type htmlEntry struct {
+	entry
+	Images []any
+}
+
+// WriteHTML 把消息渲染为独立的 HTML 页面写入 w。所有文本都会转义，思考过程放在可折叠的 <details> 中，
+// 图片（包括 data:image/ 形式的内嵌图片）直接显示
+func WriteHTML(w io.Writer, messages []spec.Message, opts Options) error {
+	data := htmlData{Title: opts.Title, Expand: opts.ExpandThinking}
+	for _, e := range entries(messages, opts) {
+		he := htmlEntry{entry: e}
+		for _, url := range e.Images {
+			if strings.HasPrefix(url, "data:image/") {
+				he.Images = append(he.Images, template.URL(url))
+			} else {
+				he.Images = append(he.Images, url)
+			}
+		}
+		data.Entries = append(data.Entries, he)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// HTML 把消息渲染为独立的 HTML 页面，见 WriteHTML
+func HTML(messages []spec.Message, opts Options) (string, error) {
+	var b strings.Builder
+	if err := WriteHTML(&b, messages, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,86 @@
+package transcript
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// Markdown 把消息渲染为 Markdown 记录。思考过程放在 <details> 折叠块中，
+// 工具调用的参数和工具结果放在代码块中；内嵌的 data URL 图片只输出占位文字
+func Markdown(messages []spec.Message, opts Options) string {
+	var b strings.Builder
+	if opts.Title != "" {
+		b.WriteString("# " + opts.Title + "\n\n")
+	}
+	for _, e := range entries(messages, opts) {
+		b.WriteString("### " + e.Label)
+		if e.ToolName != "" {
+			b.WriteString(" `" + e.ToolName + "`")
+		}
+		b.WriteString("\n\n")
+
+		if e.Thinking != "" {
+			open := ""
+			if opts.ExpandThinking {
+				open = " open"
+			}
+			b.WriteString("<details" + open + ">\n<summary>思考过程</summary>\n\n")
+			b.WriteString(quote(e.Thinking))
+			b.WriteString("\n\n</details>\n\n")
+		}
+		if e.Text != "" {
+			if e.Role == string(spec.RoleTool) {
+				b.WriteString(fence(e.Text, ""))
+			} else {
+				b.WriteString(e.Text)
+			}
+			b.WriteString("\n\n")
+		}
+		for _, url := range e.Images {
+			if isDataURL(url) {
+				b.WriteString("*[图片]*\n\n")
+			} else {
+				b.WriteString("![图片](<" + url + ">)\n\n")
+			}
+		}
+		for _, c := range e.ToolCalls {
+			b.WriteString("**调用工具** `" + c.Name + "`\n\n")
+			b.WriteString(fence(c.Arguments, "json"))
+			b.WriteString("\n\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// WriteMarkdown 把 Markdown 记录写入 w
+func WriteMarkdown(w io.Writer, messages []spec.Message, opts Options) error {
+	_, err := io.WriteString(w, Markdown(messages, opts))
+	return err
+}
+
+// quote 把文本渲染为引用块，HTML 标签会被转义以免破坏 <details> 结构
+func quote(text string) string {
+	lines := strings.Split(html.EscapeString(text), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fence 把文本放进代码块，围栏长度大于文本中最长的连续反引号
+func fence(text, lang string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	marker := strings.Repeat("`", max(3, longest+1))
+	return marker + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + marker
+}
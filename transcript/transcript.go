@@ -0,0 +1,115 @@
+// Package transcript 把对话消息导出为 Markdown 或 HTML 记录，包括思考过程（可折叠）、
+// 工具调用及其结果，用于客服质检和审计留档。
+//
+//	f, _ := os.Create("session.html")
+//	defer f.Close()
+//	transcript.WriteHTML(f, c.GetHistory(), transcript.Options{Title: "会话 " + sessionID})
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/iEvan-lhr/go-llm-client/spec"
+)
+
+// defaultLabels 是各角色默认的显示名称
+var defaultLabels = map[spec.Role]string{
+	spec.RoleSystem:    "系统",
+	spec.RoleUser:      "用户",
+	spec.RoleAssistant: "助手",
+	spec.RoleTool:      "工具结果",
+}
+
+// Options 配置导出内容，零值即可使用
+type Options struct {
+	// Title 标题，为空时不输出标题
+	Title string
+	// Labels 覆盖角色的显示名称
+	Labels map[spec.Role]string
+	// HideSystem 不输出系统消息
+	HideSystem bool
+	// HideThinking 不输出思考过程
+	HideThinking bool
+	// HideTools 不输出工具调用和工具结果
+	HideTools bool
+	// ExpandThinking 思考过程默认展开，默认折叠
+	ExpandThinking bool
+}
+
+// label 返回角色的显示名称
+func (o Options) label(role spec.Role) string {
+	if l, ok := o.Labels[role]; ok {
+		return l
+	}
+	if l, ok := defaultLabels[role]; ok {
+		return l
+	}
+	return string(role)
+}
+
+// entry 是一条待渲染的消息，工具结果已关联到对应调用的函数名
+type entry struct {
+	Label     string
+	Role      string
+	Text      string
+	Images    []string
+	Thinking  string
+	ToolCalls []toolCall
+	// ToolName 工具结果对应的函数名，未知时为空
+	ToolName string
+}
+
+type toolCall struct {
+	Name      string
+	Arguments string
+}
+
+// entries 按 opts 过滤消息并整理为渲染所需的结构
+func entries(messages []spec.Message, opts Options) []entry {
+	names := make(map[string]string)
+	out := make([]entry, 0, len(messages))
+	for _, m := range messages {
+		if (opts.HideSystem && m.Role == spec.RoleSystem) || (opts.HideTools && m.Role == spec.RoleTool) {
+			continue
+		}
+		e := entry{Label: opts.label(m.Role), Role: string(m.Role), Text: m.PlainText()}
+		for _, p := range m.Parts {
+			if p.ImageURL != nil {
+				e.Images = append(e.Images, p.ImageURL.URL)
+			}
+		}
+		if !opts.HideThinking {
+			e.Thinking = m.ReasoningContent
+		}
+		for _, c := range m.ToolCalls {
+			names[c.ID] = c.Function.Name
+			if !opts.HideTools {
+				e.ToolCalls = append(e.ToolCalls, toolCall{Name: c.Function.Name, Arguments: prettyJSON(c.Function.Arguments)})
+			}
+		}
+		if m.Role == spec.RoleTool {
+			e.ToolName = names[m.ToolCallID]
+		}
+		if e.Text == "" && e.Thinking == "" && len(e.ToolCalls) == 0 && len(e.Images) == 0 {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// prettyJSON 缩进 JSON 文本，不是合法 JSON 时原样返回
+func prettyJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// isDataURL 判断图片是否为内嵌的 data URL
+func isDataURL(url string) bool {
+	return strings.HasPrefix(url, "data:")
+}